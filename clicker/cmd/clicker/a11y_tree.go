@@ -12,7 +12,10 @@ func newA11yTreeCmd() *cobra.Command {
   # Print the accessibility tree (interesting nodes only)
 
   vibium a11y-tree --everything
-  # Include all nodes (generic containers, etc.)`,
+  # Include all nodes (generic containers, etc.)
+
+  vibium a11y-tree --with-selectors
+  # Add a CSS selector to each interactive node`,
 		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
 			everything, _ := cmd.Flags().GetBool("everything")
@@ -21,6 +24,9 @@ func newA11yTreeCmd() *cobra.Command {
 			if everything {
 				toolArgs["everything"] = true
 			}
+			if withSelectors, _ := cmd.Flags().GetBool("with-selectors"); withSelectors {
+				toolArgs["withSelectors"] = true
+			}
 
 			result, err := daemonCall("browser_a11y_tree", toolArgs)
 			if err != nil {
@@ -31,5 +37,6 @@ func newA11yTreeCmd() *cobra.Command {
 		},
 	}
 	cmd.Flags().Bool("everything", false, "Show all nodes including generic containers")
+	cmd.Flags().Bool("with-selectors", false, "Attach a CSS selector to each interactive node")
 	return cmd
 }
@@ -6,21 +6,40 @@ import (
 
 func newAttrCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "attr [selector] [attribute]",
-		Short: "Get an HTML attribute value from an element",
+		Use:   "attr [selector] [attribute...]",
+		Short: "Get HTML attribute values from an element",
 		Example: `  vibium attr "a" "href"
   # Get the href of the first link
 
   vibium attr "img" "src"
-  # Get the image source URL`,
-		Args: cobra.ExactArgs(2),
+  # Get the image source URL
+
+  vibium attr "a.product" href data-id title
+  # Get several attributes at once as JSON`,
+		Args: cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			selector := args[0]
-			attribute := args[1]
+
+			if len(args) > 2 {
+				attributes := make([]interface{}, 0, len(args)-1)
+				for _, a := range args[1:] {
+					attributes = append(attributes, a)
+				}
+				result, err := daemonCall("browser_get_attributes", map[string]interface{}{
+					"selector":   selector,
+					"attributes": attributes,
+				})
+				if err != nil {
+					printError(err)
+					return
+				}
+				printResult(result)
+				return
+			}
 
 			result, err := daemonCall("browser_get_attribute", map[string]interface{}{
 				"selector":  selector,
-				"attribute": attribute,
+				"attribute": args[1],
 			})
 			if err != nil {
 				printError(err)
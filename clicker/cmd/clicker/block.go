@@ -0,0 +1,51 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newBlockCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "block <pattern>...",
+		Short: "Block requests whose URL matches any pattern",
+		Example: `  vibium block "*google-analytics.com*" "*doubleclick.net*"
+  # Skip analytics and ads
+
+  vibium block "*.woff2"
+  # Don't load web fonts`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			result, err := daemonCall("browser_block_urls", map[string]interface{}{"patterns": args})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+
+	clearCmd := &cobra.Command{
+		Use:   "clear [pattern]...",
+		Short: "Stop blocking URL patterns",
+		Example: `  vibium block clear "*.woff2"
+  # Unblock one pattern
+
+  vibium block clear
+  # Unblock everything`,
+		Run: func(cmd *cobra.Command, args []string) {
+			toolArgs := map[string]interface{}{}
+			if len(args) > 0 {
+				toolArgs["patterns"] = args
+			}
+			result, err := daemonCall("browser_unblock_urls", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+
+	cmd.AddCommand(clearCmd)
+	return cmd
+}
@@ -2,10 +2,11 @@ package main
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/vibium/clicker/internal/api"
 )
 
 func newCheckCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "check [selector]",
 		Short: "Check a checkbox or radio button",
 		Example: `  vibium check "input[name=agree]"
@@ -14,7 +15,10 @@ func newCheckCmd() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			selector := args[0]
 
-			result, err := daemonCall("browser_check", map[string]interface{}{"selector": selector})
+			toolArgs := map[string]interface{}{"selector": selector}
+			addTimeoutArg(cmd, toolArgs)
+
+			result, err := daemonCall("browser_check", toolArgs)
 			if err != nil {
 				printError(err)
 				return
@@ -22,4 +26,6 @@ func newCheckCmd() *cobra.Command {
 			printResult(result)
 		},
 	}
+	cmd.Flags().Duration("timeout", api.DefaultTimeout, "Timeout for actionability checks (e.g., 5s, 30s)")
+	return cmd
 }
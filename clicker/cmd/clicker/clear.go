@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newClearCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear [selector]",
+		Short: "Clear an input field",
+		Example: `  vibium clear "#search"
+  # Empty the search box
+
+  vibium clear @e3
+  # Clear field from map`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			result, err := daemonCall("browser_clear", map[string]interface{}{"selector": args[0]})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+}
@@ -16,7 +16,10 @@ func newClickCmd() *cobra.Command {
   # Navigates to URL first, then clicks
 
   vibium click https://example.com "a" --timeout 5s
-  # Custom timeout for actionability checks`,
+  # Custom timeout for actionability checks
+
+  vibium click "a.docs" --button middle
+  # Middle-click a link to open it in a new tab`,
 		Args: cobra.RangeArgs(1, 2),
 		Run: func(cmd *cobra.Command, args []string) {
 			var selector string
@@ -34,7 +37,15 @@ func newClickCmd() *cobra.Command {
 			}
 
 			// Click element
-			result, err := daemonCall("browser_click", map[string]interface{}{"selector": selector})
+			toolArgs := map[string]interface{}{"selector": selector}
+			if button, _ := cmd.Flags().GetString("button"); button != "" && button != "left" {
+				toolArgs["button"] = button
+			}
+			if force, _ := cmd.Flags().GetBool("force"); force {
+				toolArgs["force"] = true
+			}
+			addTimeoutArg(cmd, toolArgs)
+			result, err := daemonCall("browser_click", toolArgs)
 			if err != nil {
 				printError(err)
 				return
@@ -43,5 +54,7 @@ func newClickCmd() *cobra.Command {
 		},
 	}
 	cmd.Flags().Duration("timeout", api.DefaultTimeout, "Timeout for actionability checks (e.g., 5s, 30s)")
+	cmd.Flags().Bool("force", false, "Skip actionability checks (bypasses safety; for elements that never settle)")
+	cmd.Flags().String("button", "left", "Mouse button: left, middle, or right")
 	return cmd
 }
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newConsoleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "console",
+		Short: "Show console messages captured from the page",
+		Example: `  vibium console
+  # Show all console messages since the browser started
+
+  vibium console --level error
+  # Show only console.error messages
+
+  vibium console --clear
+  # Show messages, then clear the buffer`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			level, _ := cmd.Flags().GetString("level")
+			clear, _ := cmd.Flags().GetBool("clear")
+
+			toolArgs := map[string]interface{}{}
+			if level != "" {
+				toolArgs["level"] = level
+			}
+			if clear {
+				toolArgs["clear"] = true
+			}
+
+			result, err := daemonCall("browser_console_logs", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	cmd.Flags().String("level", "", "Only show messages at this level: debug, info, warn, error")
+	cmd.Flags().Bool("clear", false, "Clear the buffer after showing messages")
+	return cmd
+}
@@ -25,15 +25,15 @@ func newContentCmd() *cobra.Command {
 			if useStdin {
 				data, err := io.ReadAll(os.Stdin)
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
-					os.Exit(1)
+					printError(fmt.Errorf("reading stdin: %w", err))
+					return
 				}
 				html = string(data)
 			} else if len(args) == 1 {
 				html = args[0]
 			} else {
-				fmt.Fprintf(os.Stderr, "Error: html argument or --stdin flag is required\n")
-				os.Exit(1)
+				printError(fmt.Errorf("html argument or --stdin flag is required"))
+				return
 			}
 
 			result, err := daemonCall("browser_set_content", map[string]interface{}{"html": html})
@@ -12,15 +12,40 @@ func newCookiesCmd() *cobra.Command {
   # List all cookies
 
   vibium cookies "session" "abc123"
-  # Set a cookie with name and value`,
+  # Set a cookie with name and value
+
+  vibium cookies "session" "abc123" --secure --http-only --same-site strict
+  # Set a secure, HTTP-only cookie
+
+  vibium cookies "remember" "1" --expiry 1893456000
+  # Set a persistent cookie that expires at the given epoch seconds`,
 		Args: cobra.RangeArgs(0, 2),
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) == 2 {
 				// Set cookie
-				result, err := daemonCall("browser_set_cookie", map[string]interface{}{
+				toolArgs := map[string]interface{}{
 					"name":  args[0],
 					"value": args[1],
-				})
+				}
+				if domain, _ := cmd.Flags().GetString("domain"); domain != "" {
+					toolArgs["domain"] = domain
+				}
+				if path, _ := cmd.Flags().GetString("path"); path != "" {
+					toolArgs["path"] = path
+				}
+				if secure, _ := cmd.Flags().GetBool("secure"); secure {
+					toolArgs["secure"] = true
+				}
+				if httpOnly, _ := cmd.Flags().GetBool("http-only"); httpOnly {
+					toolArgs["httpOnly"] = true
+				}
+				if sameSite, _ := cmd.Flags().GetString("same-site"); sameSite != "" {
+					toolArgs["sameSite"] = sameSite
+				}
+				if expiry, _ := cmd.Flags().GetInt64("expiry"); expiry > 0 {
+					toolArgs["expiry"] = expiry
+				}
+				result, err := daemonCall("browser_set_cookie", toolArgs)
 				if err != nil {
 					printError(err)
 					return
@@ -39,6 +64,30 @@ func newCookiesCmd() *cobra.Command {
 		},
 	}
 
+	getCmd := &cobra.Command{
+		Use:   "get <name>",
+		Short: "Show a single cookie as JSON (null if not set)",
+		Example: `  vibium cookies get session
+  # {"name":"session","value":"abc123","domain":"example.com",...}
+
+  vibium cookies get session --domain auth.example.com
+  # Only match the cookie set for auth.example.com`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			toolArgs := map[string]interface{}{"name": args[0]}
+			if domain, _ := cmd.Flags().GetString("domain"); domain != "" {
+				toolArgs["domain"] = domain
+			}
+			result, err := daemonCall("browser_get_cookie", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	getCmd.Flags().String("domain", "", "Only match a cookie set for this domain")
+
 	clearCmd := &cobra.Command{
 		Use:   "clear",
 		Short: "Clear all cookies",
@@ -55,6 +104,34 @@ func newCookiesCmd() *cobra.Command {
 		},
 	}
 
+	changedCmd := &cobra.Command{
+		Use:   "changed",
+		Short: "Show cookies added, removed, or changed since the last call",
+		Example: `  vibium cookies changed
+  # First call takes a snapshot
+
+  vibium click "#login" && vibium cookies changed
+  # Shows e.g. {"added":[{"name":"session",...}],"removed":[],"changed":[]}`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			result, err := daemonCall("browser_get_cookies_changed", map[string]interface{}{})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+
+	cookiesCmd.Flags().String("domain", "", "Cookie domain (defaults to the current page's domain)")
+	cookiesCmd.Flags().String("path", "", "Cookie path (defaults to /)")
+	cookiesCmd.Flags().Bool("secure", false, "Only send the cookie over HTTPS")
+	cookiesCmd.Flags().Bool("http-only", false, "Hide the cookie from document.cookie")
+	cookiesCmd.Flags().String("same-site", "", "SameSite policy: strict, lax, or none (none requires --secure)")
+	cookiesCmd.Flags().Int64("expiry", 0, "Expiry as Unix epoch seconds (defaults to a session cookie)")
+
+	cookiesCmd.AddCommand(getCmd)
 	cookiesCmd.AddCommand(clearCmd)
+	cookiesCmd.AddCommand(changedCmd)
 	return cookiesCmd
 }
@@ -5,19 +5,27 @@ import (
 )
 
 func newCountCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "count [selector]",
 		Short: "Count matching elements",
 		Example: `  vibium count "a"
   # Print number of links on the page
 
   vibium count "li.item"
-  # Count list items`,
+  # Count list items
+
+  vibium count "tr" --scope "#orders"
+  # Count rows inside one table only`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			selector := args[0]
 
-			result, err := daemonCall("browser_count", map[string]interface{}{"selector": selector})
+			toolArgs := map[string]interface{}{"selector": selector}
+			if scope, _ := cmd.Flags().GetString("scope"); scope != "" {
+				toolArgs["scope"] = scope
+			}
+
+			result, err := daemonCall("browser_count", toolArgs)
 			if err != nil {
 				printError(err)
 				return
@@ -25,4 +33,6 @@ func newCountCmd() *cobra.Command {
 			printResult(result)
 		},
 	}
+	cmd.Flags().String("scope", "", "Count only within the element matching this selector or @ref")
+	return cmd
 }
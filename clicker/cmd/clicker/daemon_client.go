@@ -15,6 +15,54 @@ import (
 // daemonCall sends a tool call to the daemon, auto-starting if needed.
 // Returns the result or an error.
 func daemonCall(toolName string, args map[string]interface{}) (*agent.ToolsCallResult, error) {
+	applyGlobalArgs(toolName, args)
+
+	result, err := callDaemon(toolName, args)
+	delay := retryDelay
+	for attempt := 1; attempt <= retryCount && isRetryableError(err); attempt++ {
+		fmt.Fprintf(os.Stderr, "Retrying in %s (%d/%d): %v\n", delay, attempt, retryCount, err)
+		time.Sleep(delay)
+		delay *= 2
+		result, err = callDaemon(toolName, args)
+	}
+	return result, err
+}
+
+// applyGlobalArgs fills in arguments set by global flags unless the caller
+// already passed them.
+func applyGlobalArgs(toolName string, args map[string]interface{}) {
+	// A global --timeout/VIBIUM_TIMEOUT applies to this call even when the
+	// daemon was started with a different default. Only tools whose schema
+	// declares a timeout get one, so other tools see exactly the arguments
+	// their schema describes.
+	if timeoutOverride > 0 && args != nil && toolHasParam(toolName, "timeout") {
+		if _, ok := args["timeout"]; !ok {
+			args["timeout"] = float64(timeoutOverride.Milliseconds())
+		}
+	}
+
+	if frameTarget != "" && args != nil {
+		if _, ok := args["frame"]; !ok {
+			args["frame"] = frameTarget
+		}
+	}
+}
+
+// toolHasParam reports whether the named tool's input schema declares param.
+func toolHasParam(toolName, param string) bool {
+	for _, t := range agent.GetToolSchemas() {
+		if t.Name != toolName {
+			continue
+		}
+		props, _ := t.InputSchema["properties"].(map[string]interface{})
+		_, ok := props[param]
+		return ok
+	}
+	return false
+}
+
+// callDaemon makes one tool call, auto-starting the daemon if it isn't running.
+func callDaemon(toolName string, args map[string]interface{}) (*agent.ToolsCallResult, error) {
 	// First attempt
 	result, err := daemon.Call(toolName, args)
 	if err == nil {
@@ -49,6 +97,9 @@ func autoStartDaemon() error {
 	if headless {
 		args = append(args, "--headless")
 	}
+	if timeoutOverride > 0 {
+		args = append(args, fmt.Sprintf("--timeout=%s", timeoutOverride))
+	}
 
 	// Forward connect env vars to the spawned daemon
 	connectURL, connectHeaders := connectFromEnv()
@@ -105,6 +156,28 @@ func isConnectionError(err error) bool {
 	return false
 }
 
+// isRetryableError reports whether a failed tool call is worth re-running
+// under --retry: the element wasn't found or wasn't actionable in time.
+// Connection and daemon errors are not retried.
+func isRetryableError(err error) bool {
+	if err == nil || isConnectionError(err) {
+		return false
+	}
+	errMsg := err.Error()
+	for _, pattern := range []string{
+		"element not ",   // not found, not visible, not enabled, ...
+		"element still ", // still moving, still visible
+		"no elements found",
+		"waiting for element",
+		"check failed", // actionability check
+	} {
+		if containsString(errMsg, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
 func containsString(s, substr string) bool {
 	return len(s) >= len(substr) && searchString(s, substr)
 }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestToolHasParam(t *testing.T) {
+	tests := []struct {
+		tool  string
+		param string
+		want  bool
+	}{
+		{"browser_click", "timeout", true},
+		{"browser_wait", "timeout", true},
+		{"browser_navigate", "url", true},
+		{"browser_navigate", "timeout", false},
+		{"browser_start", "timeout", false},
+		{"no_such_tool", "timeout", false},
+	}
+	for _, tt := range tests {
+		if got := toolHasParam(tt.tool, tt.param); got != tt.want {
+			t.Errorf("toolHasParam(%q, %q) = %v, want %v", tt.tool, tt.param, got, tt.want)
+		}
+	}
+}
+
+func TestApplyGlobalArgsTimeout(t *testing.T) {
+	saved := timeoutOverride
+	defer func() { timeoutOverride = saved }()
+	timeoutOverride = 5 * time.Second
+
+	click := map[string]interface{}{"selector": "#go"}
+	applyGlobalArgs("browser_click", click)
+	if click["timeout"] != float64(5000) {
+		t.Errorf("browser_click timeout = %v, want 5000", click["timeout"])
+	}
+
+	explicit := map[string]interface{}{"selector": "#go", "timeout": float64(100)}
+	applyGlobalArgs("browser_click", explicit)
+	if explicit["timeout"] != float64(100) {
+		t.Errorf("explicit timeout = %v, want 100", explicit["timeout"])
+	}
+
+	start := map[string]interface{}{}
+	applyGlobalArgs("browser_start", start)
+	if _, ok := start["timeout"]; ok {
+		t.Errorf("browser_start got a timeout it does not declare: %v", start)
+	}
+}
@@ -86,7 +86,7 @@ func newDaemonStopCmd() *cobra.Command {
 		Short: "Stop the vibium daemon",
 		Run: func(cmd *cobra.Command, args []string) {
 			if !daemon.IsRunning() {
-				fmt.Println("Daemon is not running.")
+				printMessage("Daemon is not running.")
 				return
 			}
 
@@ -94,8 +94,8 @@ func newDaemonStopCmd() *cobra.Command {
 			pid, _ := daemon.ReadPID()
 
 			if err := daemon.Shutdown(); err != nil {
-				fmt.Fprintf(os.Stderr, "Error stopping daemon: %v\n", err)
-				os.Exit(1)
+				printError(fmt.Errorf("stopping daemon: %w", err))
+				return
 			}
 
 			// Wait for the daemon process to fully exit (including Chrome cleanup)
@@ -109,7 +109,7 @@ func newDaemonStopCmd() *cobra.Command {
 				}
 			}
 
-			fmt.Println("Daemon stopped.")
+			printMessage("Daemon stopped.")
 		},
 	}
 }
@@ -120,29 +120,30 @@ func newDaemonStatusCmd() *cobra.Command {
 		Short: "Show daemon status",
 		Run: func(cmd *cobra.Command, args []string) {
 			if !daemon.IsRunning() {
-				fmt.Println("Daemon is not running.")
 				if jsonOutput {
-					printJSON(map[string]interface{}{
+					printJSON(jsonEnvelope{OK: true, Result: map[string]interface{}{
 						"running": false,
-					})
+					}})
+					return
 				}
+				fmt.Println("Daemon is not running.")
 				return
 			}
 
 			status, err := daemon.Status()
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error getting status: %v\n", err)
-				os.Exit(1)
+				printError(fmt.Errorf("getting status: %w", err))
+				return
 			}
 
 			if jsonOutput {
-				printJSON(map[string]interface{}{
+				printJSON(jsonEnvelope{OK: true, Result: map[string]interface{}{
 					"running": true,
 					"version": status.Version,
 					"pid":     status.PID,
 					"uptime":  status.Uptime,
 					"socket":  status.Socket,
-				})
+				}})
 				return
 			}
 
@@ -238,14 +239,14 @@ func daemonize(idleTimeout time.Duration, connectFlag string, headerFlags []stri
 	daemon.CleanStale()
 
 	if daemon.IsRunning() {
-		fmt.Println("Daemon is already running.")
+		printMessage("Daemon is already running.")
 		return
 	}
 
 	exe, err := os.Executable()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error finding executable: %v\n", err)
-		os.Exit(1)
+		printError(fmt.Errorf("finding executable: %w", err))
+		return
 	}
 
 	args := []string{"daemon", "start", "--_internal",
@@ -271,18 +272,18 @@ func daemonize(idleTimeout time.Duration, connectFlag string, headerFlags []stri
 	setSysProcAttr(cmd)
 
 	if err := cmd.Start(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error starting daemon: %v\n", err)
-		os.Exit(1)
+		printError(fmt.Errorf("starting daemon: %w", err))
+		return
 	}
 
 	// Poll for socket availability
 	socketPath, _ := paths.GetSocketPath()
 	if err := waitForSocket(socketPath, 5*time.Second); err != nil {
-		fmt.Fprintf(os.Stderr, "Daemon failed to start: %v\n", err)
-		os.Exit(1)
+		printError(fmt.Errorf("daemon failed to start: %w", err))
+		return
 	}
 
-	fmt.Printf("Daemon started (pid %d)\n", cmd.Process.Pid)
+	printMessage("Daemon started (pid %d)", cmd.Process.Pid)
 }
 
 // waitForSocket polls until the socket is connectable or timeout.
@@ -18,7 +18,7 @@ func newVersionCmd() *cobra.Command {
 		Use:   "version",
 		Short: "Print the version number",
 		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Printf("%s v%s\n", filepath.Base(os.Args[0]), version)
+			printMessage("%s v%s", filepath.Base(os.Args[0]), version)
 		},
 	}
 }
@@ -28,6 +28,18 @@ func newPathsCmd() *cobra.Command {
 		Use:   "paths",
 		Short: "Print browser and cache paths",
 		Run: func(cmd *cobra.Command, args []string) {
+			if jsonOutput {
+				cacheDir, _ := paths.GetCacheDir()
+				chromePath, _ := paths.GetChromeExecutable()
+				chromedriverPath, _ := paths.GetChromedriverPath()
+				printJSON(jsonEnvelope{OK: true, Result: map[string]interface{}{
+					"cacheDir":     cacheDir,
+					"chrome":       chromePath,
+					"chromedriver": chromedriverPath,
+				}})
+				return
+			}
+
 			cacheDir, err := paths.GetCacheDir()
 			if err != nil {
 				fmt.Printf("Cache directory: error: %v\n", err)
@@ -59,8 +71,17 @@ func newInstallCmd() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			result, err := browser.Install()
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
+				printError(err)
+				return
+			}
+
+			if jsonOutput {
+				printJSON(jsonEnvelope{OK: true, Result: map[string]interface{}{
+					"chrome":       result.ChromePath,
+					"chromedriver": result.ChromedriverPath,
+					"version":      result.Version,
+				}})
+				return
 			}
 
 			fmt.Println("Installation complete!")
@@ -53,7 +53,51 @@ func newDialogCmd() *cobra.Command {
 		},
 	}
 
+	getCmd := &cobra.Command{
+		Use:   "get",
+		Short: "Show the open dialog's type and message",
+		Example: `  vibium dialog get
+  # Prints {"type":"confirm","message":"Delete this item?"}, or null`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			result, err := daemonCall("browser_get_dialog", map[string]interface{}{})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+
+	handlerCmd := &cobra.Command{
+		Use:   "handler <accept|dismiss|accept-with-text|none> [text]",
+		Short: "Answer dialogs automatically as they open",
+		Example: `  vibium dialog handler accept
+  # Accept every alert and confirm from now on
+
+  vibium dialog handler accept-with-text "my input"
+  # Also answer prompts with "my input"
+
+  vibium dialog handler none
+  # Stop handling dialogs automatically`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			callArgs := map[string]interface{}{"policy": args[0]}
+			if len(args) == 2 {
+				callArgs["text"] = args[1]
+			}
+			result, err := daemonCall("browser_set_dialog_handler", callArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+
 	dialogCmd.AddCommand(acceptCmd)
 	dialogCmd.AddCommand(dismissCmd)
+	dialogCmd.AddCommand(getCmd)
+	dialogCmd.AddCommand(handlerCmd)
 	return dialogCmd
 }
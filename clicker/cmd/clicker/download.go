@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
@@ -27,8 +26,8 @@ func newDownloadCmd() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			dir, err := filepath.Abs(args[0])
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: invalid path: %v\n", err)
-				os.Exit(1)
+				printError(fmt.Errorf("invalid path: %v", err))
+				return
 			}
 
 			result, err := daemonCall("browser_download_set_dir", map[string]interface{}{"path": dir})
@@ -40,6 +39,23 @@ func newDownloadCmd() *cobra.Command {
 		},
 	}
 
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List downloads with their state and size",
+		Example: `  vibium download list
+  # [{"url":"...","suggestedFilename":"report.csv","state":"complete","path":"...","bytes":1234,...}]`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			result, err := daemonCall("browser_list_downloads", map[string]interface{}{})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+
 	downloadCmd.AddCommand(dirCmd)
+	downloadCmd.AddCommand(listCmd)
 	return downloadCmd
 }
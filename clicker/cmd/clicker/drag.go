@@ -1,27 +1,90 @@
 package main
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 )
 
 func newDragCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "drag [source] [target]",
 		Short: "Drag from one element to another",
 		Example: `  vibium drag ".draggable" ".dropzone"
   # Drag element to drop target
 
   vibium drag @e1 @e3
-  # Drag using map refs`,
+  # Drag using map refs
+
+  vibium drag ".card" ".column" --hold 300ms --move 600ms --steps 10
+  # Slower drag with intermediate moves for sortable lists
+
+  vibium drag "#item" "#trash" --mode html5
+  # Use HTML5 drag events (react-dnd, draggable="true" elements)
+
+  vibium drag by "input[type=range]" --dx 120
+  # Drag a slider thumb 120px to the right`,
 		Args: cobra.ExactArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			source := args[0]
 			target := args[1]
 
-			result, err := daemonCall("browser_drag", map[string]interface{}{
+			steps, _ := cmd.Flags().GetInt("steps")
+
+			toolArgs := map[string]interface{}{
 				"source": source,
 				"target": target,
-			})
+				"steps":  float64(steps),
+			}
+			addDragTimingArgs(cmd, toolArgs)
+			if mode, _ := cmd.Flags().GetString("mode"); mode != "" {
+				toolArgs["mode"] = mode
+			}
+
+			result, err := daemonCall("browser_drag", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+
+	cmd.Flags().Duration("hold", 100*time.Millisecond, "Pause between pressing the button and moving")
+	cmd.Flags().Duration("move", 200*time.Millisecond, "Total duration of the move")
+	cmd.Flags().Int("steps", 1, "Number of intermediate pointer moves")
+	cmd.Flags().String("mode", "pointer", "Drag mode: pointer (mouse input) or html5 (drag events)")
+
+	byCmd := &cobra.Command{
+		Use:   "by [source]",
+		Short: "Drag by a pixel delta from an element or point",
+		Example: `  vibium drag by ".slider-thumb" --dx 120
+  # Drag a slider thumb 120px to the right
+
+  vibium drag by --x 200 --y 300 --dx 150 --dy 80 --steps 10
+  # Draw a stroke on a canvas from (200, 300)`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			dx, _ := cmd.Flags().GetInt("dx")
+			dy, _ := cmd.Flags().GetInt("dy")
+			steps, _ := cmd.Flags().GetInt("steps")
+
+			toolArgs := map[string]interface{}{
+				"deltaX": float64(dx),
+				"deltaY": float64(dy),
+				"steps":  float64(steps),
+			}
+			addDragTimingArgs(cmd, toolArgs)
+			if len(args) == 1 {
+				toolArgs["source"] = args[0]
+			} else {
+				x, _ := cmd.Flags().GetInt("x")
+				y, _ := cmd.Flags().GetInt("y")
+				toolArgs["x"] = float64(x)
+				toolArgs["y"] = float64(y)
+			}
+
+			result, err := daemonCall("browser_drag_by", toolArgs)
 			if err != nil {
 				printError(err)
 				return
@@ -29,4 +92,22 @@ func newDragCmd() *cobra.Command {
 			printResult(result)
 		},
 	}
+	byCmd.Flags().Duration("hold", 100*time.Millisecond, "Pause between pressing the button and moving")
+	byCmd.Flags().Duration("move", 200*time.Millisecond, "Total duration of the move")
+	byCmd.Flags().Int("dx", 0, "Horizontal distance in pixels (negative drags left)")
+	byCmd.Flags().Int("dy", 0, "Vertical distance in pixels (negative drags up)")
+	byCmd.Flags().Int("x", 0, "Start X coordinate when no source is given")
+	byCmd.Flags().Int("y", 0, "Start Y coordinate when no source is given")
+	byCmd.Flags().Int("steps", 1, "Number of intermediate pointer moves")
+
+	cmd.AddCommand(byCmd)
+	return cmd
+}
+
+// addDragTimingArgs copies the --hold and --move flags into tool args.
+func addDragTimingArgs(cmd *cobra.Command, toolArgs map[string]interface{}) {
+	hold, _ := cmd.Flags().GetDuration("hold")
+	move, _ := cmd.Flags().GetDuration("move")
+	toolArgs["holdMs"] = float64(hold.Milliseconds())
+	toolArgs["moveMs"] = float64(move.Milliseconds())
 }
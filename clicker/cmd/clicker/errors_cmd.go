@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newErrorsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "errors",
+		Short: "Show uncaught JavaScript errors captured from the page",
+		Example: `  vibium errors
+  # Show uncaught exceptions and unhandled rejections since the browser started
+
+  vibium errors --clear
+  # Show errors, then clear the buffer`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			toolArgs := map[string]interface{}{}
+			if clear, _ := cmd.Flags().GetBool("clear"); clear {
+				toolArgs["clear"] = true
+			}
+
+			result, err := daemonCall("browser_page_errors", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	cmd.Flags().Bool("clear", false, "Clear the buffer after showing errors")
+	return cmd
+}
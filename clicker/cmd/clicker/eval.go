@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -20,7 +21,10 @@ func newEvalCmd() *cobra.Command {
   # Navigates to URL first, then evaluates
 
   echo 'document.title' | vibium eval --stdin
-  # Read expression from stdin (avoids shell quoting issues)`,
+  # Read expression from stdin (avoids shell quoting issues)
+
+  vibium eval '(sel, n) => document.querySelectorAll(sel).length > n' --arg "li.item" --arg 3
+  # Call a function with arguments (JSON values, otherwise strings)`,
 		Args: cobra.RangeArgs(0, 2),
 		Run: func(cmd *cobra.Command, args []string) {
 			useStdin, _ := cmd.Flags().GetBool("stdin")
@@ -46,19 +50,37 @@ func newEvalCmd() *cobra.Command {
 				// eval <expression> — current page
 				expression = args[0]
 			} else {
-				fmt.Fprintf(os.Stderr, "Error: expression is required (use args or --stdin)\n")
-				os.Exit(1)
+				printError(fmt.Errorf("expression is required (use args or --stdin)"))
+				return
 			}
 
 			// Evaluate
-			result, err := daemonCall("browser_evaluate", map[string]interface{}{"expression": expression})
+			toolArgs := map[string]interface{}{"expression": expression}
+			if argStrs, _ := cmd.Flags().GetStringArray("arg"); len(argStrs) > 0 {
+				fnArgs := make([]interface{}, len(argStrs))
+				for i, a := range argStrs {
+					var v interface{}
+					if err := json.Unmarshal([]byte(a), &v); err != nil {
+						v = a
+					}
+					fnArgs[i] = v
+				}
+				toolArgs["args"] = fnArgs
+			}
+			result, err := daemonCall("browser_evaluate", toolArgs)
 			if err != nil {
 				printError(err)
 				return
 			}
+			if jsonOutput {
+				// Embed the value itself; the text form leaves strings unquoted
+				printJSON(jsonEnvelope{OK: true, Result: result.StructuredContent["value"]})
+				return
+			}
 			printResult(result)
 		},
 	}
 	cmd.Flags().Bool("stdin", false, "Read expression from stdin")
+	cmd.Flags().StringArray("arg", nil, "Argument for a function expression (repeatable; parsed as JSON, else a string)")
 	return cmd
 }
@@ -2,10 +2,11 @@ package main
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/vibium/clicker/internal/api"
 )
 
 func newFillCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "fill [selector] [text]",
 		Short: "Clear an input field and type new text",
 		Example: `  vibium fill "input[name=email]" "user@example.com"
@@ -18,10 +19,13 @@ func newFillCmd() *cobra.Command {
 			selector := args[0]
 			text := args[1]
 
-			result, err := daemonCall("browser_fill", map[string]interface{}{
+			toolArgs := map[string]interface{}{
 				"selector": selector,
 				"value":    text,
-			})
+			}
+			addTimeoutArg(cmd, toolArgs)
+
+			result, err := daemonCall("browser_fill", toolArgs)
 			if err != nil {
 				printError(err)
 				return
@@ -29,4 +33,6 @@ func newFillCmd() *cobra.Command {
 			printResult(result)
 		},
 	}
+	cmd.Flags().Duration("timeout", api.DefaultTimeout, "Timeout for actionability checks (e.g., 5s, 30s)")
+	return cmd
 }
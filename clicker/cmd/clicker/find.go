@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -28,8 +27,8 @@ func newFindCmd() *cobra.Command {
 		Args: cobra.RangeArgs(0, 2),
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) == 0 {
-				fmt.Fprintf(os.Stderr, "Error: requires a CSS selector or use a subcommand (text, role, label, etc.)\n")
-				os.Exit(1)
+				printError(fmt.Errorf("requires a CSS selector or use a subcommand (text, role, label, etc.)"))
+				return
 			}
 
 			all, _ := cmd.Flags().GetBool("all")
@@ -76,10 +75,15 @@ func newFindCmd() *cobra.Command {
 		Use:   "text [text]",
 		Short: "Find element by text content",
 		Example: `  vibium find text "Sign In"
-  # → @e1 [button] "Sign In"`,
+  # → @e1 [button] "Sign In"
+
+  vibium find text "sign in" --exact --ignore-case
+  # Whole-text match instead of substring, ignoring case`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			result, err := daemonCall("browser_find", map[string]interface{}{"text": args[0]})
+			toolArgs := map[string]interface{}{"text": args[0]}
+			addTextMatchArgs(cmd, toolArgs)
+			result, err := daemonCall("browser_find", toolArgs)
 			if err != nil {
 				printError(err)
 				return
@@ -95,15 +99,27 @@ func newFindCmd() *cobra.Command {
   # → @e1 [button] "Submit"
 
   vibium find role heading --name "Example"
-  # Find heading with accessible name "Example"`,
+  # Find heading with accessible name "Example"
+
+  vibium find role tab --all
+  # JSON array of every tab with index, @ref, tag, name, text, box`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			toolArgs := map[string]interface{}{"role": args[0]}
 			name, _ := cmd.Flags().GetString("name")
-			if name != "" {
-				toolArgs["text"] = name
+			tool := "browser_find"
+			if all, _ := cmd.Flags().GetBool("all"); all {
+				tool = "browser_find_all_by_role"
+				if name != "" {
+					toolArgs["name"] = name
+				}
+			} else {
+				if name != "" {
+					toolArgs["text"] = name
+				}
+				addTextMatchArgs(cmd, toolArgs)
 			}
-			result, err := daemonCall("browser_find", toolArgs)
+			result, err := daemonCall(tool, toolArgs)
 			if err != nil {
 				printError(err)
 				return
@@ -112,6 +128,7 @@ func newFindCmd() *cobra.Command {
 		},
 	}
 	roleCmd.Flags().String("name", "", "Accessible name filter")
+	roleCmd.Flags().Bool("all", false, "Return every matching element as a JSON array")
 
 	labelCmd := &cobra.Command{
 		Use:   "label [label]",
@@ -120,7 +137,9 @@ func newFindCmd() *cobra.Command {
   # → @e1 [input type="email"] placeholder="Email"`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			result, err := daemonCall("browser_find", map[string]interface{}{"label": args[0]})
+			toolArgs := map[string]interface{}{"label": args[0]}
+			addTextMatchArgs(cmd, toolArgs)
+			result, err := daemonCall("browser_find", toolArgs)
 			if err != nil {
 				printError(err)
 				return
@@ -207,6 +226,11 @@ func newFindCmd() *cobra.Command {
 		},
 	}
 
+	for _, c := range []*cobra.Command{textCmd, roleCmd, labelCmd} {
+		c.Flags().Bool("exact", false, "Match the whole text (after trimming) instead of a substring")
+		c.Flags().Bool("ignore-case", false, "Ignore case when matching text")
+	}
+
 	cmd.AddCommand(textCmd)
 	cmd.AddCommand(roleCmd)
 	cmd.AddCommand(labelCmd)
@@ -223,3 +247,14 @@ func newFindCmd() *cobra.Command {
 func isURL(s string) bool {
 	return len(s) > 8 && (s[:7] == "http://" || s[:8] == "https://")
 }
+
+// addTextMatchArgs copies --exact and --ignore-case into browser_find args.
+// By default text matching is a case-sensitive substring match.
+func addTextMatchArgs(cmd *cobra.Command, toolArgs map[string]interface{}) {
+	if exact, _ := cmd.Flags().GetBool("exact"); exact {
+		toolArgs["exact"] = true
+	}
+	if ignoreCase, _ := cmd.Flags().GetBool("ignore-case"); ignoreCase {
+		toolArgs["caseInsensitive"] = true
+	}
+}
@@ -5,7 +5,7 @@ import (
 )
 
 func newFocusCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "focus [selector]",
 		Short: "Focus an element",
 		Example: `  vibium focus "input[name=email]"
@@ -25,4 +25,40 @@ func newFocusCmd() *cobra.Command {
 			printResult(result)
 		},
 	}
+
+	nextCmd := &cobra.Command{
+		Use:   "next",
+		Short: "Move focus to the next element (Tab)",
+		Example: `  vibium focus next
+  # Press Tab and show the newly focused element`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			result, err := daemonCall("browser_focus_next", map[string]interface{}{})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+
+	prevCmd := &cobra.Command{
+		Use:   "prev",
+		Short: "Move focus to the previous element (Shift+Tab)",
+		Example: `  vibium focus prev
+  # Press Shift+Tab and show the newly focused element`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			result, err := daemonCall("browser_focus_previous", map[string]interface{}{})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+
+	cmd.AddCommand(nextCmd)
+	cmd.AddCommand(prevCmd)
+	return cmd
 }
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 
 	"github.com/spf13/cobra"
@@ -16,18 +15,41 @@ func newGeolocationCmd() *cobra.Command {
   # Set location to New York City
 
   vibium geolocation 51.5074 -0.1278 --accuracy 10
-  # Set location to London with 10m accuracy`,
-		Args: cobra.ExactArgs(2),
+  # Set location to London with 10m accuracy
+
+  vibium geolocation 37.7749 -122.4194 --heading 90 --speed 12.5
+  # Report a moving device in San Francisco
+
+  vibium geolocation --clear
+  # Remove the override`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if clear, _ := cmd.Flags().GetBool("clear"); clear {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(2)(cmd, args)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
+			if clear, _ := cmd.Flags().GetBool("clear"); clear {
+				result, err := daemonCall("browser_set_geolocation", map[string]interface{}{
+					"clear": true,
+				})
+				if err != nil {
+					printError(err)
+					return
+				}
+				printResult(result)
+				return
+			}
+
 			lat, err := strconv.ParseFloat(args[0], 64)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: invalid latitude: %s\n", args[0])
-				os.Exit(1)
+				printError(fmt.Errorf("invalid latitude: %s", args[0]))
+				return
 			}
 			lng, err := strconv.ParseFloat(args[1], 64)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: invalid longitude: %s\n", args[1])
-				os.Exit(1)
+				printError(fmt.Errorf("invalid longitude: %s", args[1]))
+				return
 			}
 
 			accuracy, _ := cmd.Flags().GetFloat64("accuracy")
@@ -39,6 +61,12 @@ func newGeolocationCmd() *cobra.Command {
 			if accuracy > 0 {
 				callArgs["accuracy"] = accuracy
 			}
+			for _, name := range []string{"altitude", "heading", "speed"} {
+				if cmd.Flags().Changed(name) {
+					v, _ := cmd.Flags().GetFloat64(name)
+					callArgs[name] = v
+				}
+			}
 			result, err := daemonCall("browser_set_geolocation", callArgs)
 			if err != nil {
 				printError(err)
@@ -48,5 +76,9 @@ func newGeolocationCmd() *cobra.Command {
 		},
 	}
 	cmd.Flags().Float64("accuracy", 0, "Accuracy in meters (default: 1)")
+	cmd.Flags().Float64("altitude", 0, "Altitude in meters")
+	cmd.Flags().Float64("heading", 0, "Heading in degrees clockwise from north (0-360)")
+	cmd.Flags().Float64("speed", 0, "Speed in meters per second")
+	cmd.Flags().Bool("clear", false, "Remove the geolocation override")
 	return cmd
 }
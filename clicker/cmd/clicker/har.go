@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+func newHarCmd() *cobra.Command {
+	harCmd := &cobra.Command{
+		Use:   "har",
+		Short: "Capture network requests to a HAR file",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	startCmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start capturing network requests",
+		Example: `  vibium har start
+  # Capture every request from now until 'vibium har stop'`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			result, err := daemonCall("browser_har_start", map[string]interface{}{})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+
+	stopCmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop capturing and save a HAR file",
+		Example: `  vibium har stop
+  # Save captured requests to network.har
+
+  vibium har stop -o api-calls.har
+  # Save to a custom path`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			output, _ := cmd.Flags().GetString("output")
+
+			callArgs := map[string]interface{}{}
+			if output != "" {
+				path, err := filepath.Abs(output)
+				if err != nil {
+					printError(fmt.Errorf("invalid path: %v", err))
+					return
+				}
+				callArgs["path"] = path
+			}
+			result, err := daemonCall("browser_har_stop", callArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	stopCmd.Flags().StringP("output", "o", "", "Output file path (default: network.har)")
+
+	harCmd.AddCommand(startCmd)
+	harCmd.AddCommand(stopCmd)
+	return harCmd
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newHeadersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "headers [\"Name: Value\"]...",
+		Short: "Send extra HTTP headers with requests to the current origin",
+		Example: `  vibium headers "X-Feature-Flag: beta" "Authorization: Bearer abc123"
+  # Add headers to requests to the current page's origin (replaces earlier ones)
+
+  vibium headers "X-Api-Key: abc123" --origin https://api.example.com
+  # Send a header to another origin
+
+  vibium headers --clear
+  # Stop sending extra headers`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if clear, _ := cmd.Flags().GetBool("clear"); clear {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			headers := map[string]interface{}{}
+			for _, h := range args {
+				name, value, ok := strings.Cut(h, ":")
+				if !ok {
+					printError(fmt.Errorf("invalid header %q (expected \"Name: Value\")", h))
+					return
+				}
+				headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+			}
+			toolArgs := map[string]interface{}{"headers": headers}
+			if origin, _ := cmd.Flags().GetString("origin"); origin != "" {
+				toolArgs["origin"] = origin
+			}
+			result, err := daemonCall("browser_set_extra_headers", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	cmd.Flags().Bool("clear", false, "Remove all extra headers")
+	cmd.Flags().String("origin", "", "Origin or URL glob to send the headers to, \"*\" for every host (default: the current page's origin)")
+	return cmd
+}
+
+func newBasicAuthCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "basic-auth <username> [password]",
+		Short: "Send HTTP Basic credentials with requests to the current origin",
+		Example: `  vibium basic-auth admin s3cret
+  # Log in to a staging site behind Basic auth
+
+  vibium basic-auth --clear
+  # Stop sending credentials`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if clear, _ := cmd.Flags().GetBool("clear"); clear {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.RangeArgs(1, 2)(cmd, args)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			toolArgs := map[string]interface{}{"username": ""}
+			if len(args) > 0 {
+				toolArgs["username"] = args[0]
+			}
+			if len(args) > 1 {
+				toolArgs["password"] = args[1]
+			}
+			if origin, _ := cmd.Flags().GetString("origin"); origin != "" {
+				toolArgs["origin"] = origin
+			}
+			result, err := daemonCall("browser_set_basic_auth", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	cmd.Flags().Bool("clear", false, "Remove the credentials")
+	cmd.Flags().String("origin", "", "Origin or URL glob to send the credentials to, \"*\" for every host (default: the current page's origin)")
+	return cmd
+}
@@ -1,6 +1,30 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// addTimeoutArg passes an explicitly set --timeout flag to the tool as
+// milliseconds; otherwise the tool's default applies.
+func addTimeoutArg(cmd *cobra.Command, toolArgs map[string]interface{}) {
+	if !cmd.Flags().Changed("timeout") {
+		return
+	}
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	toolArgs["timeout"] = float64(timeout.Milliseconds())
+}
+
+// toInterfaceSlice converts string flag values to the []interface{} form
+// tool arguments decode to.
+func toInterfaceSlice(values []string) []interface{} {
+	items := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		items = append(items, v)
+	}
+	return items
+}
 
 // printCheck prints an actionability check result with a checkmark or X.
 func printCheck(name string, passed bool) {
@@ -5,14 +5,17 @@ import (
 )
 
 func newHoverCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "hover [selector]",
 		Short: "Hover over an element by CSS selector",
 		Example: `  vibium hover "a"
   # Hover over first link
 
   vibium hover https://example.com "a"
-  # Navigate then hover`,
+  # Navigate then hover
+
+  vibium hover "nav .products" --steps 20 --hold 500ms
+  # Glide onto a flyout trigger and linger so the menu opens`,
 		Args: cobra.RangeArgs(1, 2),
 		Run: func(cmd *cobra.Command, args []string) {
 			var selector string
@@ -27,7 +30,18 @@ func newHoverCmd() *cobra.Command {
 				selector = args[0]
 			}
 
-			result, err := daemonCall("browser_hover", map[string]interface{}{"selector": selector})
+			steps, _ := cmd.Flags().GetInt("steps")
+			hold, _ := cmd.Flags().GetDuration("hold")
+
+			toolArgs := map[string]interface{}{"selector": selector}
+			if steps > 0 {
+				toolArgs["steps"] = float64(steps)
+			}
+			if hold > 0 {
+				toolArgs["holdMs"] = float64(hold.Milliseconds())
+			}
+
+			result, err := daemonCall("browser_hover", toolArgs)
 			if err != nil {
 				printError(err)
 				return
@@ -35,4 +49,8 @@ func newHoverCmd() *cobra.Command {
 			printResult(result)
 		},
 	}
+
+	cmd.Flags().Int("steps", 0, "Glide to the element over this many frames")
+	cmd.Flags().Duration("hold", 0, "How long to keep hovering after arriving (e.g., 500ms)")
+	return cmd
 }
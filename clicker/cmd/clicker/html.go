@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 )
 
@@ -15,13 +17,21 @@ func newHTMLCmd() *cobra.Command {
   # Get innerHTML of a specific element
 
   vibium html "div.content" --outer
-  # Get outerHTML of a specific element
+  # Get outerHTML of a specific element (including its own tag)
 
   vibium html https://example.com "h1"
-  # Navigate then get element HTML`,
+  # Navigate then get element HTML
+
+  vibium html "div.card" --with-text
+  # Get outerHTML and innerText together as JSON
+
+  vibium html "div.card" --with-text --max-length 1000
+  # Truncate the HTML to 1000 characters`,
 		Args: cobra.MaximumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			outer, _ := cmd.Flags().GetBool("outer")
+			withText, _ := cmd.Flags().GetBool("with-text")
+			maxLength, _ := cmd.Flags().GetInt("max-length")
 
 			toolArgs := map[string]interface{}{}
 			if outer {
@@ -49,7 +59,25 @@ func newHTMLCmd() *cobra.Command {
 				}
 			}
 
-			result, err := daemonCall("browser_get_html", toolArgs)
+			tool := "browser_get_html"
+			if outer {
+				if _, ok := toolArgs["selector"]; ok {
+					tool = "browser_get_outer_html"
+					delete(toolArgs, "outer")
+				}
+			}
+			if withText {
+				if _, ok := toolArgs["selector"]; !ok {
+					printError(fmt.Errorf("--with-text requires a selector"))
+					return
+				}
+				tool = "browser_get_element_html_and_text"
+				if maxLength > 0 {
+					toolArgs["maxLength"] = float64(maxLength)
+				}
+			}
+
+			result, err := daemonCall(tool, toolArgs)
 			if err != nil {
 				printError(err)
 				return
@@ -58,5 +86,7 @@ func newHTMLCmd() *cobra.Command {
 		},
 	}
 	cmd.Flags().Bool("outer", false, "Return outerHTML instead of innerHTML")
+	cmd.Flags().Bool("with-text", false, "Return outerHTML and innerText together as JSON")
+	cmd.Flags().Int("max-length", 0, "Truncate HTML to this many characters with --with-text (default: 5000)")
 	return cmd
 }
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newInterceptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "intercept [pattern]",
+		Short: "Block or stub network requests matching a URL pattern",
+		Example: `  vibium intercept "*google-analytics.com*"
+  # Block analytics requests
+
+  vibium intercept "/api/user" --action fulfill --status 200 \
+    --header "Content-Type: application/json" --body '{"name":"test"}'
+  # Stub an API response
+
+  vibium intercept '\.(png|jpe?g)$' --regex
+  # Block image requests matching a regular expression`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			action, _ := cmd.Flags().GetString("action")
+			status, _ := cmd.Flags().GetInt("status")
+			headerStrs, _ := cmd.Flags().GetStringArray("header")
+			body, _ := cmd.Flags().GetString("body")
+			regex, _ := cmd.Flags().GetBool("regex")
+
+			toolArgs := map[string]interface{}{
+				"pattern": args[0],
+				"action":  action,
+			}
+			if regex {
+				toolArgs["regex"] = true
+			}
+			if cmd.Flags().Changed("status") {
+				toolArgs["status"] = float64(status)
+			}
+			if len(headerStrs) > 0 {
+				headers := map[string]interface{}{}
+				for _, h := range headerStrs {
+					name, value, ok := strings.Cut(h, ":")
+					if !ok {
+						printError(fmt.Errorf("invalid header %q (expected \"Name: Value\")", h))
+						return
+					}
+					headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+				}
+				toolArgs["headers"] = headers
+			}
+			if cmd.Flags().Changed("body") {
+				toolArgs["body"] = body
+			}
+
+			result, err := daemonCall("browser_request_intercept", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	cmd.Flags().String("action", "block", "What to do with matching requests: block (alias abort), fulfill")
+	cmd.Flags().Int("status", 200, "Response status code (fulfill)")
+	cmd.Flags().StringArray("header", nil, "Response header (fulfill, repeatable, format: \"Name: Value\")")
+	cmd.Flags().String("body", "", "Response body (fulfill)")
+	cmd.Flags().Bool("regex", false, "Treat the pattern as a regular expression")
+
+	clearCmd := &cobra.Command{
+		Use:   "clear [pattern]",
+		Short: "Remove intercept rules",
+		Example: `  vibium intercept clear "*google-analytics.com*"
+  # Remove the rule for one pattern
+
+  vibium intercept clear
+  # Remove all rules`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			toolArgs := map[string]interface{}{}
+			if len(args) == 1 {
+				toolArgs["pattern"] = args[0]
+			}
+			result, err := daemonCall("browser_request_unintercept", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+
+	cmd.AddCommand(clearCmd)
+	return cmd
+}
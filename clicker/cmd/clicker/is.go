@@ -10,7 +10,7 @@ import (
 func newIsCmd() *cobra.Command {
 	isCmd := &cobra.Command{
 		Use:   "is",
-		Short: "Check element state (visible, enabled, checked, actionable)",
+		Short: "Check element state (visible, enabled, editable, checked, actionable)",
 		Args:  cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
 			cmd.Help()
@@ -49,6 +49,22 @@ func newIsCmd() *cobra.Command {
 		},
 	}
 
+	editableCmd := &cobra.Command{
+		Use:   "editable [selector]",
+		Short: "Check if an element is editable (not disabled or read-only)",
+		Example: `  vibium is editable "input[name=email]"
+  # Prints true or false`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			result, err := daemonCall("browser_is_editable", map[string]interface{}{"selector": args[0]})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+
 	checkedCmd := &cobra.Command{
 		Use:   "checked [selector]",
 		Short: "Check if a checkbox or radio is checked",
@@ -88,8 +104,6 @@ func newIsCmd() *cobra.Command {
 				return
 			}
 
-			fmt.Printf("\nChecking actionability for selector: %s\n", selector)
-
 			// Evaluate actionability script
 			script := `(() => {
 				const selector = ` + fmt.Sprintf("%q", selector) + `;
@@ -161,6 +175,18 @@ func newIsCmd() *cobra.Command {
 				return
 			}
 
+			if jsonOutput {
+				printJSON(jsonEnvelope{OK: true, Result: map[string]interface{}{
+					"visible":        actionResult.Visible,
+					"stable":         actionResult.Stable,
+					"receivesEvents": actionResult.ReceivesEvents,
+					"enabled":        actionResult.Enabled,
+					"editable":       actionResult.Editable,
+				}})
+				return
+			}
+
+			fmt.Printf("\nChecking actionability for selector: %s\n", selector)
 			printCheck("Visible", actionResult.Visible)
 			printCheck("Stable", actionResult.Stable)
 			printCheck("ReceivesEvents", actionResult.ReceivesEvents)
@@ -171,6 +197,7 @@ func newIsCmd() *cobra.Command {
 
 	isCmd.AddCommand(visibleCmd)
 	isCmd.AddCommand(enabledCmd)
+	isCmd.AddCommand(editableCmd)
 	isCmd.AddCommand(checkedCmd)
 	isCmd.AddCommand(actionableCmd)
 	return isCmd
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newKeySequenceCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "key-sequence [steps...]",
+		Short: "Press an ordered sequence of keys, holding modifiers across steps",
+		Example: `  vibium key-sequence down:Shift ArrowDown ArrowDown ArrowDown up:Shift
+  # Extend a selection three lines down
+
+  vibium key-sequence down:Control press:a up:Control Delete
+  # Select all, then delete`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			actions := make([]interface{}, 0, len(args))
+			for _, arg := range args {
+				action, key := "press", arg
+				if i := strings.Index(arg, ":"); i > 0 && i < len(arg)-1 {
+					switch arg[:i] {
+					case "down", "up", "press":
+						action, key = arg[:i], arg[i+1:]
+					default:
+						printError(fmt.Errorf("unknown step %q (use down:KEY, up:KEY, press:KEY, or KEY)", arg))
+						return
+					}
+				}
+				actions = append(actions, map[string]interface{}{"action": action, "key": key})
+			}
+
+			result, err := daemonCall("browser_key_sequence", map[string]interface{}{"actions": actions})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+}
@@ -5,8 +5,10 @@ import (
 )
 
 func newKeysCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "keys [keys]",
+	var sequence bool
+
+	cmd := &cobra.Command{
+		Use:   "keys [keys...]",
 		Short: "Press a key or key combination",
 		Example: `  vibium keys Enter
   # Press Enter
@@ -15,12 +17,30 @@ func newKeysCmd() *cobra.Command {
   # Select all
 
   vibium keys "Shift+Tab"
-  # Shift+Tab to previous field`,
-		Args: cobra.ExactArgs(1),
+  # Shift+Tab to previous field
+
+  vibium keys --sequence g g
+  # Press g twice in quick succession (vim-style binding)
+
+  vibium keys --sequence "Control+x" "Control+s"
+  # Multi-key shortcut`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if sequence {
+				return cobra.MinimumNArgs(1)(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
-			keys := args[0]
+			toolArgs := map[string]interface{}{"keys": args[0]}
+			if sequence {
+				keys := make([]interface{}, len(args))
+				for i, k := range args {
+					keys[i] = k
+				}
+				toolArgs = map[string]interface{}{"sequence": keys}
+			}
 
-			result, err := daemonCall("browser_keys", map[string]interface{}{"keys": keys})
+			result, err := daemonCall("browser_keys", toolArgs)
 			if err != nil {
 				printError(err)
 				return
@@ -28,4 +48,6 @@ func newKeysCmd() *cobra.Command {
 			printResult(result)
 		},
 	}
+	cmd.Flags().BoolVar(&sequence, "sequence", false, "Press each argument in order, in one rapid burst")
+	return cmd
 }
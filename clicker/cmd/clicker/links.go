@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newLinksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "links [pattern]",
+		Short: "List the page's links as JSON",
+		Example: `  vibium links
+  # Prints a JSON array of {text, href, visible}
+
+  vibium links "/docs/"
+  # Only links whose URL contains /docs/
+
+  vibium links "\.pdf$" --regex
+  # Only links to PDFs`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			toolArgs := map[string]interface{}{}
+			if len(args) == 1 {
+				toolArgs["pattern"] = args[0]
+			}
+			if regex, _ := cmd.Flags().GetBool("regex"); regex {
+				toolArgs["regex"] = true
+			}
+
+			result, err := daemonCall("browser_get_links", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	cmd.Flags().Bool("regex", false, "Treat the pattern as a regular expression")
+	return cmd
+}
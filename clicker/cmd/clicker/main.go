@@ -5,8 +5,11 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/vibium/clicker/internal/api"
 	"github.com/vibium/clicker/internal/log"
 )
 
@@ -25,6 +28,23 @@ func connectFromEnv() (string, http.Header) {
 	return url, headers
 }
 
+// timeoutFromEnv reads VIBIUM_TIMEOUT, either a duration ("90s", "2m") or a
+// number of milliseconds. Returns 0 if unset.
+func timeoutFromEnv() (time.Duration, error) {
+	raw := os.Getenv("VIBIUM_TIMEOUT")
+	if raw == "" {
+		return 0, nil
+	}
+	if ms, err := strconv.Atoi(raw); err == nil && ms > 0 {
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid VIBIUM_TIMEOUT %q (use a duration like 60s or milliseconds)", raw)
+	}
+	return d, nil
+}
+
 var version = "dev"
 
 // Global flags
@@ -32,11 +52,35 @@ var (
 	headless   bool
 	verbose    bool
 	jsonOutput bool
+
+	// timeoutOverride is the default timeout from --timeout or VIBIUM_TIMEOUT,
+	// or 0 when neither is set.
+	timeoutOverride time.Duration
+
+	// frameTarget is the iframe from --frame that commands run inside.
+	frameTarget string
+
+	// retryCount and retryDelay are from --retry and --retry-delay: how many
+	// times to re-run a command whose element wasn't found or actionable, and
+	// the delay before the first retry (doubled for each one after).
+	retryCount int
+	retryDelay time.Duration
 )
 
 func main() {
 	progName := filepath.Base(os.Args[0])
 
+	// Apply VIBIUM_TIMEOUT before building commands so flag defaults show it.
+	envTimeout, err := timeoutFromEnv()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	if envTimeout > 0 {
+		timeoutOverride = envTimeout
+		api.DefaultTimeout = envTimeout
+	}
+
 	rootCmd := &cobra.Command{
 		Use:   progName,
 		Short: "Browser automation for AI agents and humans",
@@ -45,6 +89,14 @@ func main() {
 			if verbose {
 				log.Setup(log.LevelVerbose)
 			}
+			// --timeout on the root command overrides VIBIUM_TIMEOUT.
+			// Commands with their own --timeout flag shadow it.
+			if f := cmd.Flags().Lookup("timeout"); f != nil && f.Changed && f == cmd.InheritedFlags().Lookup("timeout") {
+				if d, err := time.ParseDuration(f.Value.String()); err == nil && d > 0 {
+					timeoutOverride = d
+					api.DefaultTimeout = d
+				}
+			}
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			cmd.Help()
@@ -55,6 +107,10 @@ func main() {
 	rootCmd.PersistentFlags().BoolVar(&headless, "headless", false, "Hide browser window (visible by default)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable debug logging")
 	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	rootCmd.PersistentFlags().Duration("timeout", api.DefaultTimeout, "Default timeout for waits and actionability checks (env: VIBIUM_TIMEOUT)")
+	rootCmd.PersistentFlags().StringVar(&frameTarget, "frame", "", "Run the command inside an iframe (frame name, URL substring, or @ref)")
+	rootCmd.PersistentFlags().IntVar(&retryCount, "retry", 0, "Re-run the command up to N times if its element isn't found or actionable")
+	rootCmd.PersistentFlags().DurationVar(&retryDelay, "retry-delay", 500*time.Millisecond, "Delay before the first retry; doubles for each retry after")
 
 	// Register all commands
 	rootCmd.AddCommand(newVersionCmd())
@@ -71,17 +127,23 @@ func main() {
 	rootCmd.AddCommand(newTypeCmd())
 	rootCmd.AddCommand(newServeCmd())
 	rootCmd.AddCommand(newPipeCmd())
+	rootCmd.AddCommand(newReplCmd())
+	rootCmd.AddCommand(newRunCmd())
 	rootCmd.AddCommand(newMCPCmd())
 	rootCmd.AddCommand(newDaemonCmd())
 	rootCmd.AddCommand(newTextCmd())
 	rootCmd.AddCommand(newURLCmd())
 	rootCmd.AddCommand(newTitleCmd())
 	rootCmd.AddCommand(newHTMLCmd())
+	rootCmd.AddCommand(newTableCmd())
+	rootCmd.AddCommand(newLinksCmd())
 	rootCmd.AddCommand(newWaitCmd())
 	rootCmd.AddCommand(newHoverCmd())
 	rootCmd.AddCommand(newSelectCmd())
+	rootCmd.AddCommand(newOptionsCmd())
 	rootCmd.AddCommand(newScrollCmd())
 	rootCmd.AddCommand(newKeysCmd())
+	rootCmd.AddCommand(newKeySequenceCmd())
 	rootCmd.AddCommand(newPagesCmd())
 	rootCmd.AddCommand(newBackCmd())
 	rootCmd.AddCommand(newForwardCmd())
@@ -89,19 +151,25 @@ func main() {
 	rootCmd.AddCommand(newStartCmd())
 	rootCmd.AddCommand(newStopCmd())
 	rootCmd.AddCommand(newFillCmd())
+	rootCmd.AddCommand(newClearCmd())
 	rootCmd.AddCommand(newPressCmd())
 	rootCmd.AddCommand(newCheckCmd())
 	rootCmd.AddCommand(newUncheckCmd())
 	rootCmd.AddCommand(newValueCmd())
 	rootCmd.AddCommand(newAttrCmd())
 	rootCmd.AddCommand(newA11yTreeCmd())
+	rootCmd.AddCommand(newSnapshotCmd())
 	rootCmd.AddCommand(newSleepCmd())
 	rootCmd.AddCommand(newSkillCmd())
+	rootCmd.AddCommand(newRunSkillCmd())
 	rootCmd.AddCommand(newMapCmd())
 	rootCmd.AddCommand(newDiffCmd())
 	rootCmd.AddCommand(newPDFCmd())
 	rootCmd.AddCommand(newHighlightCmd())
 	rootCmd.AddCommand(newDblClickCmd())
+	rootCmd.AddCommand(newRightClickCmd())
+	rootCmd.AddCommand(newTapCmd())
+	rootCmd.AddCommand(newPinchCmd())
 	rootCmd.AddCommand(newFocusCmd())
 	rootCmd.AddCommand(newCountCmd())
 	rootCmd.AddCommand(newDialogCmd())
@@ -111,9 +179,18 @@ func main() {
 	rootCmd.AddCommand(newWindowCmd())
 	rootCmd.AddCommand(newFramesCmd())
 	rootCmd.AddCommand(newFrameCmd())
+	rootCmd.AddCommand(newSwitchFrameCmd())
 	rootCmd.AddCommand(newUploadCmd())
 	rootCmd.AddCommand(newRecordCmd())
+	rootCmd.AddCommand(newHarCmd())
 	rootCmd.AddCommand(newDownloadCmd())
+	rootCmd.AddCommand(newInterceptCmd())
+	rootCmd.AddCommand(newBlockCmd())
+	rootCmd.AddCommand(newHeadersCmd())
+	rootCmd.AddCommand(newBasicAuthCmd())
+	rootCmd.AddCommand(newMockCmd())
+	rootCmd.AddCommand(newConsoleCmd())
+	rootCmd.AddCommand(newErrorsCmd())
 
 	// Subcommand groups
 	rootCmd.AddCommand(newIsCmd())
@@ -123,14 +200,25 @@ func main() {
 
 	// Renamed commands
 	rootCmd.AddCommand(newGeolocationCmd())
+	rootCmd.AddCommand(newOfflineCmd())
+	rootCmd.AddCommand(newThrottleCmd())
+	rootCmd.AddCommand(newThrottleCPUCmd())
+	rootCmd.AddCommand(newUserAgentCmd())
 	rootCmd.AddCommand(newContentCmd())
 	rootCmd.AddCommand(newMediaCmd())
 
 	rootCmd.Version = version
 	rootCmd.SetVersionTemplate(progName + " v{{.Version}}\n")
 
+	rootCmd.SilenceErrors = true
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		// Errors such as an unknown command are returned before flags are
+		// parsed, so look for --json directly.
+		for _, arg := range os.Args[1:] {
+			if arg == "--json" || arg == "--json=true" {
+				jsonOutput = true
+			}
+		}
+		printError(err)
 	}
 }
@@ -13,13 +13,43 @@ func newMapCmd() *cobra.Command {
   # Use refs with other commands: vibium click @e1
 
   vibium map --selector "nav"
-  # Only map elements inside the <nav> element`,
+  # Only map elements inside the <nav> element
+
+  vibium map --role button --role link --limit 20
+  # Only buttons and links, at most 20
+
+  vibium map --in-viewport
+  # Only elements currently on screen
+
+  vibium map --mark-offscreen
+  # Tag elements outside the viewport with (offscreen)
+
+  vibium map --stable-refs
+  # Elements seen in the previous stable map keep their @refs`,
 		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
 			toolArgs := map[string]interface{}{}
 			if sel, _ := cmd.Flags().GetString("selector"); sel != "" {
 				toolArgs["selector"] = sel
 			}
+			if roles, _ := cmd.Flags().GetStringSlice("role"); len(roles) > 0 {
+				toolArgs["roles"] = toInterfaceSlice(roles)
+			}
+			if tags, _ := cmd.Flags().GetStringSlice("tag"); len(tags) > 0 {
+				toolArgs["tags"] = toInterfaceSlice(tags)
+			}
+			if limit, _ := cmd.Flags().GetInt("limit"); limit > 0 {
+				toolArgs["limit"] = float64(limit)
+			}
+			if inViewport, _ := cmd.Flags().GetBool("in-viewport"); inViewport {
+				toolArgs["onlyInViewport"] = true
+			}
+			if mark, _ := cmd.Flags().GetBool("mark-offscreen"); mark {
+				toolArgs["markOffscreen"] = true
+			}
+			if stable, _ := cmd.Flags().GetBool("stable-refs"); stable {
+				toolArgs["stableRefs"] = true
+			}
 			result, err := daemonCall("browser_map", toolArgs)
 			if err != nil {
 				printError(err)
@@ -30,6 +60,12 @@ func newMapCmd() *cobra.Command {
 	}
 
 	cmd.Flags().String("selector", "", "Scope to elements within this CSS selector")
+	cmd.Flags().StringSlice("role", nil, "Only map elements with this ARIA role (repeatable or comma-separated)")
+	cmd.Flags().StringSlice("tag", nil, "Only map elements with this tag name (repeatable or comma-separated)")
+	cmd.Flags().Int("limit", 0, "Maximum number of elements to map")
+	cmd.Flags().Bool("in-viewport", false, "Only map elements currently intersecting the viewport")
+	cmd.Flags().Bool("mark-offscreen", false, "Mark elements outside the viewport as (offscreen)")
+	cmd.Flags().Bool("stable-refs", false, "Keep @refs of elements that persist from the previous stable map")
 
 	return cmd
 }
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -45,8 +44,8 @@ func newMediaCmd() *cobra.Command {
 			}
 
 			if len(callArgs) == 0 {
-				fmt.Fprintf(os.Stderr, "Error: at least one media feature flag is required\n")
-				os.Exit(1)
+				printError(fmt.Errorf("at least one media feature flag is required"))
+				return
 			}
 
 			result, err := daemonCall("browser_emulate_media", callArgs)
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+func newMockCmd() *cobra.Command {
+	mockCmd := &cobra.Command{
+		Use:   "mock",
+		Short: "Record network responses and replay them for deterministic runs",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	recordCmd := &cobra.Command{
+		Use:   "record <dir> [pattern]...",
+		Short: "Save responses for matching URLs to a directory",
+		Example: `  vibium mock record ./mocks "*/api/*"
+  # Save API responses while you drive the page, then 'vibium mock stop'
+
+  vibium mock record ./mocks
+  # Save every response`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			toolArgs := mockArgs(args)
+			result, err := daemonCall("browser_mock_record", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+
+	replayCmd := &cobra.Command{
+		Use:   "replay <dir> [pattern]...",
+		Short: "Serve saved responses instead of hitting the network",
+		Example: `  vibium mock replay ./mocks "*/api/*"
+  # Answer API calls from ./mocks; other requests go to the network
+
+  vibium mock replay ./mocks "*/api/*" --strict
+  # Fail API calls that weren't recorded`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			toolArgs := mockArgs(args)
+			if strict, _ := cmd.Flags().GetBool("strict"); strict {
+				toolArgs["strict"] = true
+			}
+			result, err := daemonCall("browser_mock_replay", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	replayCmd.Flags().Bool("strict", false, "Fail matching requests that have no saved response")
+
+	stopCmd := &cobra.Command{
+		Use:     "stop",
+		Short:   "Stop mock recording or replay",
+		Example: `  vibium mock stop`,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			result, err := daemonCall("browser_mock_stop", map[string]interface{}{})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+
+	mockCmd.AddCommand(recordCmd)
+	mockCmd.AddCommand(replayCmd)
+	mockCmd.AddCommand(stopCmd)
+	return mockCmd
+}
+
+// mockArgs builds the dir and patterns arguments for mock record/replay.
+func mockArgs(args []string) map[string]interface{} {
+	dir, err := filepath.Abs(args[0])
+	if err != nil {
+		printError(fmt.Errorf("invalid path: %v", err))
+		return nil
+	}
+	toolArgs := map[string]interface{}{"dir": dir}
+	if len(args) > 1 {
+		toolArgs["patterns"] = args[1:]
+	}
+	return toolArgs
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 
 	"github.com/spf13/cobra"
@@ -45,13 +44,13 @@ func newMouseCmd() *cobra.Command {
 			if len(args) == 2 {
 				x, err := strconv.ParseFloat(args[0], 64)
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error: invalid x coordinate: %s\n", args[0])
-					os.Exit(1)
+					printError(fmt.Errorf("invalid x coordinate: %s", args[0]))
+					return
 				}
 				y, err := strconv.ParseFloat(args[1], 64)
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error: invalid y coordinate: %s\n", args[1])
-					os.Exit(1)
+					printError(fmt.Errorf("invalid y coordinate: %s", args[1]))
+					return
 				}
 				params["x"] = x
 				params["y"] = y
@@ -76,13 +75,13 @@ func newMouseCmd() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			x, err := strconv.ParseFloat(args[0], 64)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: invalid x coordinate: %s\n", args[0])
-				os.Exit(1)
+				printError(fmt.Errorf("invalid x coordinate: %s", args[0]))
+				return
 			}
 			y, err := strconv.ParseFloat(args[1], 64)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: invalid y coordinate: %s\n", args[1])
-				os.Exit(1)
+				printError(fmt.Errorf("invalid y coordinate: %s", args[1]))
+				return
 			}
 
 			result, err := daemonCall("browser_mouse_move", map[string]interface{}{"x": x, "y": y})
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newOfflineCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "offline [on|off]",
+		Short: "Take the browser offline or back online",
+		Example: `  vibium offline
+  # Go offline (requests fail, navigator.onLine is false)
+
+  vibium offline off
+  # Go back online`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			offline := true
+			if len(args) == 1 {
+				switch args[0] {
+				case "on", "true":
+				case "off", "false":
+					offline = false
+				default:
+					printError(fmt.Errorf("expected on or off, got %q", args[0]))
+					return
+				}
+			}
+			result, err := daemonCall("browser_set_offline", map[string]interface{}{"offline": offline})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newOptionsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "options [selector]",
+		Short: "List the options of a <select> element",
+		Example: `  vibium options "select#color"
+  # Prints a JSON array of {value, label, selected, disabled}
+
+  vibium options @e4
+  # List options of a dropdown from map`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			result, err := daemonCall("browser_select_options", map[string]interface{}{"selector": args[0]})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"os"
 
+	"github.com/spf13/cobra"
 	"github.com/vibium/clicker/internal/agent"
 	"github.com/vibium/clicker/internal/process"
 )
@@ -56,6 +58,62 @@ func printError(err error) {
 	os.Exit(1)
 }
 
+// printMessage prints a status message, respecting --json mode.
+// In JSON mode: {"ok":true,"result":"..."}
+func printMessage(format string, a ...interface{}) {
+	msg := fmt.Sprintf(format, a...)
+	if jsonOutput {
+		printJSON(jsonEnvelope{OK: true, Result: msg})
+		return
+	}
+	fmt.Println(msg)
+}
+
+// writeResultData writes a tool result's base64 payload (an image, or the
+// text of a result such as browser_pdf without a filename) to stdout as raw
+// bytes, for -o - / --stdout. Any other text goes to stderr so stdout holds
+// only the file.
+func writeResultData(result *agent.ToolsCallResult) error {
+	var data string
+	for _, c := range result.Content {
+		if c.Type == "image" {
+			data = c.Data
+			break
+		}
+	}
+	if data == "" {
+		data = extractText(result)
+	} else {
+		for _, c := range result.Content {
+			if c.Type == "text" && c.Text != "" {
+				fmt.Fprintln(os.Stderr, c.Text)
+			}
+		}
+	}
+	if data == "" {
+		return fmt.Errorf("no data returned")
+	}
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return fmt.Errorf("failed to decode result: %w", err)
+	}
+	_, err = os.Stdout.Write(raw)
+	return err
+}
+
+// outputToStdout reports whether -o - or --stdout was given. In that mode
+// stdout carries only the file bytes, so --json is turned off and errors go
+// to stderr.
+func outputToStdout(cmd *cobra.Command) bool {
+	output, _ := cmd.Flags().GetString("output")
+	toStdout, _ := cmd.Flags().GetBool("stdout")
+	if output != "-" && !toStdout {
+		return false
+	}
+	jsonOutput = false
+	return true
+}
+
 // printJSON marshals and prints a value as a single JSON line.
 func printJSON(v interface{}) {
 	data, err := json.Marshal(v)
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/vibium/clicker/internal/agent"
+)
+
+// captureOutput returns what fn writes to stdout and stderr.
+func captureOutput(t *testing.T, fn func()) (string, string) {
+	t.Helper()
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	errR, errW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = outW, errW
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+
+	var out, errOut bytes.Buffer
+	done := make(chan struct{}, 2)
+	go func() { io.Copy(&out, outR); done <- struct{}{} }()
+	go func() { io.Copy(&errOut, errR); done <- struct{}{} }()
+	fn()
+	outW.Close()
+	errW.Close()
+	<-done
+	<-done
+	return out.String(), errOut.String()
+}
+
+func TestWriteResultDataKeepsStdoutClean(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\nimage"
+	result := &agent.ToolsCallResult{Content: []agent.Content{
+		{Type: "text", Text: "Screenshot captured"},
+		{Type: "image", Data: base64.StdEncoding.EncodeToString([]byte(png)), MimeType: "image/png"},
+	}}
+	var err error
+	stdout, stderr := captureOutput(t, func() { err = writeResultData(result) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stdout != png {
+		t.Errorf("stdout = %q, want only the image bytes", stdout)
+	}
+	if stderr != "Screenshot captured\n" {
+		t.Errorf("stderr = %q, want the text content", stderr)
+	}
+
+	// A PDF comes back as base64 text.
+	pdf := "%PDF-1.4"
+	result = &agent.ToolsCallResult{Content: []agent.Content{
+		{Type: "text", Text: base64.StdEncoding.EncodeToString([]byte(pdf))},
+	}}
+	stdout, _ = captureOutput(t, func() { err = writeResultData(result) })
+	if err != nil || stdout != pdf {
+		t.Errorf("stdout = %q, %v; want the PDF bytes", stdout, err)
+	}
+
+	if err := writeResultData(&agent.ToolsCallResult{}); err == nil {
+		t.Error("empty result should be an error")
+	}
+}
+
+func TestOutputToStdoutDisablesJSON(t *testing.T) {
+	defer func(v bool) { jsonOutput = v }(jsonOutput)
+
+	for _, args := range [][]string{{"-o", "-"}, {"--stdout"}} {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringP("output", "o", "screenshot.png", "")
+		cmd.Flags().Bool("stdout", false, "")
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		jsonOutput = true
+		if !outputToStdout(cmd) {
+			t.Errorf("outputToStdout(%v) = false", args)
+		}
+		if jsonOutput {
+			t.Errorf("outputToStdout(%v) left --json on", args)
+		}
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringP("output", "o", "screenshot.png", "")
+	cmd.Flags().Bool("stdout", false, "")
+	jsonOutput = true
+	if outputToStdout(cmd) || !jsonOutput {
+		t.Error("default output should write a file and keep --json")
+	}
+}
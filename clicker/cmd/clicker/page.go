@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 
 	"github.com/spf13/cobra"
@@ -25,13 +24,19 @@ func newPageCmd() *cobra.Command {
   # Open a blank new page
 
   vibium page new https://example.com
-  # Open a new page and navigate to URL`,
+  # → New page [1] opened and navigated to https://example.com (context ...)
+
+  vibium page new https://example.com --background
+  # Open it without switching away from the current page`,
 		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			toolArgs := map[string]interface{}{}
 			if len(args) == 1 {
 				toolArgs["url"] = args[0]
 			}
+			if background, _ := cmd.Flags().GetBool("background"); background {
+				toolArgs["switch"] = false
+			}
 
 			result, err := daemonCall("browser_new_page", toolArgs)
 			if err != nil {
@@ -56,8 +61,8 @@ func newPageCmd() *cobra.Command {
 			if len(args) == 1 {
 				idx, err := strconv.Atoi(args[0])
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error: invalid page index: %s\n", args[0])
-					os.Exit(1)
+					printError(fmt.Errorf("invalid page index: %s", args[0]))
+					return
 				}
 				toolArgs["index"] = float64(idx)
 			}
@@ -78,13 +83,21 @@ func newPageCmd() *cobra.Command {
   # Switch to page at index 1
 
   vibium page switch google.com
-  # Switch to page containing "google.com" in URL`,
-		Args: cobra.ExactArgs(1),
+  # Switch to page containing "google.com" in URL
+
+  vibium page switch --latest
+  # Switch to the page that opened most recently (popup, target=_blank)`,
+		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			toolArgs := map[string]interface{}{}
 
-			// Try to parse as integer index
-			if idx, err := strconv.Atoi(args[0]); err == nil {
+			latest, _ := cmd.Flags().GetBool("latest")
+			if latest {
+				toolArgs["latest"] = true
+			} else if len(args) == 0 {
+				printError(fmt.Errorf("requires a page index or URL, or --latest"))
+				return
+			} else if idx, err := strconv.Atoi(args[0]); err == nil {
 				toolArgs["index"] = float64(idx)
 			} else {
 				toolArgs["url"] = args[0]
@@ -99,6 +112,10 @@ func newPageCmd() *cobra.Command {
 		},
 	}
 
+	newCmd.Flags().Bool("background", false, "Keep the current page active")
+
+	switchCmd.Flags().Bool("latest", false, "Switch to the most recently opened page")
+
 	pageCmd.AddCommand(newCmd)
 	pageCmd.AddCommand(closeCmd)
 	pageCmd.AddCommand(switchCmd)
@@ -12,10 +12,20 @@ func newPDFCmd() *cobra.Command {
   # Save current page as PDF
 
   vibium pdf https://example.com -o page.pdf
-  # Navigate to URL first, then save as PDF`,
+  # Navigate to URL first, then save as PDF
+
+  vibium pdf -o invoice.pdf --format A4 --landscape --print-background
+  # A4 landscape with background colors
+
+  vibium pdf -o report.pdf --margin 1in --scale 0.8
+  # 1 inch margins on every side, scaled to 80%
+
+  vibium pdf -o - | lpr
+  # Write the PDF to stdout (same as --stdout)`,
 		Args: cobra.RangeArgs(0, 1),
 		Run: func(cmd *cobra.Command, args []string) {
 			output, _ := cmd.Flags().GetString("output")
+			toStdout := outputToStdout(cmd)
 
 			// Navigate first if URL provided
 			if len(args) == 1 {
@@ -26,14 +36,49 @@ func newPDFCmd() *cobra.Command {
 				}
 			}
 
-			result, err := daemonCall("browser_pdf", map[string]interface{}{"filename": output})
+			pdfArgs := map[string]interface{}{"filename": output}
+			if toStdout {
+				delete(pdfArgs, "filename")
+			}
+			if format, _ := cmd.Flags().GetString("format"); format != "" {
+				pdfArgs["format"] = format
+			}
+			if landscape, _ := cmd.Flags().GetBool("landscape"); landscape {
+				pdfArgs["landscape"] = true
+			}
+			if background, _ := cmd.Flags().GetBool("print-background"); background {
+				pdfArgs["printBackground"] = true
+			}
+			if cmd.Flags().Changed("scale") {
+				scale, _ := cmd.Flags().GetFloat64("scale")
+				pdfArgs["scale"] = scale
+			}
+			if margin, _ := cmd.Flags().GetString("margin"); margin != "" {
+				pdfArgs["margin"] = map[string]interface{}{
+					"top": margin, "right": margin, "bottom": margin, "left": margin,
+				}
+			}
+
+			result, err := daemonCall("browser_pdf", pdfArgs)
 			if err != nil {
 				printError(err)
 				return
 			}
+			if toStdout {
+				if err := writeResultData(result); err != nil {
+					printError(err)
+				}
+				return
+			}
 			printResult(result)
 		},
 	}
-	cmd.Flags().StringP("output", "o", "page.pdf", "Output file path")
+	cmd.Flags().StringP("output", "o", "page.pdf", "Output file path (- for stdout)")
+	cmd.Flags().Bool("stdout", false, "Write the PDF to stdout instead of a file")
+	cmd.Flags().String("format", "", "Paper format: Letter, Legal, Tabloid, Ledger, A0-A6")
+	cmd.Flags().Bool("landscape", false, "Use landscape orientation")
+	cmd.Flags().Bool("print-background", false, "Print background colors and images")
+	cmd.Flags().Float64("scale", 1, "Scale of the page rendering (0.1-2)")
+	cmd.Flags().String("margin", "", "Margin for every side, e.g. 1in, 10mm, 2cm")
 	return cmd
 }
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newPinchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pinch [selector]",
+		Short: "Pinch-zoom an element with two fingers",
+		Example: `  vibium pinch "#map" --scale 2
+  # Zoom in on a map
+
+  vibium pinch "img.photo" --scale 0.5 --steps 20
+  # Zoom out slowly`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			scale, _ := cmd.Flags().GetFloat64("scale")
+			steps, _ := cmd.Flags().GetInt("steps")
+
+			result, err := daemonCall("browser_pinch", map[string]interface{}{
+				"selector": args[0],
+				"scale":    scale,
+				"steps":    float64(steps),
+			})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	cmd.Flags().Float64("scale", 2, "Ratio of final to initial finger distance (>1 zooms in, <1 zooms out)")
+	cmd.Flags().Int("steps", 10, "Number of synchronized finger moves")
+	return cmd
+}
@@ -33,7 +33,10 @@ func newRecordCmd() *cobra.Command {
   # Lower JPEG quality for smaller recording files
 
   vibium record start --title "Login Flow"
-  # Set a title shown in the trace viewer`,
+  # Set a title shown in the trace viewer
+
+  vibium record start --interval 200ms
+  # Also capture a screenshot every 200ms (catches animations between actions)`,
 		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
 			screenshots, _ := cmd.Flags().GetBool("screenshots")
@@ -44,6 +47,7 @@ func newRecordCmd() *cobra.Command {
 			sources, _ := cmd.Flags().GetBool("sources")
 			format, _ := cmd.Flags().GetString("format")
 			quality, _ := cmd.Flags().GetFloat64("quality")
+			interval, _ := cmd.Flags().GetDuration("interval")
 
 			callArgs := map[string]interface{}{}
 			if name != "" {
@@ -68,6 +72,9 @@ func newRecordCmd() *cobra.Command {
 			if quality != 0.5 {
 				callArgs["quality"] = quality
 			}
+			if interval > 0 {
+				callArgs["intervalMs"] = interval.Milliseconds()
+			}
 			result, err := daemonCall("browser_record_start", callArgs)
 			if err != nil {
 				printError(err)
@@ -83,6 +90,7 @@ func newRecordCmd() *cobra.Command {
 	startCmd.Flags().String("name", "", "Name for the recording")
 	startCmd.Flags().String("title", "", "Title shown in trace viewer (defaults to name)")
 	startCmd.Flags().String("format", "jpeg", "Screenshot format: jpeg or png")
+	startCmd.Flags().Duration("interval", 0, "Also capture a screenshot at this interval, minimum 100ms (e.g. 250ms)")
 	startCmd.Flags().Float64("quality", 0.5, "JPEG quality 0.0-1.0 (ignored for png)")
 
 	stopCmd := &cobra.Command{
@@ -203,15 +211,22 @@ func newRecordCmd() *cobra.Command {
 		Example: `  vibium record chunk stop
   # Save chunk to chunk.zip
 
-  vibium record chunk stop -o part1.zip`,
+  vibium record chunk stop -o part1.zip
+
+  vibium record chunk stop -o checkpoint-3.zip --next
+  # Checkpoint and keep recording into a fresh chunk`,
 		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
 			output, _ := cmd.Flags().GetString("output")
+			next, _ := cmd.Flags().GetBool("next")
 
 			callArgs := map[string]interface{}{}
 			if output != "" {
 				callArgs["path"] = output
 			}
+			if next {
+				callArgs["next"] = true
+			}
 			result, err := daemonCall("browser_record_stop_chunk", callArgs)
 			if err != nil {
 				printError(err)
@@ -221,6 +236,7 @@ func newRecordCmd() *cobra.Command {
 		},
 	}
 	chunkStopCmd.Flags().StringP("output", "o", "", "Output file path (default: chunk.zip)")
+	chunkStopCmd.Flags().Bool("next", false, "Start the next chunk immediately")
 
 	chunkCmd.AddCommand(chunkStartCmd)
 	chunkCmd.AddCommand(chunkStopCmd)
@@ -229,5 +245,6 @@ func newRecordCmd() *cobra.Command {
 	recordCmd.AddCommand(stopCmd)
 	recordCmd.AddCommand(groupCmd)
 	recordCmd.AddCommand(chunkCmd)
+	recordCmd.AddCommand(newRecordOpenCmd())
 	return recordCmd
 }
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+func newRecordOpenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "open <file.zip>",
+		Short: "View a recording in a local timeline viewer",
+		Example: `  vibium record open record.zip
+  # Serve the recording on a local port and open it in your browser
+
+  vibium record open record.zip --port 9400 --no-open
+  # Serve on a fixed port without opening a browser`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			port, _ := cmd.Flags().GetInt("port")
+			noOpen, _ := cmd.Flags().GetBool("no-open")
+
+			zr, err := zip.OpenReader(args[0])
+			if err != nil {
+				printError(fmt.Errorf("failed to open recording: %w", err))
+			}
+			defer zr.Close()
+
+			view, err := loadRecordingView(&zr.Reader)
+			if err != nil {
+				printError(err)
+			}
+
+			ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+			if err != nil {
+				printError(fmt.Errorf("failed to listen: %w", err))
+			}
+			url := fmt.Sprintf("http://%s/", ln.Addr().String())
+
+			server := &http.Server{Handler: recordingViewerHandler(&zr.Reader, view)}
+			go server.Serve(ln)
+
+			if jsonOutput {
+				printJSON(jsonEnvelope{OK: true, Result: map[string]interface{}{
+					"file": args[0],
+					"url":  url,
+				}})
+			} else {
+				fmt.Printf("Serving %s at %s\n", args[0], url)
+				fmt.Println("Press Ctrl+C to stop...")
+			}
+			if !noOpen {
+				if err := openURL(url); err != nil {
+					fmt.Fprintf(os.Stderr, "Could not open a browser (%v); open the URL manually.\n", err)
+				}
+			}
+
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+			<-sigCh
+			server.Close()
+		},
+	}
+	cmd.Flags().Int("port", 0, "Port to serve on (default: a free port)")
+	cmd.Flags().Bool("no-open", false, "Print the URL without opening a browser")
+	return cmd
+}
+
+// recordingAction is one action (or group) from the trace, with its
+// before/after events merged.
+type recordingAction struct {
+	CallID   string                 `json:"callId"`
+	ParentID string                 `json:"parentId,omitempty"`
+	Title    string                 `json:"title"`
+	Method   string                 `json:"method"`
+	Params   map[string]interface{} `json:"params,omitempty"`
+	Start    float64                `json:"start"`
+	End      float64                `json:"end"`
+	PageID   string                 `json:"pageId,omitempty"`
+	Point    map[string]interface{} `json:"point,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+}
+
+// recordingFrame is a screencast-frame event.
+type recordingFrame struct {
+	SHA1      string  `json:"sha1"`
+	PageID    string  `json:"pageId"`
+	Width     int     `json:"width"`
+	Height    int     `json:"height"`
+	Timestamp float64 `json:"timestamp"`
+}
+
+// recordingRequest is a network entry from the trace's resource snapshots.
+type recordingRequest struct {
+	Method string  `json:"method"`
+	URL    string  `json:"url"`
+	Status int     `json:"status"`
+	Start  float64 `json:"start"`
+	Time   float64 `json:"time"`
+}
+
+// recordingView is what the viewer page renders.
+type recordingView struct {
+	Title    string             `json:"title"`
+	Start    float64            `json:"start"`
+	Actions  []recordingAction  `json:"actions"`
+	Frames   []recordingFrame   `json:"frames"`
+	Requests []recordingRequest `json:"requests"`
+}
+
+// loadRecordingView reads every chunk's trace and network events from a
+// recording zip.
+func loadRecordingView(zr *zip.Reader) (*recordingView, error) {
+	view := &recordingView{
+		Actions:  []recordingAction{},
+		Frames:   []recordingFrame{},
+		Requests: []recordingRequest{},
+	}
+	byID := map[string]int{}
+	found := false
+
+	for _, f := range zr.File {
+		switch {
+		case strings.HasSuffix(f.Name, "-trace.trace"):
+			found = true
+			if err := readTraceLines(f, func(ev map[string]interface{}) {
+				addTraceEvent(view, byID, ev)
+			}); err != nil {
+				return nil, err
+			}
+		case strings.HasSuffix(f.Name, "-trace.network"):
+			if err := readTraceLines(f, func(ev map[string]interface{}) {
+				addNetworkEvent(view, ev)
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("no trace found in recording (expected <n>-trace.trace)")
+	}
+
+	sort.SliceStable(view.Actions, func(i, j int) bool { return view.Actions[i].Start < view.Actions[j].Start })
+	sort.SliceStable(view.Frames, func(i, j int) bool { return view.Frames[i].Timestamp < view.Frames[j].Timestamp })
+	sort.SliceStable(view.Requests, func(i, j int) bool { return view.Requests[i].Start < view.Requests[j].Start })
+	return view, nil
+}
+
+// readTraceLines calls fn for each newline-delimited JSON event in f.
+func readTraceLines(f *zip.File, fn func(map[string]interface{})) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	scanner := bufio.NewScanner(rc)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var ev map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			continue
+		}
+		fn(ev)
+	}
+	return scanner.Err()
+}
+
+func addTraceEvent(view *recordingView, byID map[string]int, ev map[string]interface{}) {
+	str := func(key string) string { s, _ := ev[key].(string); return s }
+	num := func(key string) float64 { n, _ := ev[key].(float64); return n }
+
+	switch str("type") {
+	case "context-options":
+		if view.Title == "" {
+			view.Title = str("title")
+		}
+		if view.Start == 0 || num("wallTime") < view.Start {
+			view.Start = num("wallTime")
+		}
+	case "before":
+		params, _ := ev["params"].(map[string]interface{})
+		byID[str("callId")] = len(view.Actions)
+		view.Actions = append(view.Actions, recordingAction{
+			CallID:   str("callId"),
+			ParentID: str("parentId"),
+			Title:    str("title"),
+			Method:   str("method"),
+			Params:   params,
+			Start:    num("startTime"),
+			End:      num("startTime"),
+			PageID:   str("pageId"),
+		})
+	case "input":
+		if i, ok := byID[str("callId")]; ok {
+			view.Actions[i].Point, _ = ev["point"].(map[string]interface{})
+		}
+	case "after":
+		if i, ok := byID[str("callId")]; ok {
+			view.Actions[i].End = num("endTime")
+			if e, ok := ev["error"].(map[string]interface{}); ok {
+				view.Actions[i].Error, _ = e["message"].(string)
+			}
+		}
+	case "screencast-frame":
+		view.Frames = append(view.Frames, recordingFrame{
+			SHA1:      str("sha1"),
+			PageID:    str("pageId"),
+			Width:     int(num("width")),
+			Height:    int(num("height")),
+			Timestamp: num("timestamp"),
+		})
+	}
+}
+
+func addNetworkEvent(view *recordingView, ev map[string]interface{}) {
+	if t, _ := ev["type"].(string); t != "resource-snapshot" {
+		return
+	}
+	entry, _ := ev["snapshot"].(map[string]interface{})
+	req, _ := entry["request"].(map[string]interface{})
+	resp, _ := entry["response"].(map[string]interface{})
+	r := recordingRequest{}
+	r.Method, _ = req["method"].(string)
+	r.URL, _ = req["url"].(string)
+	if status, ok := resp["status"].(float64); ok {
+		r.Status = int(status)
+	}
+	r.Time, _ = entry["time"].(float64)
+	if mono, ok := entry["_monotonicTime"].(float64); ok {
+		r.Start = mono * 1000
+	}
+	view.Requests = append(view.Requests, r)
+}
+
+// recordingViewerHandler serves the viewer page, the parsed recording, and
+// the zip's resources (screenshots).
+func recordingViewerHandler(zr *zip.Reader, view *recordingView) http.Handler {
+	resources := map[string]*zip.File{}
+	for _, f := range zr.File {
+		if strings.HasPrefix(f.Name, "resources/") {
+			resources[path.Base(f.Name)] = f
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, recordingViewerHTML)
+	})
+	mux.HandleFunc("/recording.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(view)
+	})
+	mux.HandleFunc("/resources/", func(w http.ResponseWriter, r *http.Request) {
+		f, ok := resources[path.Base(r.URL.Path)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		rc, err := f.Open()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", http.DetectContentType(data))
+		w.Header().Set("Cache-Control", "max-age=3600")
+		w.Write(data)
+	})
+	return mux
+}
+
+// openURL opens url in the user's default browser.
+func openURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+// recordingViewerHTML is a self-contained viewer: an action list (groups
+// nested), the screenshot nearest the selected action, a filmstrip of all
+// frames, and the network requests made during the action.
+//
+//go:embed record_viewer.html
+var recordingViewerHTML string
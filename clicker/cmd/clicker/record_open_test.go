@@ -0,0 +1,91 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// testRecording builds a one-action recording zip with a single frame.
+func testRecording(t *testing.T) *zip.Reader {
+	files := map[string]string{
+		"0-trace.trace": strings.Join([]string{
+			`{"type":"context-options","title":"demo","wallTime":1000}`,
+			`{"type":"before","callId":"call@1","title":"Page.navigate","method":"navigate","startTime":10}`,
+			`{"type":"after","callId":"call@1","endTime":20}`,
+			`{"type":"screencast-frame","sha1":"frame.jpeg","pageId":"page@1","width":800,"height":600,"timestamp":15}`,
+		}, "\n"),
+		"0-trace.network":      `{"type":"resource-snapshot","snapshot":{"request":{"method":"GET","url":"https://example.com/"},"response":{"status":200},"time":5,"_monotonicTime":0.012}}`,
+		"resources/frame.jpeg": "\xff\xd8\xff\xe0frame",
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, body)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return zr
+}
+
+func TestRecordingViewerServesPageAndRecording(t *testing.T) {
+	zr := testRecording(t)
+	view, err := loadRecordingView(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(recordingViewerHandler(zr, view))
+	defer srv.Close()
+
+	get := func(path string) (*http.Response, string) {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	resp, page := get("/")
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		t.Errorf("page Content-Type = %q", resp.Header.Get("Content-Type"))
+	}
+	if !strings.HasPrefix(page, "<!DOCTYPE html>") || !strings.Contains(page, "fetch('recording.json')") {
+		t.Error("viewer page is missing or does not load recording.json")
+	}
+
+	_, body := get("/recording.json")
+	var got recordingView
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("recording.json: %v", err)
+	}
+	if got.Title != "demo" || len(got.Actions) != 1 || len(got.Frames) != 1 || len(got.Requests) != 1 {
+		t.Errorf("recording.json = %+v", got)
+	}
+	if a := got.Actions[0]; a.Start != 10 || a.End != 20 {
+		t.Errorf("action span = %v..%v, want 10..20", a.Start, a.End)
+	}
+
+	resp, frame := get("/resources/frame.jpeg")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "image/jpeg" || !strings.HasSuffix(frame, "frame") {
+		t.Errorf("frame: status %d, Content-Type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if resp, _ := get("/resources/missing.jpeg"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("missing resource status = %d, want 404", resp.StatusCode)
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/vibium/clicker/internal/agent"
+	"github.com/vibium/clicker/internal/process"
+)
+
+func newReplCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "repl",
+		Short: "Run commands interactively against one browser session",
+		Long: `Start an interactive session that reads one command per line and runs it
+against the same browser, printing each result.
+
+A command is an MCP tool name without the browser_ prefix (navigate, click,
+type, get_text, ...); get_ may also be dropped (text, url, title, html).
+Positional arguments fill the tool's required parameters in order, or the
+selector when nothing is required. Other parameters are given as name=value.
+
+Dot commands:
+  .screenshot [file]   Save a screenshot (default screenshot.png)
+  .tools               List available commands
+  .help                Show this help
+  .quit                Close the browser and exit`,
+		Example: `  vibium repl
+  vibium> navigate https://example.com
+  vibium> text h1
+  vibium> click "a[href]" timeout=5000
+  vibium> .screenshot home.png
+  vibium> .quit`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			process.WithCleanup(func() {
+				runRepl(cmd)
+			})
+		},
+	}
+}
+
+func runRepl(cmd *cobra.Command) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	connectURL, connectHeaders := connectFromEnv()
+	h := agent.NewHandlers(cwd, headless, connectURL, connectHeaders)
+	defer h.Close()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		h.Close()
+		os.Exit(0)
+	}()
+
+	tools := make(map[string]agent.Tool)
+	for _, t := range agent.GetToolSchemas() {
+		tools[t.Name] = t
+	}
+
+	interactive := false
+	if stat, _ := os.Stdin.Stat(); (stat.Mode() & os.ModeCharDevice) != 0 {
+		interactive = true
+		fmt.Fprintf(os.Stderr, "Vibium REPL v%s. Type .help for help, .quit to exit.\n", version)
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for {
+		if interactive {
+			fmt.Fprint(os.Stderr, "vibium> ")
+		}
+		if !scanner.Scan() {
+			return
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		words, err := splitReplLine(line)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			continue
+		}
+
+		switch words[0] {
+		case ".quit", ".exit":
+			return
+		case ".help":
+			fmt.Println(cmd.Long)
+			continue
+		case ".tools":
+			names := make([]string, 0, len(tools))
+			for name := range tools {
+				names = append(names, strings.TrimPrefix(name, "browser_"))
+			}
+			sort.Strings(names)
+			fmt.Println(strings.Join(names, "\n"))
+			continue
+		case ".screenshot":
+			filename := "screenshot.png"
+			if len(words) > 1 {
+				filename = words[1]
+			}
+			if err := saveReplScreenshot(h, filename); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				continue
+			}
+			fmt.Printf("Screenshot saved to %s\n", filename)
+			continue
+		}
+
+		tool, ok := resolveReplTool(tools, words[0])
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: unknown command %q (type .tools for a list)\n", words[0])
+			continue
+		}
+		toolArgs, err := replToolArgs(tool, words[1:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			continue
+		}
+
+		result, err := h.Call(tool.Name, toolArgs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			continue
+		}
+		printReplResult(result)
+	}
+}
+
+// resolveReplTool finds the tool a REPL command names: browser_<cmd>,
+// browser_get_<cmd>, or the full tool name. Dashes count as underscores.
+func resolveReplTool(tools map[string]agent.Tool, name string) (agent.Tool, bool) {
+	name = strings.ReplaceAll(name, "-", "_")
+	for _, candidate := range []string{"browser_" + name, "browser_get_" + name, name} {
+		if t, ok := tools[candidate]; ok {
+			return t, true
+		}
+	}
+	return agent.Tool{}, false
+}
+
+// replToolArgs builds tool arguments from REPL words. Positional words fill
+// the tool's required parameters in order (or selector when none are
+// required); name=value words set any parameter. Values for non-string
+// parameters are parsed as JSON.
+func replToolArgs(tool agent.Tool, words []string) (map[string]interface{}, error) {
+	props, _ := tool.InputSchema["properties"].(map[string]interface{})
+	positional, _ := tool.InputSchema["required"].([]string)
+	if len(positional) == 0 {
+		if _, ok := props["selector"]; ok {
+			positional = []string{"selector"}
+		}
+	}
+
+	args := map[string]interface{}{}
+	next := 0
+	for _, word := range words {
+		name, value := "", word
+		if i := strings.Index(word, "="); i > 0 {
+			if _, ok := props[word[:i]]; ok {
+				name, value = word[:i], word[i+1:]
+			}
+		}
+		if name == "" {
+			if next >= len(positional) {
+				return nil, fmt.Errorf("unexpected argument %q (use name=value)", word)
+			}
+			name = positional[next]
+			next++
+		}
+
+		prop, _ := props[name].(map[string]interface{})
+		if t, _ := prop["type"].(string); t == "string" || t == "" {
+			args[name] = value
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal([]byte(value), &v); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %q", name, value)
+		}
+		args[name] = v
+	}
+	return args, nil
+}
+
+// saveReplScreenshot captures the viewport and writes the PNG to path.
+func saveReplScreenshot(h *agent.Handlers, path string) error {
+	result, err := h.Call("browser_screenshot", map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+	for _, c := range result.Content {
+		if c.Type != "image" {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(c.Data)
+		if err != nil {
+			return fmt.Errorf("failed to decode screenshot: %w", err)
+		}
+		return os.WriteFile(path, data, 0644)
+	}
+	return fmt.Errorf("no screenshot returned")
+}
+
+// printReplResult prints a tool result's text. Images are noted rather than
+// dumped to the terminal.
+func printReplResult(result *agent.ToolsCallResult) {
+	if result == nil {
+		return
+	}
+	for _, c := range result.Content {
+		switch {
+		case c.Type == "text" && c.Text != "":
+			fmt.Println(c.Text)
+		case c.Type == "image":
+			fmt.Println("(image; use .screenshot [file] to save)")
+		}
+	}
+}
+
+// splitReplLine splits a line into words, honoring single and double quotes
+// and backslash escapes.
+func splitReplLine(line string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
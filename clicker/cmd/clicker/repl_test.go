@@ -0,0 +1,116 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vibium/clicker/internal/agent"
+)
+
+func TestSplitReplLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []string
+	}{
+		{"words", "click  #submit\tnow", []string{"click", "#submit", "now"}},
+		{"empty", "   ", nil},
+		{"double quotes", `type "#q" "hello world"`, []string{"type", "#q", "hello world"}},
+		{"single quotes", `type '#q' 'say "hi"'`, []string{"type", "#q", `say "hi"`}},
+		{"quotes inside a word", `find a[title="Sign in"]`, []string{"find", "a[title=Sign in]"}},
+		{"empty quoted word", `type #q ""`, []string{"type", "#q", ""}},
+		{"escaped space", `type #q hello\ world`, []string{"type", "#q", "hello world"}},
+		{"escaped quote in double quotes", `type #q "say \"hi\""`, []string{"type", "#q", `say "hi"`}},
+		{"backslash kept in single quotes", `eval 'a\nb'`, []string{"eval", `a\nb`}},
+		{"name=value", `type selector=#q text="a b"`, []string{"type", "selector=#q", "text=a b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitReplLine(tt.line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitReplLine(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := splitReplLine(`type "#q`); err == nil {
+		t.Error("unterminated quote should be an error")
+	}
+}
+
+func TestReplToolArgs(t *testing.T) {
+	typeTool := agent.Tool{
+		Name: "browser_type",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"selector": map[string]interface{}{"type": "string"},
+				"text":     map[string]interface{}{"type": "string"},
+				"timeout":  map[string]interface{}{"type": "number"},
+				"force":    map[string]interface{}{"type": "boolean"},
+			},
+			"required": []string{"selector", "text"},
+		},
+	}
+	hoverTool := agent.Tool{
+		Name: "browser_hover",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"selector": map[string]interface{}{"type": "string"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		tool  agent.Tool
+		words []string
+		want  map[string]interface{}
+	}{
+		{"positional", typeTool, []string{"#q", "hello"},
+			map[string]interface{}{"selector": "#q", "text": "hello"}},
+		{"named", typeTool, []string{"text=hello", "selector=#q"},
+			map[string]interface{}{"selector": "#q", "text": "hello"}},
+		{"typed values", typeTool, []string{"#q", "hi", "timeout=500", "force=true"},
+			map[string]interface{}{"selector": "#q", "text": "hi", "timeout": float64(500), "force": true}},
+		{"selector containing =", typeTool, []string{"input[name=q]", "a=b"},
+			map[string]interface{}{"selector": "input[name=q]", "text": "a=b"}},
+		{"value containing =", typeTool, []string{"#q", "text=x=1"},
+			map[string]interface{}{"selector": "#q", "text": "x=1"}},
+		{"selector is positional when not required", hoverTool, []string{"#menu"},
+			map[string]interface{}{"selector": "#menu"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := replToolArgs(tt.tool, tt.words)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("replToolArgs(%q) = %v, want %v", tt.words, got, tt.want)
+			}
+		})
+	}
+
+	errTests := []struct {
+		name    string
+		words   []string
+		wantErr string
+	}{
+		{"extra positional", []string{"#q", "hi", "more"}, `unexpected argument "more"`},
+		{"bad number", []string{"#q", "hi", "timeout=soon"}, `invalid value for timeout: "soon"`},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := replToolArgs(typeTool, tt.words)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
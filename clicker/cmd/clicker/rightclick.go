@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newRightClickCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "right-click [selector]",
+		Short: "Right-click an element to open its context menu",
+		Example: `  vibium right-click ".file-row"
+  # Open a custom context menu
+
+  vibium right-click @e4
+  # Right-click element from map`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			selector := args[0]
+
+			result, err := daemonCall("browser_right_click", map[string]interface{}{"selector": selector})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/vibium/clicker/internal/agent"
+	"github.com/vibium/clicker/internal/process"
+)
+
+// scriptStep is one step of a run script: an MCP tool (named as in the
+// REPL) and its arguments.
+type scriptStep struct {
+	Action string                 `json:"action"`
+	Args   map[string]interface{} `json:"args"`
+
+	// words holds the arguments of a REPL-style line, resolved against the
+	// tool's schema when the step runs.
+	words []string
+}
+
+// stepResult is the --json output for one step.
+type stepResult struct {
+	Step   int    `json:"step"`
+	Action string `json:"action"`
+	OK     bool   `json:"ok"`
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func newRunCmd() *cobra.Command {
+	var keepGoing bool
+
+	cmd := &cobra.Command{
+		Use:   "run <script>",
+		Short: "Run a script of steps against one browser session",
+		Long: `Run steps from a file in order against a single browser session,
+printing each step's result. Stops at the first failing step unless
+--keep-going is set; exits non-zero if any step failed.
+
+A .json script is an array of {"action": ..., "args": {...}} objects.
+Any other file has one step per line, written as in vibium repl
+(e.g. click "#submit" timeout=5000) or as a JSON step object.
+Blank lines and lines starting with # are skipped.
+
+Actions are MCP tool names; the browser_ and get_ prefixes are optional.`,
+		Example: `  vibium run login.txt
+  # login.txt:
+  #   navigate https://example.com/login
+  #   fill "#user" alice
+  #   click "button[type=submit]"
+  #   wait_for_url /dashboard
+
+  vibium run steps.json --keep-going
+  # [{"action": "navigate", "args": {"url": "https://example.com"}}, ...]`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			steps, err := loadScript(args[0])
+			if err != nil {
+				printError(err)
+				return
+			}
+			var failed bool
+			process.WithCleanup(func() {
+				failed = runScript(steps, keepGoing)
+			})
+			if failed {
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&keepGoing, "keep-going", false, "Run the remaining steps after a step fails")
+	return cmd
+}
+
+// loadScript reads the steps from a run script.
+func loadScript(path string) ([]scriptStep, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var steps []scriptStep
+		if err := json.Unmarshal(data, &steps); err != nil {
+			return nil, fmt.Errorf("invalid script %s: %w", path, err)
+		}
+		for i := range steps {
+			if steps[i].Action == "" {
+				return nil, fmt.Errorf("invalid script %s: step %d has no action", path, i+1)
+			}
+		}
+		return steps, nil
+	}
+
+	var steps []scriptStep
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var step scriptStep
+		if strings.HasPrefix(line, "{") {
+			if err := json.Unmarshal([]byte(line), &step); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, i+1, err)
+			}
+		} else {
+			words, err := splitReplLine(line)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, i+1, err)
+			}
+			step.Action, step.words = words[0], words[1:]
+		}
+		if step.Action == "" {
+			return nil, fmt.Errorf("%s:%d: step has no action", path, i+1)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// runScript runs the steps through an in-process Handlers and prints each
+// result. It reports whether any step failed.
+func runScript(steps []scriptStep, keepGoing bool) bool {
+	connectURL, connectHeaders := connectFromEnv()
+	cwd, _ := os.Getwd()
+	h := agent.NewHandlers(cwd, headless, connectURL, connectHeaders)
+	defer h.Close()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		h.Close()
+		os.Exit(1)
+	}()
+
+	tools := make(map[string]agent.Tool)
+	for _, t := range agent.GetToolSchemas() {
+		tools[t.Name] = t
+	}
+
+	var results []stepResult
+	failed := false
+	for i, step := range steps {
+		res := stepResult{Step: i + 1, Action: step.Action}
+		text, err := runStep(h, tools, step)
+		if err != nil {
+			res.Error = err.Error()
+			failed = true
+		} else {
+			res.OK = true
+			res.Result = text
+		}
+		results = append(results, res)
+
+		if !jsonOutput {
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "[%d] %s: Error: %v\n", res.Step, res.Action, err)
+			} else if text != "" {
+				fmt.Printf("[%d] %s: %s\n", res.Step, res.Action, text)
+			} else {
+				fmt.Printf("[%d] %s: ok\n", res.Step, res.Action)
+			}
+		}
+		if err != nil && !keepGoing {
+			break
+		}
+	}
+
+	if jsonOutput {
+		printJSON(jsonEnvelope{OK: !failed, Result: results})
+	}
+	return failed
+}
+
+// runStep runs one step and returns its text output.
+func runStep(h *agent.Handlers, tools map[string]agent.Tool, step scriptStep) (string, error) {
+	tool, ok := resolveReplTool(tools, step.Action)
+	if !ok {
+		return "", fmt.Errorf("unknown action %q", step.Action)
+	}
+
+	args := step.Args
+	if step.words != nil {
+		var err error
+		if args, err = replToolArgs(tool, step.words); err != nil {
+			return "", err
+		}
+	}
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+
+	result, err := h.Call(tool.Name, args)
+	if err != nil {
+		return "", err
+	}
+	var texts []string
+	for _, c := range result.Content {
+		if c.Type == "text" && c.Text != "" {
+			texts = append(texts, c.Text)
+		}
+	}
+	return strings.Join(texts, "\n"), nil
+}
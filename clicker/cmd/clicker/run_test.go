@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/vibium/clicker/internal/agent"
+)
+
+func writeScript(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadScriptLines(t *testing.T) {
+	path := writeScript(t, "login.txt", `# log in
+navigate https://example.com/login
+
+fill "#user" alice
+{"action": "click", "args": {"selector": "button[type=submit]"}}
+`)
+	steps, err := loadScript(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []scriptStep{
+		{Action: "navigate", words: []string{"https://example.com/login"}},
+		{Action: "fill", words: []string{"#user", "alice"}},
+		{Action: "click", Args: map[string]interface{}{"selector": "button[type=submit]"}},
+	}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("steps = %+v, want %+v", steps, want)
+	}
+}
+
+func TestLoadScriptJSON(t *testing.T) {
+	path := writeScript(t, "steps.json", `[
+		{"action": "navigate", "args": {"url": "https://example.com"}},
+		{"action": "browser_get_title"}
+	]`)
+	steps, err := loadScript(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []scriptStep{
+		{Action: "navigate", Args: map[string]interface{}{"url": "https://example.com"}},
+		{Action: "browser_get_title"},
+	}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("steps = %+v, want %+v", steps, want)
+	}
+
+	for name, content := range map[string]string{
+		"missing.json": `[{"args": {}}]`,
+		"invalid.json": `{"action": "navigate"}`,
+		"unclosed.txt": `type "#q`,
+		"noaction.txt": `{"args": {"url": "https://example.com"}}`,
+		"badjson.txt":  `{"action": `,
+	} {
+		if _, err := loadScript(writeScript(t, name, content)); err == nil {
+			t.Errorf("loadScript(%s) should fail", name)
+		}
+	}
+}
+
+func TestRunStepUnknownAction(t *testing.T) {
+	tools := make(map[string]agent.Tool)
+	for _, tool := range agent.GetToolSchemas() {
+		tools[tool.Name] = tool
+	}
+	if _, err := runStep(nil, tools, scriptStep{Action: "teleport"}); err == nil {
+		t.Error("unknown action should be an error")
+	}
+}
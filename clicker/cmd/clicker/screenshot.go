@@ -15,12 +15,33 @@ func newScreenshotCmd() *cobra.Command {
   # Navigates to URL first, then screenshots
 
   vibium screenshot -o full.png --full-page
-  # Capture the entire page (not just the viewport)`,
+  # Capture the entire page (not just the viewport)
+
+  vibium screenshot -o long.png --stitch
+  # Full page for very tall pages: scroll and stitch viewport captures
+
+  vibium screenshot -o button.png --selector "#submit"
+  # Capture only the matching element
+
+  vibium screenshot -o print.png --print
+  # Preview the full page as it looks when printed
+
+  vibium screenshot -o home.png --baseline baseline/home.png --diff home-diff.png
+  # Compare against a baseline (created on first run); write changed pixels to home-diff.png
+
+  vibium screenshot -o home.png --baseline baseline/home.png --mask .timestamp --mask "#ad"
+  # Paint over volatile elements before comparing
+
+  vibium screenshot --stdout > shot.png
+  # Write the PNG to stdout (same as -o -)`,
 		Args: cobra.RangeArgs(0, 1),
 		Run: func(cmd *cobra.Command, args []string) {
 			output, _ := cmd.Flags().GetString("output")
 			fullPage, _ := cmd.Flags().GetBool("full-page")
 			annotate, _ := cmd.Flags().GetBool("annotate")
+			printMedia, _ := cmd.Flags().GetBool("print")
+			selector, _ := cmd.Flags().GetString("selector")
+			toStdout := outputToStdout(cmd)
 
 			// Navigate first if URL provided
 			if len(args) == 1 {
@@ -31,24 +52,85 @@ func newScreenshotCmd() *cobra.Command {
 				}
 			}
 
+			if printMedia {
+				previewArgs := map[string]interface{}{"filename": output}
+				if toStdout {
+					previewArgs = map[string]interface{}{}
+				}
+				result, err := daemonCall("browser_print_preview", previewArgs)
+				if err != nil {
+					printError(err)
+					return
+				}
+				if toStdout {
+					if err := writeResultData(result); err != nil {
+						printError(err)
+					}
+					return
+				}
+				printResult(result)
+				return
+			}
+
 			// Take screenshot with filename
 			screenshotArgs := map[string]interface{}{"filename": output}
+			if toStdout {
+				delete(screenshotArgs, "filename")
+			}
 			if fullPage {
 				screenshotArgs["fullPage"] = true
 			}
+			if stitch, _ := cmd.Flags().GetBool("stitch"); stitch {
+				screenshotArgs["stitch"] = true
+			}
 			if annotate {
 				screenshotArgs["annotate"] = true
 			}
+			if selector != "" {
+				screenshotArgs["selector"] = selector
+			}
+			if masks, _ := cmd.Flags().GetStringArray("mask"); len(masks) > 0 {
+				screenshotArgs["mask"] = toInterfaceSlice(masks)
+			}
+			if maskColor, _ := cmd.Flags().GetString("mask-color"); maskColor != "" {
+				screenshotArgs["maskColor"] = maskColor
+			}
+			// Like -o, these are relative to the daemon's screenshot directory.
+			if baseline, _ := cmd.Flags().GetString("baseline"); baseline != "" {
+				screenshotArgs["baseline"] = baseline
+				if cmd.Flags().Changed("threshold") {
+					threshold, _ := cmd.Flags().GetFloat64("threshold")
+					screenshotArgs["threshold"] = threshold
+				}
+				if diffPath, _ := cmd.Flags().GetString("diff"); diffPath != "" {
+					screenshotArgs["diffPath"] = diffPath
+				}
+			}
 			result, err := daemonCall("browser_screenshot", screenshotArgs)
 			if err != nil {
 				printError(err)
 				return
 			}
+			if toStdout {
+				if err := writeResultData(result); err != nil {
+					printError(err)
+				}
+				return
+			}
 			printResult(result)
 		},
 	}
-	cmd.Flags().StringP("output", "o", "screenshot.png", "Output file path")
+	cmd.Flags().StringP("output", "o", "screenshot.png", "Output file path (- for stdout)")
+	cmd.Flags().Bool("stdout", false, "Write the PNG to stdout instead of a file")
 	cmd.Flags().Bool("full-page", false, "Capture the full page instead of just the viewport")
+	cmd.Flags().Bool("stitch", false, "Capture the full page by scrolling and stitching (for pages too tall for --full-page)")
 	cmd.Flags().Bool("annotate", false, "Annotate interactive elements with numbered labels")
+	cmd.Flags().StringP("selector", "s", "", "Clip the screenshot to the element matching this selector")
+	cmd.Flags().Bool("print", false, "Render with print styles (full page), then restore screen media")
+	cmd.Flags().StringArray("mask", nil, "Paint over elements matching this selector (repeatable)")
+	cmd.Flags().String("mask-color", "", "CSS color for --mask (default #FF00FF)")
+	cmd.Flags().String("baseline", "", "Compare against this baseline PNG in the screenshot directory (saved there if it doesn't exist); exits non-zero if it differs")
+	cmd.Flags().Float64("threshold", 0.1, "Per-pixel color tolerance for --baseline, 0-1")
+	cmd.Flags().String("diff", "", "Write a diff image to this path in the screenshot directory when the screenshot differs from --baseline")
 	return cmd
 }
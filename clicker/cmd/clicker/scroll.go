@@ -1,6 +1,10 @@
 package main
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/spf13/cobra"
 )
 
@@ -18,7 +22,13 @@ func newScrollCmd() *cobra.Command {
   # Scroll down 5 increments
 
   vibium scroll down --selector "div.content"
-  # Scroll within a specific element`,
+  # Scroll within a specific element
+
+  vibium scroll top
+  # Jump to the top of the page (also: bottom)
+
+  vibium scroll --to 0,1500
+  # Scroll the window to an exact x,y position`,
 		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			direction := "down"
@@ -35,6 +45,19 @@ func newScrollCmd() *cobra.Command {
 			if selector != "" {
 				toolArgs["selector"] = selector
 			}
+			if direction == "top" || direction == "bottom" {
+				toolArgs = map[string]interface{}{"to": direction}
+			}
+			if to, _ := cmd.Flags().GetString("to"); to != "" {
+				xs, ys, ok := strings.Cut(to, ",")
+				x, errX := strconv.ParseFloat(strings.TrimSpace(xs), 64)
+				y, errY := strconv.ParseFloat(strings.TrimSpace(ys), 64)
+				if !ok || errX != nil || errY != nil {
+					printError(fmt.Errorf("invalid --to %q (expected x,y)", to))
+					return
+				}
+				toolArgs = map[string]interface{}{"scrollX": x, "scrollY": y}
+			}
 
 			result, err := daemonCall("browser_scroll", toolArgs)
 			if err != nil {
@@ -46,6 +69,7 @@ func newScrollCmd() *cobra.Command {
 	}
 	cmd.Flags().Int("amount", 3, "Number of scroll increments")
 	cmd.Flags().String("selector", "", "CSS selector for element to scroll to")
+	cmd.Flags().String("to", "", "Scroll the window to an absolute position (format: x,y)")
 
 	intoViewCmd := &cobra.Command{
 		Use:   "into-view [selector]",
@@ -63,6 +87,41 @@ func newScrollCmd() *cobra.Command {
 		},
 	}
 
+	byCmd := &cobra.Command{
+		Use:   "by",
+		Short: "Scroll by an exact number of pixels",
+		Example: `  vibium scroll by --dy 800
+  # Scroll down exactly 800px
+
+  vibium scroll by --dy -400 --selector "#feed"
+  # Scroll up 400px with the wheel over the #feed element`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			dx, _ := cmd.Flags().GetInt("dx")
+			dy, _ := cmd.Flags().GetInt("dy")
+			selector, _ := cmd.Flags().GetString("selector")
+
+			toolArgs := map[string]interface{}{
+				"deltaX": float64(dx),
+				"deltaY": float64(dy),
+			}
+			if selector != "" {
+				toolArgs["selector"] = selector
+			}
+
+			result, err := daemonCall("browser_scroll_by", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	byCmd.Flags().Int("dx", 0, "Horizontal delta in pixels (negative scrolls left)")
+	byCmd.Flags().Int("dy", 0, "Vertical delta in pixels (negative scrolls up)")
+	byCmd.Flags().String("selector", "", "CSS selector for the element to scroll over")
+
 	cmd.AddCommand(intoViewCmd)
+	cmd.AddCommand(byCmd)
 	return cmd
 }
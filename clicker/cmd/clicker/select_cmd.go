@@ -1,23 +1,61 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 )
 
 func newSelectCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "select [selector] [value]",
+	cmd := &cobra.Command{
+		Use:   "select [selector] [value...]",
 		Short: "Select an option in a <select> element",
 		Example: `  vibium select "select#color" "blue"
-  # Select "blue" in the color dropdown`,
-		Args: cobra.ExactArgs(2),
+  # Select "blue" in the color dropdown
+
+  vibium select "select#color" "Dark Blue" --label
+  # Select by visible option text instead of value
+
+  vibium select "select#toppings" cheese olives
+  # Select several options in a <select multiple>
+
+  vibium select "select#size" --index 2
+  # Select the third option by position`,
+		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			selector := args[0]
-			value := args[1]
+			byLabel, _ := cmd.Flags().GetBool("label")
+
+			if cmd.Flags().Changed("index") {
+				index, _ := cmd.Flags().GetInt("index")
+				result, err := daemonCall("browser_select_by_index", map[string]interface{}{
+					"selector":    selector,
+					"optionIndex": float64(index),
+				})
+				if err != nil {
+					printError(err)
+					return
+				}
+				printResult(result)
+				return
+			}
+			if len(args) < 2 {
+				printError(fmt.Errorf("a value (or --index) is required"))
+				return
+			}
+
+			key := "values"
+			if byLabel {
+				key = "labels"
+			}
+			items := make([]interface{}, 0, len(args)-1)
+			for _, v := range args[1:] {
+				items = append(items, v)
+			}
 
 			result, err := daemonCall("browser_select", map[string]interface{}{
 				"selector": selector,
-				"value":    value,
+				key:        items,
 			})
 			if err != nil {
 				printError(err)
@@ -26,4 +64,7 @@ func newSelectCmd() *cobra.Command {
 			printResult(result)
 		},
 	}
+	cmd.Flags().Bool("label", false, "Match options by visible text instead of value")
+	cmd.Flags().Int("index", 0, "Select the option at this zero-based position")
+	return cmd
 }
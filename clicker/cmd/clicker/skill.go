@@ -54,8 +54,50 @@ func installSkill() error {
 		return fmt.Errorf("could not write SKILL.md: %w", err)
 	}
 
+	if jsonOutput {
+		printJSON(jsonEnvelope{OK: true, Result: map[string]interface{}{
+			"dir":   skillDir,
+			"files": []string{skillPath},
+		}})
+		return nil
+	}
+
 	fmt.Printf("Installed Vibium skill to %s\n", skillDir)
 	fmt.Println("Files:")
 	fmt.Printf("  %s\n", skillPath)
 	return nil
 }
+
+func newRunSkillCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run-skill [name]",
+		Short: "Run a composable skill from the skills directory",
+		Example: `  vibium run-skill login --param user=alice --param password=secret
+  # Runs ~/.vibium/skills/login.json with {{user}} and {{password}} filled in
+
+  VIBIUM_SKILLS_DIR=./skills vibium run-skill checkout
+  # Runs ./skills/checkout.json, including any skills it references`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			params, _ := cmd.Flags().GetStringToString("param")
+
+			toolArgs := map[string]interface{}{"name": args[0]}
+			if len(params) > 0 {
+				p := make(map[string]interface{}, len(params))
+				for k, v := range params {
+					p[k] = v
+				}
+				toolArgs["params"] = p
+			}
+
+			result, err := daemonCall("browser_invoke_skill_chain", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	cmd.Flags().StringToString("param", nil, "Skill parameter as key=value (repeatable)")
+	return cmd
+}
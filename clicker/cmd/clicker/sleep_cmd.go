@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 
 	"github.com/spf13/cobra"
@@ -21,8 +20,8 @@ func newSleepCmd() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			ms, err := strconv.ParseFloat(args[0], 64)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: invalid milliseconds value: %s\n", args[0])
-				os.Exit(1)
+				printError(fmt.Errorf("invalid milliseconds value: %s", args[0]))
+				return
 			}
 
 			result, err := daemonCall("browser_sleep", map[string]interface{}{"ms": ms})
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Get the accessibility tree with @refs on interactive nodes",
+		Example: `  vibium snapshot
+  # Accessibility tree where buttons, links, inputs carry "ref": "@eN"
+  # Use refs with other commands: vibium click @e3
+
+  vibium snapshot --everything
+  # Include all nodes (generic containers, etc.)`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			toolArgs := map[string]interface{}{}
+			if everything, _ := cmd.Flags().GetBool("everything"); everything {
+				toolArgs["everything"] = true
+			}
+			if stable, _ := cmd.Flags().GetBool("stable-refs"); stable {
+				toolArgs["stableRefs"] = true
+			}
+
+			result, err := daemonCall("browser_snapshot", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	cmd.Flags().Bool("everything", false, "Show all nodes including generic containers")
+	cmd.Flags().Bool("stable-refs", false, "Keep @refs of elements that persist from the previous stable map")
+	return cmd
+}
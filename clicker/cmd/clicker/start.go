@@ -57,8 +57,8 @@ Set VIBIUM_CONNECT_API_KEY to send an Authorization: Bearer header.`,
 			if daemon.IsRunning() {
 				pid, _ := daemon.ReadPID()
 				if err := daemon.Shutdown(); err != nil {
-					fmt.Fprintf(os.Stderr, "Error stopping existing daemon: %v\n", err)
-					os.Exit(1)
+					printError(fmt.Errorf("stopping existing daemon: %w", err))
+					return
 				}
 				// Wait for the daemon process to fully exit
 				if pid > 0 {
@@ -76,8 +76,8 @@ Set VIBIUM_CONNECT_API_KEY to send an Authorization: Bearer header.`,
 
 			exe, err := os.Executable()
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error finding executable: %v\n", err)
-				os.Exit(1)
+				printError(fmt.Errorf("finding executable: %w", err))
+				return
 			}
 
 			daemonArgs := []string{"daemon", "start", "--_internal", "--idle-timeout=30m",
@@ -100,17 +100,17 @@ Set VIBIUM_CONNECT_API_KEY to send an Authorization: Bearer header.`,
 			setSysProcAttr(child)
 
 			if err := child.Start(); err != nil {
-				fmt.Fprintf(os.Stderr, "Error starting daemon: %v\n", err)
-				os.Exit(1)
+				printError(fmt.Errorf("starting daemon: %w", err))
+				return
 			}
 
 			socketPath, _ := paths.GetSocketPath()
 			if err := waitForSocket(socketPath, 5*time.Second); err != nil {
-				fmt.Fprintf(os.Stderr, "Daemon failed to start: %v\n", err)
-				os.Exit(1)
+				printError(fmt.Errorf("daemon failed to start: %w", err))
+				return
 			}
 
-			fmt.Printf("Connected to %s (daemon pid %d)\n", connectURL, child.Process.Pid)
+			printMessage("Connected to %s (daemon pid %d)", connectURL, child.Process.Pid)
 		},
 	}
 }
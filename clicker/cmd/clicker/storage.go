@@ -11,17 +11,24 @@ import (
 func newStorageCmd() *cobra.Command {
 	storageCmd := &cobra.Command{
 		Use:   "storage",
-		Short: "Export or restore browser state (cookies, localStorage, sessionStorage)",
+		Short: "Export or restore browser state (cookies, localStorage, sessionStorage, IndexedDB)",
 		Example: `  vibium storage
   # Print state as JSON
 
   vibium storage -o state.json
-  # Save state to file`,
+  # Save state to file (Playwright storageState format)
+
+  vibium storage --indexeddb=false -o state.json
+  # Skip IndexedDB (smaller file for apps with large offline caches)`,
 		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
 			output, _ := cmd.Flags().GetString("output")
 
-			result, err := daemonCall("browser_storage_state", map[string]interface{}{})
+			indexedDB, _ := cmd.Flags().GetBool("indexeddb")
+
+			result, err := daemonCall("browser_storage_state", map[string]interface{}{
+				"indexedDB": indexedDB,
+			})
 			if err != nil {
 				printError(err)
 				return
@@ -33,25 +40,29 @@ func newStorageCmd() *cobra.Command {
 					printError(fmt.Errorf("failed to write file: %w", err))
 					return
 				}
-				fmt.Printf("State saved to %s\n", output)
+				printMessage("State saved to %s", output)
 				return
 			}
 			printResult(result)
 		},
 	}
 	storageCmd.Flags().StringP("output", "o", "", "Output file path")
+	storageCmd.Flags().Bool("indexeddb", true, "Include IndexedDB databases")
 
 	restoreCmd := &cobra.Command{
 		Use:   "restore [path]",
 		Short: "Restore browser state from a JSON file",
 		Example: `  vibium storage restore state.json
-  # Restore cookies and storage from saved state`,
+  # Restore cookies and storage from saved state
+
+  vibium storage restore playwright-auth.json
+  # Files saved by Playwright's storageState() work too`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			path, err := filepath.Abs(args[0])
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: invalid path: %v\n", err)
-				os.Exit(1)
+				printError(fmt.Errorf("invalid path: %v", err))
+				return
 			}
 
 			result, err := daemonCall("browser_restore_storage", map[string]interface{}{"path": path})
@@ -63,6 +74,46 @@ func newStorageCmd() *cobra.Command {
 		},
 	}
 
+	clearCmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Clear cookies and storage for a clean slate",
+		Example: `  vibium storage clear
+  # Delete cookies and clear localStorage + sessionStorage
+
+  vibium storage clear --indexeddb --cache-storage
+  # Also delete IndexedDB databases and Cache Storage
+
+  vibium storage clear --cookies=false
+  # Keep cookies, clear only Web Storage`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cookies, _ := cmd.Flags().GetBool("cookies")
+			localStorage, _ := cmd.Flags().GetBool("local-storage")
+			sessionStorage, _ := cmd.Flags().GetBool("session-storage")
+			indexedDB, _ := cmd.Flags().GetBool("indexeddb")
+			cacheStorage, _ := cmd.Flags().GetBool("cache-storage")
+
+			result, err := daemonCall("browser_clear_storage", map[string]interface{}{
+				"cookies":        cookies,
+				"localStorage":   localStorage,
+				"sessionStorage": sessionStorage,
+				"indexedDB":      indexedDB,
+				"cacheStorage":   cacheStorage,
+			})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	clearCmd.Flags().Bool("cookies", true, "Delete all cookies")
+	clearCmd.Flags().Bool("local-storage", true, "Clear localStorage")
+	clearCmd.Flags().Bool("session-storage", true, "Clear sessionStorage")
+	clearCmd.Flags().Bool("indexeddb", false, "Delete all IndexedDB databases")
+	clearCmd.Flags().Bool("cache-storage", false, "Delete all Cache Storage caches")
+
 	storageCmd.AddCommand(restoreCmd)
+	storageCmd.AddCommand(clearCmd)
 	return storageCmd
 }
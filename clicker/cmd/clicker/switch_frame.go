@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newSwitchFrameCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "switch-frame [frame]",
+		Short: "Run later commands inside an iframe until switched back",
+		Example: `  vibium switch-frame checkout
+  # Enter the frame named "checkout" (or a frame URL substring, or @ref)
+
+  vibium click "button.pay"
+  # Runs inside the checkout frame
+
+  vibium switch-frame --parent
+  # Leave for the enclosing frame
+
+  vibium switch-frame --top
+  # Back to the page (navigating does this too)`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			parent, _ := cmd.Flags().GetBool("parent")
+			top, _ := cmd.Flags().GetBool("top")
+
+			var tool string
+			toolArgs := map[string]interface{}{}
+			switch {
+			case parent && top, (parent || top) && len(args) == 1:
+				printError(fmt.Errorf("use one of a frame, --parent, or --top"))
+				return
+			case parent:
+				tool = "browser_switch_frame_parent"
+			case top:
+				tool = "browser_switch_frame_top"
+			case len(args) == 1:
+				tool = "browser_switch_frame"
+				toolArgs["frame"] = args[0]
+			default:
+				printError(fmt.Errorf("frame argument, --parent, or --top is required"))
+				return
+			}
+
+			result, err := daemonCall(tool, toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	cmd.Flags().Bool("parent", false, "Switch to the parent of the current frame")
+	cmd.Flags().Bool("top", false, "Switch back to the top-level page")
+	return cmd
+}
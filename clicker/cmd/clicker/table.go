@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newTableCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "table [selector]",
+		Short: "Extract a table's cell text as JSON",
+		Example: `  vibium table "table#prices"
+  # Prints a JSON 2D array of cell text
+
+  vibium table "#results" --headers
+  # Prints an array of row objects keyed by header text`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			toolArgs := map[string]interface{}{"selector": args[0]}
+			if headers, _ := cmd.Flags().GetBool("headers"); headers {
+				toolArgs["headers"] = true
+			}
+
+			result, err := daemonCall("browser_get_table", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	cmd.Flags().Bool("headers", false, "Return rows as objects keyed by header text")
+	return cmd
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newTapCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tap [selector]",
+		Short: "Tap an element with a touch pointer",
+		Example: `  vibium tap "button.menu"
+  # Tap a button (fires touchstart/touchend)
+
+  vibium tap @e2
+  # Tap element from map`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			selector := args[0]
+
+			result, err := daemonCall("browser_tap", map[string]interface{}{"selector": selector})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+}
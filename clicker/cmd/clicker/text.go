@@ -1,11 +1,13 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 )
 
 func newTextCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "text [selector]",
 		Short: "Get text content of the page or an element",
 		Example: `  vibium text
@@ -18,7 +20,16 @@ func newTextCmd() *cobra.Command {
   # Navigate then get all page text
 
   vibium text https://example.com "h1"
-  # Navigate then get element text`,
+  # Navigate then get element text
+
+  vibium text "li.item" --all
+  # Get the text of every matching element, one per line
+
+  vibium text "li.item" --all --json
+  # Same, as a JSON array in the result
+
+  vibium text ".details" --mode textContent
+  # Include text hidden with display:none`,
 		Args: cobra.MaximumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			toolArgs := map[string]interface{}{}
@@ -44,12 +55,34 @@ func newTextCmd() *cobra.Command {
 				}
 			}
 
+			all, _ := cmd.Flags().GetBool("all")
+			if all {
+				if _, ok := toolArgs["selector"]; !ok {
+					printError(fmt.Errorf("--all requires a selector"))
+					return
+				}
+				toolArgs["all"] = true
+			}
+
+			mode, _ := cmd.Flags().GetString("mode")
+			if mode != "" {
+				toolArgs["mode"] = mode
+			}
+
 			result, err := daemonCall("browser_get_text", toolArgs)
 			if err != nil {
 				printError(err)
 				return
 			}
+			if all && jsonOutput {
+				// Newline-joined text can't be split back apart, so use the list
+				printJSON(jsonEnvelope{OK: true, Result: result.StructuredContent["texts"]})
+				return
+			}
 			printResult(result)
 		},
 	}
+	cmd.Flags().Bool("all", false, "Get text of every element matching the selector")
+	cmd.Flags().String("mode", "", "Text to read: innerText (rendered, default) or textContent (includes hidden text)")
+	return cmd
 }
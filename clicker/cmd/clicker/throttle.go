@@ -0,0 +1,50 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newThrottleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "throttle [preset]",
+		Short: "Throttle the network (slow-3g, fast-3g, offline, online)",
+		Example: `  vibium throttle slow-3g
+  # Emulate a slow 3G connection
+
+  vibium throttle --latency 300 --download 250000 --upload 100000
+  # Custom profile (latency in ms, throughput in bytes/s)
+
+  vibium throttle online
+  # Remove throttling`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			toolArgs := map[string]interface{}{}
+			if len(args) == 1 {
+				toolArgs["preset"] = args[0]
+			}
+			if cmd.Flags().Changed("latency") {
+				latency, _ := cmd.Flags().GetFloat64("latency")
+				toolArgs["latency"] = latency
+			}
+			if cmd.Flags().Changed("download") {
+				download, _ := cmd.Flags().GetFloat64("download")
+				toolArgs["downloadThroughput"] = download
+			}
+			if cmd.Flags().Changed("upload") {
+				upload, _ := cmd.Flags().GetFloat64("upload")
+				toolArgs["uploadThroughput"] = upload
+			}
+
+			result, err := daemonCall("browser_emulate_network", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	cmd.Flags().Float64("latency", 0, "Added latency in milliseconds")
+	cmd.Flags().Float64("download", 0, "Download throughput in bytes per second (-1 for unthrottled)")
+	cmd.Flags().Float64("upload", 0, "Upload throughput in bytes per second (-1 for unthrottled)")
+	return cmd
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func newThrottleCPUCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "throttle-cpu <rate>",
+		Short: "Throttle the CPU to simulate a slower device",
+		Example: `  vibium throttle-cpu 4
+  # Run 4x slower, like a low-end phone
+
+  vibium throttle-cpu 1
+  # Remove CPU throttling`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			rate, err := strconv.ParseFloat(args[0], 64)
+			if err != nil {
+				printError(fmt.Errorf("invalid rate: %s", args[0]))
+				return
+			}
+			result, err := daemonCall("browser_emulate_cpu", map[string]interface{}{"rate": rate})
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+}
@@ -7,7 +7,7 @@ import (
 
 func newTypeCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "type [url] [selector] [text]",
+		Use:   "type [url] [selector] <text>",
 		Short: "Type text into an element (optionally navigate to URL first)",
 		Example: `  vibium type "input" "12345"
   # Types on current page
@@ -16,8 +16,14 @@ func newTypeCmd() *cobra.Command {
   # Navigates to URL first, then types
 
   vibium type https://the-internet.herokuapp.com/inputs "input" "12345" --timeout 5s
-  # Custom timeout for actionability checks`,
-		Args: cobra.RangeArgs(2, 3),
+  # Custom timeout for actionability checks
+
+  vibium type "hello"
+  # Types into the focused element (e.g. after vibium keys Tab)
+
+  vibium type "#search" "東京タワー" --raw
+  # Insert CJK text or emoji in one edit`,
+		Args: cobra.RangeArgs(1, 3),
 		Run: func(cmd *cobra.Command, args []string) {
 			var selector, text string
 			if len(args) == 3 {
@@ -29,17 +35,28 @@ func newTypeCmd() *cobra.Command {
 				}
 				selector = args[1]
 				text = args[2]
-			} else {
+			} else if len(args) == 2 {
 				// type <selector> <text> — current page
 				selector = args[0]
 				text = args[1]
+			} else {
+				// type <text> — focused element
+				text = args[0]
 			}
 
 			// Type into element
-			result, err := daemonCall("browser_type", map[string]interface{}{
-				"selector": selector,
-				"text":     text,
-			})
+			toolArgs := map[string]interface{}{"text": text}
+			if selector != "" {
+				toolArgs["selector"] = selector
+			}
+			if raw, _ := cmd.Flags().GetBool("raw"); raw {
+				toolArgs["raw"] = true
+			}
+			if force, _ := cmd.Flags().GetBool("force"); force {
+				toolArgs["force"] = true
+			}
+			addTimeoutArg(cmd, toolArgs)
+			result, err := daemonCall("browser_type", toolArgs)
 			if err != nil {
 				printError(err)
 				return
@@ -48,5 +65,7 @@ func newTypeCmd() *cobra.Command {
 		},
 	}
 	cmd.Flags().Duration("timeout", api.DefaultTimeout, "Timeout for actionability checks (e.g., 5s, 30s)")
+	cmd.Flags().Bool("force", false, "Skip actionability checks (bypasses safety; for elements that never settle)")
+	cmd.Flags().Bool("raw", false, "Insert text in one edit instead of per-character key presses (CJK, emoji)")
 	return cmd
 }
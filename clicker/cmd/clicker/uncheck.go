@@ -2,10 +2,11 @@ package main
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/vibium/clicker/internal/api"
 )
 
 func newUncheckCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "uncheck [selector]",
 		Short: "Uncheck a checkbox",
 		Example: `  vibium uncheck "input[name=agree]"
@@ -14,7 +15,10 @@ func newUncheckCmd() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			selector := args[0]
 
-			result, err := daemonCall("browser_uncheck", map[string]interface{}{"selector": selector})
+			toolArgs := map[string]interface{}{"selector": selector}
+			addTimeoutArg(cmd, toolArgs)
+
+			result, err := daemonCall("browser_uncheck", toolArgs)
 			if err != nil {
 				printError(err)
 				return
@@ -22,4 +26,6 @@ func newUncheckCmd() *cobra.Command {
 			printResult(result)
 		},
 	}
+	cmd.Flags().Duration("timeout", api.DefaultTimeout, "Timeout for actionability checks (e.g., 5s, 30s)")
+	return cmd
 }
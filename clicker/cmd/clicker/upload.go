@@ -16,7 +16,10 @@ func newUploadCmd() *cobra.Command {
   # Upload a single file
 
   vibium upload "#file-input" ./photo.jpg ./doc.pdf
-  # Upload multiple files`,
+  # Upload multiple files
+
+  vibium upload "input[webkitdirectory]" ./photos
+  # Upload a directory (the input must have webkitdirectory)`,
 		Args: cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			selector := args[0]
@@ -27,16 +30,26 @@ func newUploadCmd() *cobra.Command {
 			for i, f := range filePaths {
 				abs, err := filepath.Abs(f)
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error: invalid file path %q: %v\n", f, err)
-					os.Exit(1)
+					printError(fmt.Errorf("invalid file path %q: %v", f, err))
+					return
 				}
 				absFiles[i] = abs
 			}
 
-			result, err := daemonCall("browser_upload", map[string]interface{}{
+			toolArgs := map[string]interface{}{
 				"selector": selector,
 				"files":    absFiles,
-			})
+			}
+			if len(absFiles) == 1 {
+				if info, err := os.Stat(absFiles[0].(string)); err == nil && info.IsDir() {
+					toolArgs = map[string]interface{}{
+						"selector":  selector,
+						"directory": absFiles[0],
+					}
+				}
+			}
+
+			result, err := daemonCall("browser_upload", toolArgs)
 			if err != nil {
 				printError(err)
 				return
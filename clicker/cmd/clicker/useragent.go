@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newUserAgentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user-agent [string]",
+		Short: "Override the browser User-Agent",
+		Example: `  vibium user-agent "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)"
+  # Pretend to be an iPhone
+
+  vibium user-agent "MyBot/1.0" --accept-language "de-DE,de" --platform Linux
+  # Also override Accept-Language and navigator.platform`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			acceptLanguage, _ := cmd.Flags().GetString("accept-language")
+			platform, _ := cmd.Flags().GetString("platform")
+
+			callArgs := map[string]interface{}{"userAgent": args[0]}
+			if acceptLanguage != "" {
+				callArgs["acceptLanguage"] = acceptLanguage
+			}
+			if platform != "" {
+				callArgs["platform"] = platform
+			}
+			result, err := daemonCall("browser_set_user_agent", callArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	cmd.Flags().String("accept-language", "", "Accept-Language header value")
+	cmd.Flags().String("platform", "", "navigator.platform value")
+	return cmd
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 
 	"github.com/spf13/cobra"
@@ -34,20 +33,20 @@ func newViewportCmd() *cobra.Command {
 			}
 
 			if len(args) == 1 {
-				fmt.Fprintf(os.Stderr, "Error: provide both width and height\n")
-				os.Exit(1)
+				printError(fmt.Errorf("provide both width and height"))
+				return
 			}
 
 			// Set viewport
 			width, err := strconv.Atoi(args[0])
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: invalid width: %s\n", args[0])
-				os.Exit(1)
+				printError(fmt.Errorf("invalid width: %s", args[0]))
+				return
 			}
 			height, err := strconv.Atoi(args[1])
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: invalid height: %s\n", args[1])
-				os.Exit(1)
+				printError(fmt.Errorf("invalid height: %s", args[1]))
+				return
 			}
 
 			dpr, _ := cmd.Flags().GetFloat64("dpr")
@@ -18,7 +18,13 @@ func newWaitCmd() *cobra.Command {
   # Wait for element to be visible
 
   vibium wait "div.spinner" --state hidden --timeout 5000
-  # Wait for spinner to disappear`,
+  # Wait for spinner to disappear
+
+  vibium wait "button[type=submit]" --state enabled
+  # Wait for a submit button to be enabled
+
+  vibium wait ".drawer" --state stable
+  # Wait for an animation to finish (box unchanged across samples)`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			selector := args[0]
@@ -28,7 +34,13 @@ func newWaitCmd() *cobra.Command {
 			toolArgs := map[string]interface{}{
 				"selector": selector,
 				"state":    state,
-				"timeout":  float64(timeoutMs),
+			}
+			if cmd.Flags().Changed("timeout") {
+				toolArgs["timeout"] = float64(timeoutMs)
+			}
+			if cmd.Flags().Changed("stable-polls") {
+				polls, _ := cmd.Flags().GetInt("stable-polls")
+				toolArgs["stablePolls"] = float64(polls)
 			}
 
 			result, err := daemonCall("browser_wait", toolArgs)
@@ -39,17 +51,21 @@ func newWaitCmd() *cobra.Command {
 			printResult(result)
 		},
 	}
-	cmd.Flags().String("state", "attached", "State to wait for: attached, visible, hidden")
+	cmd.Flags().String("state", "attached", "State to wait for: attached, visible, hidden, enabled, editable, stable")
+	cmd.Flags().Int("stable-polls", api.DefaultStablePolls, "Consecutive unchanged samples required for --state stable")
 	cmd.Flags().Int("timeout", int(api.DefaultTimeout/time.Millisecond), "Timeout in milliseconds")
 
 	urlCmd := &cobra.Command{
 		Use:   "url [pattern]",
-		Short: "Wait until the page URL contains a substring",
+		Short: "Wait until the page URL contains a substring or matches a regex",
 		Example: `  vibium wait url "/dashboard"
   # Wait until URL contains "/dashboard"
 
   vibium wait url "success" --timeout 10000
-  # Wait up to 10 seconds`,
+  # Wait up to 10 seconds
+
+  vibium wait url '^https://app\.example\.com/dashboard/\d+' --regex
+  # Wait until URL matches a regular expression`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			pattern := args[0]
@@ -59,6 +75,9 @@ func newWaitCmd() *cobra.Command {
 			if cmd.Flags().Changed("timeout") {
 				toolArgs["timeout"] = float64(timeout)
 			}
+			if regex, _ := cmd.Flags().GetBool("regex"); regex {
+				toolArgs["regex"] = true
+			}
 
 			result, err := daemonCall("browser_wait_for_url", toolArgs)
 			if err != nil {
@@ -69,6 +88,99 @@ func newWaitCmd() *cobra.Command {
 		},
 	}
 	urlCmd.Flags().Int("timeout", 30000, "Timeout in milliseconds")
+	urlCmd.Flags().Bool("regex", false, "Treat the pattern as a regular expression")
+
+	responseCmd := &cobra.Command{
+		Use:   "response [pattern]",
+		Short: "Wait for a network response whose URL matches a pattern",
+		Example: `  vibium click "button[type=submit]" && vibium wait response "/api/login"
+  # Wait for the login request triggered by the click
+
+  vibium wait response '/api/items/\d+$' --regex --timeout 10000
+  # Wait up to 10 seconds for a URL matching a regular expression`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			timeout, _ := cmd.Flags().GetInt("timeout")
+
+			toolArgs := map[string]interface{}{"pattern": args[0]}
+			if cmd.Flags().Changed("timeout") {
+				toolArgs["timeout"] = float64(timeout)
+			}
+			if regex, _ := cmd.Flags().GetBool("regex"); regex {
+				toolArgs["regex"] = true
+			}
+
+			result, err := daemonCall("browser_wait_for_response", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	responseCmd.Flags().Int("timeout", 30000, "Timeout in milliseconds")
+	responseCmd.Flags().Bool("regex", false, "Treat the pattern as a regular expression")
+
+	pageCmd := &cobra.Command{
+		Use:   "page [url-pattern]",
+		Short: "Wait for a new page (tab or popup) to open",
+		Example: `  vibium click "a.open-docs" && vibium wait page
+  # Wait for the tab opened by the click → {"index":1,"url":"...","context":"..."}
+
+  vibium wait page "accounts.google.com" --timeout 10000
+  # Wait for an OAuth popup, then: vibium page switch --latest`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			timeout, _ := cmd.Flags().GetInt("timeout")
+
+			toolArgs := map[string]interface{}{}
+			if len(args) == 1 {
+				toolArgs["url"] = args[0]
+			}
+			if cmd.Flags().Changed("timeout") {
+				toolArgs["timeout"] = float64(timeout)
+			}
+			if regex, _ := cmd.Flags().GetBool("regex"); regex {
+				toolArgs["regex"] = true
+			}
+
+			result, err := daemonCall("browser_wait_for_page", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	pageCmd.Flags().Int("timeout", 30000, "Timeout in milliseconds")
+	pageCmd.Flags().Bool("regex", false, "Treat the pattern as a regular expression")
+
+	downloadCmd := &cobra.Command{
+		Use:   "download",
+		Short: "Wait for a download to finish",
+		Example: `  vibium click "a.export-csv" && vibium wait download
+  # Wait for the file the click downloads → {"url":"...","suggestedFilename":"report.csv","path":"...","size":1234}
+
+  vibium wait download --timeout 120000
+  # Allow up to 2 minutes for a large file`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			timeout, _ := cmd.Flags().GetInt("timeout")
+
+			toolArgs := map[string]interface{}{}
+			if cmd.Flags().Changed("timeout") {
+				toolArgs["timeout"] = float64(timeout)
+			}
+
+			result, err := daemonCall("browser_wait_for_download", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	downloadCmd.Flags().Int("timeout", 30000, "Timeout in milliseconds")
 
 	textCmd := &cobra.Command{
 		Use:   "text [text]",
@@ -84,7 +196,7 @@ func newWaitCmd() *cobra.Command {
 			timeout, _ := cmd.Flags().GetFloat64("timeout")
 
 			callArgs := map[string]interface{}{"text": text}
-			if timeout > 0 {
+			if cmd.Flags().Changed("timeout") && timeout > 0 {
 				callArgs["timeout"] = timeout
 			}
 			result, err := daemonCall("browser_wait_for_text", callArgs)
@@ -138,7 +250,7 @@ func newWaitCmd() *cobra.Command {
 			timeout, _ := cmd.Flags().GetFloat64("timeout")
 
 			callArgs := map[string]interface{}{"expression": expression}
-			if timeout > 0 {
+			if cmd.Flags().Changed("timeout") && timeout > 0 {
 				callArgs["timeout"] = timeout
 			}
 			result, err := daemonCall("browser_wait_for_fn", callArgs)
@@ -151,9 +263,55 @@ func newWaitCmd() *cobra.Command {
 	}
 	fnCmd.Flags().Float64("timeout", 30000, "Timeout in milliseconds")
 
+	stableCmd := &cobra.Command{
+		Use:   "stable",
+		Short: "Wait until the page stops changing visually",
+		Example: `  vibium wait stable
+  # Wait until two consecutive screenshots are identical
+
+  vibium wait stable --tolerance 0.01 -o settled.png
+  # Allow 1% of pixels to differ, then save the stable screenshot`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			timeout, _ := cmd.Flags().GetInt("timeout")
+			interval, _ := cmd.Flags().GetInt("interval")
+			tolerance, _ := cmd.Flags().GetFloat64("tolerance")
+			output, _ := cmd.Flags().GetString("output")
+
+			toolArgs := map[string]interface{}{}
+			if cmd.Flags().Changed("timeout") {
+				toolArgs["timeout"] = float64(timeout)
+			}
+			if cmd.Flags().Changed("interval") {
+				toolArgs["interval"] = float64(interval)
+			}
+			if tolerance > 0 {
+				toolArgs["tolerance"] = tolerance
+			}
+			if output != "" {
+				toolArgs["filename"] = output
+			}
+
+			result, err := daemonCall("browser_wait_until_stable_screenshot", toolArgs)
+			if err != nil {
+				printError(err)
+				return
+			}
+			printResult(result)
+		},
+	}
+	stableCmd.Flags().Int("timeout", 30000, "Timeout in milliseconds")
+	stableCmd.Flags().Int("interval", 250, "Delay between captures in milliseconds")
+	stableCmd.Flags().Float64("tolerance", 0, "Fraction of pixels (0.0-1.0) allowed to differ")
+	stableCmd.Flags().StringP("output", "o", "", "Save the stable screenshot to this file")
+
 	cmd.AddCommand(urlCmd)
+	cmd.AddCommand(responseCmd)
+	cmd.AddCommand(pageCmd)
+	cmd.AddCommand(downloadCmd)
 	cmd.AddCommand(textCmd)
 	cmd.AddCommand(loadCmd)
 	cmd.AddCommand(fnCmd)
+	cmd.AddCommand(stableCmd)
 	return cmd
 }
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 
 	"github.com/spf13/cobra"
@@ -39,8 +38,8 @@ func newWindowCmd() *cobra.Command {
 			}
 
 			if len(args) == 1 || len(args) == 3 {
-				fmt.Fprintf(os.Stderr, "Error: provide both width and height\n")
-				os.Exit(1)
+				printError(fmt.Errorf("provide both width and height"))
+				return
 			}
 
 			callArgs := map[string]interface{}{}
@@ -48,13 +47,13 @@ func newWindowCmd() *cobra.Command {
 			if len(args) >= 2 {
 				width, err := strconv.Atoi(args[0])
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error: invalid width: %s\n", args[0])
-					os.Exit(1)
+					printError(fmt.Errorf("invalid width: %s", args[0]))
+					return
 				}
 				height, err := strconv.Atoi(args[1])
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error: invalid height: %s\n", args[1])
-					os.Exit(1)
+					printError(fmt.Errorf("invalid height: %s", args[1]))
+					return
 				}
 				callArgs["width"] = float64(width)
 				callArgs["height"] = float64(height)
@@ -63,13 +62,13 @@ func newWindowCmd() *cobra.Command {
 			if len(args) == 4 {
 				x, err := strconv.Atoi(args[2])
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error: invalid x: %s\n", args[2])
-					os.Exit(1)
+					printError(fmt.Errorf("invalid x: %s", args[2]))
+					return
 				}
 				y, err := strconv.Atoi(args[3])
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error: invalid y: %s\n", args[3])
-					os.Exit(1)
+					printError(fmt.Errorf("invalid y: %s", args[3]))
+					return
 				}
 				callArgs["x"] = float64(x)
 				callArgs["y"] = float64(y)
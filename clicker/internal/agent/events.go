@@ -0,0 +1,1416 @@
+package agent
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vibium/clicker/internal/api"
+)
+
+// The MCP client reads from the BiDi connection only while it waits for a
+// command response, so events are handled whenever a tool call is talking to
+// the browser. While an intercept is active, the intercept pump also drains
+// them between tool calls so paused requests don't hang.
+
+// interceptPumpInterval is how often the intercept pump resolves paused
+// requests between tool calls.
+const interceptPumpInterval = 50 * time.Millisecond
+
+// maxBufferedResponses caps the responses kept for browser_wait_for_response.
+const maxBufferedResponses = 200
+
+// maxConsoleLogs caps the console messages kept for browser_console_logs;
+// the oldest entries are dropped first.
+const maxConsoleLogs = 1000
+
+// maxPageErrors caps the uncaught errors kept for browser_page_errors.
+const maxPageErrors = 200
+
+// maxClosedPages caps the recently closed pages shown by browser_list_pages.
+const maxClosedPages = 20
+
+// startEventCapture installs the session's event handler and subscribes to
+// the events it buffers from launch on. Subscription failures are ignored so
+// browsers with partial BiDi support still work.
+func (h *Handlers) startEventCapture() {
+	h.requests = api.NewRequestTracker()
+	h.client.SetEventHandler(h.onBidiEvent)
+	h.subscribe(
+		"network.beforeRequestSent",
+		"network.responseCompleted",
+		"network.fetchError",
+		"log.entryAdded",
+		"browsingContext.contextCreated",
+		"browsingContext.contextDestroyed",
+		"browsingContext.downloadWillBegin",
+		"browsingContext.downloadEnd",
+		"browsingContext.userPromptOpened",
+		"browsingContext.userPromptClosed",
+	)
+}
+
+// onBidiEvent is the single BiDi event handler for the session. It fans
+// events out to the recorder and to the session's event-driven features.
+func (h *Handlers) onBidiEvent(msg string) {
+	if h.recorder != nil {
+		h.recorder.RecordBidiEvent(msg)
+	}
+
+	var event struct {
+		Method string                 `json:"method"`
+		Params map[string]interface{} `json:"params"`
+	}
+	if err := json.Unmarshal([]byte(msg), &event); err != nil {
+		return
+	}
+
+	switch event.Method {
+	case "network.beforeRequestSent":
+		h.resolveInterceptedRequest(event.Params)
+		h.trackRequest(event.Method, event.Params)
+	case "network.responseCompleted", "network.fetchError":
+		h.trackRequest(event.Method, event.Params)
+	case "log.entryAdded":
+		h.recordLogEntry(api.ParseLogEntry(event.Params))
+	case "browsingContext.contextCreated":
+		h.trackPage(event.Method, event.Params)
+	case "browsingContext.contextDestroyed":
+		h.leaveDestroyedFrame(event.Params)
+		h.trackPage(event.Method, event.Params)
+	case "browsingContext.downloadWillBegin", "browsingContext.downloadEnd":
+		h.trackDownload(event.Method, event.Params)
+	case "browsingContext.userPromptOpened":
+		h.trackDialog(event.Method, event.Params)
+		h.handleDialog(event.Params)
+	case "browsingContext.userPromptClosed":
+		h.trackDialog(event.Method, event.Params)
+	}
+}
+
+// leaveDestroyedFrame falls back to the frame that contained a removed or
+// navigated-away frame, since it can't stay active.
+func (h *Handlers) leaveDestroyedFrame(params map[string]interface{}) {
+	context, _ := params["context"].(string)
+	for i, f := range h.frames {
+		if f == context {
+			h.frames = h.frames[:i]
+			break
+		}
+	}
+}
+
+// openedPage is a top-level context opened during the session.
+type openedPage struct {
+	Context string
+	Opened  time.Time
+}
+
+// closedPage is a top-level context that closed during the session.
+type closedPage struct {
+	Context string
+	URL     string
+	Closed  time.Time
+}
+
+// trackPage keeps h.openedPages, the top-level contexts opened during the
+// session (by browser_new_page, target=_blank links, or window.open) in
+// creation order, so browser_switch_page latest and browser_wait_for_page
+// can find new pages. Closed pages go to h.closedPages; when the active page
+// closes, commands fall back to the first remaining page and the current
+// call reports it (see Handlers.Call).
+func (h *Handlers) trackPage(method string, params map[string]interface{}) {
+	context, _ := params["context"].(string)
+	if context == "" || params["parent"] != nil {
+		return
+	}
+	if method == "browsingContext.contextDestroyed" {
+		url, _ := params["url"].(string)
+		h.closedPages = append(h.closedPages, closedPage{Context: context, URL: url, Closed: time.Now()})
+		if len(h.closedPages) > maxClosedPages {
+			h.closedPages = h.closedPages[len(h.closedPages)-maxClosedPages:]
+		}
+		if context == h.activeContext {
+			h.activeContext = ""
+			h.pageClosedNotice = fmt.Sprintf("the active page (%s) closed; now using the first remaining page", url)
+		}
+	}
+	for i, p := range h.openedPages {
+		if p.Context == context {
+			h.openedPages = append(h.openedPages[:i], h.openedPages[i+1:]...)
+			break
+		}
+	}
+	if method == "browsingContext.contextCreated" {
+		h.openedPages = append(h.openedPages, openedPage{Context: context, Opened: time.Now()})
+	}
+}
+
+// browserWaitForPage waits for a page to open, optionally one whose URL
+// matches a pattern, and returns its index, URL, and context. Pages opened
+// during the previous tool call (typically the click that opened a popup)
+// also count.
+func (h *Handlers) browserWaitForPage(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	pattern, _ := args["url"].(string)
+	regex, _ := args["regex"].(bool)
+	match := func(string) bool { return true }
+	if pattern != "" {
+		m, err := api.URLMatcher(pattern, regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", pattern, err)
+		}
+		match = m
+	}
+
+	timeout := api.DefaultTimeout
+	if t, ok := args["timeout"].(float64); ok && t > 0 {
+		timeout = time.Duration(t) * time.Millisecond
+	}
+
+	since := h.prevCallStart
+	if since.IsZero() {
+		since = h.callStart
+	}
+	s := h.newSession()
+	deadline := time.Now().Add(timeout)
+	for {
+		if err := h.flushEvents(); err != nil {
+			return nil, err
+		}
+		pages, err := api.ListPages(s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pages: %w", err)
+		}
+		for i := len(h.openedPages) - 1; i >= 0; i-- {
+			opened := h.openedPages[i]
+			if opened.Opened.Before(since) {
+				break
+			}
+			for index, page := range pages {
+				if page.Context != opened.Context || !match(page.URL) {
+					continue
+				}
+				data, _ := json.Marshal(map[string]interface{}{
+					"index":   index,
+					"url":     page.URL,
+					"context": page.Context,
+				})
+				return &ToolsCallResult{
+					Content: []Content{{Type: "text", Text: string(data)}},
+				}, nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			if pattern != "" {
+				return nil, fmt.Errorf("timeout after %s waiting for a page matching %q", timeout, pattern)
+			}
+			return nil, fmt.Errorf("timeout after %s waiting for a new page", timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// trackRequest buffers completed requests for browser_wait_for_response.
+func (h *Handlers) trackRequest(method string, params map[string]interface{}) {
+	if h.requests == nil {
+		return
+	}
+	completed := h.requests.Track(method, params)
+	if completed == nil {
+		return
+	}
+	if h.har != nil {
+		h.har.Add(completed)
+	}
+	if h.mockRecord != nil && !completed.Failed && h.mockRecord.matches(completed.URL) {
+		h.mockRecord.pending = append(h.mockRecord.pending, completed)
+	}
+	h.responses = append(h.responses, completed)
+	if len(h.responses) > maxBufferedResponses {
+		h.responses = h.responses[len(h.responses)-maxBufferedResponses:]
+	}
+}
+
+// recordLogEntry buffers console messages for browser_console_logs and
+// uncaught exceptions for browser_page_errors. Unhandled promise rejections
+// are reported as "javascript" entries too ("Uncaught (in promise) ...").
+func (h *Handlers) recordLogEntry(entry *api.LogEntry) {
+	switch entry.Type {
+	case "console":
+		h.consoleLogs = append(h.consoleLogs, entry)
+		if len(h.consoleLogs) > maxConsoleLogs {
+			h.consoleLogs = h.consoleLogs[len(h.consoleLogs)-maxConsoleLogs:]
+		}
+	case "javascript":
+		h.pageErrors = append(h.pageErrors, entry)
+		if len(h.pageErrors) > maxPageErrors {
+			h.pageErrors = h.pageErrors[len(h.pageErrors)-maxPageErrors:]
+		}
+	}
+}
+
+// flushEvents delivers events that arrived since the last command. Events are
+// only read while a command is in flight, so a cheap status round-trip is
+// enough to drain them.
+func (h *Handlers) flushEvents() error {
+	_, err := h.client.SendCommand("session.status", map[string]interface{}{})
+	return err
+}
+
+// subscribe subscribes the session to BiDi events it is not yet subscribed to.
+func (h *Handlers) subscribe(events ...string) error {
+	if h.subscribed == nil {
+		h.subscribed = make(map[string]bool)
+	}
+	var pending []string
+	for _, e := range events {
+		if !h.subscribed[e] {
+			pending = append(pending, e)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	if _, err := h.client.SendCommand("session.subscribe", map[string]interface{}{
+		"events": pending,
+	}); err != nil {
+		return err
+	}
+	for _, e := range pending {
+		h.subscribed[e] = true
+	}
+	return nil
+}
+
+// resolveInterceptedRequest completes a request blocked by the session's
+// intercept. While offline (without network emulation) every request fails;
+// otherwise a saved mock response is served during mock replay, then the most
+// recently added matching rule applies, then any browser_block_urls pattern.
+// Requests that continue to the network get the browser_set_extra_headers
+// and browser_set_basic_auth headers scoped to them.
+func (h *Handlers) resolveInterceptedRequest(params map[string]interface{}) {
+	if blocked, _ := params["isBlocked"].(bool); !blocked || h.interceptID == "" {
+		return
+	}
+	ours := false
+	intercepts, _ := params["intercepts"].([]interface{})
+	for _, id := range intercepts {
+		if id == h.interceptID {
+			ours = true
+			break
+		}
+	}
+	if !ours {
+		return
+	}
+
+	req, _ := params["request"].(map[string]interface{})
+	requestID, _ := req["request"].(string)
+	url, _ := req["url"].(string)
+	if requestID == "" {
+		return
+	}
+
+	if h.offline == "intercept" {
+		h.client.SendCommandNoWait("network.failRequest", map[string]interface{}{"request": requestID})
+		return
+	}
+	if h.mockReplay != nil && h.mockReplay.matches(url) {
+		method, _ := req["method"].(string)
+		if mock := h.mockReplay.mocks[api.MockKey(method, url)]; mock != nil {
+			h.mockReplay.served++
+			h.client.SendCommandNoWait("network.provideResponse", mock.ProvideResponseParams(requestID))
+			return
+		}
+		if h.mockReplay.strict {
+			h.mockReplay.missed++
+			h.client.SendCommandNoWait("network.failRequest", map[string]interface{}{"request": requestID})
+			return
+		}
+	}
+
+	var match *api.InterceptRule
+	for i := len(h.intercepts) - 1; i >= 0; i-- {
+		if h.intercepts[i].Matches(url) {
+			match = h.intercepts[i]
+			break
+		}
+	}
+	if match == nil {
+		for _, rule := range h.blockedURLs {
+			if rule.Matches(url) {
+				match = rule
+				break
+			}
+		}
+	}
+	method, cmdParams := match.Resolve(requestID)
+	if method == "network.continueRequest" {
+		if extra := h.headersFor(url); len(extra) > 0 {
+			headers, _ := req["headers"].([]interface{})
+			cmdParams["headers"] = api.MergeBidiHeaders(headers, extra)
+		}
+	}
+	h.client.SendCommandNoWait(method, cmdParams)
+}
+
+// browserRequestIntercept adds a rule that blocks or fulfills
+// requests matching a URL pattern. Rules persist until removed.
+func (h *Handlers) browserRequestIntercept(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	rule, err := api.NewInterceptRule(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.ensureIntercept(); err != nil {
+		return nil, err
+	}
+	h.intercepts = append(h.intercepts, rule)
+
+	text := fmt.Sprintf("Intercepting %q (%s)", rule.Pattern, rule.Action)
+	if rule.Action == "fulfill" {
+		text = fmt.Sprintf("Intercepting %q (fulfill with status %d)", rule.Pattern, rule.Status)
+	}
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("%s — %d active rule(s)", text, len(h.intercepts)),
+		}},
+	}, nil
+}
+
+// browserRequestUnintercept removes intercept rules with the given pattern,
+// or all rules when no pattern is given.
+func (h *Handlers) browserRequestUnintercept(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	pattern, _ := args["pattern"].(string)
+	kept := h.intercepts[:0]
+	removed := 0
+	for _, rule := range h.intercepts {
+		if pattern == "" || rule.Pattern == pattern {
+			removed++
+			continue
+		}
+		kept = append(kept, rule)
+	}
+	h.intercepts = kept
+
+	if err := h.releaseIntercept(); err != nil {
+		return nil, err
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Removed %d rule(s) — %d active rule(s)", removed, len(h.intercepts)),
+		}},
+	}, nil
+}
+
+// browserBlockURLs fails requests whose URL matches any of the given
+// patterns. Patterns persist across navigations until removed with
+// browser_unblock_urls.
+func (h *Handlers) browserBlockURLs(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	patterns, _ := args["patterns"].([]interface{})
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("patterns is required")
+	}
+	var rules []*api.InterceptRule
+	for _, p := range patterns {
+		pattern, _ := p.(string)
+		rule, err := api.NewInterceptRule(map[string]interface{}{"pattern": pattern, "action": "block"})
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := h.ensureIntercept(); err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if !h.isBlocked(rule.Pattern) {
+			h.blockedURLs = append(h.blockedURLs, rule)
+		}
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Blocking %d pattern(s): %s", len(h.blockedURLs), h.blockedPatterns()),
+		}},
+	}, nil
+}
+
+// browserUnblockURLs removes the given block patterns, or all of them when
+// none are given.
+func (h *Handlers) browserUnblockURLs(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	remove := map[string]bool{}
+	patterns, _ := args["patterns"].([]interface{})
+	for _, p := range patterns {
+		if pattern, ok := p.(string); ok {
+			remove[pattern] = true
+		}
+	}
+	kept := h.blockedURLs[:0]
+	removed := 0
+	for _, rule := range h.blockedURLs {
+		if len(remove) == 0 || remove[rule.Pattern] {
+			removed++
+			continue
+		}
+		kept = append(kept, rule)
+	}
+	h.blockedURLs = kept
+
+	if err := h.releaseIntercept(); err != nil {
+		return nil, err
+	}
+
+	text := fmt.Sprintf("Unblocked %d pattern(s) — %d still blocked", removed, len(h.blockedURLs))
+	if len(h.blockedURLs) > 0 {
+		text += ": " + h.blockedPatterns()
+	}
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: text,
+		}},
+	}, nil
+}
+
+// isBlocked reports whether pattern is already a browser_block_urls pattern.
+func (h *Handlers) isBlocked(pattern string) bool {
+	for _, rule := range h.blockedURLs {
+		if rule.Pattern == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// blockedPatterns lists the browser_block_urls patterns for tool output.
+func (h *Handlers) blockedPatterns() string {
+	patterns := make([]string, len(h.blockedURLs))
+	for i, rule := range h.blockedURLs {
+		patterns[i] = fmt.Sprintf("%q", rule.Pattern)
+	}
+	return strings.Join(patterns, ", ")
+}
+
+// ensureIntercept adds the session's BiDi intercept if it isn't active yet.
+// A single intercept in the beforeRequestSent phase backs both intercept
+// rules, blocked URLs, fallback offline mode, mock replay and extra headers;
+// resolveInterceptedRequest decides per request.
+func (h *Handlers) ensureIntercept() error {
+	if h.interceptID != "" {
+		return nil
+	}
+	if err := h.subscribe("network.beforeRequestSent"); err != nil {
+		return fmt.Errorf("failed to subscribe to network events: %w", err)
+	}
+	msg, err := h.client.SendCommand("network.addIntercept", map[string]interface{}{
+		"phases": []string{"beforeRequestSent"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add intercept: %w", err)
+	}
+	var result struct {
+		Intercept string `json:"intercept"`
+	}
+	if err := json.Unmarshal(msg.Result, &result); err != nil {
+		return fmt.Errorf("failed to parse addIntercept response: %w", err)
+	}
+	h.interceptID = result.Intercept
+	h.startInterceptPump()
+	return nil
+}
+
+// releaseIntercept removes the session's BiDi intercept once no intercept
+// rules, blocked URLs, offline mode, mock replay or extra headers need it, so
+// requests stop pausing.
+func (h *Handlers) releaseIntercept() error {
+	if len(h.intercepts) > 0 || len(h.blockedURLs) > 0 || h.offline == "intercept" || h.mockReplay != nil || h.extraHeaders != nil || h.basicAuth != nil || h.interceptID == "" {
+		return nil
+	}
+	if _, err := h.client.SendCommand("network.removeIntercept", map[string]interface{}{
+		"intercept": h.interceptID,
+	}); err != nil {
+		return fmt.Errorf("failed to remove intercept: %w", err)
+	}
+	h.interceptID = ""
+	h.stopInterceptPump()
+	return nil
+}
+
+// startInterceptPump drains events between tool calls, so requests paused
+// by the session's intercept are resolved without waiting for the next call.
+// The BiDi client isn't safe for concurrent use, so like the recording
+// screenshot loop it only talks to the browser while Call isn't.
+func (h *Handlers) startInterceptPump() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	h.interceptPump = stop
+	h.interceptDone = done
+	client := h.client
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interceptPumpInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			if !h.busy.TryLock() {
+				continue
+			}
+			client.SendCommandWithTimeout("session.status", map[string]interface{}{}, 5*time.Second)
+			h.busy.Unlock()
+		}
+	}()
+}
+
+// stopInterceptPump stops the intercept pump and waits for it to exit.
+func (h *Handlers) stopInterceptPump() {
+	if h.interceptPump == nil {
+		return
+	}
+	close(h.interceptPump)
+	<-h.interceptDone
+	h.interceptPump = nil
+	h.interceptDone = nil
+}
+
+// scopedHeaders are extra request headers and the requests they're sent with.
+type scopedHeaders struct {
+	headers map[string]string
+	scope   *api.HeaderScope
+}
+
+// headersFor returns the extra headers to send with a request: the
+// browser_set_extra_headers headers, then the browser_set_basic_auth
+// Authorization header, each only when the request is in its scope.
+func (h *Handlers) headersFor(url string) map[string]string {
+	extra := map[string]string{}
+	for _, set := range []*scopedHeaders{h.extraHeaders, h.basicAuth} {
+		if set == nil || !set.scope.Matches(url) {
+			continue
+		}
+		for name, value := range set.headers {
+			extra[name] = value
+		}
+	}
+	return extra
+}
+
+// headerScope builds the scope for extra headers from a tool's origin
+// argument, defaulting to the current page's origin.
+func (h *Handlers) headerScope(args map[string]interface{}) (*api.HeaderScope, error) {
+	origin, _ := args["origin"].(string)
+	if origin == "" {
+		s := h.newSession()
+		ctx, err := s.GetContextID()
+		if err != nil {
+			return nil, err
+		}
+		url, err := api.GetURL(s, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get URL: %w", err)
+		}
+		if origin, err = api.URLOrigin(url); err != nil {
+			return nil, fmt.Errorf("the current page (%s) has no origin to send headers to; navigate first or pass origin", url)
+		}
+	}
+	return api.NewHeaderScope(origin)
+}
+
+// browserSetExtraHeaders adds headers to requests for an origin (by default
+// the current page's), replacing the previous set. An empty map removes
+// them. Headers persist across navigations.
+func (h *Handlers) browserSetExtraHeaders(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	raw, ok := args["headers"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("headers is required")
+	}
+	headers := make(map[string]string, len(raw))
+	for name, val := range raw {
+		value, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("header %q must be a string", name)
+		}
+		headers[name] = value
+	}
+
+	if len(headers) == 0 {
+		h.extraHeaders = nil
+		if err := h.releaseIntercept(); err != nil {
+			return nil, err
+		}
+		return &ToolsCallResult{
+			Content: []Content{{
+				Type: "text",
+				Text: "Extra headers cleared",
+			}},
+		}, nil
+	}
+
+	scope, err := h.headerScope(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.ensureIntercept(); err != nil {
+		return nil, err
+	}
+	h.extraHeaders = &scopedHeaders{headers: headers, scope: scope}
+	// The most recent Authorization header wins
+	if _, ok := headerValue(headers, "Authorization"); ok {
+		h.basicAuth = nil
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Sending %d extra header(s) to %s: %s", len(names), scope.Origin, strings.Join(names, ", ")),
+		}},
+	}, nil
+}
+
+// browserSetBasicAuth sends HTTP Basic credentials as an Authorization
+// header with requests for an origin (by default the current page's). An
+// empty username removes it.
+func (h *Handlers) browserSetBasicAuth(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	username, _ := args["username"].(string)
+	password, _ := args["password"].(string)
+
+	if username == "" {
+		h.basicAuth = nil
+		if err := h.releaseIntercept(); err != nil {
+			return nil, err
+		}
+		return &ToolsCallResult{
+			Content: []Content{{
+				Type: "text",
+				Text: "Basic auth cleared",
+			}},
+		}, nil
+	}
+
+	scope, err := h.headerScope(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.ensureIntercept(); err != nil {
+		return nil, err
+	}
+	h.basicAuth = &scopedHeaders{
+		headers: map[string]string{
+			"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)),
+		},
+		scope: scope,
+	}
+	// The most recent Authorization header wins
+	if h.extraHeaders != nil {
+		for name := range h.extraHeaders.headers {
+			if strings.EqualFold(name, "Authorization") {
+				delete(h.extraHeaders.headers, name)
+			}
+		}
+		if len(h.extraHeaders.headers) == 0 {
+			h.extraHeaders = nil
+		}
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Sending Basic auth for %q to %s", username, scope.Origin),
+		}},
+	}, nil
+}
+
+// headerValue looks up a header by name, case-insensitively.
+func headerValue(headers map[string]string, name string) (string, bool) {
+	for n, v := range headers {
+		if strings.EqualFold(n, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// mockPatterns holds the URL patterns a mock recording or replay applies to.
+// An empty list matches every request.
+type mockPatterns []*api.InterceptRule
+
+// newMockPatterns builds matchers from a tool's patterns argument.
+func newMockPatterns(args map[string]interface{}) (mockPatterns, error) {
+	raw, _ := args["patterns"].([]interface{})
+	var patterns mockPatterns
+	for _, p := range raw {
+		pattern, _ := p.(string)
+		rule, err := api.NewInterceptRule(map[string]interface{}{"pattern": pattern})
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, rule)
+	}
+	return patterns, nil
+}
+
+func (p mockPatterns) matches(url string) bool {
+	if len(p) == 0 {
+		return true
+	}
+	for _, rule := range p {
+		if rule.Matches(url) {
+			return true
+		}
+	}
+	return false
+}
+
+// String lists the patterns for tool output.
+func (p mockPatterns) String() string {
+	if len(p) == 0 {
+		return "all requests"
+	}
+	patterns := make([]string, len(p))
+	for i, rule := range p {
+		patterns[i] = fmt.Sprintf("%q", rule.Pattern)
+	}
+	return strings.Join(patterns, ", ")
+}
+
+// mockRecording saves responses matching its patterns to dir.
+type mockRecording struct {
+	mockPatterns
+	dir       string
+	collector string                  // BiDi data collector keeping response bodies
+	pending   []*api.CompletedRequest // completed since the last flush
+	saved     int
+	errors    int
+}
+
+// mockReplay answers requests matching its patterns with saved responses.
+type mockReplay struct {
+	mockPatterns
+	dir    string
+	mocks  map[string]*api.MockResponse // by api.MockKey
+	strict bool                         // fail matching requests that have no saved response
+	served int
+	missed int
+}
+
+// browserMockRecord starts saving responses for matching URLs to a
+// directory, one file per method and URL, for browser_mock_replay.
+func (h *Handlers) browserMockRecord(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+	if h.mockRecord != nil || h.mockReplay != nil {
+		return nil, fmt.Errorf("mock %s already in progress (stop it with browser_mock_stop)", h.mockMode())
+	}
+
+	dir, _ := args["dir"].(string)
+	if dir == "" {
+		return nil, fmt.Errorf("dir is required")
+	}
+	patterns, err := newMockPatterns(args)
+	if err != nil {
+		return nil, err
+	}
+
+	collector, err := api.AddResponseCollector(h.newSession())
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect response bodies (browser may not support network.addDataCollector): %w", err)
+	}
+	h.mockRecord = &mockRecording{mockPatterns: patterns, dir: dir, collector: collector}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Recording responses for %s to %s", patterns, dir),
+		}},
+	}, nil
+}
+
+// flushMockRecording saves the responses completed since the last flush.
+// Bodies are read from the browser here rather than in the event handler,
+// which can't wait for command responses.
+func (h *Handlers) flushMockRecording() {
+	rec := h.mockRecord
+	if rec == nil || len(rec.pending) == 0 {
+		return
+	}
+	s := h.newSession()
+	for _, c := range rec.pending {
+		body, err := api.GetResponseBody(s, rec.collector, c.RequestID)
+		if err != nil {
+			// Redirects and bodiless responses have no data
+			body = nil
+		}
+		if err := api.NewMockResponse(c, body).Save(rec.dir); err != nil {
+			rec.errors++
+			continue
+		}
+		rec.saved++
+	}
+	rec.pending = nil
+}
+
+// browserMockReplay serves responses saved by browser_mock_record instead
+// of hitting the network. Requests without a saved response go to the
+// network, or fail when strict is set.
+func (h *Handlers) browserMockReplay(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+	if h.mockRecord != nil || h.mockReplay != nil {
+		return nil, fmt.Errorf("mock %s already in progress (stop it with browser_mock_stop)", h.mockMode())
+	}
+
+	dir, _ := args["dir"].(string)
+	if dir == "" {
+		return nil, fmt.Errorf("dir is required")
+	}
+	patterns, err := newMockPatterns(args)
+	if err != nil {
+		return nil, err
+	}
+	mocks, err := api.LoadMocks(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mocks: %w", err)
+	}
+	if len(mocks) == 0 {
+		return nil, fmt.Errorf("no saved responses in %s (record some with browser_mock_record)", dir)
+	}
+
+	if err := h.ensureIntercept(); err != nil {
+		return nil, err
+	}
+	strict, _ := args["strict"].(bool)
+	h.mockReplay = &mockReplay{mockPatterns: patterns, dir: dir, mocks: mocks, strict: strict}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Replaying %d saved response(s) from %s for %s", len(mocks), dir, patterns),
+		}},
+	}, nil
+}
+
+// browserMockStop ends mock recording or replay.
+func (h *Handlers) browserMockStop(args map[string]interface{}) (*ToolsCallResult, error) {
+	var text string
+	switch {
+	case h.mockRecord != nil:
+		h.flushMockRecording()
+		rec := h.mockRecord
+		h.mockRecord = nil
+		if h.client != nil {
+			api.RemoveDataCollector(h.newSession(), rec.collector)
+		}
+		text = fmt.Sprintf("Saved %d response(s) to %s", rec.saved, rec.dir)
+		if rec.errors > 0 {
+			text += fmt.Sprintf(" (%d failed to save)", rec.errors)
+		}
+	case h.mockReplay != nil:
+		replay := h.mockReplay
+		h.mockReplay = nil
+		if h.client != nil {
+			if err := h.releaseIntercept(); err != nil {
+				return nil, err
+			}
+		}
+		text = fmt.Sprintf("Served %d saved response(s) from %s", replay.served, replay.dir)
+		if replay.missed > 0 {
+			text += fmt.Sprintf("; failed %d request(s) with no saved response", replay.missed)
+		}
+	default:
+		return nil, fmt.Errorf("no mock recording or replay in progress")
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: text,
+		}},
+	}, nil
+}
+
+// mockMode names the active mock mode for error messages.
+func (h *Handlers) mockMode() string {
+	if h.mockRecord != nil {
+		return "recording"
+	}
+	return "replay"
+}
+
+// browserWaitForResponse waits for a network response whose URL matches a
+// pattern. Responses that completed during the previous tool call (typically
+// the click or navigation that triggered the request) also count.
+func (h *Handlers) browserWaitForResponse(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	pattern, _ := args["pattern"].(string)
+	if pattern == "" {
+		return nil, fmt.Errorf("pattern is required")
+	}
+	regex, _ := args["regex"].(bool)
+	match, err := api.URLMatcher(pattern, regex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %q: %w", pattern, err)
+	}
+
+	timeout := api.DefaultTimeout
+	if t, ok := args["timeout"].(float64); ok && t > 0 {
+		timeout = time.Duration(t) * time.Millisecond
+	}
+
+	since := h.prevCallStart
+	if since.IsZero() {
+		since = h.callStart
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		for i, resp := range h.responses {
+			if resp.Completed.Before(since) || !match(resp.URL) {
+				continue
+			}
+			h.responses = append(h.responses[:i], h.responses[i+1:]...)
+			if resp.Failed {
+				return nil, fmt.Errorf("request to %s failed: %s", resp.URL, resp.ErrorText)
+			}
+			data, _ := json.Marshal(map[string]interface{}{
+				"url":        resp.URL,
+				"method":     resp.Method,
+				"status":     resp.Status,
+				"statusText": resp.StatusText,
+				"headers":    resp.Headers,
+			})
+			return &ToolsCallResult{
+				Content: []Content{{Type: "text", Text: string(data)}},
+			}, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timeout after %s waiting for response matching %q", timeout, pattern)
+		}
+		time.Sleep(100 * time.Millisecond)
+		if err := h.flushEvents(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// browserConsoleLogs returns console messages captured since launch (or the
+// last clear), optionally filtered by level.
+func (h *Handlers) browserConsoleLogs(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	level, _ := args["level"].(string)
+	switch level {
+	case "", "debug", "info", "warn", "error":
+	default:
+		return nil, fmt.Errorf("unknown level %q (use debug, info, warn, or error)", level)
+	}
+
+	if err := h.flushEvents(); err != nil {
+		return nil, err
+	}
+
+	entries := []*api.LogEntry{}
+	for _, entry := range h.consoleLogs {
+		if level == "" || entry.Level == level {
+			entries = append(entries, entry)
+		}
+	}
+	if clear, _ := args["clear"].(bool); clear {
+		h.consoleLogs = nil
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal console logs: %w", err)
+	}
+	return &ToolsCallResult{
+		Content: []Content{{Type: "text", Text: string(data)}},
+	}, nil
+}
+
+// browserPageErrors returns uncaught exceptions and unhandled promise
+// rejections captured since launch (or the last clear), with stack traces.
+func (h *Handlers) browserPageErrors(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	if err := h.flushEvents(); err != nil {
+		return nil, err
+	}
+
+	entries := h.pageErrors
+	if entries == nil {
+		entries = []*api.LogEntry{}
+	}
+	if clear, _ := args["clear"].(bool); clear {
+		h.pageErrors = nil
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal page errors: %w", err)
+	}
+	return &ToolsCallResult{
+		Content: []Content{{Type: "text", Text: string(data)}},
+	}, nil
+}
+
+// dialogPolicy is how browser_set_dialog_handler answers dialogs.
+type dialogPolicy struct {
+	Action string // "accept", "dismiss", or "accept-with-text"
+	Text   string // prompt() answer for "accept-with-text"
+}
+
+// openDialog is a dialog waiting to be answered.
+type openDialog struct {
+	Context      string `json:"-"`
+	Type         string `json:"type"` // "alert", "confirm", "prompt", or "beforeunload"
+	Message      string `json:"message"`
+	DefaultValue string `json:"defaultValue,omitempty"`
+}
+
+// trackDialog keeps h.dialog set to the most recent dialog while it is open.
+func (h *Handlers) trackDialog(method string, params map[string]interface{}) {
+	context, _ := params["context"].(string)
+	if method == "browsingContext.userPromptClosed" {
+		if h.dialog != nil && h.dialog.Context == context {
+			h.dialog = nil
+		}
+		return
+	}
+	d := &openDialog{Context: context}
+	d.Type, _ = params["type"].(string)
+	d.Message, _ = params["message"].(string)
+	d.DefaultValue, _ = params["defaultValue"].(string)
+	h.dialog = d
+}
+
+// browserGetDialog returns the open dialog's type and message, or null if no
+// dialog is open, so the agent can decide whether to accept or dismiss it.
+func (h *Handlers) browserGetDialog(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+	if err := h.flushEvents(); err != nil {
+		return nil, err
+	}
+
+	text := "null"
+	if h.dialog != nil {
+		data, err := json.Marshal(h.dialog)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode dialog: %w", err)
+		}
+		text = string(data)
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{Type: "text", Text: text}},
+	}, nil
+}
+
+// handleDialog answers a dialog according to the session's dialog policy,
+// so a click that opens a blocking alert() or confirm() doesn't hang. The
+// outcome is reported with the current tool call's result.
+func (h *Handlers) handleDialog(params map[string]interface{}) {
+	if h.dialogPolicy == nil {
+		return
+	}
+	context, _ := params["context"].(string)
+	dialogType, _ := params["type"].(string)
+	message, _ := params["message"].(string)
+
+	accept := h.dialogPolicy.Action != "dismiss"
+	cmd := map[string]interface{}{"context": context, "accept": accept}
+	if h.dialogPolicy.Action == "accept-with-text" && dialogType == "prompt" {
+		cmd["userText"] = h.dialogPolicy.Text
+	}
+	h.client.SendCommandNoWait("browsingContext.handleUserPrompt", cmd)
+
+	verb := "accepted"
+	if !accept {
+		verb = "dismissed"
+	}
+	h.dialogNotices = append(h.dialogNotices, fmt.Sprintf("auto-%s %s dialog %q", verb, dialogType, message))
+}
+
+// browserSetDialogHandler sets how dialogs are answered as they open:
+// accept, dismiss, accept-with-text (answers prompt() with text), or none to
+// leave them for browser_dialog_accept/browser_dialog_dismiss.
+func (h *Handlers) browserSetDialogHandler(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	policy, _ := args["policy"].(string)
+	text, _ := args["text"].(string)
+	switch policy {
+	case "none":
+		h.dialogPolicy = nil
+		return &ToolsCallResult{
+			Content: []Content{{Type: "text", Text: "Dialogs are no longer handled automatically"}},
+		}, nil
+	case "accept", "dismiss":
+	case "accept-with-text":
+		if _, ok := args["text"].(string); !ok {
+			return nil, fmt.Errorf("text is required for accept-with-text")
+		}
+	default:
+		return nil, fmt.Errorf("unknown policy %q (use accept, dismiss, accept-with-text, or none)", policy)
+	}
+
+	h.dialogPolicy = &dialogPolicy{Action: policy, Text: text}
+
+	msg := "Dialogs will be accepted automatically"
+	switch policy {
+	case "dismiss":
+		msg = "Dialogs will be dismissed automatically"
+	case "accept-with-text":
+		msg = fmt.Sprintf("Dialogs will be accepted automatically, answering prompts with %q", text)
+	}
+	return &ToolsCallResult{
+		Content: []Content{{Type: "text", Text: msg}},
+	}, nil
+}
+
+// maxDownloads caps the downloads remembered for browser_wait_for_download
+// and browser_list_downloads.
+const maxDownloads = 100
+
+// download is a file download seen through browsingContext.downloadWillBegin
+// and browsingContext.downloadEnd.
+type download struct {
+	Navigation        string // BiDi navigation ID shared by both events
+	URL               string
+	SuggestedFilename string
+	Started           time.Time
+	State             string // "in-progress", "complete", or "failed"
+	Path              string // set by downloadEnd when complete
+}
+
+// trackDownload records download progress from BiDi download events.
+func (h *Handlers) trackDownload(method string, params map[string]interface{}) {
+	navigation, _ := params["navigation"].(string)
+	if method == "browsingContext.downloadWillBegin" {
+		d := &download{Navigation: navigation, Started: time.Now(), State: "in-progress"}
+		d.URL, _ = params["url"].(string)
+		d.SuggestedFilename, _ = params["suggestedFilename"].(string)
+		h.downloads = append(h.downloads, d)
+		if len(h.downloads) > maxDownloads {
+			h.downloads = h.downloads[len(h.downloads)-maxDownloads:]
+		}
+		return
+	}
+
+	for i := len(h.downloads) - 1; i >= 0; i-- {
+		d := h.downloads[i]
+		if d.Navigation != navigation || d.State != "in-progress" {
+			continue
+		}
+		d.State = "failed"
+		if status, _ := params["status"].(string); status == "complete" {
+			d.State = "complete"
+		}
+		d.Path, _ = params["filepath"].(string)
+		if d.Path == "" && d.State == "complete" && h.downloadDir != "" {
+			d.Path = filepath.Join(h.downloadDir, d.SuggestedFilename)
+		}
+		return
+	}
+}
+
+// browserWaitForDownload waits for a download to finish and returns its
+// path and size. Downloads started during the previous tool call (typically
+// the click that triggered it) also count.
+func (h *Handlers) browserWaitForDownload(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	timeout := api.DefaultTimeout
+	if t, ok := args["timeout"].(float64); ok && t > 0 {
+		timeout = time.Duration(t) * time.Millisecond
+	}
+
+	since := h.prevCallStart
+	if since.IsZero() {
+		since = h.callStart
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		if err := h.flushEvents(); err != nil {
+			return nil, err
+		}
+
+		// Oldest first, so back-to-back waits return successive downloads
+		for _, d := range h.downloads {
+			if d.Started.Before(since) || d.State == "in-progress" {
+				continue
+			}
+			if d.State != "complete" {
+				return nil, fmt.Errorf("download of %s failed", d.URL)
+			}
+			size, err := waitForStableFile(d.Path, deadline)
+			if err != nil {
+				return nil, err
+			}
+			data, _ := json.Marshal(map[string]interface{}{
+				"url":               d.URL,
+				"suggestedFilename": d.SuggestedFilename,
+				"path":              d.Path,
+				"size":              size,
+			})
+			return &ToolsCallResult{
+				Content: []Content{{Type: "text", Text: string(data)}},
+			}, nil
+		}
+
+		if time.Now().After(deadline) {
+			for _, d := range h.downloads {
+				if !d.Started.Before(since) && d.State == "in-progress" {
+					return nil, fmt.Errorf("timeout after %s waiting for download of %s to finish", timeout, d.URL)
+				}
+			}
+			return nil, fmt.Errorf("timeout after %s waiting for a download", timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// bytes returns how much of the download is on disk: the saved file's size
+// once complete, or the partial .crdownload file's size while in progress
+// (when the download directory is known). It returns -1 when unknown.
+func (d *download) bytes(downloadDir string) int64 {
+	path := d.Path
+	if d.State == "in-progress" && downloadDir != "" && d.SuggestedFilename != "" {
+		path = filepath.Join(downloadDir, d.SuggestedFilename+".crdownload")
+	}
+	if path == "" {
+		return -1
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return -1
+	}
+	return info.Size()
+}
+
+// browserListDownloads returns the downloads seen this session, oldest
+// first, with their state and bytes on disk.
+func (h *Handlers) browserListDownloads(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	if err := h.flushEvents(); err != nil {
+		return nil, err
+	}
+
+	type downloadEntry struct {
+		URL               string `json:"url"`
+		SuggestedFilename string `json:"suggestedFilename"`
+		State             string `json:"state"`
+		Path              string `json:"path,omitempty"`
+		Bytes             int64  `json:"bytes"`
+		Started           string `json:"started"`
+	}
+	entries := []downloadEntry{}
+	for _, d := range h.downloads {
+		entries = append(entries, downloadEntry{
+			URL:               d.URL,
+			SuggestedFilename: d.SuggestedFilename,
+			State:             d.State,
+			Path:              d.Path,
+			Bytes:             d.bytes(h.downloadDir),
+			Started:           d.Started.Format(time.RFC3339),
+		})
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal downloads: %w", err)
+	}
+	return &ToolsCallResult{
+		Content: []Content{{Type: "text", Text: string(data)}},
+	}, nil
+}
+
+// waitForStableFile waits until path exists and its size stops changing,
+// and returns the size.
+func waitForStableFile(path string, deadline time.Time) (int64, error) {
+	if path == "" {
+		return 0, fmt.Errorf("download finished but the browser did not report where it was saved (set a directory with browser_download_set_dir)")
+	}
+	last := int64(-1)
+	for {
+		info, err := os.Stat(path)
+		if err == nil {
+			if info.Size() == last {
+				return last, nil
+			}
+			last = info.Size()
+		}
+		if time.Now().After(deadline) {
+			if err != nil {
+				return 0, fmt.Errorf("download finished but %s was not found: %w", path, err)
+			}
+			return last, nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,274 @@
+package agent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vibium/clicker/internal/bidi"
+)
+
+// fakeBrowser is a BiDi endpoint that answers every command with an empty
+// success result, or with reply's result when it returns one, and records
+// the commands it receives. Tests push events with send.
+type fakeBrowser struct {
+	mu       sync.Mutex
+	conn     *websocket.Conn
+	commands []map[string]interface{}
+	reply    func(cmd map[string]interface{}) map[string]interface{}
+}
+
+func newFakeBrowser(t *testing.T) (*fakeBrowser, *bidi.Connection) {
+	fb := &fakeBrowser{}
+	upgrader := websocket.Upgrader{}
+	connected := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		fb.mu.Lock()
+		fb.conn = conn
+		fb.mu.Unlock()
+		close(connected)
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var cmd map[string]interface{}
+			json.Unmarshal(data, &cmd)
+			fb.mu.Lock()
+			fb.commands = append(fb.commands, cmd)
+			reply := fb.reply
+			fb.mu.Unlock()
+
+			result := map[string]interface{}{}
+			if cmd["method"] == "network.addIntercept" {
+				result["intercept"] = "intercept-1"
+			}
+			if reply != nil {
+				if r := reply(cmd); r != nil {
+					result = r
+				}
+			}
+			fb.send(map[string]interface{}{"id": cmd["id"], "type": "success", "result": result})
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	conn, err := bidi.Connect("ws" + strings.TrimPrefix(srv.URL, "http"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-connected
+	return fb, conn
+}
+
+func (fb *fakeBrowser) send(msg map[string]interface{}) {
+	data, _ := json.Marshal(msg)
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+	fb.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// waitFor returns the first command with the given method, waiting up to a
+// second for it to arrive.
+func (fb *fakeBrowser) waitFor(method string) map[string]interface{} {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		fb.mu.Lock()
+		for _, cmd := range fb.commands {
+			if cmd["method"] == method {
+				fb.mu.Unlock()
+				return cmd
+			}
+		}
+		fb.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	return nil
+}
+
+// newFakeHandlers returns Handlers connected to a fakeBrowser.
+func newFakeHandlers(t *testing.T) (*Handlers, *fakeBrowser) {
+	fb, conn := newFakeBrowser(t)
+	h := NewHandlers("", true, "", nil)
+	h.conn = conn
+	h.client = bidi.NewClient(conn)
+	h.startEventCapture()
+	t.Cleanup(h.Close)
+	return h, fb
+}
+
+// blockedRequest builds a network.beforeRequestSent event paused by the
+// fake browser's intercept.
+func blockedRequest(id, url string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":   "event",
+		"method": "network.beforeRequestSent",
+		"params": map[string]interface{}{
+			"isBlocked":  true,
+			"intercepts": []interface{}{"intercept-1"},
+			"request": map[string]interface{}{
+				"request": id,
+				"url":     url,
+				"method":  "GET",
+				"headers": []interface{}{},
+			},
+		},
+	}
+}
+
+func TestInterceptResolvesRequestsBetweenCalls(t *testing.T) {
+	h, fb := newFakeHandlers(t)
+
+	if _, err := h.Call("browser_request_intercept", map[string]interface{}{"pattern": "*.png"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// No tool call is running; the intercept pump must still resolve these.
+	fb.send(blockedRequest("r1", "https://example.com/app.js"))
+	fb.send(blockedRequest("r2", "https://example.com/logo.png"))
+
+	cont := fb.waitFor("network.continueRequest")
+	if cont == nil {
+		t.Fatal("unmatched request was not continued between tool calls")
+	}
+	if got := cont["params"].(map[string]interface{})["request"]; got != "r1" {
+		t.Errorf("continued request %v, want r1", got)
+	}
+	fail := fb.waitFor("network.failRequest")
+	if fail == nil {
+		t.Fatal("blocked request was not failed between tool calls")
+	}
+	if got := fail["params"].(map[string]interface{})["request"]; got != "r2" {
+		t.Errorf("failed request %v, want r2", got)
+	}
+}
+
+func TestInterceptPumpStopsWithLastRule(t *testing.T) {
+	h, _ := newFakeHandlers(t)
+
+	if _, err := h.Call("browser_request_intercept", map[string]interface{}{"pattern": "*.png"}); err != nil {
+		t.Fatal(err)
+	}
+	if h.interceptPump == nil {
+		t.Fatal("intercept pump not started")
+	}
+	if _, err := h.Call("browser_request_unintercept", map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if h.interceptPump != nil || h.interceptID != "" {
+		t.Error("intercept still active after removing the last rule")
+	}
+}
+
+func TestInterceptAbortFailsRequests(t *testing.T) {
+	h, fb := newFakeHandlers(t)
+
+	if _, err := h.Call("browser_request_intercept", map[string]interface{}{"pattern": "*.png", "action": "abort"}); err != nil {
+		t.Fatal(err)
+	}
+
+	fb.send(blockedRequest("r1", "https://example.com/logo.png"))
+	fail := fb.waitFor("network.failRequest")
+	if fail == nil {
+		t.Fatal("aborted request was not failed")
+	}
+	if got := fail["params"].(map[string]interface{})["request"]; got != "r1" {
+		t.Errorf("failed request %v, want r1", got)
+	}
+}
+
+func TestBasicAuthOnlySentToItsOrigin(t *testing.T) {
+	h, fb := newFakeHandlers(t)
+
+	_, err := h.Call("browser_set_basic_auth", map[string]interface{}{
+		"username": "admin",
+		"password": "s3cret",
+		"origin":   "https://staging.example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fb.send(blockedRequest("cdn", "https://cdn.example.net/lib.js"))
+	fb.send(blockedRequest("app", "https://staging.example.com/api/user"))
+
+	deadline := time.Now().Add(time.Second)
+	continued := map[string]map[string]interface{}{}
+	for len(continued) < 2 && time.Now().Before(deadline) {
+		fb.mu.Lock()
+		for _, cmd := range fb.commands {
+			if cmd["method"] == "network.continueRequest" {
+				params := cmd["params"].(map[string]interface{})
+				continued[params["request"].(string)] = params
+			}
+		}
+		fb.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(continued) != 2 {
+		t.Fatalf("continued %d request(s), want 2", len(continued))
+	}
+	if _, ok := continued["cdn"]["headers"]; ok {
+		t.Error("credentials sent to a third-party origin")
+	}
+	headers, _ := continued["app"]["headers"].([]interface{})
+	if len(headers) != 1 || headers[0].(map[string]interface{})["name"] != "Authorization" {
+		t.Errorf("headers for the scoped origin = %v, want Authorization", headers)
+	}
+}
+
+func TestActivePageCloseFallsBack(t *testing.T) {
+	h, fb := newFakeHandlers(t)
+	fb.reply = func(cmd map[string]interface{}) map[string]interface{} {
+		if cmd["method"] == "browsingContext.getTree" {
+			return map[string]interface{}{"contexts": []interface{}{
+				map[string]interface{}{"context": "ctx-2", "url": "https://example.com/", "children": []interface{}{}},
+			}}
+		}
+		return nil
+	}
+	h.activeContext = "ctx-1"
+
+	// The popup closes itself between tool calls.
+	fb.send(map[string]interface{}{
+		"type":   "event",
+		"method": "browsingContext.contextDestroyed",
+		"params": map[string]interface{}{"context": "ctx-1", "url": "https://auth.example.com/done", "children": []interface{}{}},
+	})
+
+	result, err := h.Call("browser_list_pages", map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.activeContext != "" {
+		t.Errorf("activeContext = %q, want the fallback to the first page", h.activeContext)
+	}
+	if len(result.Content) != 2 {
+		t.Fatalf("content = %+v, want the page list and a note", result.Content)
+	}
+	if got := result.Content[0].Text; !strings.HasPrefix(got, "[0] https://example.com/\n[closed ") || !strings.HasSuffix(got, "] https://auth.example.com/done\n") {
+		t.Errorf("list = %q, want the open page and the closed popup", got)
+	}
+	if want := "Note: the active page (https://auth.example.com/done) closed; now using the first remaining page"; result.Content[1].Text != want {
+		t.Errorf("note = %q, want %q", result.Content[1].Text, want)
+	}
+
+	// The notice is reported once.
+	result, err = h.Call("browser_list_pages", map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Content) != 1 {
+		t.Errorf("content = %+v, want no note on the next call", result.Content)
+	}
+}
@@ -1,18 +1,23 @@
 package agent
 
 import (
+	"crypto/sha1"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/vibium/clicker/internal/bidi"
 	"github.com/vibium/clicker/internal/browser"
 	"github.com/vibium/clicker/internal/log"
+	"github.com/vibium/clicker/internal/paths"
 	"github.com/vibium/clicker/internal/api"
 )
 
@@ -22,15 +27,49 @@ type Handlers struct {
 	client         *bidi.Client
 	conn           *bidi.Connection
 	screenshotDir  string
+	screenshotRoot string // screenshotDir as configured at startup; browser_set_screenshot_dir stays inside it
 	headless       bool
 	connectURL     string      // remote BiDi WebSocket URL (empty = local browser)
 	connectHeaders http.Header // headers for remote WebSocket connection
 	refMap         map[string]string // @e1 -> CSS selector
-	lastMap        string            // last map output (for diff)
+	lastMap        []mapEntry        // last map's elements (for diff); nil = no map yet
+	mapRefs        map[string]string // stable-ref maps: element key -> @ref
+	nextMapRef     int               // last @ref number assigned in mapRefs
 	recorder       *api.Recorder
 	downloadDir    string
+	uploadDirs     []string // temp dirs holding browser_upload contents, removed on Close
 	lastElementBox *api.BoxInfo // stashed by AgentSession.SetLastElementBox via callback
 	activeContext  string         // last page context switched to or created
+	frames         []string       // frames entered with browser_switch_frame, outermost first
+	lastCookies    []api.CookieInfo // snapshot for browser_get_cookies_changed
+	subscribed     map[string]bool      // BiDi events subscribed to via h.subscribe
+	intercepts     []*api.InterceptRule // active browser_request_intercept rules
+	blockedURLs    []*api.InterceptRule // browser_block_urls patterns, checked after h.intercepts
+	extraHeaders   *scopedHeaders       // browser_set_extra_headers headers and the origin they're sent to
+	basicAuth      *scopedHeaders       // browser_set_basic_auth Authorization header and the origin it's sent to
+	mockRecord     *mockRecording       // set between browser_mock_record and browser_mock_stop
+	mockReplay     *mockReplay          // set between browser_mock_replay and browser_mock_stop
+	offline        string               // browser_set_offline mode: "", "emulation", or "intercept" (fallback)
+	offlineScript  string               // preload script keeping navigator.onLine false in "intercept" mode
+	interceptID    string               // BiDi intercept backing h.intercepts and h.blockedURLs
+	interceptPump  chan struct{}        // closed to stop resolving paused requests between tool calls
+	interceptDone  chan struct{}        // closed when the intercept pump has stopped
+	requests       *api.RequestTracker      // correlates network events
+	responses      []*api.CompletedRequest  // recent responses for browser_wait_for_response
+	har            *api.HARRecorder         // set between browser_har_start and browser_har_stop
+	consoleLogs    []*api.LogEntry          // buffered console messages
+	pageErrors     []*api.LogEntry          // buffered uncaught exceptions
+	openedPages    []openedPage             // top-level contexts opened this session, oldest first
+	closedPages    []closedPage             // recently closed top-level contexts, oldest first
+	downloads      []*download              // downloads seen this session, oldest first
+	pageClosedNotice string                 // set when the active page closes; reported by Call
+	dialogPolicy   *dialogPolicy            // browser_set_dialog_handler policy; nil = dialogs wait for a tool call
+	dialog         *openDialog              // most recent dialog while it is open
+	dialogNotices  []string                 // dialogs auto-answered during the current call; reported by Call
+	callStart      time.Time                // start of the current tool call
+	prevCallStart  time.Time                // start of the previous tool call
+	busy           sync.Mutex               // held during a tool call; the recording screenshot loop only captures while it's free
+	callDepth      int                      // nesting depth of call (browser_invoke_skill_chain steps call it recursively); guarded by busy
 }
 
 // NewHandlers creates a new Handlers instance.
@@ -39,6 +78,7 @@ type Handlers struct {
 func NewHandlers(screenshotDir string, headless bool, connectURL string, connectHeaders http.Header) *Handlers {
 	return &Handlers{
 		screenshotDir:  screenshotDir,
+		screenshotRoot: screenshotDir,
 		headless:       headless,
 		connectURL:     connectURL,
 		connectHeaders: connectHeaders,
@@ -61,8 +101,75 @@ func (h *Handlers) newSession() *api.AgentSession {
 // to produce before/after events (matching the API path), and captures a
 // screenshot after each non-recording action completes.
 func (h *Handlers) Call(name string, args map[string]interface{}) (*ToolsCallResult, error) {
+	h.busy.Lock()
+	defer h.busy.Unlock()
+	return h.call(name, args)
+}
+
+// call is Call for a caller that already holds h.busy: Call itself, and
+// tools that run other tools (browser_invoke_skill_chain).
+func (h *Handlers) call(name string, args map[string]interface{}) (*ToolsCallResult, error) {
 	log.Debug("tool call", "name", name, "args", args)
 
+	h.callDepth++
+	defer func() { h.callDepth-- }()
+
+	h.prevCallStart, h.callStart = h.callStart, time.Now()
+	h.pageClosedNotice = ""
+	if h.callDepth == 1 {
+		h.dialogNotices = nil
+	}
+
+	// Pick up a close of the active page or frame since the last call, so
+	// this call runs against the fallback page or the frame's parent instead
+	// of failing on a dead context. browser_stop must work on a dead browser.
+	if h.client != nil && (h.activeContext != "" || len(h.frames) > 0) && name != "browser_stop" {
+		if err := h.flushEvents(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Like WebDriver, navigating leaves the frame entered with
+	// browser_switch_frame; other page-level tools act on the page itself.
+	if navigatesPage(name) {
+		h.frames = nil
+	}
+	pageCtx := h.activeContext
+	if len(h.frames) > 0 && !pageLevelTool(name) {
+		h.activeContext = h.frames[len(h.frames)-1]
+	}
+
+	// A frame argument runs the whole call inside that frame's document.
+	// browser_switch_frame resolves its own.
+	if frame, _ := args["frame"].(string); frame != "" && name != "browser_switch_frame" {
+		var frameCtx string
+		err := h.ensureBrowser()
+		if err == nil {
+			frameCtx, err = h.frameContext(frame)
+		}
+		if err != nil {
+			h.activeContext = pageCtx
+			return nil, err
+		}
+		h.activeContext = frameCtx
+	}
+
+	// Return to the page afterwards. A tool that switched pages leaves the
+	// active frame behind with the old page.
+	callCtx := h.activeContext
+	defer func() {
+		if h.activeContext != callCtx {
+			h.frames = nil
+		} else if h.pageClosedNotice == "" {
+			h.activeContext = pageCtx
+		}
+	}()
+
+	args, err := h.resolveSelectorArgs(name, args)
+	if err != nil {
+		return nil, err
+	}
+
 	// Inject a synthetic find trace event before selector-based actions
 	// so CLI recordings match the JS client's find→action pairs.
 	// Skip @e refs — those come from an explicit find the user already ran.
@@ -78,11 +185,16 @@ func (h *Handlers) Call(name string, args map[string]interface{}) (*ToolsCallRes
 		pageId := h.getContext()
 		// Resolve @e1 refs to real selectors so the trace shows meaningful selectors
 		recordArgs := h.resolveRefsInArgs(args)
-		h.recorder.RecordAction(callId, mcpToolToMethod(name), recordArgs, "", pageId)
+		var beforeSnapshot string
+		if h.recorder.Options().Snapshots {
+			beforeSnapshot = h.captureActionSnapshot(callId, "before")
+		}
+		h.recorder.RecordAction(callId, mcpToolToMethod(name), recordArgs, beforeSnapshot, pageId)
 		h.lastElementBox = nil
 	}
 
 	result, err := h.dispatch(name, args)
+	h.flushMockRecording()
 
 	endTime := time.Now()
 
@@ -94,9 +206,28 @@ func (h *Handlers) Call(name string, args map[string]interface{}) (*ToolsCallRes
 	if err == nil && h.recorder != nil && h.recorder.IsRecording() && !isRecordingCommand(name) {
 		api.CaptureRecordingScreenshot(h.newSession(), h.recorder, endTime)
 	}
+	if h.recorder != nil {
+		h.recorder.SetActivePage(h.activeContext)
+	}
 
 	if callId != "" {
-		h.recorder.RecordActionEnd(callId, "", endTime, box)
+		var afterSnapshot string
+		if h.recorder.Options().Snapshots {
+			afterSnapshot = h.captureActionSnapshot(callId, "after")
+		}
+		h.recorder.RecordActionEnd(callId, afterSnapshot, endTime, box)
+	}
+
+	if h.pageClosedNotice != "" {
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", h.pageClosedNotice, err)
+		}
+		if result != nil {
+			result.Content = append(result.Content, Content{Type: "text", Text: "Note: " + h.pageClosedNotice})
+		}
+	}
+	if h.callDepth == 1 && len(h.dialogNotices) > 0 && result != nil {
+		result.Content = append(result.Content, Content{Type: "text", Text: "Note: " + strings.Join(h.dialogNotices, "; ")})
 	}
 
 	return result, err
@@ -115,6 +246,10 @@ func (h *Handlers) dispatch(name string, args map[string]interface{}) (*ToolsCal
 		return h.browserType(args)
 	case "browser_screenshot":
 		return h.browserScreenshot(args)
+	case "browser_wait_until_stable_screenshot":
+		return h.browserWaitUntilStableScreenshot(args)
+	case "browser_invoke_skill_chain":
+		return h.browserInvokeSkillChain(args)
 	case "browser_find":
 		return h.browserFind(args)
 	case "browser_evaluate":
@@ -129,18 +264,36 @@ func (h *Handlers) dispatch(name string, args map[string]interface{}) (*ToolsCal
 		return h.browserGetTitle(args)
 	case "browser_get_html":
 		return h.browserGetHTML(args)
+	case "browser_get_outer_html":
+		return h.browserGetOuterHTML(args)
+	case "browser_get_element_html_and_text":
+		return h.browserGetElementHTMLAndText(args)
+	case "browser_get_table":
+		return h.browserGetTable(args)
+	case "browser_get_links":
+		return h.browserGetLinks(args)
 	case "browser_find_all":
 		return h.browserFindAll(args)
+	case "browser_find_all_by_role":
+		return h.browserFindAllByRole(args)
 	case "browser_wait":
 		return h.browserWait(args)
 	case "browser_hover":
 		return h.browserHover(args)
 	case "browser_select":
 		return h.browserSelect(args)
+	case "browser_select_by_index":
+		return h.browserSelectByIndex(args)
+	case "browser_select_options":
+		return h.browserSelectOptions(args)
 	case "browser_scroll":
 		return h.browserScroll(args)
+	case "browser_scroll_by":
+		return h.browserScrollBy(args)
 	case "browser_keys":
 		return h.browserKeys(args)
+	case "browser_key_sequence":
+		return h.browserKeySequence(args)
 	case "browser_new_page":
 		return h.browserNewPage(args)
 	case "browser_list_pages":
@@ -149,8 +302,16 @@ func (h *Handlers) dispatch(name string, args map[string]interface{}) (*ToolsCal
 		return h.browserSwitchPage(args)
 	case "browser_close_page":
 		return h.browserClosePage(args)
+	case "browser_switch_frame":
+		return h.browserSwitchFrame(args)
+	case "browser_switch_frame_parent":
+		return h.browserSwitchFrameParent(args)
+	case "browser_switch_frame_top":
+		return h.browserSwitchFrameTop(args)
 	case "browser_a11y_tree":
 		return h.browserA11yTree(args)
+	case "browser_snapshot":
+		return h.browserSnapshot(args)
 	case "page_clock_install":
 		return h.pageClockInstall(args)
 	case "page_clock_fast_forward":
@@ -169,6 +330,8 @@ func (h *Handlers) dispatch(name string, args map[string]interface{}) (*ToolsCal
 		return h.pageClockSetTimezone(args)
 	case "browser_fill":
 		return h.browserFill(args)
+	case "browser_clear":
+		return h.browserClear(args)
 	case "browser_press":
 		return h.browserPress(args)
 	case "browser_back":
@@ -181,6 +344,8 @@ func (h *Handlers) dispatch(name string, args map[string]interface{}) (*ToolsCal
 		return h.browserGetValue(args)
 	case "browser_get_attribute":
 		return h.browserGetAttribute(args)
+	case "browser_get_attributes":
+		return h.browserGetAttributes(args)
 	case "browser_is_visible":
 		return h.browserIsVisible(args)
 	case "browser_check":
@@ -201,16 +366,30 @@ func (h *Handlers) dispatch(name string, args map[string]interface{}) (*ToolsCal
 		return h.browserDiffMap(args)
 	case "browser_pdf":
 		return h.browserPDF(args)
+	case "browser_print_preview":
+		return h.browserPrintPreview(args)
 	case "browser_highlight":
 		return h.browserHighlight(args)
 	case "browser_dblclick":
 		return h.browserDblClick(args)
+	case "browser_right_click":
+		return h.browserRightClick(args)
+	case "browser_tap":
+		return h.browserTap(args)
+	case "browser_pinch":
+		return h.browserPinch(args)
 	case "browser_focus":
 		return h.browserFocus(args)
+	case "browser_focus_next":
+		return h.browserMoveFocus("Tab")
+	case "browser_focus_previous":
+		return h.browserMoveFocus("Shift+Tab")
 	case "browser_count":
 		return h.browserCount(args)
 	case "browser_is_enabled":
 		return h.browserIsEnabled(args)
+	case "browser_is_editable":
+		return h.browserIsEditable(args)
 	case "browser_is_checked":
 		return h.browserIsChecked(args)
 	case "browser_wait_for_text":
@@ -221,12 +400,20 @@ func (h *Handlers) dispatch(name string, args map[string]interface{}) (*ToolsCal
 		return h.browserDialogAccept(args)
 	case "browser_dialog_dismiss":
 		return h.browserDialogDismiss(args)
+	case "browser_set_dialog_handler":
+		return h.browserSetDialogHandler(args)
+	case "browser_get_dialog":
+		return h.browserGetDialog(args)
 	case "browser_get_cookies":
 		return h.browserGetCookies(args)
+	case "browser_get_cookie":
+		return h.browserGetCookie(args)
 	case "browser_set_cookie":
 		return h.browserSetCookie(args)
 	case "browser_delete_cookies":
 		return h.browserDeleteCookies(args)
+	case "browser_get_cookies_changed":
+		return h.browserGetCookiesChanged(args)
 	case "browser_mouse_move":
 		return h.browserMouseMove(args)
 	case "browser_mouse_down":
@@ -237,6 +424,8 @@ func (h *Handlers) dispatch(name string, args map[string]interface{}) (*ToolsCal
 		return h.browserMouseClick(args)
 	case "browser_drag":
 		return h.browserDrag(args)
+	case "browser_drag_by":
+		return h.browserDragBy(args)
 	case "browser_set_viewport":
 		return h.browserSetViewport(args)
 	case "browser_get_viewport":
@@ -249,6 +438,44 @@ func (h *Handlers) dispatch(name string, args map[string]interface{}) (*ToolsCal
 		return h.browserEmulateMedia(args)
 	case "browser_set_geolocation":
 		return h.browserSetGeolocation(args)
+	case "browser_set_offline":
+		return h.browserSetOffline(args)
+	case "browser_emulate_network":
+		return h.browserEmulateNetwork(args)
+	case "browser_emulate_cpu":
+		return h.browserEmulateCPU(args)
+	case "browser_set_user_agent":
+		return h.browserSetUserAgent(args)
+	case "browser_request_intercept":
+		return h.browserRequestIntercept(args)
+	case "browser_request_unintercept":
+		return h.browserRequestUnintercept(args)
+	case "browser_block_urls":
+		return h.browserBlockURLs(args)
+	case "browser_set_extra_headers":
+		return h.browserSetExtraHeaders(args)
+	case "browser_set_basic_auth":
+		return h.browserSetBasicAuth(args)
+	case "browser_mock_record":
+		return h.browserMockRecord(args)
+	case "browser_mock_replay":
+		return h.browserMockReplay(args)
+	case "browser_mock_stop":
+		return h.browserMockStop(args)
+	case "browser_unblock_urls":
+		return h.browserUnblockURLs(args)
+	case "browser_wait_for_response":
+		return h.browserWaitForResponse(args)
+	case "browser_wait_for_download":
+		return h.browserWaitForDownload(args)
+	case "browser_list_downloads":
+		return h.browserListDownloads(args)
+	case "browser_wait_for_page":
+		return h.browserWaitForPage(args)
+	case "browser_console_logs":
+		return h.browserConsoleLogs(args)
+	case "browser_page_errors":
+		return h.browserPageErrors(args)
 	case "browser_set_content":
 		return h.browserSetContent(args)
 	case "browser_frames":
@@ -269,12 +496,20 @@ func (h *Handlers) dispatch(name string, args map[string]interface{}) (*ToolsCal
 		return h.browserRecordStartChunk(args)
 	case "browser_record_stop_chunk":
 		return h.browserRecordStopChunk(args)
+	case "browser_har_start":
+		return h.browserHarStart(args)
+	case "browser_har_stop":
+		return h.browserHarStop(args)
 	case "browser_storage_state":
 		return h.browserStorageState(args)
 	case "browser_restore_storage":
 		return h.browserRestoreStorage(args)
+	case "browser_clear_storage":
+		return h.browserClearStorage(args)
 	case "browser_download_set_dir":
 		return h.browserDownloadSetDir(args)
+	case "browser_set_screenshot_dir":
+		return h.browserSetScreenshotDir(args)
 	default:
 		return nil, fmt.Errorf("unknown tool: %s", name)
 	}
@@ -287,7 +522,8 @@ func isRecordingCommand(name string) bool {
 	case "browser_record_start", "browser_record_stop",
 		"browser_record_start_group", "browser_record_stop_group",
 		"browser_record_start_chunk", "browser_record_stop_chunk",
-		"browser_screenshot":
+		"browser_screenshot", "browser_wait_until_stable_screenshot",
+		"browser_invoke_skill_chain":
 		return true
 	}
 	return false
@@ -298,19 +534,33 @@ func isRecordingCommand(name string) bool {
 // before dispatch so CLI recordings match the JS client's find→action pairs.
 func needsFindStep(name string) bool {
 	switch name {
-	case "browser_click", "browser_dblclick", "browser_fill", "browser_type",
-		"browser_press", "browser_hover", "browser_select",
+	case "browser_click", "browser_dblclick", "browser_right_click", "browser_tap", "browser_pinch", "browser_fill", "browser_clear", "browser_type",
+		"browser_press", "browser_hover", "browser_select", "browser_select_by_index",
+		"browser_select_options",
 		"browser_check", "browser_uncheck", "browser_focus",
 		"browser_scroll_into_view", "browser_drag",
 		"browser_get_text", "browser_get_html", "browser_get_value",
-		"browser_get_attribute", "browser_is_visible",
-		"browser_is_enabled", "browser_is_checked",
+		"browser_get_outer_html", "browser_get_element_html_and_text", "browser_get_table",
+		"browser_get_attribute", "browser_get_attributes", "browser_is_visible",
+		"browser_is_enabled", "browser_is_editable", "browser_is_checked",
 		"browser_upload", "browser_highlight":
 		return true
 	}
 	return false
 }
 
+// captureActionSnapshot adds a before/after frame-snapshot of the active page
+// to the recording so the trace viewer can show the page around each action.
+func (h *Handlers) captureActionSnapshot(callId, snapshotType string) string {
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return ""
+	}
+	frameURL, _ := api.EvalSimpleScript(s, ctx, "() => location.href")
+	return api.CaptureActionSnapshot(s, h.recorder, callId, snapshotType, frameURL)
+}
+
 // recordFindStep emits a complete find trace event (before + screenshot + after)
 // so that CLI recordings produce the same find→action pairs as the JS client.
 func (h *Handlers) recordFindStep(selector string) {
@@ -378,8 +628,12 @@ func mcpToolToMethod(name string) string {
 		return "vibium:element.click"
 	case "browser_dblclick":
 		return "vibium:element.dblclick"
+	case "browser_tap":
+		return "vibium:element.tap"
 	case "browser_fill":
 		return "vibium:element.fill"
+	case "browser_clear":
+		return "vibium:element.clear"
 	case "browser_type":
 		return "vibium:element.type"
 	case "browser_press":
@@ -388,12 +642,16 @@ func mcpToolToMethod(name string) string {
 		return "vibium:element.hover"
 	case "browser_select":
 		return "vibium:element.selectOption"
+	case "browser_select_by_index":
+		return "vibium:element.selectByIndex"
 	case "browser_check":
 		return "vibium:element.check"
 	case "browser_uncheck":
 		return "vibium:element.uncheck"
 	case "browser_focus":
 		return "vibium:element.focus"
+	case "browser_focus_next", "browser_focus_previous":
+		return "vibium:keyboard.press"
 	case "browser_scroll_into_view":
 		return "vibium:element.scrollIntoView"
 	case "browser_drag":
@@ -410,7 +668,7 @@ func mcpToolToMethod(name string) string {
 		return "vibium:mouse.up"
 	case "browser_mouse_click":
 		return "vibium:mouse.click"
-	case "browser_scroll":
+	case "browser_scroll", "browser_scroll_by":
 		return "vibium:page.scroll"
 
 	// Page queries
@@ -422,6 +680,8 @@ func mcpToolToMethod(name string) string {
 		return "vibium:element.text"
 	case "browser_get_html":
 		return "vibium:element.html"
+	case "browser_get_element_html_and_text":
+		return "vibium:element.html"
 	case "browser_get_url":
 		return "vibium:page.url"
 	case "browser_get_title":
@@ -430,10 +690,14 @@ func mcpToolToMethod(name string) string {
 		return "vibium:element.value"
 	case "browser_get_attribute":
 		return "vibium:element.attr"
+	case "browser_get_attributes":
+		return "vibium:element.attr"
 	case "browser_is_visible":
 		return "vibium:element.isVisible"
 	case "browser_is_enabled":
 		return "vibium:element.isEnabled"
+	case "browser_is_editable":
+		return "vibium:element.isEditable"
 	case "browser_is_checked":
 		return "vibium:element.isChecked"
 	case "browser_count":
@@ -442,8 +706,12 @@ func mcpToolToMethod(name string) string {
 		return "vibium:page.eval"
 	case "browser_screenshot":
 		return "vibium:page.screenshot"
+	case "browser_wait_until_stable_screenshot":
+		return "vibium:page.screenshot"
 	case "browser_pdf":
 		return "vibium:page.pdf"
+	case "browser_print_preview":
+		return "vibium:page.screenshot"
 	case "browser_a11y_tree":
 		return "vibium:page.a11yTree"
 
@@ -484,14 +752,20 @@ func mcpToolToMethod(name string) string {
 	// Cookies/storage
 	case "browser_get_cookies":
 		return "vibium:context.cookies"
+	case "browser_get_cookie":
+		return "vibium:context.cookies"
 	case "browser_set_cookie":
 		return "vibium:context.setCookies"
 	case "browser_delete_cookies":
 		return "vibium:context.clearCookies"
+	case "browser_get_cookies_changed":
+		return "vibium:context.cookies"
 	case "browser_storage_state":
 		return "vibium:context.storage"
 	case "browser_restore_storage":
 		return "vibium:context.setStorage"
+	case "browser_clear_storage":
+		return "vibium:context.clearStorage"
 
 	// Dialog
 	case "browser_dialog_accept":
@@ -504,6 +778,14 @@ func mcpToolToMethod(name string) string {
 		return "vibium:page.emulateMedia"
 	case "browser_set_geolocation":
 		return "vibium:page.setGeolocation"
+	case "browser_set_offline":
+		return "vibium:context.setOffline"
+	case "browser_emulate_network":
+		return "vibium:page.emulateNetwork"
+	case "browser_emulate_cpu":
+		return "vibium:page.emulateCPU"
+	case "browser_set_user_agent":
+		return "vibium:browser.setUserAgent"
 	case "browser_set_content":
 		return "vibium:page.setContent"
 
@@ -558,6 +840,11 @@ func mcpToolToMethod(name string) string {
 
 // Close cleans up any active browser sessions.
 func (h *Handlers) Close() {
+	// Stop the recording screenshot loop before closing BiDi (captures use the connection)
+	if h.recorder != nil {
+		h.recorder.StopScreenshots()
+	}
+	h.stopInterceptPump()
 	// Remote mode: end the BiDi session so chromedriver closes Chrome
 	if h.connectURL != "" && h.client != nil {
 		h.client.SendCommand("session.end", map[string]interface{}{})
@@ -571,6 +858,29 @@ func (h *Handlers) Close() {
 		h.launchResult = nil
 	}
 	h.client = nil
+	h.frames = nil
+	h.lastCookies = nil
+	h.subscribed = nil
+	h.intercepts = nil
+	h.blockedURLs = nil
+	h.extraHeaders = nil
+	h.basicAuth = nil
+	h.mockRecord = nil
+	h.mockReplay = nil
+	h.offline = ""
+	h.offlineScript = ""
+	h.interceptID = ""
+	h.requests = nil
+	h.responses = nil
+	h.consoleLogs = nil
+	h.pageErrors = nil
+	h.downloads = nil
+	h.dialogPolicy = nil
+	h.dialog = nil
+	for _, dir := range h.uploadDirs {
+		os.RemoveAll(dir)
+	}
+	h.uploadDirs = nil
 }
 
 // browserLaunch launches a new browser session or connects to a remote one.
@@ -593,6 +903,7 @@ func (h *Handlers) browserLaunch(args map[string]interface{}) (*ToolsCallResult,
 		}
 		h.conn = conn
 		h.client = client
+		h.startEventCapture()
 
 		return &ToolsCallResult{
 			Content: []Content{{
@@ -629,6 +940,7 @@ func (h *Handlers) browserLaunch(args map[string]interface{}) (*ToolsCallResult,
 	h.launchResult = launchResult
 	h.conn = conn
 	h.client = bidi.NewClient(conn)
+	h.startEventCapture()
 
 	return &ToolsCallResult{
 		Content: []Content{{
@@ -678,19 +990,35 @@ func (h *Handlers) browserClick(args map[string]interface{}) (*ToolsCallResult,
 	}
 	selector = h.resolveSelector(selector)
 
+	click := api.Click
+	button, _ := args["button"].(string)
+	switch button {
+	case "", "left":
+	case "middle":
+		click = api.MiddleClick
+	case "right":
+		click = api.RightClick
+	default:
+		return nil, fmt.Errorf("unknown button %q (use left, middle, or right)", button)
+	}
+
 	s := h.newSession()
 	ctx, err := s.GetContextID()
 	if err != nil {
 		return nil, err
 	}
-	if err := api.Click(s, ctx, api.ElementParams{Selector: selector}); err != nil {
+	if err := click(s, ctx, actionParams(selector, args)); err != nil {
 		return nil, fmt.Errorf("failed to click: %w", err)
 	}
 
+	text := fmt.Sprintf("Clicked element: %s", selector)
+	if button != "" && button != "left" {
+		text = fmt.Sprintf("Clicked element with %s button: %s", button, selector)
+	}
 	return &ToolsCallResult{
 		Content: []Content{{
 			Type: "text",
-			Text: fmt.Sprintf("Clicked element: %s", selector),
+			Text: text,
 		}},
 	}, nil
 }
@@ -701,12 +1029,6 @@ func (h *Handlers) browserType(args map[string]interface{}) (*ToolsCallResult, e
 		return nil, err
 	}
 
-	selector, ok := args["selector"].(string)
-	if !ok || selector == "" {
-		return nil, fmt.Errorf("selector is required")
-	}
-	selector = h.resolveSelector(selector)
-
 	text, ok := args["text"].(string)
 	if !ok {
 		return nil, fmt.Errorf("text is required")
@@ -717,7 +1039,34 @@ func (h *Handlers) browserType(args map[string]interface{}) (*ToolsCallResult, e
 	if err != nil {
 		return nil, err
 	}
-	if err := api.TypeInto(s, ctx, api.ElementParams{Selector: selector}, text); err != nil {
+
+	// raw inserts the text in one edit, for CJK and emoji that per-rune key
+	// events garble.
+	typeText, typeInto := api.TypeText, api.TypeInto
+	if raw, _ := args["raw"].(bool); raw {
+		typeText, typeInto = api.InsertText, api.InsertTextInto
+	}
+
+	// Without a selector, type into whatever already has focus (e.g. after
+	// pressing Tab, or a contenteditable region with no stable selector).
+	selector, _ := args["selector"].(string)
+	if selector == "" {
+		if text == "" {
+			return nil, fmt.Errorf("text must not be empty")
+		}
+		if err := typeText(s, ctx, text); err != nil {
+			return nil, fmt.Errorf("failed to type: %w", err)
+		}
+		return &ToolsCallResult{
+			Content: []Content{{
+				Type: "text",
+				Text: fmt.Sprintf("Typed %d character(s) into the focused element", len([]rune(text))),
+			}},
+		}, nil
+	}
+	selector = h.resolveSelector(selector)
+
+	if err := typeInto(s, ctx, actionParams(selector, args), text); err != nil {
 		return nil, fmt.Errorf("failed to type: %w", err)
 	}
 
@@ -738,48 +1087,130 @@ func (h *Handlers) browserScreenshot(args map[string]interface{}) (*ToolsCallRes
 	fullPage, _ := args["fullPage"].(bool)
 	annotate, _ := args["annotate"].(bool)
 
-	// If annotate, run map first to get refs, then inject matching labels
+	// If annotate, run map first to get refs, then inject matching labels.
+	// A page mapped with stable refs stays that way so the labels match the
+	// @refs the caller already holds.
 	if annotate {
-		if _, err := h.browserMap(map[string]interface{}{}); err != nil {
+		mapArgs := map[string]interface{}{}
+		if h.mapRefs != nil {
+			mapArgs["stableRefs"] = true
+		}
+		if _, err := h.browserMap(mapArgs); err != nil {
 			return nil, fmt.Errorf("failed to map for annotation: %w", err)
 		}
 
-		// Build ordered list of selectors from refMap (@e1, @e2, ...)
-		selectors := make([]string, 0, len(h.refMap))
-		for i := 1; i <= len(h.refMap); i++ {
-			ref := fmt.Sprintf("@e%d", i)
-			if sel, ok := h.refMap[ref]; ok {
-				selectors = append(selectors, sel)
-			}
+		// Stable refs can leave gaps, so label each element with its own ref
+		// number rather than its position.
+		refs := make([]string, 0, len(h.refMap))
+		for ref := range h.refMap {
+			refs = append(refs, ref)
+		}
+		sort.Strings(refs)
+		type annotation struct {
+			Selector string `json:"selector"`
+			Label    string `json:"label"`
+		}
+		targets := make([]annotation, 0, len(refs))
+		for _, ref := range refs {
+			targets = append(targets, annotation{Selector: h.refMap[ref], Label: strings.TrimPrefix(ref, "@e")})
+		}
+		// CallFunction can only pass strings, so the targets go as JSON.
+		targetsJSON, err := json.Marshal(targets)
+		if err != nil {
+			return nil, err
 		}
 
-		annotateScript := `(selectors) => {
+		annotateScript := `(targetsJSON) => {
+			` + api.DeepQueryJS() + `
 			let count = 0;
-			for (let i = 0; i < selectors.length; i++) {
-				const el = document.querySelector(selectors[i]);
+			for (const target of JSON.parse(targetsJSON)) {
+				const el = queryDeep(document, target.selector);
 				if (!el) continue;
 				const rect = el.getBoundingClientRect();
 				if (rect.width === 0 || rect.height === 0) continue;
 				const label = document.createElement('div');
 				label.className = '__vibium_annotation';
-				label.textContent = i + 1;
+				label.textContent = target.label;
 				label.style.cssText = 'position:fixed;z-index:2147483647;background:red;color:white;font:bold 11px sans-serif;padding:1px 4px;border-radius:8px;pointer-events:none;line-height:16px;min-width:16px;text-align:center;left:' + (rect.left - 2) + 'px;top:' + (rect.top - 2) + 'px;';
 				document.body.appendChild(label);
 				count++;
 			}
 			return JSON.stringify({count: count});
 		}`
-		if _, err := h.client.CallFunction("", annotateScript, []interface{}{selectors}); err != nil {
+		if _, err := h.client.CallFunction(h.activeContext, annotateScript, []interface{}{string(targetsJSON)}); err != nil {
 			return nil, fmt.Errorf("failed to annotate: %w", err)
 		}
 	}
 
+	// Paint over masked elements so volatile content (timestamps, ads)
+	// doesn't affect baseline comparisons.
+	if rawMask, ok := args["mask"].([]interface{}); ok && len(rawMask) > 0 {
+		masks := make([]string, 0, len(rawMask))
+		for _, m := range rawMask {
+			if sel, ok := m.(string); ok && sel != "" {
+				masks = append(masks, h.resolveSelector(sel))
+			}
+		}
+		maskColor, _ := args["maskColor"].(string)
+		if maskColor == "" {
+			maskColor = "#FF00FF"
+		}
+
+		// CallFunction can only pass strings, so the selectors go as JSON.
+		masksJSON, err := json.Marshal(masks)
+		if err != nil {
+			return nil, err
+		}
+		maskScript := `(selectorsJSON, color) => {
+			` + api.DeepQueryJS() + `
+			if (!CSS.supports('color', color)) return 'invalid color';
+			let count = 0;
+			for (const selector of JSON.parse(selectorsJSON)) {
+				for (const el of queryAllDeep(document, selector)) {
+					const rect = el.getBoundingClientRect();
+					if (rect.width === 0 || rect.height === 0) continue;
+					const mask = document.createElement('div');
+					mask.className = '__vibium_mask';
+					mask.style.cssText = 'position:absolute;z-index:2147483647;pointer-events:none;margin:0;padding:0;border:0;left:' + (rect.left + window.scrollX) + 'px;top:' + (rect.top + window.scrollY) + 'px;width:' + rect.width + 'px;height:' + rect.height + 'px;';
+					mask.style.background = color;
+					document.documentElement.appendChild(mask);
+					count++;
+				}
+			}
+			return JSON.stringify({count: count});
+		}`
+		masked, err := h.client.CallFunction(h.activeContext, maskScript, []interface{}{string(masksJSON), maskColor})
+		if err != nil {
+			return nil, fmt.Errorf("failed to mask: %w", err)
+		}
+		if masked == "invalid color" {
+			return nil, fmt.Errorf("invalid maskColor %q: must be a CSS color", maskColor)
+		}
+		defer h.client.CallFunction(h.activeContext, `() => {
+			document.querySelectorAll('.__vibium_mask').forEach(el => el.remove());
+			return 'cleaned';
+		}`, nil)
+	}
+
 	s := h.newSession()
 	ctx, err := s.GetContextID()
 	if err != nil {
 		return nil, err
 	}
-	base64Data, err := api.Screenshot(s, ctx, fullPage)
+
+	stitch, _ := args["stitch"].(bool)
+	var base64Data, warning string
+	if selector, ok := args["selector"].(string); ok && selector != "" {
+		if fullPage || stitch {
+			warning = "Warning: fullPage is ignored when selector is given; captured the element only"
+		}
+		selector = h.resolveSelector(selector)
+		base64Data, err = api.ElementScreenshot(s, ctx, api.ElementParams{Selector: selector})
+	} else if stitch {
+		base64Data, err = api.StitchedScreenshot(s, ctx)
+	} else {
+		base64Data, err = api.Screenshot(s, ctx, fullPage)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to capture screenshot: %w", err)
 	}
@@ -790,45 +1221,218 @@ func (h *Handlers) browserScreenshot(args map[string]interface{}) (*ToolsCallRes
 			document.querySelectorAll('.__vibium_annotation').forEach(el => el.remove());
 			return 'cleaned';
 		}`
-		h.client.CallFunction("", cleanupScript, nil)
+		h.client.CallFunction(h.activeContext, cleanupScript, nil)
+	}
+
+	// A screenshot that differs from its baseline is reported as an error
+	// result (the CLI exits non-zero) that still carries the screenshot.
+	var diffSummary string
+	var differs bool
+	if baseline, _ := args["baseline"].(string); baseline != "" {
+		diffSummary, differs, err = h.compareWithBaseline(base64Data, baseline, args)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// If filename provided, save to file (only if screenshotDir is configured)
 	if filename, ok := args["filename"].(string); ok && filename != "" {
-		if h.screenshotDir == "" {
-			return nil, fmt.Errorf("screenshot file saving is disabled (use --screenshot-dir to enable)")
+		fullPath, err := h.saveScreenshot(filename, base64Data)
+		if err != nil {
+			return nil, err
+		}
+		text := fmt.Sprintf("Screenshot saved to %s", fullPath)
+		if warning != "" {
+			text += "\n" + warning
+		}
+		if diffSummary != "" {
+			text += "\n" + diffSummary
+		}
+		return &ToolsCallResult{
+			Content: []Content{{
+				Type: "text",
+				Text: text,
+			}},
+			IsError: differs,
+		}, nil
+	}
+
+	content := []Content{{
+		Type:     "image",
+		Data:     base64Data,
+		MimeType: "image/png",
+	}}
+	if warning != "" {
+		content = append(content, Content{Type: "text", Text: warning})
+	}
+	if diffSummary != "" {
+		content = append(content, Content{Type: "text", Text: diffSummary})
+	}
+	return &ToolsCallResult{Content: content, IsError: differs}, nil
+}
+
+// compareWithBaseline compares a screenshot against the baseline PNG at name
+// and summarizes the difference, writing a diff image to args["diffPath"]
+// when pixels differ. A missing baseline is created from the screenshot.
+// Both paths are inside the screenshot directory (see screenshotPath).
+func (h *Handlers) compareWithBaseline(base64Data, name string, args map[string]interface{}) (summary string, differs bool, err error) {
+	path, err := h.screenshotPath(name)
+	if err != nil {
+		return "", false, err
+	}
+	diffPath := ""
+	if d, _ := args["diffPath"].(string); d != "" {
+		if diffPath, err = h.screenshotPath(d); err != nil {
+			return "", false, err
+		}
+	}
+
+	pngData, err := base64.StdEncoding.DecodeString(base64Data)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+
+	baseline, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		if err := os.WriteFile(path, pngData, 0644); err != nil {
+			return "", false, fmt.Errorf("failed to save baseline: %w", err)
 		}
+		return fmt.Sprintf("No baseline at %s; saved this screenshot as the baseline", path), false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read baseline: %w", err)
+	}
 
-		// Create directory if it doesn't exist
-		if err := os.MkdirAll(h.screenshotDir, 0755); err != nil {
-			return nil, fmt.Errorf("failed to create screenshot directory: %w", err)
+	threshold := 0.1
+	if t, ok := args["threshold"].(float64); ok {
+		if t < 0 || t > 1 {
+			return "", false, fmt.Errorf("threshold must be between 0 and 1")
 		}
+		threshold = t
+	}
 
-		// Use only the basename to prevent path traversal
-		safeName := filepath.Base(filename)
-		fullPath := filepath.Join(h.screenshotDir, safeName)
+	diff, err := api.DiffImages(baseline, pngData, threshold)
+	if err != nil {
+		return "", false, err
+	}
+	if diff.DiffPixels == 0 {
+		return fmt.Sprintf("Matches baseline %s", path), false, nil
+	}
 
-		pngData, err := base64.StdEncoding.DecodeString(base64Data)
+	summary = fmt.Sprintf("Differs from baseline %s: %.2f%% of pixels (%d of %d)",
+		path, diff.Ratio*100, diff.DiffPixels, diff.Width*diff.Height)
+	if diff.SizeMismatch {
+		summary += "; image sizes differ"
+	}
+	if diffPath != "" {
+		data, err := diff.EncodePNG()
 		if err != nil {
-			return nil, fmt.Errorf("failed to decode screenshot: %w", err)
+			return "", false, err
+		}
+		if err := os.WriteFile(diffPath, data, 0644); err != nil {
+			return "", false, fmt.Errorf("failed to save diff image: %w", err)
+		}
+		summary += fmt.Sprintf("; diff image saved to %s", diffPath)
+	}
+	return summary, true, nil
+}
+
+// screenshotPath resolves a file name for a screenshot, baseline or diff
+// image against the screenshot directory, creating the directories it needs.
+// Subdirectories are kept; absolute paths and paths that climb out with ..
+// are reduced to their base name, so files always land inside the directory.
+// Fails if file saving is disabled.
+func (h *Handlers) screenshotPath(name string) (string, error) {
+	if h.screenshotDir == "" {
+		return "", fmt.Errorf("screenshot file saving is disabled (use --screenshot-dir to enable)")
+	}
+	if !filepath.IsLocal(name) {
+		name = filepath.Base(name)
+	}
+	if name == "." || !filepath.IsLocal(name) {
+		return "", fmt.Errorf("invalid file name %q", name)
+	}
+	path := filepath.Join(h.screenshotDir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create screenshot directory: %w", err)
+	}
+	return path, nil
+}
+
+// saveScreenshot writes base64 PNG data to filename inside the screenshot
+// directory (see screenshotPath) and returns the full path.
+func (h *Handlers) saveScreenshot(filename, base64Data string) (string, error) {
+	fullPath, err := h.screenshotPath(filename)
+	if err != nil {
+		return "", err
+	}
+
+	pngData, err := base64.StdEncoding.DecodeString(base64Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+	if err := os.WriteFile(fullPath, pngData, 0644); err != nil {
+		return "", fmt.Errorf("failed to save screenshot: %w", err)
+	}
+	return fullPath, nil
+}
+
+// browserWaitUntilStableScreenshot captures the viewport repeatedly until two
+// consecutive captures match (within tolerance), then returns the stable image.
+func (h *Handlers) browserWaitUntilStableScreenshot(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	timeout := api.DefaultTimeout
+	if t, ok := args["timeout"].(float64); ok {
+		timeout = time.Duration(t) * time.Millisecond
+	}
+
+	interval := 250 * time.Millisecond
+	if i, ok := args["interval"].(float64); ok && i > 0 {
+		interval = time.Duration(i) * time.Millisecond
+	}
+
+	tolerance := 0.0
+	if t, ok := args["tolerance"].(float64); ok {
+		if t < 0 || t > 1 {
+			return nil, fmt.Errorf("tolerance must be between 0 and 1")
 		}
-		if err := os.WriteFile(fullPath, pngData, 0644); err != nil {
-			return nil, fmt.Errorf("failed to save screenshot: %w", err)
+		tolerance = t
+	}
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	base64Data, captures, err := api.WaitForStableScreenshot(s, ctx, interval, timeout, tolerance)
+	if err != nil {
+		return nil, err
+	}
+	msg := fmt.Sprintf("Page visually stable after %d captures (%s)", captures, time.Since(start).Round(time.Millisecond))
+
+	if filename, ok := args["filename"].(string); ok && filename != "" {
+		fullPath, err := h.saveScreenshot(filename, base64Data)
+		if err != nil {
+			return nil, err
 		}
 		return &ToolsCallResult{
 			Content: []Content{{
 				Type: "text",
-				Text: fmt.Sprintf("Screenshot saved to %s", fullPath),
+				Text: fmt.Sprintf("%s, saved to %s", msg, fullPath),
 			}},
 		}, nil
 	}
 
 	return &ToolsCallResult{
-		Content: []Content{{
-			Type:     "image",
-			Data:     base64Data,
-			MimeType: "image/png",
-		}},
+		Content: []Content{
+			{Type: "text", Text: msg},
+			{Type: "image", Data: base64Data, MimeType: "image/png"},
+		},
 	}, nil
 }
 
@@ -857,8 +1461,11 @@ func (h *Handlers) browserFind(args map[string]interface{}) (*ToolsCallResult, e
 			timeout = time.Duration(t) * time.Millisecond
 		}
 
+		exact, _ := args["exact"].(bool)
+		caseInsensitive, _ := args["caseInsensitive"].(bool)
+
 		script := findBySemanticScript()
-		result, err := pollCallFunction(h, script, []interface{}{role, text, label, placeholder, testid, xpath, alt, title}, timeout)
+		result, err := pollCallFunction(h, script, []interface{}{role, text, label, placeholder, testid, xpath, alt, title, exact, caseInsensitive, ""}, timeout)
 		if err != nil {
 			desc := ""
 			for _, pair := range []struct{ k, v string }{
@@ -906,7 +1513,8 @@ func (h *Handlers) browserFind(args map[string]interface{}) (*ToolsCallResult, e
 	// Run getLabel in browser to get consistent label format (with scroll-into-view)
 	labelScript := `(selector) => {
 		` + GetLabelJS() + `
-		const el = document.querySelector(selector);
+		` + api.DeepQueryJS() + `
+		const el = queryDeep(document, selector);
 		if (!el) return null;
 		if (el.scrollIntoViewIfNeeded) {
 			el.scrollIntoViewIfNeeded(true);
@@ -915,7 +1523,7 @@ func (h *Handlers) browserFind(args map[string]interface{}) (*ToolsCallResult, e
 		}
 		return getLabel(el);
 	}`
-	labelResult, err := h.client.CallFunction("", labelScript, []interface{}{selector})
+	labelResult, err := h.client.CallFunction(h.activeContext, labelScript, []interface{}{selector})
 	if err != nil {
 		return nil, err
 	}
@@ -936,122 +1544,21 @@ func (h *Handlers) browserFind(args map[string]interface{}) (*ToolsCallResult, e
 // findBySemanticScript returns the JS function for finding elements by semantic criteria.
 // Returns JSON: {"selector":"...","label":"...","tag":"...","text":"...","box":{...}}
 func findBySemanticScript() string {
-	return `(role, text, label, placeholder, testid, xpath, alt, title) => {
+	return `(role, text, label, placeholder, testid, xpath, alt, title, exact, caseInsensitive, base) => {
 		` + GetSelectorJS() + `
 		` + GetLabelJS() + `
 
-		const IMPLICIT_ROLES = {
-			A: (el) => el.hasAttribute('href') ? 'link' : '',
-			AREA: (el) => el.hasAttribute('href') ? 'link' : '',
-			ARTICLE: () => 'article',
-			ASIDE: () => 'complementary',
-			BUTTON: () => 'button',
-			DETAILS: () => 'group',
-			DIALOG: () => 'dialog',
-			FOOTER: () => 'contentinfo',
-			FORM: () => 'form',
-			H1: () => 'heading', H2: () => 'heading', H3: () => 'heading',
-			H4: () => 'heading', H5: () => 'heading', H6: () => 'heading',
-			HEADER: () => 'banner',
-			HR: () => 'separator',
-			IMG: (el) => el.getAttribute('alt') ? 'img' : 'presentation',
-			INPUT: (el) => {
-				const t = (el.getAttribute('type') || 'text').toLowerCase();
-				const map = {button:'button',checkbox:'checkbox',image:'button',
-					number:'spinbutton',radio:'radio',range:'slider',
-					reset:'button',search:'searchbox',submit:'button',text:'textbox',
-					email:'textbox',tel:'textbox',url:'textbox',password:'textbox'};
-				return map[t] || 'textbox';
-			},
-			LI: () => 'listitem',
-			MAIN: () => 'main',
-			MENU: () => 'list',
-			NAV: () => 'navigation',
-			OL: () => 'list',
-			OPTION: () => 'option',
-			OUTPUT: () => 'status',
-			PROGRESS: () => 'progressbar',
-			SECTION: () => 'region',
-			SELECT: (el) => el.hasAttribute('multiple') ? 'listbox' : 'combobox',
-			SUMMARY: () => 'button',
-			TABLE: () => 'table',
-			TBODY: () => 'rowgroup', THEAD: () => 'rowgroup', TFOOT: () => 'rowgroup',
-			TD: () => 'cell',
-			TEXTAREA: () => 'textbox',
-			TH: () => 'columnheader',
-			TR: () => 'row',
-			UL: () => 'list',
-		};
+		` + FindByRoleJS() + `
 
-		function getImplicitRole(el) {
-			const explicit = el.getAttribute('role');
-			if (explicit) return explicit.toLowerCase();
-			const fn = IMPLICIT_ROLES[el.tagName];
-			return fn ? fn(el).toLowerCase() : '';
-		}
-
-		function getName(el) {
-			const ariaLabel = el.getAttribute('aria-label');
-			if (ariaLabel) return ariaLabel;
-			const labelledBy = el.getAttribute('aria-labelledby');
-			if (labelledBy) {
-				const parts = labelledBy.split(/\s+/).map(id => {
-					const ref = document.getElementById(id);
-					return ref ? (ref.textContent || '').trim() : '';
-				}).filter(Boolean);
-				if (parts.length) return parts.join(' ');
-			}
-			if (el.id) {
-				const assocLabel = document.querySelector('label[for="' + el.id + '"]');
-				if (assocLabel) return (assocLabel.textContent || '').trim();
-			}
-			const ph = el.getAttribute('placeholder');
-			if (ph) return ph;
-			const altAttr = el.getAttribute('alt');
-			if (altAttr) return altAttr;
-			const titleAttr = el.getAttribute('title');
-			if (titleAttr) return titleAttr;
-			return (el.textContent || '').trim();
-		}
-
-		let el = null;
+		const match = { exact, caseInsensitive };
+		let el = null;
 
 		if (role) {
-			// Role-based matching: walk all elements, filter by role + other criteria
-			const roleLower = role.toLowerCase();
-			const walker = document.createTreeWalker(document.body, NodeFilter.SHOW_ELEMENT);
-			const found = [];
-			let node;
-			while (node = walker.nextNode()) {
-				if (getImplicitRole(node) !== roleLower) continue;
-				// Apply additional filters
-				if (text && !(node.textContent || '').trim().includes(text)) continue;
-				if (label) {
-					const elName = getName(node);
-					if (!elName.includes(label)) continue;
-				}
-				if (placeholder) {
-					const ph = node.getAttribute('placeholder');
-					if (!ph || !ph.includes(placeholder)) continue;
-				}
-				if (testid) {
-					const tid = node.getAttribute('data-testid');
-					if (tid !== testid) continue;
-				}
-				if (alt) {
-					const a = node.getAttribute('alt');
-					if (!a || !a.includes(alt)) continue;
-				}
-				if (title) {
-					const t = node.getAttribute('title');
-					if (!t || !t.includes(title)) continue;
-				}
-				found.push(node);
-			}
+			const found = findByRole(role, text, label, placeholder, testid, alt, title, match);
 			if (found.length === 0) return null;
-			// Pick best: prefer shortest text match if text filter is used
+			// Pick best: prefer shortest text match if a substring text filter is used
 			el = found[0];
-			if (text && found.length > 1) {
+			if (text && !exact && found.length > 1) {
 				let bestLen = (el.textContent || '').length;
 				for (let i = 1; i < found.length; i++) {
 					const len = (found[i].textContent || '').length;
@@ -1073,7 +1580,7 @@ func findBySemanticScript() string {
 			// Try <label> with for= attribute pointing to an input
 			const labels = document.querySelectorAll('label');
 			for (const lbl of labels) {
-				if (lbl.textContent.trim().includes(label)) {
+				if (textMatches(lbl.textContent, label, match)) {
 					if (lbl.htmlFor) {
 						el = document.getElementById(lbl.htmlFor);
 					} else {
@@ -1092,19 +1599,21 @@ func findBySemanticScript() string {
 				for (const candidate of all) {
 					const labelId = candidate.getAttribute('aria-labelledby');
 					const labelEl = document.getElementById(labelId);
-					if (labelEl && labelEl.textContent.trim().includes(label)) {
+					if (labelEl && textMatches(labelEl.textContent, label, match)) {
 						el = candidate;
 						break;
 					}
 				}
 			}
 		} else if (text) {
-			// Find leaf elements containing the text
+			// Find leaf elements containing the text, optionally limited to
+			// elements matching a base CSS selector
 			const walker = document.createTreeWalker(document.body, NodeFilter.SHOW_ELEMENT, {
 				acceptNode: (node) => {
 					if (node.offsetWidth === 0 && node.offsetHeight === 0) return NodeFilter.FILTER_REJECT;
 					const style = window.getComputedStyle(node);
 					if (style.display === 'none' || style.visibility === 'hidden') return NodeFilter.FILTER_REJECT;
+					if (base && !node.matches(base)) return NodeFilter.FILTER_SKIP;
 					return NodeFilter.FILTER_ACCEPT;
 				}
 			});
@@ -1112,8 +1621,14 @@ func findBySemanticScript() string {
 			let bestLen = Infinity;
 			let node;
 			while (node = walker.nextNode()) {
+				if (!textMatches(node.textContent, text, match)) continue;
+				if (exact) {
+					// Exact matches are taken in document order
+					best = node;
+					break;
+				}
 				const content = node.textContent.trim();
-				if (content.includes(text) && content.length < bestLen) {
+				if (content.length < bestLen) {
 					// Prefer the most specific (smallest text) match
 					best = node;
 					bestLen = content.length;
@@ -1152,20 +1667,28 @@ func (h *Handlers) browserEvaluate(args map[string]interface{}) (*ToolsCallResul
 		return nil, fmt.Errorf("expression is required")
 	}
 
-	result, err := h.client.Evaluate("", expression)
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+
+	// With args, the expression is a function and args are bound positionally.
+	var result interface{}
+	if fnArgs, ok := args["args"].([]interface{}); ok {
+		result, err = api.EvaluateFunction(s, ctx, expression, fnArgs)
+	} else {
+		result, err = api.Evaluate(s, ctx, expression)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to evaluate: %w", err)
 	}
 
-	// Format result as string
-	var resultText string
-	switch v := result.(type) {
-	case string:
-		resultText = v
-	case nil:
-		resultText = "null"
-	default:
-		resultText = fmt.Sprintf("%v", v)
+	// Objects and arrays come back as JSON; strings stay unquoted for
+	// readability, so the value itself is returned as well.
+	resultText, err := formatEvalResult(result)
+	if err != nil {
+		return nil, err
 	}
 
 	return &ToolsCallResult{
@@ -1173,9 +1696,23 @@ func (h *Handlers) browserEvaluate(args map[string]interface{}) (*ToolsCallResul
 			Type: "text",
 			Text: resultText,
 		}},
+		StructuredContent: map[string]interface{}{"value": result},
 	}, nil
 }
 
+// formatEvalResult renders an evaluate result as text: JSON for objects,
+// arrays, numbers, booleans and null; strings raw.
+func formatEvalResult(result interface{}) (string, error) {
+	if str, ok := result.(string); ok {
+		return str, nil
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode result: %w", err)
+	}
+	return string(data), nil
+}
+
 // browserQuit closes the browser session.
 func (h *Handlers) browserQuit(args map[string]interface{}) (*ToolsCallResult, error) {
 	if h.client == nil {
@@ -1197,28 +1734,55 @@ func (h *Handlers) browserQuit(args map[string]interface{}) (*ToolsCallResult, e
 	}, nil
 }
 
-// browserNewPage creates a new browser page.
+// browserNewPage creates a new browser page and reports its index and
+// context ID. The new page becomes the active page unless switch is false.
 func (h *Handlers) browserNewPage(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
 		return nil, err
 	}
 
 	url, _ := args["url"].(string)
+	activate := true
+	if v, ok := args["switch"].(bool); ok {
+		activate = v
+	}
 
 	s := h.newSession()
 	contextID, err := api.NewPage(s, url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create page: %w", err)
 	}
-	// Activate and track the new page so subsequent commands target it
-	if err := api.SwitchPage(s, contextID); err != nil {
-		return nil, fmt.Errorf("failed to activate new page: %w", err)
+	if activate {
+		// Activate and track the new page so subsequent commands target it
+		if err := api.SwitchPage(s, contextID); err != nil {
+			return nil, fmt.Errorf("failed to activate new page: %w", err)
+		}
+		h.activeContext = contextID
+	} else if h.activeContext != "" {
+		// The browser focuses new tabs; give focus back to the active page
+		if err := api.SwitchPage(s, h.activeContext); err != nil {
+			return nil, fmt.Errorf("failed to reactivate page: %w", err)
+		}
+	}
+
+	pages, err := api.ListPages(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pages: %w", err)
+	}
+	index := -1
+	for i, page := range pages {
+		if page.Context == contextID {
+			index = i
+			break
+		}
 	}
-	h.activeContext = contextID
 
-	msg := "New page opened"
+	msg := fmt.Sprintf("New page [%d] opened (context %s)", index, contextID)
 	if url != "" {
-		msg = fmt.Sprintf("New page opened and navigated to %s", url)
+		msg = fmt.Sprintf("New page [%d] opened and navigated to %s (context %s)", index, url, contextID)
+	}
+	if !activate {
+		msg += " in the background"
 	}
 
 	return &ToolsCallResult{
@@ -1229,7 +1793,8 @@ func (h *Handlers) browserNewPage(args map[string]interface{}) (*ToolsCallResult
 	}, nil
 }
 
-// browserListPages lists all open browser pages.
+// browserListPages lists all open browser pages, followed by the pages that
+// closed recently.
 func (h *Handlers) browserListPages(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
 		return nil, err
@@ -1248,6 +1813,10 @@ func (h *Handlers) browserListPages(args map[string]interface{}) (*ToolsCallResu
 	if text == "" {
 		text = "No pages open"
 	}
+	// Recently closed pages, so an agent can tell that a popup closed itself
+	for _, page := range h.closedPages {
+		text += fmt.Sprintf("[closed %s] %s\n", page.Closed.Format("15:04:05"), page.URL)
+	}
 
 	return &ToolsCallResult{
 		Content: []Content{{
@@ -1257,7 +1826,8 @@ func (h *Handlers) browserListPages(args map[string]interface{}) (*ToolsCallResu
 	}, nil
 }
 
-// browserSwitchPage switches to a page by index or URL substring.
+// browserSwitchPage switches to a page by index, URL substring, or to the
+// most recently opened page (latest).
 func (h *Handlers) browserSwitchPage(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
 		return nil, err
@@ -1271,8 +1841,27 @@ func (h *Handlers) browserSwitchPage(args map[string]interface{}) (*ToolsCallRes
 
 	var contextID string
 
-	// Try index first
-	if idx, ok := args["index"].(float64); ok {
+	if latest, _ := args["latest"].(bool); latest {
+		if err := h.flushEvents(); err != nil {
+			return nil, err
+		}
+		// Newest page opened this session that is still open; otherwise
+		// the last page in the tree.
+		for i := len(h.openedPages) - 1; i >= 0 && contextID == ""; i-- {
+			for _, page := range pages {
+				if page.Context == h.openedPages[i].Context {
+					contextID = page.Context
+					break
+				}
+			}
+		}
+		if contextID == "" && len(pages) > 0 {
+			contextID = pages[len(pages)-1].Context
+		}
+		if contextID == "" {
+			return nil, fmt.Errorf("no pages open")
+		}
+	} else if idx, ok := args["index"].(float64); ok {
 		i := int(idx)
 		if i < 0 || i >= len(pages) {
 			return nil, fmt.Errorf("page index %d out of range (0-%d)", i, len(pages)-1)
@@ -1290,7 +1879,7 @@ func (h *Handlers) browserSwitchPage(args map[string]interface{}) (*ToolsCallRes
 			return nil, fmt.Errorf("no page matching URL %q", url)
 		}
 	} else {
-		return nil, fmt.Errorf("index or url is required")
+		return nil, fmt.Errorf("index, url, or latest is required")
 	}
 
 	if err := api.SwitchPage(s, contextID); err != nil {
@@ -1358,6 +1947,85 @@ func (h *Handlers) browserClosePage(args map[string]interface{}) (*ToolsCallResu
 	}, nil
 }
 
+// browserSwitchFrame makes a frame the active frame: later commands run
+// inside it until browser_switch_frame_parent, browser_switch_frame_top,
+// navigation, or a page switch. The frame is looked up below the current
+// active frame, so nested frames are entered one level at a time.
+func (h *Handlers) browserSwitchFrame(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	frame, _ := args["frame"].(string)
+	if frame == "" {
+		return nil, fmt.Errorf("frame is required")
+	}
+	frameCtx, err := h.frameContext(frame)
+	if err != nil {
+		return nil, err
+	}
+	h.frames = append(h.frames, frameCtx)
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Switched to frame: %s", frameCtx),
+		}},
+	}, nil
+}
+
+// browserSwitchFrameParent leaves the active frame for its parent frame, or
+// for the page when the active frame is top-level.
+func (h *Handlers) browserSwitchFrameParent(args map[string]interface{}) (*ToolsCallResult, error) {
+	if len(h.frames) == 0 {
+		return &ToolsCallResult{
+			Content: []Content{{Type: "text", Text: "Already on the top-level page"}},
+		}, nil
+	}
+	h.frames = h.frames[:len(h.frames)-1]
+	if len(h.frames) == 0 {
+		return &ToolsCallResult{
+			Content: []Content{{Type: "text", Text: "Switched to the top-level page"}},
+		}, nil
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Switched to parent frame: %s", h.frames[len(h.frames)-1]),
+		}},
+	}, nil
+}
+
+// browserSwitchFrameTop leaves all frames so commands run against the page.
+func (h *Handlers) browserSwitchFrameTop(args map[string]interface{}) (*ToolsCallResult, error) {
+	h.frames = nil
+	return &ToolsCallResult{
+		Content: []Content{{Type: "text", Text: "Switched to the top-level page"}},
+	}, nil
+}
+
+// navigatesPage reports whether a tool navigates the active page, which
+// leaves any frame entered with browser_switch_frame.
+func navigatesPage(name string) bool {
+	switch name {
+	case "browser_navigate", "browser_back", "browser_forward", "browser_reload":
+		return true
+	}
+	return false
+}
+
+// pageLevelTool reports whether a tool acts on pages or frame selection
+// rather than the document, and so ignores the active frame.
+func pageLevelTool(name string) bool {
+	switch name {
+	case "browser_new_page", "browser_list_pages", "browser_switch_page", "browser_close_page",
+		"browser_switch_frame_parent", "browser_switch_frame_top":
+		return true
+	}
+	return false
+}
+
 // browserA11yTree returns the accessibility tree of the current page.
 func (h *Handlers) browserA11yTree(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
@@ -1375,7 +2043,20 @@ func (h *Handlers) browserA11yTree(args map[string]interface{}) (*ToolsCallResul
 		return nil, err
 	}
 
-	result, err := api.A11yTree(s, ctx, interestingOnly, "")
+	var result string
+	if withSelectors, _ := args["withSelectors"].(bool); withSelectors {
+		elements, err := h.mapElements("")
+		if err != nil {
+			return nil, err
+		}
+		annotations := make(map[string]map[string]string, len(elements))
+		for _, el := range elements {
+			annotations[el.Selector] = map[string]string{"selector": el.Selector}
+		}
+		result, err = api.A11yTreeAnnotated(s, ctx, interestingOnly, annotations)
+	} else {
+		result, err = api.A11yTree(s, ctx, interestingOnly, "")
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get accessibility tree: %w", err)
 	}
@@ -1389,6 +2070,49 @@ func (h *Handlers) browserA11yTree(args map[string]interface{}) (*ToolsCallResul
 }
 
 
+// browserSnapshot returns the accessibility tree with the @refs of a fresh
+// browser_map attached to the nodes of interactive elements.
+func (h *Handlers) browserSnapshot(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	mapArgs := map[string]interface{}{}
+	if stable, ok := args["stableRefs"].(bool); ok {
+		mapArgs["stableRefs"] = stable
+	}
+	if _, err := h.browserMap(mapArgs); err != nil {
+		return nil, err
+	}
+	annotations := make(map[string]map[string]string, len(h.refMap))
+	for ref, selector := range h.refMap {
+		annotations[selector] = map[string]string{"ref": ref}
+	}
+
+	interestingOnly := true
+	if val, ok := args["everything"].(bool); ok {
+		interestingOnly = !val
+	}
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := api.A11yTreeAnnotated(s, ctx, interestingOnly, annotations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: result,
+		}},
+	}, nil
+}
+
 // browserHover moves the mouse over an element.
 func (h *Handlers) browserHover(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
@@ -1406,7 +2130,7 @@ func (h *Handlers) browserHover(args map[string]interface{}) (*ToolsCallResult,
 	if err != nil {
 		return nil, err
 	}
-	if err := api.Hover(s, ctx, api.ElementParams{Selector: selector}); err != nil {
+	if err := api.Hover(s, ctx, api.ElementParams{Selector: selector}, api.ExtractHoverOptions(args)); err != nil {
 		return nil, fmt.Errorf("failed to hover: %w", err)
 	}
 
@@ -1418,7 +2142,7 @@ func (h *Handlers) browserHover(args map[string]interface{}) (*ToolsCallResult,
 	}, nil
 }
 
-// browserSelect selects an option in a <select> element.
+// browserSelect selects one or more options in a <select> element by value or label.
 func (h *Handlers) browserSelect(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
 		return nil, err
@@ -1430,9 +2154,9 @@ func (h *Handlers) browserSelect(args map[string]interface{}) (*ToolsCallResult,
 	}
 	selector = h.resolveSelector(selector)
 
-	value, ok := args["value"].(string)
-	if !ok || value == "" {
-		return nil, fmt.Errorf("value is required")
+	spec, err := api.ExtractSelectSpec(args)
+	if err != nil {
+		return nil, err
 	}
 
 	s := h.newSession()
@@ -1440,24 +2164,98 @@ func (h *Handlers) browserSelect(args map[string]interface{}) (*ToolsCallResult,
 	if err != nil {
 		return nil, err
 	}
-	if err := api.SelectOption(s, ctx, api.ElementParams{Selector: selector}, value); err != nil {
+	selected, err := api.SelectOptions(s, ctx, api.ElementParams{Selector: selector}, spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	selectedJSON, _ := json.Marshal(selected)
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Selected values %s in %s", selectedJSON, selector),
+		}},
+	}, nil
+}
+
+// browserSelectByIndex selects the option at a zero-based position in a <select>.
+func (h *Handlers) browserSelectByIndex(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	selector, ok := args["selector"].(string)
+	if !ok || selector == "" {
+		return nil, fmt.Errorf("selector is required")
+	}
+	selector = h.resolveSelector(selector)
+
+	optionIndex, ok := args["optionIndex"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("optionIndex is required")
+	}
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	value, err := api.SelectByIndex(s, ctx, api.ElementParams{Selector: selector}, int(optionIndex))
+	if err != nil {
 		return nil, fmt.Errorf("failed to select: %w", err)
 	}
 
 	return &ToolsCallResult{
 		Content: []Content{{
 			Type: "text",
-			Text: fmt.Sprintf("Selected value %q in %s", value, selector),
+			Text: fmt.Sprintf("Selected option %d (value %q) in %s", int(optionIndex), value, selector),
 		}},
 	}, nil
 }
 
+// browserSelectOptions lists the options of a <select> element as JSON.
+func (h *Handlers) browserSelectOptions(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	selector, ok := args["selector"].(string)
+	if !ok || selector == "" {
+		return nil, fmt.Errorf("selector is required")
+	}
+	selector = h.resolveSelector(selector)
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	options, err := api.ListOptions(s, ctx, api.ElementParams{Selector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list options: %w", err)
+	}
+
+	data, err := json.Marshal(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal options: %w", err)
+	}
+	return &ToolsCallResult{
+		Content: []Content{{Type: "text", Text: string(data)}},
+	}, nil
+}
+
 // browserScroll scrolls the page or an element.
 func (h *Handlers) browserScroll(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
 		return nil, err
 	}
 
+	_, hasX := args["scrollX"]
+	_, hasY := args["scrollY"]
+	if to, _ := args["to"].(string); to != "" || hasX || hasY {
+		return h.scrollTo(args)
+	}
+
 	direction := "down"
 	if d, ok := args["direction"].(string); ok && d != "" {
 		direction = d
@@ -1516,15 +2314,16 @@ func (h *Handlers) browserScroll(args map[string]interface{}) (*ToolsCallResult,
 	}, nil
 }
 
-// browserKeys presses a key or key combination.
-func (h *Handlers) browserKeys(args map[string]interface{}) (*ToolsCallResult, error) {
+// browserScrollBy scrolls by an exact pixel delta with a single wheel event.
+func (h *Handlers) browserScrollBy(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
 		return nil, err
 	}
 
-	keys, ok := args["keys"].(string)
-	if !ok || keys == "" {
-		return nil, fmt.Errorf("keys is required")
+	deltaX, _ := args["deltaX"].(float64)
+	deltaY, _ := args["deltaY"].(float64)
+	if deltaX == 0 && deltaY == 0 {
+		return nil, fmt.Errorf("deltaX or deltaY is required")
 	}
 
 	s := h.newSession()
@@ -1532,22 +2331,49 @@ func (h *Handlers) browserKeys(args map[string]interface{}) (*ToolsCallResult, e
 	if err != nil {
 		return nil, err
 	}
-	if err := api.PressKey(s, ctx, keys); err != nil {
-		return nil, fmt.Errorf("failed to press keys: %w", err)
+
+	x, y := 400, 300 // Viewport center fallback
+	if selector, ok := args["selector"].(string); ok && selector != "" {
+		selector = h.resolveSelector(selector)
+		info, err := api.ResolveElement(s, ctx, api.ElementParams{Selector: selector})
+		if err != nil {
+			return nil, err
+		}
+		x = int(info.Box.X + info.Box.Width/2)
+		y = int(info.Box.Y + info.Box.Height/2)
+	}
+
+	pos, err := api.ScrollBy(s, ctx, x, y, int(deltaX), int(deltaY))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scroll: %w", err)
 	}
 
 	return &ToolsCallResult{
 		Content: []Content{{
 			Type: "text",
-			Text: fmt.Sprintf("Pressed keys: %s", keys),
+			Text: fmt.Sprintf("Scrolled by (%g, %g) (scrollX: %g, scrollY: %g)", deltaX, deltaY, pos.X, pos.Y),
 		}},
 	}, nil
 }
 
-// browserGetHTML returns the HTML content of the page or an element.
-func (h *Handlers) browserGetHTML(args map[string]interface{}) (*ToolsCallResult, error) {
-	if err := h.ensureBrowser(); err != nil {
-		return nil, err
+// scrollTo handles browser_scroll's absolute targets: to ("top" or
+// "bottom"), or a scrollX/scrollY position.
+func (h *Handlers) scrollTo(args map[string]interface{}) (*ToolsCallResult, error) {
+	target, _ := args["to"].(string)
+	var x, y *float64
+	if fx, ok := args["scrollX"].(float64); ok {
+		x = &fx
+	}
+	if fy, ok := args["scrollY"].(float64); ok {
+		y = &fy
+	}
+	switch {
+	case target != "" && (x != nil || y != nil):
+		return nil, fmt.Errorf("use either to or scrollX/scrollY, not both")
+	case target != "" && target != "top" && target != "bottom":
+		return nil, fmt.Errorf("invalid to: %q (use top or bottom)", target)
+	case target == "" && x == nil && y == nil:
+		return nil, fmt.Errorf("scrollX and scrollY must be numbers")
 	}
 
 	s := h.newSession()
@@ -1556,33 +2382,284 @@ func (h *Handlers) browserGetHTML(args map[string]interface{}) (*ToolsCallResult
 		return nil, err
 	}
 
-	outer, _ := args["outer"].(bool)
-
-	var html string
-	if selector, ok := args["selector"].(string); ok && selector != "" {
-		selector = h.resolveSelector(selector)
-		ep := api.ElementParams{Selector: selector}
-		if outer {
-			html, err = api.GetOuterHTML(s, ctx, ep)
-		} else {
-			html, err = api.GetInnerHTML(s, ctx, ep)
-		}
-	} else {
-		html, err = api.GetContent(s, ctx)
-	}
+	pos, err := api.ScrollTo(s, ctx, target, x, y)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get HTML: %w", err)
+		return nil, fmt.Errorf("failed to scroll: %w", err)
 	}
 
+	label := target
+	if label == "" {
+		label = "position"
+	}
 	return &ToolsCallResult{
 		Content: []Content{{
 			Type: "text",
-			Text: html,
+			Text: fmt.Sprintf("Scrolled to %s (scrollX: %g, scrollY: %g)", label, pos.X, pos.Y),
 		}},
 	}, nil
 }
 
-// browserFindAll finds all elements matching a CSS selector.
+// browserKeys presses a key or key combination, or a sequence of them.
+func (h *Handlers) browserKeys(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	var sequence []string
+	if raw, ok := args["sequence"].([]interface{}); ok {
+		for _, k := range raw {
+			if key, ok := k.(string); ok && key != "" {
+				sequence = append(sequence, key)
+			}
+		}
+	}
+	keys, _ := args["keys"].(string)
+	if keys == "" && len(sequence) == 0 {
+		return nil, fmt.Errorf("keys or sequence is required")
+	}
+	if keys != "" {
+		sequence = append([]string{keys}, sequence...)
+	}
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	if err := api.PressKeys(s, ctx, sequence); err != nil {
+		return nil, fmt.Errorf("failed to press keys: %w", err)
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Pressed keys: %s", strings.Join(sequence, " ")),
+		}},
+	}, nil
+}
+
+// browserKeySequence performs an ordered list of key down/up/press steps,
+// e.g. holding Shift across several ArrowDown presses.
+func (h *Handlers) browserKeySequence(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	steps, err := api.ExtractKeySequence(args)
+	if err != nil {
+		return nil, err
+	}
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	if err := api.PressKeySequence(s, ctx, steps); err != nil {
+		return nil, fmt.Errorf("failed to perform key sequence: %w", err)
+	}
+
+	parts := make([]string, len(steps))
+	for i, step := range steps {
+		parts[i] = step.Action + ":" + step.Key
+	}
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Performed key sequence: %s", strings.Join(parts, " ")),
+		}},
+	}, nil
+}
+
+// browserGetHTML returns the HTML content of the page or an element.
+func (h *Handlers) browserGetHTML(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+
+	outer, _ := args["outer"].(bool)
+
+	var html string
+	if selector, ok := args["selector"].(string); ok && selector != "" {
+		selector = h.resolveSelector(selector)
+		ep := api.ElementParams{Selector: selector}
+		if outer {
+			html, err = api.GetOuterHTML(s, ctx, ep)
+		} else {
+			html, err = api.GetInnerHTML(s, ctx, ep)
+		}
+	} else {
+		html, err = api.GetContent(s, ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HTML: %w", err)
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: html,
+		}},
+	}, nil
+}
+
+// browserGetOuterHTML returns an element's outerHTML, including its own tag.
+func (h *Handlers) browserGetOuterHTML(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	selector, ok := args["selector"].(string)
+	if !ok || selector == "" {
+		return nil, fmt.Errorf("selector is required")
+	}
+	selector = h.resolveSelector(selector)
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+
+	html, err := api.GetOuterHTML(s, ctx, api.ElementParams{Selector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outer HTML: %w", err)
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: html,
+		}},
+	}, nil
+}
+
+// browserGetTable extracts a table's cell text as a JSON 2D array, or with
+// headers as an array of row objects keyed by header text.
+func (h *Handlers) browserGetTable(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	selector, ok := args["selector"].(string)
+	if !ok || selector == "" {
+		return nil, fmt.Errorf("selector is required")
+	}
+	selector = h.resolveSelector(selector)
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	table, err := api.GetTable(s, ctx, api.ElementParams{Selector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table: %w", err)
+	}
+
+	var result interface{} = table.Rows
+	if headers, _ := args["headers"].(bool); headers {
+		records, err := table.Records()
+		if err != nil {
+			return nil, err
+		}
+		result = records
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal table: %w", err)
+	}
+	return &ToolsCallResult{
+		Content: []Content{{Type: "text", Text: string(data)}},
+	}, nil
+}
+
+// browserGetLinks lists the page's links as JSON, optionally filtered by a
+// URL pattern.
+func (h *Handlers) browserGetLinks(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	var match func(string) bool
+	if pattern, _ := args["pattern"].(string); pattern != "" {
+		regex, _ := args["regex"].(bool)
+		m, err := api.URLMatcher(pattern, regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", pattern, err)
+		}
+		match = m
+	}
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	links, err := api.GetLinks(s, ctx, match)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get links: %w", err)
+	}
+
+	data, err := json.Marshal(links)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal links: %w", err)
+	}
+	return &ToolsCallResult{
+		Content: []Content{{Type: "text", Text: string(data)}},
+	}, nil
+}
+
+// browserGetElementHTMLAndText returns an element's outerHTML and innerText
+// together. The HTML is truncated to maxLength characters.
+func (h *Handlers) browserGetElementHTMLAndText(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	selector, ok := args["selector"].(string)
+	if !ok || selector == "" {
+		return nil, fmt.Errorf("selector is required")
+	}
+	selector = h.resolveSelector(selector)
+
+	maxLength := 5000
+	if m, ok := args["maxLength"].(float64); ok && m > 0 {
+		maxLength = int(m)
+	}
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := api.GetHTMLAndText(s, ctx, api.ElementParams{Selector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get element content: %w", err)
+	}
+
+	if html := []rune(content.OuterHTML); len(html) > maxLength {
+		content.OuterHTML = fmt.Sprintf("%s... [truncated %d characters]", string(html[:maxLength]), len(html)-maxLength)
+	}
+
+	contentJSON, _ := json.Marshal(content)
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: string(contentJSON),
+		}},
+	}, nil
+}
+
+// browserFindAll finds all elements matching a CSS selector.
 func (h *Handlers) browserFindAll(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
 		return nil, err
@@ -1603,7 +2680,8 @@ func (h *Handlers) browserFindAll(args map[string]interface{}) (*ToolsCallResult
 	findAllScript := `(selector, limit) => {
 		` + GetSelectorJS() + `
 		` + GetLabelJS() + `
-		const els = document.querySelectorAll(selector);
+		` + api.DeepQueryJS() + `
+		const els = queryAllDeep(document, selector);
 		const results = [];
 		const n = Math.min(els.length, limit);
 		for (let i = 0; i < n; i++) {
@@ -1612,7 +2690,7 @@ func (h *Handlers) browserFindAll(args map[string]interface{}) (*ToolsCallResult
 		}
 		return JSON.stringify(results);
 	}`
-	result, err := h.client.CallFunction("", findAllScript, []interface{}{selector, limit})
+	result, err := h.client.CallFunction(h.activeContext, findAllScript, []interface{}{selector, limit})
 	if err != nil {
 		return nil, fmt.Errorf("failed to find elements: %w", err)
 	}
@@ -1647,6 +2725,73 @@ func (h *Handlers) browserFindAll(args map[string]interface{}) (*ToolsCallResult
 	}, nil
 }
 
+// browserFindAllByRole returns every element with an ARIA role, optionally
+// filtered by accessible name, as a JSON array in document order. Each match
+// gets an @ref (@e1 for index 0, and so on).
+func (h *Handlers) browserFindAllByRole(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	role, _ := args["role"].(string)
+	if role == "" {
+		return nil, fmt.Errorf("role is required")
+	}
+	name, _ := args["name"].(string)
+	limit := 0
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	script := `(role, name, limit) => {
+		` + GetSelectorJS() + `
+		` + FindByRoleJS() + `
+		let found = findByRole(role, '', name, '', '', '', '');
+		if (limit > 0) found = found.slice(0, limit);
+		return JSON.stringify(found.map((el, index) => {
+			const rect = el.getBoundingClientRect();
+			return {
+				index,
+				selector: getSelector(el),
+				tag: el.tagName.toLowerCase(),
+				name: getName(el).substring(0, 100),
+				text: (el.textContent || '').trim().substring(0, 100),
+				box: { x: Math.round(rect.x), y: Math.round(rect.y), w: Math.round(rect.width), h: Math.round(rect.height) }
+			};
+		}));
+	}`
+	result, err := h.client.CallFunction(h.activeContext, script, []interface{}{role, name, limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find elements: %w", err)
+	}
+
+	var matches []map[string]interface{}
+	if err := json.Unmarshal([]byte(fmt.Sprintf("%v", result)), &matches); err != nil {
+		return nil, fmt.Errorf("failed to parse find results: %w", err)
+	}
+
+	h.refMap = make(map[string]string)
+	for i, m := range matches {
+		ref := fmt.Sprintf("@e%d", i+1)
+		if sel, ok := m["selector"].(string); ok {
+			h.refMap[ref] = sel
+		}
+		m["ref"] = ref
+		delete(m, "selector")
+	}
+	if matches == nil {
+		matches = []map[string]interface{}{}
+	}
+
+	data, err := json.Marshal(matches)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal find results: %w", err)
+	}
+	return &ToolsCallResult{
+		Content: []Content{{Type: "text", Text: string(data)}},
+	}, nil
+}
+
 // browserWait waits for an element to reach a specified state.
 func (h *Handlers) browserWait(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
@@ -1691,8 +2836,24 @@ func (h *Handlers) browserWait(args map[string]interface{}) (*ToolsCallResult, e
 		if err := api.WaitForHidden(s, ctx, ep); err != nil {
 			return nil, err
 		}
+	case "enabled":
+		if err := api.WaitForEnabled(s, ctx, ep); err != nil {
+			return nil, err
+		}
+	case "editable":
+		if err := api.WaitForEditable(s, ctx, ep); err != nil {
+			return nil, err
+		}
+	case "stable":
+		polls := api.DefaultStablePolls
+		if n, ok := args["stablePolls"].(float64); ok && n > 0 {
+			polls = int(n)
+		}
+		if err := api.WaitForStable(s, ctx, ep, polls); err != nil {
+			return nil, err
+		}
 	default:
-		return nil, fmt.Errorf("invalid state: %q (use \"attached\", \"visible\", or \"hidden\")", state)
+		return nil, fmt.Errorf("invalid state: %q (use \"attached\", \"visible\", \"hidden\", \"enabled\", \"editable\", or \"stable\")", state)
 	}
 
 	return &ToolsCallResult{
@@ -1715,12 +2876,28 @@ func (h *Handlers) browserGetText(args map[string]interface{}) (*ToolsCallResult
 		return nil, err
 	}
 
+	mode := "innerText"
+	if m, ok := args["mode"].(string); ok && m != "" {
+		mode = m
+	}
+	if mode != "innerText" && mode != "textContent" {
+		return nil, fmt.Errorf("invalid mode: %q (use \"innerText\" or \"textContent\")", mode)
+	}
+
 	var text string
 	if selector, ok := args["selector"].(string); ok && selector != "" {
 		selector = h.resolveSelector(selector)
-		text, err = api.GetInnerText(s, ctx, api.ElementParams{Selector: selector})
+		if all, _ := args["all"].(bool); all {
+			return h.getAllText(s, ctx, selector, mode)
+		}
+		ep := api.ElementParams{Selector: selector}
+		if mode == "textContent" {
+			text, err = api.GetTextContent(s, ctx, ep)
+		} else {
+			text, err = api.GetInnerText(s, ctx, ep)
+		}
 	} else {
-		text, err = api.EvalSimpleScript(s, ctx, "() => document.body.innerText")
+		text, err = api.EvalSimpleScript(s, ctx, "() => document.body."+mode)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get text: %w", err)
@@ -1734,6 +2911,32 @@ func (h *Handlers) browserGetText(args map[string]interface{}) (*ToolsCallResult
 	}, nil
 }
 
+// getAllText returns the text of every element matching selector,
+// newline-joined.
+func (h *Handlers) getAllText(s *api.AgentSession, ctx, selector, mode string) (*ToolsCallResult, error) {
+	getAll := api.GetAllInnerText
+	if mode == "textContent" {
+		getAll = api.GetAllTextContent
+	}
+	texts, err := getAll(s, ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get text: %w", err)
+	}
+	if texts == nil {
+		texts = []string{}
+	}
+
+	// Newline-joined text can't be split back apart, so the list is also
+	// returned as-is for callers that need it.
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: strings.Join(texts, "\n"),
+		}},
+		StructuredContent: map[string]interface{}{"texts": texts},
+	}, nil
+}
+
 // browserGetURL returns the current page URL.
 func (h *Handlers) browserGetURL(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
@@ -2006,7 +3209,7 @@ func pollCallFunction(h *Handlers, script string, args []interface{}, timeout ti
 	interval := 100 * time.Millisecond
 
 	for {
-		result, err := h.client.CallFunction("", script, args)
+		result, err := h.client.CallFunction(h.activeContext, script, args)
 		if err == nil && result != nil {
 			s := fmt.Sprintf("%v", result)
 			if s != "" && s != "null" && s != "<nil>" {
@@ -2048,7 +3251,7 @@ func (h *Handlers) browserFill(args map[string]interface{}) (*ToolsCallResult, e
 	if err != nil {
 		return nil, err
 	}
-	if err := api.Fill(s, ctx, api.ElementParams{Selector: selector}, value); err != nil {
+	if err := api.Fill(s, ctx, actionParams(selector, args), value); err != nil {
 		return nil, fmt.Errorf("failed to fill: %w", err)
 	}
 
@@ -2060,13 +3263,42 @@ func (h *Handlers) browserFill(args map[string]interface{}) (*ToolsCallResult, e
 	}, nil
 }
 
-// browserPress presses a key on a specific element or the focused element.
-func (h *Handlers) browserPress(args map[string]interface{}) (*ToolsCallResult, error) {
+// browserClear empties an input field without clicking it.
+func (h *Handlers) browserClear(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
 		return nil, err
 	}
 
-	key, ok := args["key"].(string)
+	selector, ok := args["selector"].(string)
+	if !ok || selector == "" {
+		return nil, fmt.Errorf("selector is required")
+	}
+	selector = h.resolveSelector(selector)
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	if err := api.Clear(s, ctx, api.ElementParams{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to clear: %w", err)
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Cleared %s", selector),
+		}},
+	}, nil
+}
+
+// browserPress presses a key on a specific element or the focused element.
+func (h *Handlers) browserPress(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	key, ok := args["key"].(string)
 	if !ok || key == "" {
 		return nil, fmt.Errorf("key is required")
 	}
@@ -2231,6 +3463,50 @@ func (h *Handlers) browserGetAttribute(args map[string]interface{}) (*ToolsCallR
 	}, nil
 }
 
+// browserGetAttributes returns several attributes of an element as a JSON object.
+func (h *Handlers) browserGetAttributes(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	selector, ok := args["selector"].(string)
+	if !ok || selector == "" {
+		return nil, fmt.Errorf("selector is required")
+	}
+	selector = h.resolveSelector(selector)
+
+	rawNames, ok := args["attributes"].([]interface{})
+	if !ok || len(rawNames) == 0 {
+		return nil, fmt.Errorf("attributes is required")
+	}
+	names := make([]string, 0, len(rawNames))
+	for _, n := range rawNames {
+		name, ok := n.(string)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("attributes must be an array of non-empty strings")
+		}
+		names = append(names, name)
+	}
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := api.GetAttributes(s, ctx, api.ElementParams{Selector: selector}, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attributes: %w", err)
+	}
+
+	attrsJSON, _ := json.Marshal(attrs)
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: string(attrsJSON),
+		}},
+	}, nil
+}
+
 // browserIsVisible checks if an element is visible on the page.
 func (h *Handlers) browserIsVisible(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
@@ -2284,7 +3560,7 @@ func (h *Handlers) browserCheck(args map[string]interface{}) (*ToolsCallResult,
 	if err != nil {
 		return nil, err
 	}
-	toggled, err := api.Check(s, ctx, api.ElementParams{Selector: selector})
+	toggled, err := api.Check(s, ctx, actionParams(selector, args))
 	if err != nil {
 		return nil, fmt.Errorf("failed to check: %w", err)
 	}
@@ -2319,7 +3595,7 @@ func (h *Handlers) browserUncheck(args map[string]interface{}) (*ToolsCallResult
 	if err != nil {
 		return nil, err
 	}
-	toggled, err := api.Uncheck(s, ctx, api.ElementParams{Selector: selector})
+	toggled, err := api.Uncheck(s, ctx, actionParams(selector, args))
 	if err != nil {
 		return nil, fmt.Errorf("failed to uncheck: %w", err)
 	}
@@ -2387,7 +3663,12 @@ func (h *Handlers) browserWaitForURL(args map[string]interface{}) (*ToolsCallRes
 	if err != nil {
 		return nil, err
 	}
-	url, err := api.WaitForURL(s, ctx, pattern, timeout)
+	var url string
+	if isRegex, _ := args["regex"].(bool); isRegex {
+		url, err = api.WaitForURLRegex(s, ctx, pattern, timeout)
+	} else {
+		url, err = api.WaitForURL(s, ctx, pattern, timeout)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -2481,6 +3762,121 @@ func (h *Handlers) resolveRefsInArgs(args map[string]interface{}) map[string]int
 	return cp
 }
 
+// frameContext resolves a frame argument — a frame name, a URL substring,
+// or the @ref of an <iframe> element — to the frame's browsing context,
+// searching the frames below the active page.
+func (h *Handlers) frameContext(frame string) (string, error) {
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return "", err
+	}
+
+	if strings.HasPrefix(frame, "@e") {
+		selector := h.resolveSelector(frame)
+		if selector == frame {
+			return "", fmt.Errorf("unknown ref %s (run browser_map or browser_find first)", frame)
+		}
+		frameCtx, err := api.FrameContextForElement(s, ctx, api.ElementParams{Selector: selector, Timeout: api.DefaultTimeout})
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve frame %s: %w", frame, err)
+		}
+		return frameCtx, nil
+	}
+
+	found, err := api.FindFrame(s, ctx, frame)
+	if err != nil {
+		return "", fmt.Errorf("failed to find frame: %w", err)
+	}
+	if found == nil {
+		return "", fmt.Errorf("no frame matching %q", frame)
+	}
+	return found.Context, nil
+}
+
+// resolveSelectorArgs returns args with XPath (xpath=//a, //a, (//a)[2])
+// and Playwright-style text selectors (text=Submit, text="Submit",
+// button:has-text("Save")) in selector, source, and target replaced by the
+// CSS selector of the element they match, found through the same search as
+// browser_find's xpath and text locators, so every tool accepts them. Tools
+// for which a missing element is an answer (is_visible, count, waiting for
+// hidden) get a selector that matches nothing instead of waiting for it.
+// Tools that read every match of selector (see readsAllMatches) query text
+// selectors in the page instead, so no match is dropped.
+func (h *Handlers) resolveSelectorArgs(name string, args map[string]interface{}) (map[string]interface{}, error) {
+	var resolved map[string]interface{}
+	for _, key := range []string{"selector", "scope", "source", "target"} {
+		sel, _ := args[key].(string)
+		var findArgs []interface{}
+		if xpath, ok := api.ParseXPathSelector(sel); ok {
+			if name == "browser_count" && key == "selector" {
+				continue // counted natively so every match is included
+			}
+			findArgs = []interface{}{"", "", "", "", "", xpath, "", "", false, false, ""}
+		} else if ts, ok := api.ParseTextSelector(sel); ok {
+			if key == "selector" && readsAllMatches(name, args) {
+				continue // queryAllDeep matches text selectors itself
+			}
+			findArgs = []interface{}{"", ts.Text, "", "", "", "", "", "", ts.Exact, ts.CaseInsensitive, ts.Base}
+		} else {
+			continue
+		}
+		if err := h.ensureBrowser(); err != nil {
+			return nil, err
+		}
+
+		timeout := api.DefaultTimeout
+		if t, ok := args["timeout"].(float64); ok && t > 0 {
+			timeout = time.Duration(t) * time.Millisecond
+		}
+		state, _ := args["state"].(string)
+		mayBeMissing := name == "browser_is_visible" || name == "browser_is_editable" || name == "browser_count" ||
+			(name == "browser_wait" && (state == "hidden" || state == "detached"))
+		if mayBeMissing {
+			timeout = 0
+		}
+
+		css := ":not(*)"
+		result, err := pollCallFunction(h, findBySemanticScript(), findArgs, timeout)
+		if err == nil {
+			var found struct {
+				Selector string `json:"selector"`
+			}
+			if err := json.Unmarshal([]byte(fmt.Sprintf("%v", result)), &found); err != nil {
+				return nil, fmt.Errorf("failed to parse find result: %w", err)
+			}
+			css = found.Selector
+		} else if !mayBeMissing {
+			return nil, fmt.Errorf("element not found: %s (timeout %s)", sel, timeout)
+		}
+
+		if resolved == nil {
+			resolved = make(map[string]interface{}, len(args))
+			for k, v := range args {
+				resolved[k] = v
+			}
+		}
+		resolved[key] = css
+	}
+	if resolved == nil {
+		return args, nil
+	}
+	return resolved, nil
+}
+
+// readsAllMatches reports whether a tool reads every element matching its
+// selector argument rather than the first one.
+func readsAllMatches(name string, args map[string]interface{}) bool {
+	switch name {
+	case "browser_count", "browser_find_all":
+		return true
+	case "browser_get_text":
+		all, _ := args["all"].(bool)
+		return all
+	}
+	return false
+}
+
 // resolveSelector resolves @ref selectors to CSS selectors from the refMap.
 func (h *Handlers) resolveSelector(selector string) string {
 	if strings.HasPrefix(selector, "@e") {
@@ -2491,10 +3887,26 @@ func (h *Handlers) resolveSelector(selector string) string {
 	return selector
 }
 
+// actionParams builds the element params for an action on selector, applying
+// the per-call options tools share: timeout (ms) bounds the actionability
+// wait, and force skips the actionability checks.
+func actionParams(selector string, args map[string]interface{}) api.ElementParams {
+	ep := api.ElementParams{Selector: selector, Timeout: api.DefaultTimeout}
+	if t, ok := args["timeout"].(float64); ok && t > 0 {
+		ep.Timeout = time.Duration(t) * time.Millisecond
+	}
+	ep.Force, _ = args["force"].(bool)
+	return ep
+}
+
 // GetSelectorJS returns the JS getSelector(el) function body that generates unique CSS selectors.
 func GetSelectorJS() string {
 	return `function getSelector(el) {
-			if (el.id) return '#' + CSS.escape(el.id);
+			// Elements inside an open shadow root are addressed through
+			// their host with the >>> piercing combinator.
+			const rootNode = el.getRootNode();
+			const prefix = rootNode instanceof ShadowRoot ? getSelector(rootNode.host) + ' >>> ' : '';
+			if (el.id) return prefix + '#' + CSS.escape(el.id);
 			const parts = [];
 			let cur = el;
 			while (cur && cur !== document.body && cur !== document.documentElement) {
@@ -2514,9 +3926,197 @@ func GetSelectorJS() string {
 				parts.unshift(seg);
 				cur = parent;
 			}
-			if (parts.length === 0) return el.tagName.toLowerCase();
-			if (!parts[0].startsWith('#')) parts.unshift('body');
-			return parts.join(' > ');
+			if (parts.length === 0) return prefix + el.tagName.toLowerCase();
+			if (!prefix && !parts[0].startsWith('#')) parts.unshift('body');
+			return prefix + parts.join(' > ');
+		}`
+}
+
+// GetImplicitRoleJS returns JS defining getImplicitRole(el): the element's
+// explicit role attribute, or the ARIA role implied by its tag.
+func GetImplicitRoleJS() string {
+	return `const IMPLICIT_ROLES = {
+			A: (el) => el.hasAttribute('href') ? 'link' : '',
+			AREA: (el) => el.hasAttribute('href') ? 'link' : '',
+			ARTICLE: () => 'article',
+			ASIDE: () => 'complementary',
+			BUTTON: () => 'button',
+			DETAILS: () => 'group',
+			DIALOG: () => 'dialog',
+			FOOTER: () => 'contentinfo',
+			FORM: () => 'form',
+			H1: () => 'heading', H2: () => 'heading', H3: () => 'heading',
+			H4: () => 'heading', H5: () => 'heading', H6: () => 'heading',
+			HEADER: () => 'banner',
+			HR: () => 'separator',
+			IMG: (el) => el.getAttribute('alt') ? 'img' : 'presentation',
+			INPUT: (el) => {
+				const t = (el.getAttribute('type') || 'text').toLowerCase();
+				const map = {button:'button',checkbox:'checkbox',image:'button',
+					number:'spinbutton',radio:'radio',range:'slider',
+					reset:'button',search:'searchbox',submit:'button',text:'textbox',
+					email:'textbox',tel:'textbox',url:'textbox',password:'textbox'};
+				return map[t] || 'textbox';
+			},
+			LI: () => 'listitem',
+			MAIN: () => 'main',
+			MENU: () => 'list',
+			NAV: () => 'navigation',
+			OL: () => 'list',
+			OPTION: () => 'option',
+			OUTPUT: () => 'status',
+			PROGRESS: () => 'progressbar',
+			SECTION: () => 'region',
+			SELECT: (el) => el.hasAttribute('multiple') ? 'listbox' : 'combobox',
+			SUMMARY: () => 'button',
+			TABLE: () => 'table',
+			TBODY: () => 'rowgroup', THEAD: () => 'rowgroup', TFOOT: () => 'rowgroup',
+			TD: () => 'cell',
+			TEXTAREA: () => 'textbox',
+			TH: () => 'columnheader',
+			TR: () => 'row',
+			UL: () => 'list',
+		};
+
+		function getImplicitRole(el) {
+			const explicit = el.getAttribute('role');
+			if (explicit) return explicit.toLowerCase();
+			const fn = IMPLICIT_ROLES[el.tagName];
+			return fn ? fn(el).toLowerCase() : '';
+		}`
+}
+
+// FindByRoleJS returns JS defining getName(el), an approximation of the
+// element's accessible name, textMatches(actual, expected, match), and
+// findByRole(role, text, label, placeholder, testid, alt, title, match),
+// which returns every element with the role (explicit or implicit) that
+// passes the other filters, in document order. Text and label filters are
+// case-sensitive substring matches unless match sets exact (equality after
+// trimming) or caseInsensitive.
+func FindByRoleJS() string {
+	return GetImplicitRoleJS() + `
+
+		function textMatches(actual, expected, match) {
+			let a = (actual || '').trim();
+			let e = match && match.exact ? expected.trim() : expected;
+			if (match && match.caseInsensitive) {
+				a = a.toLowerCase();
+				e = e.toLowerCase();
+			}
+			return match && match.exact ? a === e : a.includes(e);
+		}
+
+		// Roles whose accessible name comes from their content.
+		const NAME_FROM_CONTENT = new Set([
+			'button', 'cell', 'checkbox', 'columnheader', 'gridcell', 'heading',
+			'link', 'menuitem', 'menuitemcheckbox', 'menuitemradio', 'option',
+			'radio', 'row', 'rowheader', 'switch', 'tab', 'tooltip', 'treeitem',
+		]);
+
+		function hiddenFromName(el) {
+			if (el.getAttribute('aria-hidden') === 'true') return true;
+			if (['SCRIPT', 'STYLE', 'TEMPLATE', 'NOSCRIPT'].includes(el.tagName)) return true;
+			const style = window.getComputedStyle(el);
+			return style.display === 'none' || style.visibility === 'hidden';
+		}
+
+		// nameFromContent concatenates the accessible text of el's children:
+		// text nodes, a child's own aria-label or alt text in place of its
+		// content, and nothing from hidden or aria-hidden subtrees. Inline
+		// children join their neighbours directly ("foo<b>bar</b>" is
+		// "foobar"); block children are separated by a space.
+		function nameFromContent(el) {
+			const parts = [];
+			for (const child of el.childNodes) {
+				if (child.nodeType === Node.TEXT_NODE) {
+					parts.push(child.textContent);
+					continue;
+				}
+				if (child.nodeType !== Node.ELEMENT_NODE || hiddenFromName(child)) continue;
+				const childLabel = (child.getAttribute('aria-label') || '').trim();
+				let text;
+				if (childLabel) {
+					text = childLabel;
+				} else if (child.tagName === 'IMG' || child.tagName === 'AREA') {
+					text = child.getAttribute('alt') || '';
+				} else {
+					text = nameFromContent(child);
+				}
+				const inline = window.getComputedStyle(child).display.startsWith('inline');
+				parts.push(inline ? text : ' ' + text + ' ');
+			}
+			return parts.join('').replace(/\s+/g, ' ').trim();
+		}
+
+		// getName approximates the accessible name computation: aria-label,
+		// then aria-labelledby, then for form controls the associated <label>
+		// followed by title/placeholder, for images alt/title, and for
+		// name-from-content roles (buttons, links, tabs, ...) the text of
+		// their children. Everything else falls back to title, then to its
+		// text content.
+		function getName(el) {
+			const ariaLabel = (el.getAttribute('aria-label') || '').trim();
+			if (ariaLabel) return ariaLabel;
+			const labelledBy = el.getAttribute('aria-labelledby');
+			if (labelledBy) {
+				const parts = labelledBy.split(/\s+/).map(id => {
+					const ref = document.getElementById(id);
+					return ref ? nameFromContent(ref) : '';
+				}).filter(Boolean);
+				if (parts.length) return parts.join(' ');
+			}
+			const title = (el.getAttribute('title') || '').trim();
+			const tag = el.tagName;
+			if (tag === 'INPUT' || tag === 'SELECT' || tag === 'TEXTAREA') {
+				const type = (el.getAttribute('type') || '').toLowerCase();
+				if (tag === 'INPUT' && ['button', 'submit', 'reset'].includes(type)) {
+					return el.value || title || (type === 'submit' ? 'Submit' : type === 'reset' ? 'Reset' : '');
+				}
+				if (tag === 'INPUT' && type === 'image') {
+					return (el.getAttribute('alt') || '').trim() || title || 'Submit';
+				}
+				const fromLabels = Array.from(el.labels || []).map(nameFromContent).filter(Boolean).join(' ');
+				if (fromLabels) return fromLabels;
+				return title || (el.getAttribute('placeholder') || '').trim();
+			}
+			if (tag === 'IMG' || tag === 'AREA') {
+				return (el.getAttribute('alt') || '').trim() || title;
+			}
+			if (NAME_FROM_CONTENT.has(getImplicitRole(el))) {
+				const content = nameFromContent(el);
+				if (content) return content;
+			}
+			return title || (el.textContent || '').replace(/\s+/g, ' ').trim();
+		}
+
+		function findByRole(role, text, label, placeholder, testid, alt, title, match) {
+			const roleLower = role.toLowerCase();
+			const walker = document.createTreeWalker(document.body, NodeFilter.SHOW_ELEMENT);
+			const found = [];
+			let node;
+			while (node = walker.nextNode()) {
+				if (getImplicitRole(node) !== roleLower) continue;
+				if (text && !textMatches(node.textContent, text, match)) continue;
+				if (label && !textMatches(getName(node), label, match)) continue;
+				if (placeholder) {
+					const ph = node.getAttribute('placeholder');
+					if (!ph || !ph.includes(placeholder)) continue;
+				}
+				if (testid) {
+					const tid = node.getAttribute('data-testid');
+					if (tid !== testid) continue;
+				}
+				if (alt) {
+					const a = node.getAttribute('alt');
+					if (!a || !a.includes(alt)) continue;
+				}
+				if (title) {
+					const t = node.getAttribute('title');
+					if (!t || !t.includes(title)) continue;
+				}
+				found.push(node);
+			}
+			return found;
 		}`
 }
 
@@ -2557,10 +4157,12 @@ func mapScript() string {
 	return `(scopeSelector) => {
 		` + GetSelectorJS() + `
 		` + GetLabelJS() + `
+		` + GetImplicitRoleJS() + `
+		` + api.DeepQueryJS() + `
 
 		const interactive = 'a[href], button, input, textarea, select, [role="button"], [role="link"], [role="checkbox"], [role="radio"], [role="tab"], [role="menuitem"], [role="switch"], [onclick], [tabindex]:not([tabindex="-1"]), summary, details';
 
-		const root = scopeSelector ? document.querySelector(scopeSelector) : document;
+		const root = scopeSelector ? queryDeep(document, scopeSelector) : document;
 		if (!root) return JSON.stringify([]);
 		const els = root.querySelectorAll(interactive);
 		const results = [];
@@ -2574,52 +4176,125 @@ func mapScript() string {
 			if (seen.has(sel)) continue;
 			seen.add(sel);
 
-			results.push({ selector: sel, label: getLabel(el) });
+			const rect = el.getBoundingClientRect();
+			results.push({
+				selector: sel,
+				label: getLabel(el),
+				tag: el.tagName.toLowerCase(),
+				role: getImplicitRole(el),
+				inViewport: rect.bottom > 0 && rect.right > 0 && rect.top < window.innerHeight && rect.left < window.innerWidth,
+				aboveFold: rect.top + window.scrollY < window.innerHeight,
+			});
 		}
 
 		return JSON.stringify(results);
 	}`
 }
 
+// mappedElement is one interactive element found by mapScript.
+type mappedElement struct {
+	Selector   string `json:"selector"`
+	Label      string `json:"label"`
+	Tag        string `json:"tag"`
+	Role       string `json:"role"`
+	InViewport bool   `json:"inViewport"`
+	AboveFold  bool   `json:"aboveFold"`
+}
+
+// mapElements runs mapScript, optionally scoped to a subtree, without
+// touching the session's refs.
+func (h *Handlers) mapElements(scope string) ([]mappedElement, error) {
+	var scopeSelector interface{}
+	if scope != "" {
+		scopeSelector = scope
+	}
+	result, err := h.client.CallFunction(h.activeContext, mapScript(), []interface{}{scopeSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to map elements: %w", err)
+	}
+
+	var elements []mappedElement
+	if err := json.Unmarshal([]byte(fmt.Sprintf("%v", result)), &elements); err != nil {
+		return nil, fmt.Errorf("failed to parse map results: %w", err)
+	}
+	return elements, nil
+}
+
 // browserMap maps interactive elements with @refs.
 func (h *Handlers) browserMap(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
 		return nil, err
 	}
 
-	var scopeSelector interface{}
-	if sel, ok := args["selector"].(string); ok && sel != "" {
-		scopeSelector = sel
+	scope, _ := args["selector"].(string)
+	elements, err := h.mapElements(scope)
+	if err != nil {
+		return nil, err
 	}
-	result, err := h.client.CallFunction("", mapScript(), []interface{}{scopeSelector})
+	onlyInViewport, _ := args["onlyInViewport"].(bool)
+	markOffscreen, _ := args["markOffscreen"].(bool)
+	roles, err := stringSetArg(args, "roles")
 	if err != nil {
-		return nil, fmt.Errorf("failed to map elements: %w", err)
+		return nil, err
 	}
-
-	resultStr := fmt.Sprintf("%v", result)
-
-	var elements []struct {
-		Selector string `json:"selector"`
-		Label    string `json:"label"`
+	tags, err := stringSetArg(args, "tags")
+	if err != nil {
+		return nil, err
 	}
-	if err := json.Unmarshal([]byte(resultStr), &elements); err != nil {
-		return nil, fmt.Errorf("failed to parse map results: %w", err)
+	limit := 0
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	// With stableRefs, elements that persist between maps keep their @ref and
+	// only new elements get new numbers. Otherwise refs restart at @e1.
+	stable, _ := args["stableRefs"].(bool)
+	if !stable {
+		h.mapRefs = nil
+	} else if h.mapRefs == nil {
+		h.mapRefs = make(map[string]string)
+		h.nextMapRef = 0
 	}
 
 	// Build ref map and output
 	h.refMap = make(map[string]string)
 	var lines []string
-	for i, el := range elements {
-		ref := fmt.Sprintf("@e%d", i+1)
+	entries := []mapEntry{}
+	for _, el := range elements {
+		if onlyInViewport && !el.InViewport {
+			continue
+		}
+		if roles != nil && !roles[el.Role] {
+			continue
+		}
+		if tags != nil && !tags[el.Tag] {
+			continue
+		}
+		if limit > 0 && len(lines) >= limit {
+			break
+		}
+		ref := fmt.Sprintf("@e%d", len(lines)+1)
+		if stable {
+			ref = h.stableMapRef(el.Selector, el.Label)
+		}
 		h.refMap[ref] = el.Selector
-		lines = append(lines, fmt.Sprintf("%s %s", ref, el.Label))
+		entries = append(entries, mapEntry{Ref: ref, Selector: el.Selector, Label: el.Label})
+		line := fmt.Sprintf("%s %s", ref, el.Label)
+		switch {
+		case !markOffscreen || el.InViewport:
+		case el.AboveFold:
+			line += " (offscreen)"
+		default:
+			line += " (offscreen, below fold)"
+		}
+		lines = append(lines, line)
 	}
 
 	output := strings.Join(lines, "\n")
 	if output == "" {
 		output = "No interactive elements found"
 	}
-	h.lastMap = output
+	h.lastMap = entries
 
 	return &ToolsCallResult{
 		Content: []Content{{
@@ -2629,42 +4304,96 @@ func (h *Handlers) browserMap(args map[string]interface{}) (*ToolsCallResult, er
 	}, nil
 }
 
-// browserDiffMap compares current page state vs last map.
-func (h *Handlers) browserDiffMap(args map[string]interface{}) (*ToolsCallResult, error) {
-	if h.lastMap == "" {
-		return nil, fmt.Errorf("no previous map to diff against — run browser_map first")
-	}
+// mapEntry is one element of a browser_map result.
+type mapEntry struct {
+	Ref      string
+	Selector string
+	Label    string
+}
 
-	// Get current map
-	prevMap := h.lastMap
-	_, err := h.browserMap(args)
-	if err != nil {
-		return nil, err
+// stringSetArg reads an optional array-of-strings argument as a lowercased
+// set. Returns nil when the argument is absent or empty.
+func stringSetArg(args map[string]interface{}, key string) (map[string]bool, error) {
+	list, ok := args[key].([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, nil
+	}
+	set := make(map[string]bool, len(list))
+	for _, item := range list {
+		str, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an array of strings", key)
+		}
+		set[strings.ToLower(str)] = true
+	}
+	return set, nil
+}
+
+// stableMapRef returns the @ref for an element keyed by a hash of its
+// selector and label, assigning the next free number to elements not seen
+// in an earlier stable map. The label is part of the key on purpose: an
+// element whose text changes gets a new @ref, so a plan written against the
+// old label can't silently act on different content. Must be called after
+// h.refMap is reset.
+func (h *Handlers) stableMapRef(selector, label string) string {
+	sum := sha1.Sum([]byte(selector + "\x00" + label))
+	key := hex.EncodeToString(sum[:])
+	if ref, ok := h.mapRefs[key]; ok {
+		if _, taken := h.refMap[ref]; !taken {
+			return ref
+		}
+	}
+	h.nextMapRef++
+	ref := fmt.Sprintf("@e%d", h.nextMapRef)
+	h.mapRefs[key] = ref
+	return ref
+}
+
+// browserDiffMap compares current page state vs last map. Elements are
+// matched by selector: "+" marks new elements, "-" removed ones, and "~" an
+// element whose label changed (old → new).
+func (h *Handlers) browserDiffMap(args map[string]interface{}) (*ToolsCallResult, error) {
+	if h.lastMap == nil {
+		return nil, fmt.Errorf("no previous map to diff against — run browser_map first")
 	}
-	currentMap := h.lastMap
 
-	// Simple line-based diff
-	prevLines := strings.Split(prevMap, "\n")
-	currLines := strings.Split(currentMap, "\n")
+	// Get current map, keeping stable refs if the last map used them
+	prev := h.lastMap
+	mapArgs := map[string]interface{}{}
+	for k, v := range args {
+		mapArgs[k] = v
+	}
+	if _, ok := mapArgs["stableRefs"]; !ok && h.mapRefs != nil {
+		mapArgs["stableRefs"] = true
+	}
+	_, err := h.browserMap(mapArgs)
+	if err != nil {
+		return nil, err
+	}
+	curr := h.lastMap
 
-	prevSet := make(map[string]bool)
-	for _, l := range prevLines {
-		prevSet[l] = true
+	prevBySelector := make(map[string]mapEntry, len(prev))
+	for _, e := range prev {
+		prevBySelector[e.Selector] = e
 	}
-	currSet := make(map[string]bool)
-	for _, l := range currLines {
-		currSet[l] = true
+	currBySelector := make(map[string]mapEntry, len(curr))
+	for _, e := range curr {
+		currBySelector[e.Selector] = e
 	}
 
 	var diff []string
-	for _, l := range prevLines {
-		if !currSet[l] {
-			diff = append(diff, "- "+l)
+	for _, e := range prev {
+		if _, ok := currBySelector[e.Selector]; !ok {
+			diff = append(diff, fmt.Sprintf("- %s %s", e.Ref, e.Label))
 		}
 	}
-	for _, l := range currLines {
-		if !prevSet[l] {
-			diff = append(diff, "+ "+l)
+	for _, e := range curr {
+		old, ok := prevBySelector[e.Selector]
+		switch {
+		case !ok:
+			diff = append(diff, fmt.Sprintf("+ %s %s", e.Ref, e.Label))
+		case old.Label != e.Label:
+			diff = append(diff, fmt.Sprintf("~ %s %s → %s", e.Ref, old.Label, e.Label))
 		}
 	}
 
@@ -2692,7 +4421,11 @@ func (h *Handlers) browserPDF(args map[string]interface{}) (*ToolsCallResult, er
 	if err != nil {
 		return nil, err
 	}
-	base64Data, err := api.PrintToPDF(s, ctx)
+	opts, err := api.ExtractPDFOptions(args)
+	if err != nil {
+		return nil, err
+	}
+	base64Data, err := api.PrintToPDF(s, ctx, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to print PDF: %w", err)
 	}
@@ -2722,6 +4455,66 @@ func (h *Handlers) browserPDF(args map[string]interface{}) (*ToolsCallResult, er
 	}, nil
 }
 
+// browserPrintPreview shows how the page looks when printed, either as a
+// full-page screenshot rendered with print styles or as a PDF. Screen media
+// is always restored afterwards.
+func (h *Handlers) browserPrintPreview(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	format := "png"
+	if f, ok := args["format"].(string); ok && f != "" {
+		format = f
+	}
+	if format != "png" && format != "pdf" {
+		return nil, fmt.Errorf("format must be \"png\" or \"pdf\", got %q", format)
+	}
+	if format == "pdf" {
+		// browsingContext.print always renders with print media,
+		// so no emulation is needed. Drop "format" so it isn't read
+		// as a paper size.
+		pdfArgs := map[string]interface{}{}
+		if filename, ok := args["filename"].(string); ok {
+			pdfArgs["filename"] = filename
+		}
+		return h.browserPDF(pdfArgs)
+	}
+	filename, _ := args["filename"].(string)
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+
+	base64Data, err := api.PrintPreviewScreenshot(s, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture print preview: %w", err)
+	}
+
+	if filename != "" {
+		fullPath, err := h.saveScreenshot(filename, base64Data)
+		if err != nil {
+			return nil, err
+		}
+		return &ToolsCallResult{
+			Content: []Content{{
+				Type: "text",
+				Text: fmt.Sprintf("Print preview saved to %s", fullPath),
+			}},
+		}, nil
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type:     "image",
+			Data:     base64Data,
+			MimeType: "image/png",
+		}},
+	}, nil
+}
+
 // browserHighlight highlights an element with a visual overlay.
 func (h *Handlers) browserHighlight(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
@@ -2735,7 +4528,8 @@ func (h *Handlers) browserHighlight(args map[string]interface{}) (*ToolsCallResu
 	selector = h.resolveSelector(selector)
 
 	script := `(selector) => {
-		const el = document.querySelector(selector);
+		` + api.DeepQueryJS() + `
+		const el = queryDeep(document, selector);
 		if (!el) return 'not_found';
 		const prev = el.style.cssText;
 		el.style.outline = '3px solid red';
@@ -2745,7 +4539,7 @@ func (h *Handlers) browserHighlight(args map[string]interface{}) (*ToolsCallResu
 		return 'highlighted';
 	}`
 
-	result, err := h.client.CallFunction("", script, []interface{}{selector})
+	result, err := h.client.CallFunction(h.activeContext, script, []interface{}{selector})
 	if err != nil {
 		return nil, fmt.Errorf("failed to highlight: %w", err)
 	}
@@ -2791,6 +4585,107 @@ func (h *Handlers) browserDblClick(args map[string]interface{}) (*ToolsCallResul
 	}, nil
 }
 
+// browserRightClick right-clicks an element to open its context menu.
+func (h *Handlers) browserRightClick(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	selector, ok := args["selector"].(string)
+	if !ok || selector == "" {
+		return nil, fmt.Errorf("selector is required")
+	}
+	selector = h.resolveSelector(selector)
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	if err := api.RightClick(s, ctx, api.ElementParams{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to right-click: %w", err)
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Right-clicked element: %s", selector),
+		}},
+	}, nil
+}
+
+// browserTap taps an element with a touch pointer, firing touch events
+// rather than mouse events.
+func (h *Handlers) browserTap(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	selector, ok := args["selector"].(string)
+	if !ok || selector == "" {
+		return nil, fmt.Errorf("selector is required")
+	}
+	selector = h.resolveSelector(selector)
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	if err := api.Tap(s, ctx, api.ElementParams{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to tap: %w", err)
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Tapped element: %s", selector),
+		}},
+	}, nil
+}
+
+// browserPinch performs a two-finger pinch gesture on an element.
+func (h *Handlers) browserPinch(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	selector, ok := args["selector"].(string)
+	if !ok || selector == "" {
+		return nil, fmt.Errorf("selector is required")
+	}
+	selector = h.resolveSelector(selector)
+
+	scale, ok := args["scale"].(float64)
+	if !ok || scale <= 0 {
+		return nil, fmt.Errorf("scale must be a number greater than 0")
+	}
+	steps := 10
+	if st, ok := args["steps"].(float64); ok && st > 0 {
+		steps = int(st)
+	}
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	if err := api.Pinch(s, ctx, api.ElementParams{Selector: selector}, scale, steps); err != nil {
+		return nil, fmt.Errorf("failed to pinch: %w", err)
+	}
+
+	direction := "in"
+	if scale < 1 {
+		direction = "out"
+	}
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Pinched %s on %s (scale %g)", direction, selector, scale),
+		}},
+	}, nil
+}
+
 // browserFocus focuses an element.
 func (h *Handlers) browserFocus(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
@@ -2820,6 +4715,66 @@ func (h *Handlers) browserFocus(args map[string]interface{}) (*ToolsCallResult,
 	}, nil
 }
 
+// browserMoveFocus presses Tab or Shift+Tab and reports the newly focused element.
+func (h *Handlers) browserMoveFocus(key string) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	if err := api.PressKey(s, ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to press %s: %w", key, err)
+	}
+
+	selector, label, err := h.readFocusedElement(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	text := "No element focused"
+	if selector != "" {
+		text = fmt.Sprintf("Focused %s (%s)", label, selector)
+	}
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: text,
+		}},
+	}, nil
+}
+
+// readFocusedElement returns a selector and label for document.activeElement.
+// Both are empty when focus is on the body (nothing focused).
+func (h *Handlers) readFocusedElement(ctx string) (string, string, error) {
+	script := `() => {
+		` + GetSelectorJS() + `
+		` + GetLabelJS() + `
+		const el = document.activeElement;
+		if (!el || el === document.body || el === document.documentElement) return JSON.stringify(null);
+		return JSON.stringify({ selector: getSelector(el), label: getLabel(el) });
+	}`
+	result, err := h.client.CallFunction(ctx, script, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read focused element: %w", err)
+	}
+
+	var focused *struct {
+		Selector string `json:"selector"`
+		Label    string `json:"label"`
+	}
+	if err := json.Unmarshal([]byte(fmt.Sprintf("%v", result)), &focused); err != nil {
+		return "", "", fmt.Errorf("failed to parse focused element: %w", err)
+	}
+	if focused == nil {
+		return "", "", nil
+	}
+	return focused.Selector, focused.Label, nil
+}
+
 // browserCount counts matching elements.
 func (h *Handlers) browserCount(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
@@ -2831,13 +4786,15 @@ func (h *Handlers) browserCount(args map[string]interface{}) (*ToolsCallResult,
 		return nil, fmt.Errorf("selector is required")
 	}
 	selector = h.resolveSelector(selector)
+	scope, _ := args["scope"].(string)
+	scope = h.resolveSelector(scope)
 
 	s := h.newSession()
 	ctx, err := s.GetContextID()
 	if err != nil {
 		return nil, err
 	}
-	count, err := api.GetCount(s, ctx, selector)
+	count, err := api.GetCount(s, ctx, scope, selector)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count: %w", err)
 	}
@@ -2880,6 +4837,42 @@ func (h *Handlers) browserIsEnabled(args map[string]interface{}) (*ToolsCallResu
 	}, nil
 }
 
+// browserIsEditable checks if an element is editable (not disabled or read-only).
+func (h *Handlers) browserIsEditable(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	selector, ok := args["selector"].(string)
+	if !ok || selector == "" {
+		return nil, fmt.Errorf("selector is required")
+	}
+	selector = h.resolveSelector(selector)
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	editable, err := api.IsEditable(s, ctx, api.ElementParams{Selector: selector})
+	if err != nil {
+		// Element not found or error — return false, not an error
+		return &ToolsCallResult{
+			Content: []Content{{
+				Type: "text",
+				Text: "false",
+			}},
+		}, nil
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("%v", editable),
+		}},
+	}, nil
+}
+
 // browserIsChecked checks if an element is checked.
 func (h *Handlers) browserIsChecked(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
@@ -3068,8 +5061,9 @@ func (h *Handlers) browserGetCookies(args map[string]interface{}) (*ToolsCallRes
 	}, nil
 }
 
-// browserSetCookie sets a cookie.
-func (h *Handlers) browserSetCookie(args map[string]interface{}) (*ToolsCallResult, error) {
+// browserGetCookie returns the named cookie as JSON, or null if it isn't set.
+// A leading dot is ignored when matching the optional domain.
+func (h *Handlers) browserGetCookie(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
 		return nil, err
 	}
@@ -3078,74 +5072,175 @@ func (h *Handlers) browserSetCookie(args map[string]interface{}) (*ToolsCallResu
 	if !ok || name == "" {
 		return nil, fmt.Errorf("name is required")
 	}
-
-	value, ok := args["value"].(string)
-	if !ok {
-		return nil, fmt.Errorf("value is required")
-	}
-
 	domain, _ := args["domain"].(string)
-	path, _ := args["path"].(string)
+	domain = strings.TrimPrefix(domain, ".")
 
 	s := h.newSession()
 	ctx, err := s.GetContextID()
 	if err != nil {
 		return nil, err
 	}
-	if err := api.SetCookie(s, ctx, name, value, domain, path); err != nil {
-		return nil, fmt.Errorf("failed to set cookie: %w", err)
+	cookies, err := api.GetCookies(s, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cookies: %w", err)
+	}
+
+	var found *api.CookieInfo
+	for i, c := range cookies {
+		if c.Name != name {
+			continue
+		}
+		if domain != "" && strings.TrimPrefix(c.Domain, ".") != domain {
+			continue
+		}
+		found = &cookies[i]
+		break
+	}
+
+	text := "null"
+	if found != nil {
+		data, err := json.Marshal(found)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode cookie: %w", err)
+		}
+		text = string(data)
 	}
 
 	return &ToolsCallResult{
 		Content: []Content{{
 			Type: "text",
-			Text: fmt.Sprintf("Cookie set: %s=%s", name, value),
+			Text: text,
 		}},
 	}, nil
 }
 
-// browserDeleteCookies deletes cookies.
-func (h *Handlers) browserDeleteCookies(args map[string]interface{}) (*ToolsCallResult, error) {
+// browserGetCookiesChanged reports cookies added, removed, or changed since the
+// previous call. The first call only takes a snapshot.
+func (h *Handlers) browserGetCookiesChanged(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
 		return nil, err
 	}
 
-	name, _ := args["name"].(string)
-
 	s := h.newSession()
 	ctx, err := s.GetContextID()
 	if err != nil {
 		return nil, err
 	}
-	if err := api.DeleteCookies(s, ctx, name); err != nil {
-		return nil, fmt.Errorf("failed to delete cookies: %w", err)
+	cookies, err := api.GetCookies(s, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cookies: %w", err)
 	}
 
-	msg := "All cookies deleted"
-	if name != "" {
-		msg = fmt.Sprintf("Cookie %q deleted", name)
+	if h.lastCookies == nil {
+		h.lastCookies = cookies
+		return &ToolsCallResult{
+			Content: []Content{{
+				Type: "text",
+				Text: fmt.Sprintf("Cookie snapshot taken (%d cookies). Call again to see what changed.", len(cookies)),
+			}},
+		}, nil
 	}
 
+	diff := api.DiffCookies(h.lastCookies, cookies)
+	h.lastCookies = cookies
+
+	diffJSON, _ := json.Marshal(diff)
 	return &ToolsCallResult{
 		Content: []Content{{
 			Type: "text",
-			Text: msg,
+			Text: string(diffJSON),
 		}},
 	}, nil
 }
 
-// browserMouseMove moves the mouse to coordinates.
-func (h *Handlers) browserMouseMove(args map[string]interface{}) (*ToolsCallResult, error) {
+// browserSetCookie sets a cookie.
+func (h *Handlers) browserSetCookie(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
 		return nil, err
 	}
 
-	x, ok := args["x"].(float64)
-	if !ok {
-		return nil, fmt.Errorf("x is required")
+	name, ok := args["name"].(string)
+	if !ok || name == "" {
+		return nil, fmt.Errorf("name is required")
 	}
-	y, ok := args["y"].(float64)
-	if !ok {
+
+	value, ok := args["value"].(string)
+	if !ok {
+		return nil, fmt.Errorf("value is required")
+	}
+
+	cookie := bidi.Cookie{Name: name, Value: value}
+	cookie.Domain, _ = args["domain"].(string)
+	cookie.Path, _ = args["path"].(string)
+	cookie.Secure, _ = args["secure"].(bool)
+	cookie.HTTPOnly, _ = args["httpOnly"].(bool)
+	cookie.SameSite, _ = args["sameSite"].(string)
+	if expiry, ok := args["expiry"].(float64); ok {
+		cookie.Expiry = int64(expiry)
+	}
+	if err := cookie.Normalize(); err != nil {
+		return nil, err
+	}
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	if err := api.SetCookie(s, ctx, cookie); err != nil {
+		return nil, fmt.Errorf("failed to set cookie: %w", err)
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Cookie set: %s=%s", name, value),
+		}},
+	}, nil
+}
+
+// browserDeleteCookies deletes cookies.
+func (h *Handlers) browserDeleteCookies(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	name, _ := args["name"].(string)
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	if err := api.DeleteCookies(s, ctx, name); err != nil {
+		return nil, fmt.Errorf("failed to delete cookies: %w", err)
+	}
+
+	msg := "All cookies deleted"
+	if name != "" {
+		msg = fmt.Sprintf("Cookie %q deleted", name)
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: msg,
+		}},
+	}, nil
+}
+
+// browserMouseMove moves the mouse to coordinates.
+func (h *Handlers) browserMouseMove(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	x, ok := args["x"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("x is required")
+	}
+	y, ok := args["y"].(float64)
+	if !ok {
 		return nil, fmt.Errorf("y is required")
 	}
 
@@ -3289,12 +5384,17 @@ func (h *Handlers) browserDrag(args map[string]interface{}) (*ToolsCallResult, e
 	}
 	target = h.resolveSelector(target)
 
+	opts, err := api.ExtractDragOptions(args)
+	if err != nil {
+		return nil, err
+	}
+
 	s := h.newSession()
 	ctx, err := s.GetContextID()
 	if err != nil {
 		return nil, err
 	}
-	if err := api.DragTo(s, ctx, api.ElementParams{Selector: source}, api.ElementParams{Selector: target}); err != nil {
+	if err := api.DragTo(s, ctx, api.ElementParams{Selector: source}, api.ElementParams{Selector: target}, opts); err != nil {
 		return nil, fmt.Errorf("failed to drag: %w", err)
 	}
 
@@ -3306,6 +5406,46 @@ func (h *Handlers) browserDrag(args map[string]interface{}) (*ToolsCallResult, e
 	}, nil
 }
 
+// browserDragBy drags from an element's center or an x/y point by a pixel delta.
+func (h *Handlers) browserDragBy(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	deltaX, _ := args["deltaX"].(float64)
+	deltaY, _ := args["deltaY"].(float64)
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+
+	source, _ := args["source"].(string)
+	fx, okX := args["x"].(float64)
+	fy, okY := args["y"].(float64)
+	if source == "" && (!okX || !okY) {
+		return nil, fmt.Errorf("source selector or x and y are required")
+	}
+	source = h.resolveSelector(source)
+
+	opts, err := api.ExtractDragOptions(args)
+	if err != nil {
+		return nil, err
+	}
+	x, y, err := api.DragBy(s, ctx, api.ElementParams{Selector: source}, int(fx), int(fy), int(deltaX), int(deltaY), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to drag: %w", err)
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Dragged from (%d, %d) by (%g, %g)", x, y, deltaX, deltaY),
+		}},
+	}, nil
+}
+
 // browserSetViewport sets the viewport size.
 func (h *Handlers) browserSetViewport(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
@@ -3493,24 +5633,197 @@ func (h *Handlers) browserEmulateMedia(args map[string]interface{}) (*ToolsCallR
 	}, nil
 }
 
-// browserSetGeolocation overrides the browser geolocation.
+// browserSetGeolocation overrides the browser geolocation, or clears the
+// override when latitude and longitude are null.
 func (h *Handlers) browserSetGeolocation(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
 		return nil, err
 	}
 
-	latitude, ok := args["latitude"].(float64)
+	coords, err := api.ExtractGeolocation(args)
+	if err != nil {
+		return nil, err
+	}
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := api.SetGeolocation(s, ctx, coords); err != nil {
+		return nil, fmt.Errorf("failed to set geolocation: %w", err)
+	}
+
+	text := "Geolocation override cleared"
+	if coords != nil {
+		text = fmt.Sprintf("Geolocation set to (%f, %f)", coords.Latitude, coords.Longitude)
+	}
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: text,
+		}},
+	}, nil
+}
+
+// browserSetOffline takes the browser offline or back online. It uses BiDi
+// network condition emulation where supported; otherwise it fails every
+// request through the session's intercept and overrides navigator.onLine.
+// Either way the state persists across navigations until turned off.
+func (h *Handlers) browserSetOffline(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	offline, ok := args["offline"].(bool)
 	if !ok {
-		return nil, fmt.Errorf("latitude is required")
+		return nil, fmt.Errorf("offline is required")
 	}
-	longitude, ok := args["longitude"].(float64)
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+
+	text := "Browser is online"
+	if offline {
+		text = "Browser is offline"
+	}
+	result := &ToolsCallResult{Content: []Content{{Type: "text", Text: text}}}
+
+	if offline == (h.offline != "") {
+		return result, nil
+	}
+
+	if !offline {
+		if h.offline == "emulation" {
+			if err := api.SetOfflineEmulation(s, false); err != nil {
+				return nil, fmt.Errorf("failed to go online: %w", err)
+			}
+			h.offline = ""
+			return result, nil
+		}
+		h.offline = ""
+		if h.offlineScript != "" {
+			api.RemovePreloadScript(s, h.offlineScript)
+			h.offlineScript = ""
+		}
+		if err := h.releaseIntercept(); err != nil {
+			return nil, err
+		}
+		if err := api.SetOnLineShim(s, ctx, false); err != nil {
+			return nil, fmt.Errorf("failed to update navigator.onLine: %w", err)
+		}
+		return result, nil
+	}
+
+	if err := api.SetOfflineEmulation(s, true); err == nil {
+		h.offline = "emulation"
+		return result, nil
+	}
+
+	// Fallback: fail all requests and fake navigator.onLine
+	if err := h.ensureIntercept(); err != nil {
+		return nil, err
+	}
+	h.offline = "intercept"
+	script, err := api.AddOfflinePreloadScript(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add offline preload script: %w", err)
+	}
+	h.offlineScript = script
+	if err := api.SetOnLineShim(s, ctx, true); err != nil {
+		return nil, fmt.Errorf("failed to update navigator.onLine: %w", err)
+	}
+	return result, nil
+}
+
+// browserEmulateNetwork throttles the network with a preset or custom
+// latency and throughput, and returns the applied profile as JSON.
+func (h *Handlers) browserEmulateNetwork(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	conditions, err := api.ExtractNetworkConditions(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var port int
+	var sessionID string
+	if h.launchResult != nil {
+		port, sessionID = h.launchResult.Port, h.launchResult.SessionID
+	}
+	if err := api.EmulateNetworkConditions(port, sessionID, conditions); err != nil {
+		return nil, fmt.Errorf("failed to emulate network: %w", err)
+	}
+
+	data, err := json.Marshal(conditions)
+	if err != nil {
+		return nil, err
+	}
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
+
+// browserEmulateCPU throttles the CPU to simulate a slower device.
+func (h *Handlers) browserEmulateCPU(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	rate, ok := args["rate"].(float64)
 	if !ok {
-		return nil, fmt.Errorf("longitude is required")
+		return nil, fmt.Errorf("rate is required")
+	}
+
+	var port int
+	var sessionID string
+	if h.launchResult != nil {
+		port, sessionID = h.launchResult.Port, h.launchResult.SessionID
+	}
+	if err := api.EmulateCPUThrottling(port, sessionID, rate); err != nil {
+		return nil, fmt.Errorf("failed to emulate CPU: %w", err)
+	}
+
+	text := fmt.Sprintf("CPU throttled to %gx slowdown", rate)
+	if rate == 1 {
+		text = "CPU throttling removed"
 	}
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: text,
+		}},
+	}, nil
+}
+
+// browserSetUserAgent overrides the User-Agent, and optionally Accept-Language
+// and navigator.platform, for the active context.
+func (h *Handlers) browserSetUserAgent(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	userAgent, ok := args["userAgent"].(string)
+	if !ok || userAgent == "" {
+		return nil, fmt.Errorf("userAgent is required")
+	}
+	opts := api.UserAgentOptions{UserAgent: userAgent}
+	opts.AcceptLanguage, _ = args["acceptLanguage"].(string)
+	opts.Platform, _ = args["platform"].(string)
 
-	accuracy := 1.0
-	if a, ok := args["accuracy"].(float64); ok {
-		accuracy = a
+	var port int
+	var sessionID string
+	if h.launchResult != nil {
+		port, sessionID = h.launchResult.Port, h.launchResult.SessionID
 	}
 
 	s := h.newSession()
@@ -3519,14 +5832,14 @@ func (h *Handlers) browserSetGeolocation(args map[string]interface{}) (*ToolsCal
 		return nil, err
 	}
 
-	if err := api.SetGeolocation(s, ctx, latitude, longitude, accuracy); err != nil {
-		return nil, fmt.Errorf("failed to set geolocation: %w", err)
+	if err := api.SetUserAgent(s, ctx, port, sessionID, opts); err != nil {
+		return nil, fmt.Errorf("failed to set user agent: %w", err)
 	}
 
 	return &ToolsCallResult{
 		Content: []Content{{
 			Type: "text",
-			Text: fmt.Sprintf("Geolocation set to (%f, %f)", latitude, longitude),
+			Text: fmt.Sprintf("User agent set to %q", userAgent),
 		}},
 	}, nil
 }
@@ -3640,25 +5953,64 @@ func (h *Handlers) browserUpload(args map[string]interface{}) (*ToolsCallResult,
 	}
 	selector = h.resolveSelector(selector)
 
-	filesRaw, ok := args["files"]
-	if !ok {
-		return nil, fmt.Errorf("files is required")
+	if dir, _ := args["directory"].(string); dir != "" {
+		s := h.newSession()
+		ctx, err := s.GetContextID()
+		if err != nil {
+			return nil, err
+		}
+		n, err := api.UploadDirectory(s, ctx, api.ElementParams{Selector: selector}, dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set files: %w", err)
+		}
+		return &ToolsCallResult{
+			Content: []Content{{
+				Type: "text",
+				Text: fmt.Sprintf("Set %d file(s) from %s on %s", n, dir, selector),
+			}},
+		}, nil
+	}
+
+	filesRaw, hasFiles := args["files"]
+	contentsRaw, hasContents := args["contents"].([]interface{})
+	if !hasFiles && !hasContents {
+		return nil, fmt.Errorf("files, contents, or directory is required")
 	}
 
 	var files []string
-	switch v := filesRaw.(type) {
-	case []interface{}:
-		for _, f := range v {
-			if s, ok := f.(string); ok {
-				files = append(files, s)
+	if hasFiles {
+		switch v := filesRaw.(type) {
+		case []interface{}:
+			for _, f := range v {
+				if s, ok := f.(string); ok {
+					files = append(files, s)
+				}
 			}
+		default:
+			return nil, fmt.Errorf("files must be an array of strings")
 		}
-	default:
-		return nil, fmt.Errorf("files must be an array of strings")
+	}
+	if hasContents {
+		contents, err := api.ExtractUploadContents(contentsRaw)
+		if err != nil {
+			return nil, err
+		}
+		// The browser reads uploaded files lazily (e.g. on form submit), so
+		// the temp files are kept until the browser session closes.
+		dir, err := os.MkdirTemp("", "vibium-upload-*")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create upload dir: %w", err)
+		}
+		h.uploadDirs = append(h.uploadDirs, dir)
+		paths, err := api.WriteUploadFiles(dir, contents)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, paths...)
 	}
 
 	if len(files) == 0 {
-		return nil, fmt.Errorf("at least one file path is required")
+		return nil, fmt.Errorf("at least one file is required")
 	}
 
 	s := h.newSession()
@@ -3689,6 +6041,9 @@ func (h *Handlers) browserRecordStart(args map[string]interface{}) (*ToolsCallRe
 	}
 
 	opts := api.ParseRecordingOptions(args)
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
 	if opts.Name == "" {
 		opts.Name = "record"
 	}
@@ -3696,28 +6051,43 @@ func (h *Handlers) browserRecordStart(args map[string]interface{}) (*ToolsCallRe
 
 	h.recorder = api.NewRecorder()
 	h.recorder.Start(opts)
+	h.recorder.SetActivePage(h.activeContext)
+	if opts.Screenshots {
+		// The BiDi client isn't safe for concurrent use, so periodic frames
+		// are taken between tool calls, while Call isn't using the browser.
+		recorder := h.recorder
+		client := h.client
+		recorder.StartScreenshotLoop(func() (string, string, error) {
+			if !h.busy.TryLock() {
+				return "", "", fmt.Errorf("tool call in progress")
+			}
+			defer h.busy.Unlock()
+			s := api.NewAgentSession(client)
+			s.Context = recorder.ActivePage()
+			return api.CaptureRecordingScreenshotData(s, opts)
+		})
+	}
+
+	// Subscribe to events; onBidiEvent feeds them to the recorder
+	h.subscribe(
+		"network.beforeRequestSent",
+		"network.responseCompleted",
+		"network.fetchError",
+		"log.entryAdded",
+		"browsingContext.userPromptOpened",
+		"browsingContext.downloadWillBegin",
+		"browsingContext.load",
+		"browsingContext.fragmentNavigated",
+	)
 
-	// Subscribe to events and feed them to the recorder
-	h.client.SendCommand("session.subscribe", map[string]interface{}{
-		"events": []string{
-			"network.beforeRequestSent",
-			"network.responseCompleted",
-			"network.fetchError",
-			"log.entryAdded",
-			"browsingContext.userPromptOpened",
-			"browsingContext.downloadWillBegin",
-			"browsingContext.load",
-			"browsingContext.fragmentNavigated",
-		},
-	})
-	h.client.SetEventHandler(func(msg string) {
-		h.recorder.RecordBidiEvent(msg)
-	})
-
+	text := fmt.Sprintf("Recording %q started (screenshots: %v, snapshots: %v)", name, opts.Screenshots, opts.Snapshots)
+	if opts.Screenshots && opts.IntervalMs > 0 {
+		text = fmt.Sprintf("Recording %q started (screenshots: every %dms and after each action, snapshots: %v)", name, opts.IntervalMs, opts.Snapshots)
+	}
 	return &ToolsCallResult{
 		Content: []Content{{
 			Type: "text",
-			Text: fmt.Sprintf("Recording %q started (screenshots: %v, snapshots: %v)", name, opts.Screenshots, opts.Snapshots),
+			Text: text,
 		}},
 	}, nil
 }
@@ -3728,11 +6098,6 @@ func (h *Handlers) browserRecordStop(args map[string]interface{}) (*ToolsCallRes
 		return nil, fmt.Errorf("no recording in progress")
 	}
 
-	// Stop forwarding events to the recorder
-	if h.client != nil {
-		h.client.SetEventHandler(nil)
-	}
-
 	// Stop screenshot goroutine before stopping the recorder
 	h.recorder.StopScreenshots()
 
@@ -3789,12 +6154,15 @@ func (h *Handlers) browserRecordStopGroup(args map[string]interface{}) (*ToolsCa
 		return nil, fmt.Errorf("no recording in progress")
 	}
 
-	h.recorder.StopGroup()
+	name := h.recorder.StopGroup()
+	if name == "" {
+		return nil, fmt.Errorf("no group in progress")
+	}
 
 	return &ToolsCallResult{
 		Content: []Content{{
 			Type: "text",
-			Text: "Stopped group",
+			Text: fmt.Sprintf("Stopped group %q", name),
 		}},
 	}, nil
 }
@@ -3819,7 +6187,8 @@ func (h *Handlers) browserRecordStartChunk(args map[string]interface{}) (*ToolsC
 }
 
 // browserRecordStopChunk packages the current chunk into a zip file.
-// Recording remains active for additional chunks.
+// Recording remains active for additional chunks; with next, the following
+// chunk starts immediately so each checkpoint only holds new actions.
 func (h *Handlers) browserRecordStopChunk(args map[string]interface{}) (*ToolsCallResult, error) {
 	if h.recorder == nil {
 		return nil, fmt.Errorf("no recording in progress")
@@ -3839,56 +6208,84 @@ func (h *Handlers) browserRecordStopChunk(args map[string]interface{}) (*ToolsCa
 		return nil, fmt.Errorf("failed to write chunk: %w", err)
 	}
 
+	text := fmt.Sprintf("Chunk saved to %s", path)
+	if next, _ := args["next"].(bool); next {
+		h.recorder.StartChunk("", "")
+		text += "; started next chunk"
+	}
+
 	return &ToolsCallResult{
 		Content: []Content{{
 			Type: "text",
-			Text: fmt.Sprintf("Chunk saved to %s", path),
+			Text: text,
 		}},
 	}, nil
 }
 
-// browserStorageState exports cookies, localStorage, and sessionStorage.
-func (h *Handlers) browserStorageState(args map[string]interface{}) (*ToolsCallResult, error) {
+// browserHarStart starts collecting network requests for a HAR file.
+func (h *Handlers) browserHarStart(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
 		return nil, err
 	}
+	if h.har != nil {
+		return nil, fmt.Errorf("HAR capture already in progress")
+	}
 
-	// Get cookies
-	cookies, err := h.client.GetCookies("")
-	if err != nil {
-		return nil, fmt.Errorf("failed to get cookies: %w", err)
+	h.har = api.NewHARRecorder()
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: "HAR capture started",
+		}},
+	}, nil
+}
+
+// browserHarStop writes the requests collected since browser_har_start to a
+// HAR 1.2 file.
+func (h *Handlers) browserHarStop(args map[string]interface{}) (*ToolsCallResult, error) {
+	if h.har == nil {
+		return nil, fmt.Errorf("no HAR capture in progress")
 	}
 
-	// Get localStorage and sessionStorage
-	script := `JSON.stringify({
-		origin: location.origin,
-		localStorage: (function() {
-			var ls = {};
-			for (var i = 0; i < localStorage.length; i++) {
-				var key = localStorage.key(i);
-				ls[key] = localStorage.getItem(key);
-			}
-			return ls;
-		})(),
-		sessionStorage: (function() {
-			var ss = {};
-			for (var i = 0; i < sessionStorage.length; i++) {
-				var key = sessionStorage.key(i);
-				ss[key] = sessionStorage.getItem(key);
-			}
-			return ss;
-		})()
-	})`
+	path, _ := args["path"].(string)
+	if path == "" {
+		path = "network.har"
+	}
 
-	storageResult, err := h.client.Evaluate("", script)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get storage: %w", err)
+	har := h.har
+	h.har = nil
+	if err := har.WriteFile(path); err != nil {
+		return nil, fmt.Errorf("failed to write HAR: %w", err)
 	}
 
-	// Build combined state
-	state := map[string]interface{}{
-		"cookies": cookies,
-		"storage": storageResult,
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("HAR with %d requests saved to %s", har.Len(), path),
+		}},
+	}, nil
+}
+
+// browserStorageState exports cookies and storage in Playwright's
+// storageState shape. IndexedDB is included unless indexedDB is false.
+func (h *Handlers) browserStorageState(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	withIndexedDB := true
+	if v, ok := args["indexedDB"].(bool); ok {
+		withIndexedDB = v
+	}
+	state, err := api.GetStorageState(s, ctx, withIndexedDB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage state: %w", err)
 	}
 
 	stateJSON, _ := json.MarshalIndent(state, "", "  ")
@@ -3900,7 +6297,9 @@ func (h *Handlers) browserStorageState(args map[string]interface{}) (*ToolsCallR
 	}, nil
 }
 
-// browserRestoreStorage restores cookies and storage from a JSON state.
+// browserRestoreStorage restores cookies and storage from a state file
+// written by browser_storage_state (or Playwright's storageState). Files in
+// the older {cookies, storage} format are still accepted.
 func (h *Handlers) browserRestoreStorage(args map[string]interface{}) (*ToolsCallResult, error) {
 	if err := h.ensureBrowser(); err != nil {
 		return nil, err
@@ -3916,44 +6315,135 @@ func (h *Handlers) browserRestoreStorage(args map[string]interface{}) (*ToolsCal
 		return nil, fmt.Errorf("failed to read state file: %w", err)
 	}
 
-	var state struct {
-		Cookies []bidi.Cookie `json:"cookies"`
-		Storage json.RawMessage `json:"storage"`
-	}
-	if err := json.Unmarshal(data, &state); err != nil {
+	state, err := api.ParseStorageState(data)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse state file: %w", err)
 	}
 
-	// Restore cookies
-	for _, cookie := range state.Cookies {
-		if err := h.client.SetCookie("", cookie); err != nil {
-			log.Debug("failed to restore cookie", "name", cookie.Name, "error", err)
-		}
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	result, err := api.SetStorageState(s, ctx, state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore storage state: %w", err)
 	}
 
-	// Restore localStorage/sessionStorage if present
-	if len(state.Storage) > 0 {
-		script := fmt.Sprintf(`(function() {
-			var state = %s;
-			if (state.localStorage) {
-				for (var key in state.localStorage) {
-					localStorage.setItem(key, state.localStorage[key]);
-				}
-			}
-			if (state.sessionStorage) {
-				for (var key in state.sessionStorage) {
-					sessionStorage.setItem(key, state.sessionStorage[key]);
-				}
-			}
-			return 'ok';
-		})()`, string(state.Storage))
-		h.client.Evaluate("", script)
+	text := fmt.Sprintf("Storage state restored from %s (%d cookies, %d of %d origins)", path, result.Cookies, result.Origins, len(state.Origins))
+	if result.SkippedCookies > 0 {
+		text += fmt.Sprintf("; skipped %d invalid cookies", result.SkippedCookies)
+	}
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: text,
+		}},
+	}, nil
+}
+
+// browserClearStorage wipes cookies and per-origin storage so the next run
+// starts from a clean slate. Cookies, localStorage, and sessionStorage are
+// cleared unless disabled; IndexedDB and Cache Storage are opt-in.
+func (h *Handlers) browserClearStorage(args map[string]interface{}) (*ToolsCallResult, error) {
+	if err := h.ensureBrowser(); err != nil {
+		return nil, err
+	}
+
+	opts := api.ClearStorageOptions{Cookies: true, LocalStorage: true, SessionStorage: true}
+	if v, ok := args["cookies"].(bool); ok {
+		opts.Cookies = v
+	}
+	if v, ok := args["localStorage"].(bool); ok {
+		opts.LocalStorage = v
+	}
+	if v, ok := args["sessionStorage"].(bool); ok {
+		opts.SessionStorage = v
+	}
+	opts.IndexedDB, _ = args["indexedDB"].(bool)
+	opts.CacheStorage, _ = args["cacheStorage"].(bool)
+
+	s := h.newSession()
+	ctx, err := s.GetContextID()
+	if err != nil {
+		return nil, err
+	}
+	result, err := api.ClearStorage(s, ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clear storage: %w", err)
+	}
+
+	var parts []string
+	if opts.Cookies {
+		parts = append(parts, fmt.Sprintf("%d cookies", result.Cookies))
+	}
+	if opts.LocalStorage {
+		parts = append(parts, fmt.Sprintf("%d localStorage keys", result.LocalStorage))
+	}
+	if opts.SessionStorage {
+		parts = append(parts, fmt.Sprintf("%d sessionStorage keys", result.SessionStorage))
+	}
+	if opts.IndexedDB {
+		parts = append(parts, fmt.Sprintf("%d IndexedDB databases", result.IndexedDB))
+	}
+	if opts.CacheStorage {
+		parts = append(parts, fmt.Sprintf("%d caches", result.CacheStorage))
+	}
+	text := "Nothing to clear"
+	if len(parts) > 0 {
+		text = "Cleared " + strings.Join(parts, ", ")
 	}
 
 	return &ToolsCallResult{
 		Content: []Content{{
 			Type: "text",
-			Text: fmt.Sprintf("Storage state restored from %s (%d cookies)", path, len(state.Cookies)),
+			Text: text,
+		}},
+	}, nil
+}
+
+// browserSetScreenshotDir changes where screenshots are saved for the rest of
+// the session. The directory must be inside the one configured with
+// --screenshot-dir (relative paths are resolved against it), so a client
+// can't write screenshots elsewhere or turn saving back on once the user
+// has disabled it.
+func (h *Handlers) browserSetScreenshotDir(args map[string]interface{}) (*ToolsCallResult, error) {
+	dir, ok := args["path"].(string)
+	if !ok || dir == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+	if h.screenshotRoot == "" {
+		return nil, fmt.Errorf("screenshot file saving is disabled (use --screenshot-dir to enable)")
+	}
+	root, err := filepath.Abs(h.screenshotRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve screenshot directory: %w", err)
+	}
+
+	absDir := dir
+	if !filepath.IsAbs(absDir) {
+		absDir = filepath.Join(root, absDir)
+	}
+	absDir = filepath.Clean(absDir)
+	if rel, err := filepath.Rel(root, absDir); err != nil || rel != "." && !filepath.IsLocal(rel) {
+		return nil, fmt.Errorf("%s is outside the screenshot directory %s", absDir, root)
+	}
+
+	if info, err := os.Stat(absDir); err == nil && !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", absDir)
+	}
+
+	// Create directory if it doesn't exist
+	if err := os.MkdirAll(absDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create screenshot directory: %w", err)
+	}
+
+	h.screenshotDir = absDir
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Screenshot directory set to %s", absDir),
 		}},
 	}, nil
 }
@@ -4001,3 +6491,47 @@ func (h *Handlers) browserDownloadSetDir(args map[string]interface{}) (*ToolsCal
 		}},
 	}, nil
 }
+
+// browserInvokeSkillChain runs a skill from the skills directory, expanding any
+// skills it references. The whole chain is resolved (and checked for cycles)
+// before the first step runs.
+func (h *Handlers) browserInvokeSkillChain(args map[string]interface{}) (*ToolsCallResult, error) {
+	name, ok := args["name"].(string)
+	if !ok || name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	params, _ := args["params"].(map[string]interface{})
+
+	dir, err := paths.GetSkillsDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get skills directory: %w", err)
+	}
+
+	steps, err := resolveSkillChain(dir, name, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("skill %q has no steps", name)
+	}
+
+	var lines []string
+	for i, step := range steps {
+		result, err := h.call(step.Tool, step.Args)
+		if err != nil {
+			return nil, fmt.Errorf("step %d (%s from skill %q) failed: %w", i+1, step.Tool, step.Skill, err)
+		}
+		summary := step.Tool
+		if result != nil && len(result.Content) > 0 && result.Content[0].Type == "text" {
+			summary += ": " + result.Content[0].Text
+		}
+		lines = append(lines, fmt.Sprintf("%d. [%s] %s", i+1, step.Skill, summary))
+	}
+
+	return &ToolsCallResult{
+		Content: []Content{{
+			Type: "text",
+			Text: fmt.Sprintf("Ran skill %q (%d steps)\n%s", name, len(steps), strings.Join(lines, "\n")),
+		}},
+	}, nil
+}
@@ -0,0 +1,332 @@
+package agent
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vibium/clicker/internal/api"
+)
+
+func TestScreenshotPath(t *testing.T) {
+	dir := t.TempDir()
+	h := &Handlers{screenshotDir: dir}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "home.png", want: filepath.Join(dir, "home.png")},
+		{name: filepath.Join("baseline", "home.png"), want: filepath.Join(dir, "baseline", "home.png")},
+		{name: filepath.Join("..", "home.png"), want: filepath.Join(dir, "home.png")},
+		{name: filepath.Join("baseline", "..", "..", "home.png"), want: filepath.Join(dir, "home.png")},
+		{name: filepath.Join(t.TempDir(), "home.png"), want: filepath.Join(dir, "home.png")},
+		{name: "..", wantErr: true},
+		{name: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := h.screenshotPath(tt.name)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("screenshotPath(%q) = %q, want error", tt.name, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("screenshotPath(%q) = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestScreenshotPathDisabled(t *testing.T) {
+	h := &Handlers{}
+	if _, err := h.screenshotPath("home.png"); err == nil {
+		t.Error("screenshotPath with no screenshot directory should fail")
+	}
+}
+
+func TestSetScreenshotDirStaysInsideRoot(t *testing.T) {
+	root := t.TempDir()
+	h := NewHandlers(root, true, "", nil)
+
+	if _, err := h.browserSetScreenshotDir(map[string]interface{}{"path": "task-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(root, "task-1"); h.screenshotDir != want {
+		t.Errorf("screenshotDir = %q, want %q", h.screenshotDir, want)
+	}
+	if _, err := h.browserSetScreenshotDir(map[string]interface{}{"path": filepath.Join(root, "task-2")}); err != nil {
+		t.Errorf("absolute path inside the root: %v", err)
+	}
+
+	for _, path := range []string{t.TempDir(), filepath.Join("..", "elsewhere")} {
+		if _, err := h.browserSetScreenshotDir(map[string]interface{}{"path": path}); err == nil {
+			t.Errorf("path %q outside the root should be refused", path)
+		}
+	}
+	if want := filepath.Join(root, "task-2"); h.screenshotDir != want {
+		t.Errorf("refused paths changed screenshotDir to %q", h.screenshotDir)
+	}
+}
+
+func TestSetScreenshotDirRefusedWhenSavingDisabled(t *testing.T) {
+	h := NewHandlers("", true, "", nil)
+	if _, err := h.browserSetScreenshotDir(map[string]interface{}{"path": t.TempDir()}); err == nil {
+		t.Fatal("should not re-enable saving disabled at startup")
+	}
+	if h.screenshotDir != "" {
+		t.Errorf("screenshotDir = %q, want saving to stay disabled", h.screenshotDir)
+	}
+}
+
+// fakePage answers mapScript with its elements and every other script with
+// "{}", recording the arguments of the other scripts.
+type fakePage struct {
+	mu       sync.Mutex
+	elements []mappedElement
+	scripts  []map[string]interface{}
+}
+
+func serveFakePage(fb *fakeBrowser, elements ...mappedElement) *fakePage {
+	p := &fakePage{elements: elements}
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+	fb.reply = func(cmd map[string]interface{}) map[string]interface{} {
+		if cmd["method"] != "script.callFunction" {
+			return nil
+		}
+		params := cmd["params"].(map[string]interface{})
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		value := "{}"
+		if params["functionDeclaration"] == mapScript() {
+			data, _ := json.Marshal(p.elements)
+			value = string(data)
+		} else {
+			p.scripts = append(p.scripts, params)
+		}
+		return map[string]interface{}{"type": "success", "result": map[string]interface{}{"type": "string", "value": value}}
+	}
+	return p
+}
+
+func (p *fakePage) set(elements ...mappedElement) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.elements = elements
+}
+
+// scriptArg returns the first string argument of the last script whose
+// source contains marker.
+func (p *fakePage) scriptArg(marker string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := len(p.scripts) - 1; i >= 0; i-- {
+		if fn, _ := p.scripts[i]["functionDeclaration"].(string); strings.Contains(fn, marker) {
+			arg := p.scripts[i]["arguments"].([]interface{})[0].(map[string]interface{})
+			value, _ := arg["value"].(string)
+			return value
+		}
+	}
+	return ""
+}
+
+func TestAnnotatedScreenshotKeepsStableRefs(t *testing.T) {
+	h, fb := newFakeHandlers(t)
+	h.activeContext = "ctx-1"
+	page := serveFakePage(fb, mappedElement{Selector: "#a", Label: "A"}, mappedElement{Selector: "#b", Label: "B"}, mappedElement{Selector: "#c", Label: "C"})
+
+	if _, err := h.Call("browser_map", map[string]interface{}{"stableRefs": true}); err != nil {
+		t.Fatal(err)
+	}
+	// #a goes away and #d appears: #b and #c must keep @e2 and @e3.
+	page.set(mappedElement{Selector: "#b", Label: "B"}, mappedElement{Selector: "#c", Label: "C"}, mappedElement{Selector: "#d", Label: "D"})
+	if _, err := h.Call("browser_screenshot", map[string]interface{}{"annotate": true}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"@e2": "#b", "@e3": "#c", "@e4": "#d"}
+	if !reflect.DeepEqual(h.refMap, want) {
+		t.Errorf("refMap after annotate = %v, want %v", h.refMap, want)
+	}
+	annotated := page.scriptArg("label.className = '__vibium_annotation'")
+	var labels []struct{ Selector, Label string }
+	if err := json.Unmarshal([]byte(annotated), &labels); err != nil {
+		t.Fatalf("annotation targets %q: %v", annotated, err)
+	}
+	got := map[string]string{}
+	for _, l := range labels {
+		got["@e"+l.Label] = l.Selector
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("annotation labels = %v, want %v", got, want)
+	}
+}
+
+func TestMapFiltersKeepRefsDense(t *testing.T) {
+	h, fb := newFakeHandlers(t)
+	h.activeContext = "ctx-1"
+	serveFakePage(fb,
+		mappedElement{Selector: "#home", Label: `[a] "Home"`, Tag: "a", Role: "link"},
+		mappedElement{Selector: "#q", Label: `[input] placeholder="Search"`, Tag: "input", Role: "textbox"},
+		mappedElement{Selector: "#go", Label: `[button] "Go"`, Tag: "button", Role: "button"},
+		mappedElement{Selector: "#help", Label: `[a] "Help"`, Tag: "a", Role: "link"},
+		mappedElement{Selector: "#menu", Label: `[div] "Menu"`, Tag: "div", Role: "button"},
+	)
+
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"roles", map[string]interface{}{"roles": []interface{}{"Button"}},
+			"@e1 [button] \"Go\"\n@e2 [div] \"Menu\""},
+		{"tags", map[string]interface{}{"tags": []interface{}{"a", "input"}},
+			"@e1 [a] \"Home\"\n@e2 [input] placeholder=\"Search\"\n@e3 [a] \"Help\""},
+		{"roles and tags", map[string]interface{}{"roles": []interface{}{"button"}, "tags": []interface{}{"div"}},
+			"@e1 [div] \"Menu\""},
+		{"limit", map[string]interface{}{"roles": []interface{}{"link", "button"}, "limit": float64(2)},
+			"@e1 [a] \"Home\"\n@e2 [button] \"Go\""},
+		{"nothing left", map[string]interface{}{"roles": []interface{}{"checkbox"}},
+			"No interactive elements found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := h.Call("browser_map", tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := result.Content[0].Text; got != tt.want {
+				t.Errorf("map =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := h.Call("browser_map", map[string]interface{}{"roles": []interface{}{"button", 3}}); err == nil {
+		t.Error("a non-string role should be an error")
+	}
+}
+
+func TestDiffMapReportsChangedLabels(t *testing.T) {
+	h, fb := newFakeHandlers(t)
+	h.activeContext = "ctx-1"
+
+	if _, err := h.Call("browser_diff_map", nil); err == nil {
+		t.Error("diff_map before any map should be an error")
+	}
+
+	page := serveFakePage(fb,
+		mappedElement{Selector: "#count", Label: `[button] "Clicked 1 times"`},
+		mappedElement{Selector: "#save", Label: `[button] "Save"`},
+		mappedElement{Selector: "#old", Label: `[a] "Old link"`},
+	)
+	if _, err := h.Call("browser_map", map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	page.set(
+		mappedElement{Selector: "#count", Label: `[button] "Clicked 2 times"`},
+		mappedElement{Selector: "#save", Label: `[button] "Save"`},
+		mappedElement{Selector: "#undo", Label: `[button] "Undo"`},
+	)
+	result, err := h.Call("browser_diff_map", map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		`- @e3 [a] "Old link"`,
+		`~ @e1 [button] "Clicked 1 times" → [button] "Clicked 2 times"`,
+		`+ @e3 [button] "Undo"`,
+	}, "\n")
+	if got := result.Content[0].Text; got != want {
+		t.Errorf("diff =\n%s\nwant\n%s", got, want)
+	}
+
+	result, err = h.Call("browser_diff_map", map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.Content[0].Text; got != "No changes detected" {
+		t.Errorf("diff of an unchanged page = %q", got)
+	}
+}
+
+// readChunk returns the trace events file name and contents of a chunk zip.
+func readChunk(t *testing.T, path string) (string, string) {
+	t.Helper()
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	for _, f := range zr.File {
+		if !strings.HasSuffix(f.Name, ".trace") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f.Name, string(data)
+	}
+	t.Fatalf("%s has no trace file", path)
+	return "", ""
+}
+
+func TestRecordStopChunkNextStartsFreshChunk(t *testing.T) {
+	h := &Handlers{recorder: api.NewRecorder()}
+	h.recorder.Start(api.RecordingStartOptions{Name: "session"})
+	dir := t.TempDir()
+
+	h.recorder.StartGroup("Login")
+	first := filepath.Join(dir, "first.zip")
+	result, err := h.browserRecordStopChunk(map[string]interface{}{"path": first, "next": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Chunk saved to " + first + "; started next chunk"; result.Content[0].Text != want {
+		t.Errorf("text = %q, want %q", result.Content[0].Text, want)
+	}
+
+	h.recorder.StartGroup("Checkout")
+	second := filepath.Join(dir, "second.zip")
+	if _, err := h.browserRecordStopChunk(map[string]interface{}{"path": second}); err != nil {
+		t.Fatal(err)
+	}
+
+	name, trace := readChunk(t, first)
+	if name != "0-trace.trace" || !strings.Contains(trace, `"Login"`) {
+		t.Errorf("first chunk %s = %s, want the Login group", name, trace)
+	}
+	name, trace = readChunk(t, second)
+	if name != "1-trace.trace" || !strings.Contains(trace, `"Checkout"`) || strings.Contains(trace, `"Login"`) {
+		t.Errorf("second chunk %s = %s, want only the Checkout group", name, trace)
+	}
+}
+
+func TestRecordStopGroupNamesTheGroup(t *testing.T) {
+	h := &Handlers{recorder: api.NewRecorder()}
+	h.recorder.Start(api.RecordingStartOptions{Name: "session"})
+
+	if _, err := h.browserRecordStopGroup(map[string]interface{}{}); err == nil {
+		t.Error("stopping with no open group should fail")
+	}
+	h.recorder.StartGroup("Add to cart")
+	result, err := h.browserRecordStopGroup(map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `Stopped group "Add to cart"`; result.Content[0].Text != want {
+		t.Errorf("text = %q, want %q", result.Content[0].Text, want)
+	}
+}
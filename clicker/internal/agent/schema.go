@@ -2,7 +2,7 @@ package agent
 
 // GetToolSchemas returns the list of available MCP tools with their schemas.
 func GetToolSchemas() []Tool {
-	return []Tool{
+	tools := []Tool{
 		{
 			Name:        "browser_start",
 			Description: "Start a browser session",
@@ -43,6 +43,21 @@ func GetToolSchemas() []Tool {
 						"type":        "string",
 						"description": "CSS selector for the element to click",
 					},
+					"button": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"left", "middle", "right"},
+						"description": "Mouse button to click with; middle-clicking a link opens it in a new tab (default: left)",
+						"default":     "left",
+					},
+					"force": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Skip actionability checks and act as soon as the element is found. Bypasses the safety checks; use only for elements that never settle, such as perpetually animating buttons.",
+						"default":     false,
+					},
+					"timeout": map[string]interface{}{
+						"type":        "number",
+						"description": "Milliseconds to wait for the element to become actionable (default: 30000)",
+					},
 				},
 				"required":             []string{"selector"},
 				"additionalProperties": false,
@@ -50,26 +65,40 @@ func GetToolSchemas() []Tool {
 		},
 		{
 			Name:        "browser_type",
-			Description: "Type text into an element by CSS selector. Waits for element to be visible, stable, enabled, and editable.",
+			Description: "Type text into an element by CSS selector. Waits for element to be visible, stable, enabled, and editable. Without a selector, types into the currently focused element.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
 					"selector": map[string]interface{}{
 						"type":        "string",
-						"description": "CSS selector for the element to type into",
+						"description": "CSS selector for the element to type into (optional, defaults to the currently focused element)",
 					},
 					"text": map[string]interface{}{
 						"type":        "string",
 						"description": "The text to type",
 					},
+					"raw": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Insert the text in one edit (like an IME commit) instead of one key press per character. Use for Japanese, Chinese, and emoji; fires input but no key events.",
+						"default":     false,
+					},
+					"force": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Skip actionability checks and act as soon as the element is found. Bypasses the safety checks; use only for elements that never settle, such as perpetually animating buttons.",
+						"default":     false,
+					},
+					"timeout": map[string]interface{}{
+						"type":        "number",
+						"description": "Milliseconds to wait for the element to become actionable (default: 30000)",
+					},
 				},
-				"required":             []string{"selector", "text"},
+				"required":             []string{"text"},
 				"additionalProperties": false,
 			},
 		},
 		{
 			Name:        "browser_screenshot",
-			Description: "Capture a screenshot of the current page",
+			Description: "Capture a screenshot of the current page, or of a single element when selector is given. With baseline, also compare it against a saved PNG for visual regression checks.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -77,16 +106,87 @@ func GetToolSchemas() []Tool {
 						"type":        "string",
 						"description": "Optional filename to save the screenshot (e.g., screenshot.png)",
 					},
+					"selector": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS selector or @ref of an element to clip the screenshot to (takes precedence over fullPage)",
+					},
 					"fullPage": map[string]interface{}{
 						"type":        "boolean",
 						"description": "Capture the full page (entire document) instead of just the viewport (default: false)",
 						"default":     false,
 					},
+					"stitch": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Capture the full page by scrolling and stitching viewport captures. Use when fullPage truncates very tall pages; fixed and sticky elements appear only at the top. (default: false)",
+						"default":     false,
+					},
 					"annotate": map[string]interface{}{
 						"type":        "boolean",
 						"description": "Annotate interactive elements with numbered labels (default: false)",
 						"default":     false,
 					},
+					"mask": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "CSS selectors or @refs of elements to paint over with a solid color, e.g. timestamps or ads that would break baseline comparisons",
+					},
+					"maskColor": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS color for masked elements (default: #FF00FF)",
+					},
+					"baseline": map[string]interface{}{
+						"type":        "string",
+						"description": "Baseline PNG to compare the screenshot against, relative to the screenshot directory (subdirectories allowed); reports the percentage of differing pixels, and the result is an error when any pixel differs. If the file doesn't exist, the screenshot is saved there as the new baseline.",
+					},
+					"threshold": map[string]interface{}{
+						"type":        "number",
+						"description": "Per-pixel color tolerance for baseline comparison, 0-1 (default: 0.1; 0 = exact match)",
+					},
+					"diffPath": map[string]interface{}{
+						"type":        "string",
+						"description": "Where to write a diff image (changed pixels in red) when the screenshot differs from the baseline, relative to the screenshot directory",
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_set_screenshot_dir",
+			Description: "Set the directory where screenshots are saved for the rest of the session. The directory must be inside the one the server was started with (--screenshot-dir); relative paths are resolved against it. Creates the directory and returns its absolute path. Fails when file saving is disabled.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "Directory for screenshots, inside the configured screenshot directory",
+					},
+				},
+				"required":             []string{"path"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_wait_until_stable_screenshot",
+			Description: "Capture the viewport repeatedly until two consecutive screenshots match, indicating the page has visually settled. Returns the stable screenshot.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"timeout": map[string]interface{}{
+						"type":        "number",
+						"description": "Timeout in milliseconds (default: 30000)",
+					},
+					"interval": map[string]interface{}{
+						"type":        "number",
+						"description": "Delay between captures in milliseconds (default: 250)",
+					},
+					"tolerance": map[string]interface{}{
+						"type":        "number",
+						"description": "Fraction of pixels (0.0-1.0) allowed to differ between captures (default: 0)",
+					},
+					"filename": map[string]interface{}{
+						"type":        "string",
+						"description": "Optional filename to save the stable screenshot (e.g., stable.png)",
+					},
 				},
 				"additionalProperties": false,
 			},
@@ -107,11 +207,11 @@ func GetToolSchemas() []Tool {
 					},
 					"text": map[string]interface{}{
 						"type":        "string",
-						"description": "Find element containing this text",
+						"description": "Find element containing this text (case-sensitive substring match by default; the match with the shortest text wins)",
 					},
 					"label": map[string]interface{}{
 						"type":        "string",
-						"description": "Find input by associated label text or aria-label",
+						"description": "Find input by associated label text or aria-label (case-sensitive substring match by default)",
 					},
 					"placeholder": map[string]interface{}{
 						"type":        "string",
@@ -133,19 +233,32 @@ func GetToolSchemas() []Tool {
 						"type":        "string",
 						"description": "Find element by title attribute",
 					},
+					"exact": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Require text and label to equal the element's text after trimming, instead of containing it. The first match in document order wins.",
+					},
+					"caseInsensitive": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Ignore case when matching text and label",
+					},
 				},
 				"additionalProperties": false,
 			},
 		},
 		{
 			Name:        "browser_evaluate",
-			Description: "Execute JavaScript in the browser to extract data, query the DOM, or inspect page state. Returns the evaluated result. Use this to get text content, attributes, element data, or any information from the page.",
+			Description: "Execute JavaScript in the browser to extract data, query the DOM, or inspect page state. Returns the evaluated result: objects and arrays as JSON, strings as plain text. Use this to get text content, attributes, element data, or any information from the page.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
 					"expression": map[string]interface{}{
 						"type":        "string",
-						"description": "JavaScript expression to evaluate",
+						"description": "JavaScript expression to evaluate, or a function (e.g. \"(sel, n) => ...\") when args is given",
+					},
+					"args": map[string]interface{}{
+						"type":        "array",
+						"description": "Arguments passed positionally to the function in expression. Use this instead of concatenating values into the code.",
+						"items":       map[string]interface{}{},
 					},
 				},
 				"required":             []string{"expression"},
@@ -169,11 +282,85 @@ func GetToolSchemas() []Tool {
 				"properties": map[string]interface{}{
 					"selector": map[string]interface{}{
 						"type":        "string",
-						"description": "CSS selector for a specific element (optional, defaults to full page HTML)",
+						"description": "CSS selector or @ref for a specific element (optional, defaults to full page HTML)",
 					},
 					"outer": map[string]interface{}{
 						"type":        "boolean",
-						"description": "Return outerHTML instead of innerHTML (default: false)",
+						"description": "Return the element's outerHTML (including its own tag) instead of innerHTML (its children only) (default: false)",
+						"default":     false,
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_get_outer_html",
+			Description: "Get an element's outerHTML — the element's own tag plus its children. Use this instead of browser_get_html when you need the element itself, not just its contents.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"selector": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS selector or @ref for the element",
+					},
+				},
+				"required":             []string{"selector"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_get_element_html_and_text",
+			Description: "Get an element's outerHTML and innerText in one call. Returns JSON: {outerHTML, innerText}. The HTML is truncated to maxLength characters.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"selector": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS selector or @ref for the element",
+					},
+					"maxLength": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum HTML length in characters before truncation (default: 5000)",
+						"default":     5000,
+					},
+				},
+				"required":             []string{"selector"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_get_table",
+			Description: "Extract an HTML table's cell text. Returns a JSON 2D array of rows (header rows included), or with headers: true an array of row objects keyed by header text. Cells with colspan/rowspan repeat their text in every slot they cover.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"selector": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS selector or @ref for the <table> or an element containing one",
+					},
+					"headers": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Return data rows as objects keyed by the <th> header text (default: false)",
+						"default":     false,
+					},
+				},
+				"required":             []string{"selector"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_get_links",
+			Description: "List the links on the page. Returns a JSON array of {text, href, visible} for every <a href>, with absolute URLs and duplicate hrefs removed.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"pattern": map[string]interface{}{
+						"type":        "string",
+						"description": "Only return links whose URL matches: a substring or glob with * (optional)",
+					},
+					"regex": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Treat pattern as a regular expression (default: false)",
 						"default":     false,
 					},
 				},
@@ -200,9 +387,32 @@ func GetToolSchemas() []Tool {
 				"additionalProperties": false,
 			},
 		},
+		{
+			Name:        "browser_find_all_by_role",
+			Description: "Find every element with an ARIA role (explicit or implied by the tag), in document order. Returns a JSON array of {index, ref, tag, name, text, box}; use the ref (@e1 for index 0, ...) to act on a specific match, e.g. the 3rd tab.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"role": map[string]interface{}{
+						"type":        "string",
+						"description": "ARIA role to match (e.g., \"tab\", \"button\", \"link\", \"row\")",
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Only match elements whose accessible name contains this text",
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of elements to return (default: no limit)",
+					},
+				},
+				"required":             []string{"role"},
+				"additionalProperties": false,
+			},
+		},
 		{
 			Name:        "browser_wait",
-			Description: "Wait for an element to reach a specified state (attached, visible, or hidden)",
+			Description: "Wait for an element to reach a specified state (attached, visible, hidden, enabled, editable, or stable)",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -212,10 +422,15 @@ func GetToolSchemas() []Tool {
 					},
 					"state": map[string]interface{}{
 						"type":        "string",
-						"description": "State to wait for: \"attached\" (exists in DOM), \"visible\" (visible on page), or \"hidden\" (not found or not visible)",
-						"enum":        []string{"attached", "visible", "hidden"},
+						"description": "State to wait for: \"attached\" (exists in DOM), \"visible\" (visible on page), \"hidden\" (not found or not visible), \"enabled\" (not disabled), \"editable\" (not disabled or read-only), or \"stable\" (position and size stopped changing)",
+						"enum":        []string{"attached", "visible", "hidden", "enabled", "editable", "stable"},
 						"default":     "attached",
 					},
+					"stablePolls": map[string]interface{}{
+						"type":        "number",
+						"description": "For state \"stable\": consecutive unchanged bounding-box samples (100ms apart) required (default: 2)",
+						"default":     2,
+					},
 					"timeout": map[string]interface{}{
 						"type":        "number",
 						"description": "Timeout in milliseconds (default: 30000)",
@@ -228,7 +443,7 @@ func GetToolSchemas() []Tool {
 		},
 		{
 			Name:        "browser_new_page",
-			Description: "Open a new browser page, optionally navigating to a URL",
+			Description: "Open a new browser page, optionally navigating to a URL. Returns the page's index (as in browser_list_pages) and context ID.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -236,13 +451,17 @@ func GetToolSchemas() []Tool {
 						"type":        "string",
 						"description": "URL to navigate to in the new page (optional)",
 					},
+					"switch": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Make the new page the active page (default: true). Set false to open it in the background.",
+					},
 				},
 				"additionalProperties": false,
 			},
 		},
 		{
 			Name:        "browser_list_pages",
-			Description: "List all open browser pages with their URLs",
+			Description: "List all open browser pages with their URLs, followed by recently closed pages (e.g. a popup that closed itself)",
 			InputSchema: map[string]interface{}{
 				"type":                 "object",
 				"properties":           map[string]interface{}{},
@@ -251,7 +470,7 @@ func GetToolSchemas() []Tool {
 		},
 		{
 			Name:        "browser_switch_page",
-			Description: "Switch to a browser page by index or URL substring",
+			Description: "Switch to a browser page by index, URL substring, or the most recently opened page (latest: true, e.g. after a target=_blank link or OAuth popup)",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -263,6 +482,10 @@ func GetToolSchemas() []Tool {
 						"type":        "string",
 						"description": "URL substring to match (alternative to index)",
 					},
+					"latest": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Switch to the page opened most recently in this session (alternative to index)",
+					},
 				},
 				"additionalProperties": false,
 			},
@@ -284,7 +507,7 @@ func GetToolSchemas() []Tool {
 		},
 		{
 			Name:        "browser_hover",
-			Description: "Hover over an element by CSS selector",
+			Description: "Hover over an element by CSS selector. Use steps and holdMs for flyout menus that need a realistic mouse path or a hover delay.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -292,6 +515,14 @@ func GetToolSchemas() []Tool {
 						"type":        "string",
 						"description": "CSS selector for the element to hover over",
 					},
+					"steps": map[string]interface{}{
+						"type":        "number",
+						"description": "Glide to the element over this many frames from the current pointer position, firing intermediate mousemove events (default: jump in one move)",
+					},
+					"holdMs": map[string]interface{}{
+						"type":        "number",
+						"description": "Keep hovering for this many milliseconds before returning",
+					},
 				},
 				"required":             []string{"selector"},
 				"additionalProperties": false,
@@ -299,7 +530,7 @@ func GetToolSchemas() []Tool {
 		},
 		{
 			Name:        "browser_select",
-			Description: "Select an option in a <select> element by value",
+			Description: "Select options in a <select> element by value or visible label. Use values/labels arrays for <select multiple>. Returns the selected values.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -309,10 +540,62 @@ func GetToolSchemas() []Tool {
 					},
 					"value": map[string]interface{}{
 						"type":        "string",
-						"description": "The value to select",
+						"description": "The option value to select",
+					},
+					"values": map[string]interface{}{
+						"type":        "array",
+						"description": "Option values to select (multi-select)",
+						"items": map[string]interface{}{
+							"type": "string",
+						},
+					},
+					"label": map[string]interface{}{
+						"type":        "string",
+						"description": "The visible option text to select",
+					},
+					"labels": map[string]interface{}{
+						"type":        "array",
+						"description": "Visible option texts to select (multi-select)",
+						"items": map[string]interface{}{
+							"type": "string",
+						},
+					},
+				},
+				"required":             []string{"selector"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_select_by_index",
+			Description: "Select an option in a <select> element by its zero-based position. Useful when options have duplicate labels or values. Returns the resulting value.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"selector": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS selector for the <select> element",
+					},
+					"optionIndex": map[string]interface{}{
+						"type":        "number",
+						"description": "Zero-based index of the option to select",
+					},
+				},
+				"required":             []string{"selector", "optionIndex"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_select_options",
+			Description: "List the options of a <select> element. Returns a JSON array of {value, label, selected, disabled}. Use it to pick a valid value for browser_select.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"selector": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS selector or @ref for the <select> element",
 					},
 				},
-				"required":             []string{"selector", "value"},
+				"required":             []string{"selector"},
 				"additionalProperties": false,
 			},
 		},
@@ -337,13 +620,50 @@ func GetToolSchemas() []Tool {
 						"type":        "string",
 						"description": "CSS selector for element to scroll to (optional, defaults to viewport center)",
 					},
+					"to": map[string]interface{}{
+						"type":        "string",
+						"description": "Scroll the window to the top or bottom of the page instead. Overrides direction and amount.",
+						"enum":        []string{"top", "bottom"},
+					},
+					"scrollX": map[string]interface{}{
+						"type":        "number",
+						"description": "Scroll the window to this absolute horizontal position in pixels instead. Overrides direction and amount.",
+					},
+					"scrollY": map[string]interface{}{
+						"type":        "number",
+						"description": "Scroll the window to this absolute vertical position in pixels instead. Overrides direction and amount.",
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_scroll_by",
+			Description: "Scroll by an exact number of pixels with a single mouse wheel event, then return the window scroll position. Use this for precise scrolling; browser_scroll moves in coarse increments.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"deltaX": map[string]interface{}{
+						"type":        "number",
+						"description": "Horizontal pixels (negative scrolls left)",
+						"default":     0,
+					},
+					"deltaY": map[string]interface{}{
+						"type":        "number",
+						"description": "Vertical pixels (negative scrolls up)",
+						"default":     0,
+					},
+					"selector": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS selector for the element to scroll over (optional, defaults to viewport center)",
+					},
 				},
 				"additionalProperties": false,
 			},
 		},
 		{
 			Name:        "browser_keys",
-			Description: "Press a key or key combination (e.g., \"Enter\", \"Control+a\", \"Shift+Tab\")",
+			Description: "Press a key or key combination (e.g., \"Enter\", \"Control+a\", \"Shift+Tab\"), or a sequence of them in one rapid burst (e.g., [\"g\", \"g\"] for vim-style bindings)",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -351,8 +671,42 @@ func GetToolSchemas() []Tool {
 						"type":        "string",
 						"description": "Key or key combination to press (e.g., \"Enter\", \"Control+a\", \"Shift+ArrowDown\")",
 					},
+					"sequence": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Keys or key combinations to press in order, sent together so no delay falls between them. Use instead of keys.",
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_key_sequence",
+			Description: "Perform an ordered sequence of key steps on the focused element. Use it when a combo string can't express the input, e.g. hold Shift, press ArrowDown five times, release Shift. Keys still held at the end are released.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"actions": map[string]interface{}{
+						"type":        "array",
+						"description": "Key steps in order",
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"action": map[string]interface{}{
+									"type":        "string",
+									"enum":        []string{"down", "up", "press"},
+									"description": "down holds the key, up releases it, press taps it",
+								},
+								"key": map[string]interface{}{
+									"type":        "string",
+									"description": "Key name (e.g., \"Shift\", \"ArrowDown\", \"a\")",
+								},
+							},
+							"required": []string{"action", "key"},
+						},
+					},
 				},
-				"required":             []string{"keys"},
+				"required":             []string{"actions"},
 				"additionalProperties": false,
 			},
 		},
@@ -366,6 +720,17 @@ func GetToolSchemas() []Tool {
 						"type":        "string",
 						"description": "CSS selector for a specific element (optional, defaults to full page text)",
 					},
+					"all": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Return the text of every element matching selector, one per line (default: false)",
+						"default":     false,
+					},
+					"mode": map[string]interface{}{
+						"type":        "string",
+						"description": "\"innerText\" (rendered text, omits display:none content) or \"textContent\" (all text nodes, including hidden elements and script/style contents)",
+						"enum":        []string{"innerText", "textContent"},
+						"default":     "innerText",
+					},
 				},
 				"additionalProperties": false,
 			},
@@ -399,23 +764,48 @@ func GetToolSchemas() []Tool {
 						"description": "Show all nodes including generic containers. Default: false",
 						"default":     false,
 					},
+					"withSelectors": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Attach a CSS \"selector\" to each interactive node (buttons, links, inputs, etc.) so it can be acted on directly. Makes the output larger. Default: false",
+						"default":     false,
+					},
 				},
 				"additionalProperties": false,
 			},
 		},
 		{
-			Name:        "page_clock_install",
-			Description: "Install a fake clock on the page, overriding Date, setTimeout, setInterval, requestAnimationFrame, and performance.now",
+			Name:        "browser_snapshot",
+			Description: "Get a single structured view of the page: the accessibility tree (JSON) with a \"ref\" on every interactive node, usable as a selector in other commands. Runs browser_map, so earlier @refs are replaced.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"time": map[string]interface{}{
-						"type":        "number",
-						"description": "Initial time as epoch milliseconds (optional)",
+					"everything": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Show all nodes including generic containers. Default: false",
+						"default":     false,
 					},
-					"timezone": map[string]interface{}{
-						"type":        "string",
-						"description": "IANA timezone ID to override (e.g. 'America/New_York', 'Europe/London')",
+					"stableRefs": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Keep @refs of elements that persist from the previous stable map (see browser_map)",
+						"default":     false,
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "page_clock_install",
+			Description: "Install a fake clock on the page, overriding Date, setTimeout, setInterval, requestAnimationFrame, and performance.now",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"time": map[string]interface{}{
+						"type":        "number",
+						"description": "Initial time as epoch milliseconds (optional)",
+					},
+					"timezone": map[string]interface{}{
+						"type":        "string",
+						"description": "IANA timezone ID to override (e.g. 'America/New_York', 'Europe/London')",
 					},
 				},
 				"additionalProperties": false,
@@ -522,7 +912,7 @@ func GetToolSchemas() []Tool {
 		},
 		{
 			Name:        "browser_fill",
-			Description: "Clear an input field and type new text. Waits for element to be editable, clears existing value, then types. Use this instead of browser_type when you want to replace the field contents.",
+			Description: "Replace an input field's value. Waits for the element to be visible, enabled, and editable, then sets the value through the native setter and dispatches input and change events. It never clicks, so it works on inputs covered by sticky headers or overlays. Use this instead of browser_type when you want to replace the field contents.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -534,11 +924,30 @@ func GetToolSchemas() []Tool {
 						"type":        "string",
 						"description": "The text to fill in",
 					},
+					"timeout": map[string]interface{}{
+						"type":        "number",
+						"description": "Milliseconds to wait for the element to become actionable (default: 30000)",
+					},
 				},
 				"required":             []string{"selector", "text"},
 				"additionalProperties": false,
 			},
 		},
+		{
+			Name:        "browser_clear",
+			Description: "Clear an input field's value. Waits for the element to be visible, enabled, and editable, then empties it through the native setter and dispatches input and change events, without clicking. Clearing an empty field is a no-op.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"selector": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS selector or @ref for the input element",
+					},
+				},
+				"required":             []string{"selector"},
+				"additionalProperties": false,
+			},
+		},
 		{
 			Name:        "browser_press",
 			Description: "Press a key or key combination on a specific element or the focused element. If selector is given, clicks the element first to focus it, then presses the key.",
@@ -619,6 +1028,28 @@ func GetToolSchemas() []Tool {
 				"additionalProperties": false,
 			},
 		},
+		{
+			Name:        "browser_get_attributes",
+			Description: "Get several HTML attributes of an element in one call. Returns a JSON object mapping each attribute name to its value, or null if the attribute is not present.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"selector": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS selector for the element",
+					},
+					"attributes": map[string]interface{}{
+						"type":        "array",
+						"description": "Attribute names to retrieve (e.g., [\"href\", \"data-id\"])",
+						"items": map[string]interface{}{
+							"type": "string",
+						},
+					},
+				},
+				"required":             []string{"selector", "attributes"},
+				"additionalProperties": false,
+			},
+		},
 		{
 			Name:        "browser_is_visible",
 			Description: "Check if an element is visible on the page. Returns true/false without throwing errors.",
@@ -644,6 +1075,10 @@ func GetToolSchemas() []Tool {
 						"type":        "string",
 						"description": "CSS selector for the checkbox or radio button",
 					},
+					"timeout": map[string]interface{}{
+						"type":        "number",
+						"description": "Milliseconds to wait for the element to become actionable (default: 30000)",
+					},
 				},
 				"required":             []string{"selector"},
 				"additionalProperties": false,
@@ -659,6 +1094,10 @@ func GetToolSchemas() []Tool {
 						"type":        "string",
 						"description": "CSS selector for the checkbox",
 					},
+					"timeout": map[string]interface{}{
+						"type":        "number",
+						"description": "Milliseconds to wait for the element to become actionable (default: 30000)",
+					},
 				},
 				"required":             []string{"selector"},
 				"additionalProperties": false,
@@ -681,13 +1120,43 @@ func GetToolSchemas() []Tool {
 		},
 		{
 			Name:        "browser_wait_for_url",
-			Description: "Wait until the page URL contains a given substring",
+			Description: "Wait until the page URL contains a given substring, or matches a regular expression when regex is true",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"pattern": map[string]interface{}{
+						"type":        "string",
+						"description": "Substring to match in the URL (or a Go regular expression when regex is true)",
+					},
+					"regex": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Treat pattern as a regular expression (default: false)",
+						"default":     false,
+					},
+					"timeout": map[string]interface{}{
+						"type":        "number",
+						"description": "Timeout in milliseconds (default: 30000)",
+						"default":     30000,
+					},
+				},
+				"required":             []string{"pattern"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_wait_for_response",
+			Description: "Wait for a network response whose URL matches a pattern and return its URL, status, and headers. Responses that completed during the previous tool call (e.g. the click that sent the request) also match.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
 					"pattern": map[string]interface{}{
 						"type":        "string",
-						"description": "Substring to match in the URL",
+						"description": "URL substring or glob (e.g. \"*/api/login*\"), or a regular expression when regex is true",
+					},
+					"regex": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Treat pattern as a regular expression",
+						"default":     false,
 					},
 					"timeout": map[string]interface{}{
 						"type":        "number",
@@ -699,6 +1168,89 @@ func GetToolSchemas() []Tool {
 				"additionalProperties": false,
 			},
 		},
+		{
+			Name:        "browser_wait_for_download",
+			Description: "Wait for a download to finish and return its URL, suggested filename, saved path, and size in bytes. Downloads started during the previous tool call (e.g. the click that triggered it) also match. Use browser_download_set_dir first to choose where files go.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"timeout": map[string]interface{}{
+						"type":        "number",
+						"description": "Timeout in milliseconds (default: 30000)",
+						"default":     30000,
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_list_downloads",
+			Description: "List downloads seen this session, oldest first, with URL, suggested filename, state (in-progress, complete, or failed), saved path, and bytes on disk (-1 when unknown)",
+			InputSchema: map[string]interface{}{
+				"type":                 "object",
+				"properties":           map[string]interface{}{},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_wait_for_page",
+			Description: "Wait for a new page (tab or popup) to open, optionally one whose URL matches a pattern, and return its index, URL, and context. Pages opened during the previous tool call (e.g. the click that opened the popup) also match.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"url": map[string]interface{}{
+						"type":        "string",
+						"description": "URL substring or glob, or a regular expression when regex is true (default: any new page)",
+					},
+					"regex": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Treat url as a regular expression",
+						"default":     false,
+					},
+					"timeout": map[string]interface{}{
+						"type":        "number",
+						"description": "Timeout in milliseconds (default: 30000)",
+						"default":     30000,
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_console_logs",
+			Description: "Get console messages (console.log, warn, error, ...) captured since the browser started or the logs were last cleared",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"level": map[string]interface{}{
+						"type":        "string",
+						"description": "Only return messages at this level",
+						"enum":        []string{"debug", "info", "warn", "error"},
+					},
+					"clear": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Clear the buffer after returning the messages",
+						"default":     false,
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_page_errors",
+			Description: "Get uncaught JavaScript exceptions and unhandled promise rejections (with stack traces) captured since the browser started or the errors were last cleared",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"clear": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Clear the buffer after returning the errors",
+						"default":     false,
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
 		{
 			Name:        "browser_wait_for_load",
 			Description: "Wait until the page reaches the \"complete\" ready state (all resources loaded)",
@@ -739,13 +1291,42 @@ func GetToolSchemas() []Tool {
 						"type":        "string",
 						"description": "CSS selector to scope element discovery to a subtree (e.g. \"nav\", \"#sidebar\")",
 					},
+					"stableRefs": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Keep @refs stable across maps: an element with the same selector and label keeps its @ref, and only new elements get new refs (default: false, refs restart at @e1)",
+						"default":     false,
+					},
+					"roles": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Only map elements with one of these ARIA roles, explicit or implied by the tag (e.g. [\"button\", \"link\"])",
+					},
+					"tags": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Only map elements with one of these tag names (e.g. [\"input\", \"select\"])",
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of elements to map, after filtering (default: no limit)",
+					},
+					"onlyInViewport": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Only map elements currently intersecting the viewport (default: false)",
+						"default":     false,
+					},
+					"markOffscreen": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Mark elements outside the viewport \"(offscreen)\", or \"(offscreen, below fold)\" when below the first screenful (default: false)",
+						"default":     false,
+					},
 				},
 				"additionalProperties": false,
 			},
 		},
 		{
 			Name:        "browser_diff_map",
-			Description: "Compare current page state vs last map. Shows additions (+) and removals (-) since the last browser_map call.",
+			Description: "Compare current page state vs last map. Elements are matched by selector: shows additions (+), removals (-), and label changes (~ old → new) since the last browser_map call.",
 			InputSchema: map[string]interface{}{
 				"type":                 "object",
 				"properties":           map[string]interface{}{},
@@ -754,7 +1335,7 @@ func GetToolSchemas() []Tool {
 		},
 		{
 			Name:        "browser_pdf",
-			Description: "Save the current page as a PDF file",
+			Description: "Save the current page as a PDF file, with optional paper size, orientation, scale, and margins",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -762,6 +1343,56 @@ func GetToolSchemas() []Tool {
 						"type":        "string",
 						"description": "Output filename for the PDF (e.g., page.pdf)",
 					},
+					"format": map[string]interface{}{
+						"type":        "string",
+						"description": "Paper format: Letter, Legal, Tabloid, Ledger, A0-A6 (default: Letter)",
+					},
+					"landscape": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Use landscape orientation (default: false)",
+						"default":     false,
+					},
+					"printBackground": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Print background colors and images (default: false)",
+						"default":     false,
+					},
+					"scale": map[string]interface{}{
+						"type":        "number",
+						"description": "Scale of the page rendering, 0.1-2 (default: 1)",
+						"minimum":     0.1,
+						"maximum":     2,
+					},
+					"margin": map[string]interface{}{
+						"type":        "object",
+						"description": "Page margins as length strings with a unit (e.g., \"1in\", \"10mm\", \"2cm\"); a string without a unit (e.g., \"2\") is centimeters",
+						"properties": map[string]interface{}{
+							"top":    map[string]interface{}{"type": "string"},
+							"right":  map[string]interface{}{"type": "string"},
+							"bottom": map[string]interface{}{"type": "string"},
+							"left":   map[string]interface{}{"type": "string"},
+						},
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_print_preview",
+			Description: "Preview how the page looks when printed. Temporarily switches to print media, captures a full-page screenshot (or a PDF), then restores screen media so the page never stays in print mode.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"format": map[string]interface{}{
+						"type":        "string",
+						"description": "Output format: png (screenshot with print styles) or pdf (default: png)",
+						"enum":        []string{"png", "pdf"},
+						"default":     "png",
+					},
+					"filename": map[string]interface{}{
+						"type":        "string",
+						"description": "Optional filename to save the preview (PNG goes to the screenshot directory)",
+					},
 				},
 				"additionalProperties": false,
 			},
@@ -796,6 +1427,60 @@ func GetToolSchemas() []Tool {
 				"additionalProperties": false,
 			},
 		},
+		{
+			Name:        "browser_right_click",
+			Description: "Right-click an element by CSS selector or @ref to open its context menu. Waits for element to be visible, stable, and enabled.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"selector": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS selector or @ref for the element to right-click",
+					},
+				},
+				"required":             []string{"selector"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_tap",
+			Description: "Tap an element by CSS selector or @ref with a touch pointer. Fires touchstart/touchend instead of mouse events; use with mobile emulation.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"selector": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS selector or @ref for the element to tap",
+					},
+				},
+				"required":             []string{"selector"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_pinch",
+			Description: "Pinch-zoom an element with a two-finger touch gesture around its center. Use for maps, image viewers, and other touch-zoomable content.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"selector": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS selector or @ref for the element to pinch",
+					},
+					"scale": map[string]interface{}{
+						"type":        "number",
+						"description": "Ratio of final to initial finger distance: >1 zooms in, <1 zooms out",
+					},
+					"steps": map[string]interface{}{
+						"type":        "number",
+						"description": "Number of synchronized finger moves (default: 10)",
+						"default":     10,
+					},
+				},
+				"required":             []string{"selector", "scale"},
+				"additionalProperties": false,
+			},
+		},
 		{
 			Name:        "browser_focus",
 			Description: "Focus an element by CSS selector or @ref",
@@ -811,15 +1496,37 @@ func GetToolSchemas() []Tool {
 				"additionalProperties": false,
 			},
 		},
+		{
+			Name:        "browser_focus_next",
+			Description: "Press Tab to move focus to the next element in tab order, then return the newly focused element's label and selector",
+			InputSchema: map[string]interface{}{
+				"type":                 "object",
+				"properties":           map[string]interface{}{},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_focus_previous",
+			Description: "Press Shift+Tab to move focus to the previous element in tab order, then return the newly focused element's label and selector",
+			InputSchema: map[string]interface{}{
+				"type":                 "object",
+				"properties":           map[string]interface{}{},
+				"additionalProperties": false,
+			},
+		},
 		{
 			Name:        "browser_count",
-			Description: "Count the number of elements matching a CSS selector",
+			Description: "Count the number of elements matching a CSS selector, page-wide or within a scope element",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
 					"selector": map[string]interface{}{
 						"type":        "string",
-						"description": "CSS selector to count matches for",
+						"description": "CSS selector (or XPath) to count matches for",
+					},
+					"scope": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS selector or @ref of the element to count within (e.g. a specific table or list); defaults to the whole page",
 					},
 				},
 				"required":             []string{"selector"},
@@ -841,6 +1548,21 @@ func GetToolSchemas() []Tool {
 				"additionalProperties": false,
 			},
 		},
+		{
+			Name:        "browser_is_editable",
+			Description: "Check if an element is editable (not disabled and not read-only). Returns true/false; false if the element is not found.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"selector": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS selector or @ref for the element",
+					},
+				},
+				"required":             []string{"selector"},
+				"additionalProperties": false,
+			},
+		},
 		{
 			Name:        "browser_is_checked",
 			Description: "Check if a checkbox or radio button is checked. Returns true/false.",
@@ -920,14 +1642,62 @@ func GetToolSchemas() []Tool {
 			},
 		},
 		{
-			Name:        "browser_get_cookies",
-			Description: "List all cookies for the current page",
+			Name:        "browser_set_dialog_handler",
+			Description: "Answer dialogs (alert, confirm, prompt, beforeunload) automatically as they open, so actions that trigger them don't block. Each auto-answered dialog is noted in the tool result. Use policy none to go back to handling them with browser_dialog_accept/browser_dialog_dismiss.",
 			InputSchema: map[string]interface{}{
-				"type":                 "object",
+				"type": "object",
+				"properties": map[string]interface{}{
+					"policy": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"accept", "dismiss", "accept-with-text", "none"},
+						"description": "How to answer dialogs",
+					},
+					"text": map[string]interface{}{
+						"type":        "string",
+						"description": "Text to answer prompt() dialogs with (required for accept-with-text)",
+					},
+				},
+				"required":             []string{"policy"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_get_dialog",
+			Description: "Get the open dialog as JSON ({type, message, defaultValue}), or null if no dialog is open. Check what a dialog asks before choosing browser_dialog_accept or browser_dialog_dismiss.",
+			InputSchema: map[string]interface{}{
+				"type":                 "object",
+				"properties":           map[string]interface{}{},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_get_cookies",
+			Description: "List all cookies for the current page",
+			InputSchema: map[string]interface{}{
+				"type":                 "object",
 				"properties":           map[string]interface{}{},
 				"additionalProperties": false,
 			},
 		},
+		{
+			Name:        "browser_get_cookie",
+			Description: "Get a single cookie by name. Returns it as JSON ({name, value, domain, path, secure, httpOnly, sameSite, expiry}), or null if it isn't set.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Cookie name",
+					},
+					"domain": map[string]interface{}{
+						"type":        "string",
+						"description": "Only match a cookie set for this domain (optional)",
+					},
+				},
+				"required":             []string{"name"},
+				"additionalProperties": false,
+			},
+		},
 		{
 			Name:        "browser_set_cookie",
 			Description: "Set a cookie",
@@ -950,6 +1720,23 @@ func GetToolSchemas() []Tool {
 						"type":        "string",
 						"description": "Cookie path (optional, defaults to /)",
 					},
+					"secure": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Only send the cookie over HTTPS",
+					},
+					"httpOnly": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Hide the cookie from document.cookie",
+					},
+					"sameSite": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"strict", "lax", "none"},
+						"description": "SameSite policy. none requires secure.",
+					},
+					"expiry": map[string]interface{}{
+						"type":        "number",
+						"description": "Expiry as Unix epoch seconds (optional, defaults to a session cookie)",
+					},
 				},
 				"required":             []string{"name", "value"},
 				"additionalProperties": false,
@@ -969,6 +1756,15 @@ func GetToolSchemas() []Tool {
 				"additionalProperties": false,
 			},
 		},
+		{
+			Name:        "browser_get_cookies_changed",
+			Description: "Report which cookies were added, removed, or changed value since the previous call. The first call takes a snapshot. Returns JSON: {added, removed, changed}.",
+			InputSchema: map[string]interface{}{
+				"type":                 "object",
+				"properties":           map[string]interface{}{},
+				"additionalProperties": false,
+			},
+		},
 		// --- Mouse primitives ---
 		{
 			Name:        "browser_mouse_move",
@@ -1044,7 +1840,7 @@ func GetToolSchemas() []Tool {
 		},
 		{
 			Name:        "browser_drag",
-			Description: "Drag from one element to another",
+			Description: "Drag from one element to another. The default pointer mode works for libraries driven by mouse/pointer events (dnd-kit, SortableJS with forceFallback, jQuery UI, react-beautiful-dnd). Use mode \"html5\" for libraries built on native drag-and-drop events (react-dnd's HTML5 backend, SortableJS by default, plain draggable=\"true\" elements).",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -1056,11 +1852,79 @@ func GetToolSchemas() []Tool {
 						"type":        "string",
 						"description": "CSS selector or @ref for the target element",
 					},
+					"mode": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"pointer", "html5"},
+						"description": "pointer: real mouse input (default); html5: synthetic dragstart/dragenter/dragover/drop/dragend events with a shared DataTransfer",
+						"default":     "pointer",
+					},
+					"holdMs": map[string]interface{}{
+						"type":        "number",
+						"description": "Pause in ms between pressing the button and moving (default: 100)",
+						"default":     100,
+					},
+					"moveMs": map[string]interface{}{
+						"type":        "number",
+						"description": "Total duration of the move in ms (default: 200)",
+						"default":     200,
+					},
+					"steps": map[string]interface{}{
+						"type":        "number",
+						"description": "Number of intermediate pointer moves; raise it for drag libraries that need several dragover events (default: 1)",
+						"default":     1,
+					},
 				},
 				"required":             []string{"source", "target"},
 				"additionalProperties": false,
 			},
 		},
+		{
+			Name:        "browser_drag_by",
+			Description: "Drag by a pixel delta from an element's center or from an x/y point. Use for range sliders, canvas drawing, and map panning.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"source": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS selector or @ref for the element to start from (or give x and y)",
+					},
+					"x": map[string]interface{}{
+						"type":        "number",
+						"description": "Start X coordinate in CSS pixels (when no source)",
+					},
+					"y": map[string]interface{}{
+						"type":        "number",
+						"description": "Start Y coordinate in CSS pixels (when no source)",
+					},
+					"deltaX": map[string]interface{}{
+						"type":        "number",
+						"description": "Horizontal distance in pixels",
+						"default":     0,
+					},
+					"deltaY": map[string]interface{}{
+						"type":        "number",
+						"description": "Vertical distance in pixels",
+						"default":     0,
+					},
+					"holdMs": map[string]interface{}{
+						"type":        "number",
+						"description": "Pause in ms between pressing the button and moving (default: 100)",
+						"default":     100,
+					},
+					"moveMs": map[string]interface{}{
+						"type":        "number",
+						"description": "Total duration of the move in ms (default: 200)",
+						"default":     200,
+					},
+					"steps": map[string]interface{}{
+						"type":        "number",
+						"description": "Number of intermediate pointer moves for a smooth drag (default: 1)",
+						"default":     1,
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
 		// --- Emulation ---
 		{
 			Name:        "browser_set_viewport",
@@ -1171,7 +2035,7 @@ func GetToolSchemas() []Tool {
 		},
 		{
 			Name:        "browser_set_geolocation",
-			Description: "Override the browser geolocation",
+			Description: "Override the browser geolocation (native Geolocation API, survives navigation). Pass clear: true to remove the override.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -1183,13 +2047,293 @@ func GetToolSchemas() []Tool {
 						"type":        "number",
 						"description": "Longitude (-180 to 180)",
 					},
+					"clear": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Remove the geolocation override instead of setting one (default: false)",
+						"default":     false,
+					},
 					"accuracy": map[string]interface{}{
 						"type":        "number",
 						"description": "Accuracy in meters (default: 1)",
 						"default":     1,
 					},
+					"altitude": map[string]interface{}{
+						"type":        "number",
+						"description": "Altitude in meters above the WGS84 ellipsoid",
+					},
+					"heading": map[string]interface{}{
+						"type":        "number",
+						"description": "Direction of travel in degrees clockwise from true north (0-360)",
+					},
+					"speed": map[string]interface{}{
+						"type":        "number",
+						"description": "Ground speed in meters per second",
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_set_offline",
+			Description: "Take the browser offline or back online to test offline banners and service-worker fallbacks. Requests fail, navigator.onLine updates and offline/online events fire. Persists across navigations until set back to false.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"offline": map[string]interface{}{
+						"type":        "boolean",
+						"description": "true to go offline, false to go back online",
+					},
+				},
+				"required":             []string{"offline"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_emulate_network",
+			Description: "Throttle the network to reproduce slow mobile connections (Chrome, local browsers only). Use a preset, custom values, or both (custom values override the preset). Returns the applied profile. Use preset \"online\" to remove throttling.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"preset": map[string]interface{}{
+						"type":        "string",
+						"description": "Named profile",
+						"enum":        []string{"slow-3g", "fast-3g", "offline", "online"},
+					},
+					"latency": map[string]interface{}{
+						"type":        "number",
+						"description": "Added round-trip latency in milliseconds",
+					},
+					"downloadThroughput": map[string]interface{}{
+						"type":        "number",
+						"description": "Download speed in bytes per second (-1 for unthrottled)",
+					},
+					"uploadThroughput": map[string]interface{}{
+						"type":        "number",
+						"description": "Upload speed in bytes per second (-1 for unthrottled)",
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_emulate_cpu",
+			Description: "Throttle the CPU to simulate a slower device, e.g. to check that spinners and skeleton screens appear (Chrome, local browsers only)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"rate": map[string]interface{}{
+						"type":        "number",
+						"description": "Slowdown factor, e.g. 4 for 4x slower (1 removes throttling)",
+						"minimum":     1,
+					},
 				},
-				"required":             []string{"latitude", "longitude"},
+				"required":             []string{"rate"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_set_user_agent",
+			Description: "Override the browser User-Agent for the current page, optionally with Accept-Language and navigator.platform",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"userAgent": map[string]interface{}{
+						"type":        "string",
+						"description": "User-Agent string to send and report via navigator.userAgent",
+					},
+					"acceptLanguage": map[string]interface{}{
+						"type":        "string",
+						"description": "Accept-Language header value, e.g. \"fr-FR,fr\" (local browsers only)",
+					},
+					"platform": map[string]interface{}{
+						"type":        "string",
+						"description": "navigator.platform value, e.g. \"iPhone\" (local browsers only)",
+					},
+				},
+				"required":             []string{"userAgent"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_request_intercept",
+			Description: "Block or stub network requests whose URL matches a pattern. Rules persist across navigations until removed with browser_request_unintercept; the most recently added matching rule wins.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"pattern": map[string]interface{}{
+						"type":        "string",
+						"description": "URL substring or glob (e.g. \"*/analytics/*\"), or a regular expression when regex is true",
+					},
+					"regex": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Treat pattern as a regular expression",
+						"default":     false,
+					},
+					"action": map[string]interface{}{
+						"type":        "string",
+						"description": "block (or its alias abort) fails the request with a network error; fulfill answers it with status, headers, and body",
+						"enum":        []string{"block", "abort", "fulfill"},
+						"default":     "block",
+					},
+					"status": map[string]interface{}{
+						"type":        "number",
+						"description": "HTTP status code for fulfill (default: 200)",
+						"default":     200,
+					},
+					"headers": map[string]interface{}{
+						"type":        "object",
+						"description": "Response headers for fulfill, e.g. {\"Content-Type\": \"application/json\"}",
+						"additionalProperties": map[string]interface{}{
+							"type": "string",
+						},
+					},
+					"body": map[string]interface{}{
+						"type":        "string",
+						"description": "Response body for fulfill",
+					},
+				},
+				"required":             []string{"pattern"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_request_unintercept",
+			Description: "Remove network intercept rules added with browser_request_intercept",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"pattern": map[string]interface{}{
+						"type":        "string",
+						"description": "Pattern of the rules to remove (omit to remove all rules)",
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_block_urls",
+			Description: "Block requests whose URL matches any of the given patterns (e.g. to skip analytics, ads, or fonts). Blocks persist across navigations until removed with browser_unblock_urls. Simpler than browser_request_intercept when you only need to block.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"patterns": map[string]interface{}{
+						"type":        "array",
+						"description": "URL patterns to block: substrings or globs with * (e.g. \"*doubleclick.net*\", \"*.woff2\")",
+						"items":       map[string]interface{}{"type": "string"},
+					},
+				},
+				"required":             []string{"patterns"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_unblock_urls",
+			Description: "Stop blocking URL patterns added with browser_block_urls",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"patterns": map[string]interface{}{
+						"type":        "array",
+						"description": "Patterns to unblock (omit to unblock all)",
+						"items":       map[string]interface{}{"type": "string"},
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_set_extra_headers",
+			Description: "Send extra HTTP headers (e.g. Authorization, feature flags) with requests to one origin, by default the current page's, so they don't leak to third-party hosts. Replaces headers set earlier; pass {} to clear. Persists across navigations.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"headers": map[string]interface{}{
+						"type":                 "object",
+						"description":          "Header name to value, e.g. {\"X-Feature-Flag\": \"beta\"}",
+						"additionalProperties": map[string]interface{}{"type": "string"},
+					},
+					"origin": map[string]interface{}{
+						"type":        "string",
+						"description": "Only send the headers to this origin (e.g. \"https://staging.example.com\") or URL glob (e.g. \"https://*.example.com/*\"); \"*\" sends them to every host. Default: the current page's origin",
+					},
+				},
+				"required":             []string{"headers"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_set_basic_auth",
+			Description: "Send HTTP Basic credentials (an Authorization: Basic header) with requests to one origin, by default the current page's, e.g. for staging sites behind auth. Pass an empty username to clear. Persists across navigations.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"username": map[string]interface{}{
+						"type":        "string",
+						"description": "Username (empty to clear)",
+					},
+					"password": map[string]interface{}{
+						"type":        "string",
+						"description": "Password",
+					},
+					"origin": map[string]interface{}{
+						"type":        "string",
+						"description": "Only send the credentials to this origin (e.g. \"https://staging.example.com\") or URL glob (e.g. \"https://*.example.com/*\"); \"*\" sends them to every host. Default: the current page's origin",
+					},
+				},
+				"required":             []string{"username"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_mock_record",
+			Description: "Start saving network responses to a directory (one file per method + URL) for deterministic replay with browser_mock_replay. Stop with browser_mock_stop.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"dir": map[string]interface{}{
+						"type":        "string",
+						"description": "Directory to save responses to",
+					},
+					"patterns": map[string]interface{}{
+						"type":        "array",
+						"description": "URL patterns to record: substrings or globs with * (default: all requests)",
+						"items":       map[string]interface{}{"type": "string"},
+					},
+				},
+				"required":             []string{"dir"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_mock_replay",
+			Description: "Serve responses saved by browser_mock_record instead of hitting the network. Requests without a saved response go to the network unless strict is set. Stop with browser_mock_stop.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"dir": map[string]interface{}{
+						"type":        "string",
+						"description": "Directory of saved responses",
+					},
+					"patterns": map[string]interface{}{
+						"type":        "array",
+						"description": "URL patterns to replay: substrings or globs with * (default: all requests)",
+						"items":       map[string]interface{}{"type": "string"},
+					},
+					"strict": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Fail matching requests that have no saved response instead of letting them through (default: false)",
+					},
+				},
+				"required":             []string{"dir"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_mock_stop",
+			Description: "Stop mock recording (saving any pending responses) or mock replay",
+			InputSchema: map[string]interface{}{
+				"type":                 "object",
+				"properties":           map[string]interface{}{},
 				"additionalProperties": false,
 			},
 		},
@@ -1233,10 +2377,43 @@ func GetToolSchemas() []Tool {
 				"additionalProperties": false,
 			},
 		},
+		{
+			Name:        "browser_switch_frame",
+			Description: "Enter an iframe: later commands run inside it until browser_switch_frame_parent, browser_switch_frame_top, navigation, or a page switch. Nested frames are entered one level at a time.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"frame": map[string]interface{}{
+						"type":        "string",
+						"description": "Frame name, URL substring, or @ref of the <iframe> element, looked up inside the current frame",
+					},
+				},
+				"required":             []string{"frame"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_switch_frame_parent",
+			Description: "Leave the frame entered with browser_switch_frame for its parent frame (or the page)",
+			InputSchema: map[string]interface{}{
+				"type":                 "object",
+				"properties":           map[string]interface{}{},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "browser_switch_frame_top",
+			Description: "Leave all frames entered with browser_switch_frame so commands run against the page",
+			InputSchema: map[string]interface{}{
+				"type":                 "object",
+				"properties":           map[string]interface{}{},
+				"additionalProperties": false,
+			},
+		},
 		// --- Upload ---
 		{
 			Name:        "browser_upload",
-			Description: "Set files on an input[type=file] element",
+			Description: "Set files on an input[type=file] element, from paths on this machine or from in-memory contents",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -1251,8 +2428,30 @@ func GetToolSchemas() []Tool {
 							"type": "string",
 						},
 					},
+					"contents": map[string]interface{}{
+						"type":        "array",
+						"description": "Files to upload from memory, for clients without access to this machine's disk. Written to a temp directory that is removed when the browser closes.",
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"name": map[string]interface{}{
+									"type":        "string",
+									"description": "File name the page sees (e.g. \"report.csv\")",
+								},
+								"content": map[string]interface{}{
+									"type":        "string",
+									"description": "Base64-encoded file content",
+								},
+							},
+							"required": []string{"name", "content"},
+						},
+					},
+					"directory": map[string]interface{}{
+						"type":        "string",
+						"description": "Absolute path of a directory to upload (every file in it, recursively, each with its webkitRelativePath). The input must have the webkitdirectory attribute.",
+					},
 				},
-				"required":             []string{"selector", "files"},
+				"required":             []string{"selector"},
 				"additionalProperties": false,
 			},
 		},
@@ -1302,6 +2501,14 @@ func GetToolSchemas() []Tool {
 						"description": "JPEG quality 0.0-1.0 (default: 0.5, ignored for png)",
 						"default":     0.5,
 					},
+					"intervalMs": map[string]interface{}{
+						"type":        "number",
+						"description": "Also capture a screenshot every N ms while idle between tool calls, minimum 100 (default: 0, after each action only)",
+					},
+					"fps": map[string]interface{}{
+						"type":        "number",
+						"description": "Periodic screenshots per second; alternative to intervalMs",
+					},
 				},
 				"additionalProperties": false,
 			},
@@ -1372,23 +2579,55 @@ func GetToolSchemas() []Tool {
 						"type":        "string",
 						"description": "Output file path (default: chunk.zip)",
 					},
+					"next": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Start the next chunk right away, so periodic checkpoints don't repeat earlier actions (default: false)",
+					},
 				},
 				"additionalProperties": false,
 			},
 		},
-		// --- Storage state ---
 		{
-			Name:        "browser_storage_state",
-			Description: "Export cookies, localStorage, and sessionStorage as JSON",
+			Name:        "browser_har_start",
+			Description: "Start capturing network requests for a HAR file (independent of recording)",
 			InputSchema: map[string]interface{}{
 				"type":                 "object",
 				"properties":           map[string]interface{}{},
 				"additionalProperties": false,
 			},
 		},
+		{
+			Name:        "browser_har_stop",
+			Description: "Stop capturing network requests and write them to a HAR 1.2 file",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "Output file path (default: network.har)",
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		// --- Storage state ---
+		{
+			Name:        "browser_storage_state",
+			Description: "Export cookies, plus localStorage, sessionStorage, and IndexedDB for every origin in the page and its frames, as JSON in Playwright's storageState shape ({cookies, origins: [{origin, localStorage: [{name, value}]}]})",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"indexedDB": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Include IndexedDB databases and their records (default: true)",
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
 		{
 			Name:        "browser_restore_storage",
-			Description: "Restore cookies and storage from a JSON state file",
+			Description: "Restore cookies and storage from a storageState JSON file (as written by browser_storage_state or Playwright). Storage is restored into the page or frame currently on each origin; IndexedDB databases in the file replace existing ones. Cookies the browser rejects are skipped and counted.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -1401,6 +2640,36 @@ func GetToolSchemas() []Tool {
 				"additionalProperties": false,
 			},
 		},
+		{
+			Name:        "browser_clear_storage",
+			Description: "Reset browser state between runs: delete cookies and clear localStorage and sessionStorage for every origin in the page and its frames. IndexedDB and Cache Storage can be cleared too. Returns what was wiped.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"cookies": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Delete all cookies (default: true)",
+					},
+					"localStorage": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Clear localStorage (default: true)",
+					},
+					"sessionStorage": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Clear sessionStorage (default: true)",
+					},
+					"indexedDB": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Delete all IndexedDB databases (default: false)",
+					},
+					"cacheStorage": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Delete all Cache Storage caches (default: false)",
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
 		// --- Downloads ---
 		{
 			Name:        "browser_download_set_dir",
@@ -1417,5 +2686,43 @@ func GetToolSchemas() []Tool {
 				"additionalProperties": false,
 			},
 		},
+		// --- Skills ---
+		{
+			Name:        "browser_invoke_skill_chain",
+			Description: "Run a skill from the skills directory (~/.vibium/skills/<name>.json, override with VIBIUM_SKILLS_DIR). Skills are ordered lists of tool calls and may reference other skills by name; the chain is resolved and checked for cycles before any step runs.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Skill name (file name without .json)",
+					},
+					"params": map[string]interface{}{
+						"type":        "object",
+						"description": "Values substituted for {{param}} placeholders in the skill's steps",
+					},
+				},
+				"required":             []string{"name"},
+				"additionalProperties": false,
+			},
+		},
+	}
+	addFrameProperty(tools)
+	return tools
+}
+
+// addFrameProperty gives every element-taking tool (one with a selector or
+// source argument) an optional frame argument; Handlers.Call runs the tool
+// inside that frame.
+func addFrameProperty(tools []Tool) {
+	for _, tool := range tools {
+		props, _ := tool.InputSchema["properties"].(map[string]interface{})
+		if props["selector"] == nil && props["source"] == nil {
+			continue
+		}
+		props["frame"] = map[string]interface{}{
+			"type":        "string",
+			"description": "Run inside this iframe: a frame name, URL substring, or @ref of the <iframe> element",
+		}
 	}
 }
@@ -104,8 +104,9 @@ type ToolsCallParams struct {
 }
 
 type ToolsCallResult struct {
-	Content []Content `json:"content"`
-	IsError bool      `json:"isError,omitempty"`
+	Content           []Content              `json:"content"`
+	StructuredContent map[string]interface{} `json:"structuredContent,omitempty"` // Machine-readable result when the text is lossy
+	IsError           bool                   `json:"isError,omitempty"`
 }
 
 type Content struct {
@@ -0,0 +1,157 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Skill is a named, reusable sequence of tool calls loaded from
+// <skills dir>/<name>.json. Steps either call a tool directly or reference
+// another skill by name, so higher-level flows can be composed from smaller ones.
+//
+//	{
+//	  "name": "checkout",
+//	  "steps": [
+//	    {"skill": "login", "params": {"user": "{{user}}"}},
+//	    {"tool": "browser_click", "args": {"selector": "#buy"}}
+//	  ]
+//	}
+type Skill struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Steps       []SkillStep `json:"steps"`
+}
+
+// SkillStep is a single entry in a skill. Exactly one of Tool or Skill is set.
+// String values in Args and Params may contain {{param}} placeholders.
+type SkillStep struct {
+	Tool   string                 `json:"tool,omitempty"`
+	Args   map[string]interface{} `json:"args,omitempty"`
+	Skill  string                 `json:"skill,omitempty"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// resolvedStep is a tool call produced by flattening a skill chain.
+type resolvedStep struct {
+	Skill string // skill the step came from
+	Tool  string
+	Args  map[string]interface{}
+}
+
+var skillNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// loadSkill reads and parses <dir>/<name>.json.
+func loadSkill(dir, name string) (*Skill, error) {
+	if !skillNamePattern.MatchString(name) || strings.Contains(name, "..") {
+		return nil, fmt.Errorf("invalid skill name %q", name)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, name+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("skill %q not found in %s", name, dir)
+		}
+		return nil, fmt.Errorf("failed to read skill %q: %w", name, err)
+	}
+
+	var skill Skill
+	if err := json.Unmarshal(data, &skill); err != nil {
+		return nil, fmt.Errorf("failed to parse skill %q: %w", name, err)
+	}
+	if skill.Name == "" {
+		skill.Name = name
+	}
+	return &skill, nil
+}
+
+// resolveSkillChain flattens the named skill into an ordered list of tool
+// calls, expanding skill references depth-first and substituting params.
+// Cyclic references are reported before anything is executed.
+func resolveSkillChain(dir, name string, params map[string]interface{}) ([]resolvedStep, error) {
+	return resolveSkill(dir, name, params, nil)
+}
+
+func resolveSkill(dir, name string, params map[string]interface{}, stack []string) ([]resolvedStep, error) {
+	for _, s := range stack {
+		if s == name {
+			return nil, fmt.Errorf("cyclic skill reference: %s", strings.Join(append(stack, name), " -> "))
+		}
+	}
+	stack = append(stack, name)
+
+	skill, err := loadSkill(dir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []resolvedStep
+	for i, step := range skill.Steps {
+		switch {
+		case step.Tool != "" && step.Skill != "":
+			return nil, fmt.Errorf("skill %q step %d: set either \"tool\" or \"skill\", not both", name, i+1)
+		case step.Skill != "":
+			childParams, _ := substituteParams(step.Params, params).(map[string]interface{})
+			child, err := resolveSkill(dir, step.Skill, childParams, stack)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, child...)
+		case step.Tool == "browser_invoke_skill_chain":
+			return nil, fmt.Errorf("skill %q step %d: reference other skills with \"skill\" instead of calling browser_invoke_skill_chain", name, i+1)
+		case step.Tool != "":
+			args, _ := substituteParams(step.Args, params).(map[string]interface{})
+			if args == nil {
+				args = map[string]interface{}{}
+			}
+			steps = append(steps, resolvedStep{Skill: name, Tool: step.Tool, Args: args})
+		default:
+			return nil, fmt.Errorf("skill %q step %d: missing \"tool\" or \"skill\"", name, i+1)
+		}
+	}
+	return steps, nil
+}
+
+var skillParamPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// substituteParams replaces {{param}} placeholders in string values. A string
+// consisting of a single placeholder is replaced by the raw param value so
+// numbers and booleans keep their type.
+func substituteParams(v interface{}, params map[string]interface{}) interface{} {
+	switch val := v.(type) {
+	case string:
+		if m := skillParamPattern.FindStringSubmatch(val); m != nil && m[0] == val {
+			if p, ok := params[m[1]]; ok {
+				return p
+			}
+			return val
+		}
+		return skillParamPattern.ReplaceAllStringFunc(val, func(match string) string {
+			key := skillParamPattern.FindStringSubmatch(match)[1]
+			if p, ok := params[key]; ok {
+				return fmt.Sprintf("%v", p)
+			}
+			return match
+		})
+	case map[string]interface{}:
+		if val == nil {
+			return val
+		}
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = substituteParams(item, params)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = substituteParams(item, params)
+		}
+		return out
+	default:
+		return v
+	}
+}
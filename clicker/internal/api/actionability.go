@@ -79,14 +79,15 @@ func buildCSSActionableScript(ep ElementParams, checkVisible, checkReceivesEvent
 
 	script := `
 		(scope, selector, index, hasIndex, chkVisible, chkEvents, chkEnabled, chkEditable) => {
-			const root = scope ? document.querySelector(scope) : document;
+			const root = resolveScope(scope);
 			if (!root) return JSON.stringify({status:'not_found'});
+		` + DeepQueryJS() + `
 			let el;
 			if (hasIndex) {
-				const all = root.querySelectorAll(selector);
+				const all = queryAllDeep(root, selector);
 				el = all[index];
 			} else {
-				el = root.querySelector(selector);
+				el = queryDeep(root, selector);
 			}
 			if (!el) return JSON.stringify({status:'not_found'});
 
@@ -130,9 +131,9 @@ func buildSemanticActionableScript(ep ElementParams, checkVisible, checkReceives
 
 	script := `
 		(scope, selector, role, text, label, placeholder, alt, title, testid, xpath, index, hasIndex, chkVisible, chkEvents, chkEnabled, chkEditable) => {
-			const root = scope ? document.querySelector(scope) : document;
+			const root = resolveScope(scope);
 			if (!root) return JSON.stringify({status:'not_found'});
-	` + semanticMatchesHelper() + `
+	` + semanticMatchesHelper(ep) + `
 			const found = collectMatches(root, selector, role, text, label, placeholder, alt, title, testid, xpath);
 			let el;
 			if (hasIndex) {
@@ -292,7 +293,9 @@ func WaitForActionable(s Session, context string, ep ElementParams, checks []Act
 }
 
 // resolveWithActionability resolves an element with actionability checks.
-// If Force is set or no checks are needed, falls back to plain ResolveElement.
+// If Force is set or no checks are needed, falls back to plain ResolveElement:
+// a forced action bypasses every safety check and acts on the element as soon
+// as it is found, even if it is hidden, moving, covered, or disabled.
 func resolveWithActionability(s Session, context string, ep ElementParams, checks []ActionCheck) (*ElementInfo, error) {
 	if ep.Force || len(checks) == 0 {
 		return ResolveElement(s, context, ep)
@@ -138,9 +138,30 @@ func (r *Router) handleVibiumPageA11yTree(session *BrowserSession, cmd bidiComma
 
 // A11yTree calls the a11y tree script in the browser and returns the JSON string result.
 func A11yTree(s Session, context string, interestingOnly bool, rootSelector string) (string, error) {
+	return a11yTree(s, context, interestingOnly, rootSelector, nil)
+}
+
+// A11yTreeAnnotated returns the accessibility tree with extra fields merged
+// into the node of every element matching one of annotations' selectors
+// (e.g. selector -> {"ref": "@e3"}). Annotated nodes are kept even when
+// interestingOnly would drop them.
+func A11yTreeAnnotated(s Session, context string, interestingOnly bool, annotations map[string]map[string]string) (string, error) {
+	return a11yTree(s, context, interestingOnly, "", annotations)
+}
+
+func a11yTree(s Session, context string, interestingOnly bool, rootSelector string, annotations map[string]map[string]string) (string, error) {
+	annotationsJSON := ""
+	if len(annotations) > 0 {
+		data, err := json.Marshal(annotations)
+		if err != nil {
+			return "", fmt.Errorf("failed to serialize annotations: %w", err)
+		}
+		annotationsJSON = string(data)
+	}
 	args := []map[string]interface{}{
 		{"type": "boolean", "value": interestingOnly},
 		{"type": "string", "value": rootSelector},
+		{"type": "string", "value": annotationsJSON},
 	}
 
 	resp, err := s.SendBidiCommand("script.callFunction", map[string]interface{}{
@@ -163,8 +184,18 @@ func A11yTree(s Session, context string, interestingOnly bool, rootSelector stri
 }
 
 // A11yTreeScript returns the JS function that builds the accessibility tree.
+// annotationsJSON optionally maps selectors to extra fields for matching nodes.
 func A11yTreeScript() string {
-	return `(interestingOnly, rootSelector) => {
+	return `(interestingOnly, rootSelector, annotationsJSON) => {
+		` + DeepQueryJS() + `
+		const extraOf = new Map();
+		for (const [sel, extra] of Object.entries(annotationsJSON ? JSON.parse(annotationsJSON) : {})) {
+			try {
+				const target = document.querySelector(sel);
+				if (target) extraOf.set(target, extra);
+			} catch (e) {}
+		}
+
 		const IMPLICIT_ROLES = {
 			A: (el) => el.hasAttribute('href') ? 'link' : '',
 			AREA: (el) => el.hasAttribute('href') ? 'link' : '',
@@ -262,6 +293,7 @@ func A11yTreeScript() string {
 		function buildNode(el) {
 			const role = getRole(el);
 			const name = getName(el);
+			const extra = extraOf.get(el);
 
 			// Collect children first
 			const childNodes = [];
@@ -278,7 +310,7 @@ func A11yTreeScript() string {
 			}
 
 			// If interestingOnly, skip uninteresting nodes (promote their children)
-			if (interestingOnly) {
+			if (interestingOnly && !extra) {
 				if (role === 'none' || role === 'presentation') {
 					return childNodes.length ? childNodes : null;
 				}
@@ -289,6 +321,7 @@ func A11yTreeScript() string {
 
 			const node = { role: role };
 			if (name) node.name = name;
+			if (extra) Object.assign(node, extra);
 
 			// Collect states
 			if (el.hasAttribute('disabled') || el.disabled) node.disabled = true;
@@ -344,7 +377,7 @@ func A11yTreeScript() string {
 			return node;
 		}
 
-		const rootEl = rootSelector ? document.querySelector(rootSelector) : document.body;
+		const rootEl = rootSelector ? queryDeep(document, rootSelector) : document.body;
 		if (!rootEl) return JSON.stringify({role: 'WebArea', name: document.title, children: []});
 
 		const children = [];
@@ -1,12 +1,22 @@
 package api
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // handlePageScreenshot handles vibium:page.screenshot — captures a page screenshot.
-// Options: fullPage (boolean), clip ({x, y, width, height}).
+// Options: fullPage (boolean), stitch (boolean), clip ({x, y, width, height}).
 func (r *Router) handlePageScreenshot(session *BrowserSession, cmd bidiCommand) {
 	context, err := r.resolveContext(session, cmd.Params)
 	if err != nil {
@@ -14,6 +24,16 @@ func (r *Router) handlePageScreenshot(session *BrowserSession, cmd bidiCommand)
 		return
 	}
 
+	if stitch, ok := cmd.Params["stitch"].(bool); ok && stitch {
+		data, err := StitchedScreenshot(NewAPISession(r, session, context), context)
+		if err != nil {
+			r.sendError(session, cmd.ID, err)
+			return
+		}
+		r.sendSuccess(session, cmd.ID, map[string]interface{}{"data": data})
+		return
+	}
+
 	ssParams := map[string]interface{}{
 		"context": context,
 	}
@@ -59,6 +79,7 @@ func (r *Router) handlePageScreenshot(session *BrowserSession, cmd bidiCommand)
 }
 
 // handlePagePDF handles vibium:page.pdf — prints the page to PDF.
+// Options: format, landscape, printBackground, scale, margin ({top, right, bottom, left}).
 // Returns base64-encoded PDF data.
 func (r *Router) handlePagePDF(session *BrowserSession, cmd bidiCommand) {
 	context, err := r.resolveContext(session, cmd.Params)
@@ -67,32 +88,19 @@ func (r *Router) handlePagePDF(session *BrowserSession, cmd bidiCommand) {
 		return
 	}
 
-	printParams := map[string]interface{}{
-		"context": context,
-	}
-
-	resp, err := r.sendInternalCommand(session, "browsingContext.print", printParams)
+	opts, err := ExtractPDFOptions(cmd.Params)
 	if err != nil {
 		r.sendError(session, cmd.ID, err)
 		return
 	}
 
-	if bidiErr := checkBidiError(resp); bidiErr != nil {
-		r.sendError(session, cmd.ID, bidiErr)
-		return
-	}
-
-	var printResult struct {
-		Result struct {
-			Data string `json:"data"`
-		} `json:"result"`
-	}
-	if err := json.Unmarshal(resp, &printResult); err != nil {
-		r.sendError(session, cmd.ID, fmt.Errorf("pdf parse failed: %w", err))
+	data, err := PrintToPDF(NewAPISession(r, session, context), context, opts)
+	if err != nil {
+		r.sendError(session, cmd.ID, err)
 		return
 	}
 
-	r.sendSuccess(session, cmd.ID, map[string]interface{}{"data": printResult.Result.Data})
+	r.sendSuccess(session, cmd.ID, map[string]interface{}{"data": data})
 }
 
 // ---------------------------------------------------------------------------
@@ -127,10 +135,130 @@ func Screenshot(s Session, context string, fullPage bool) (string, error) {
 	return ssResult.Result.Data, nil
 }
 
-// PrintToPDF prints the page to PDF and returns base64-encoded PDF data.
-func PrintToPDF(s Session, context string) (string, error) {
-	resp, err := s.SendBidiCommand("browsingContext.print", map[string]interface{}{
+// maxStitchCaptures caps the viewport captures in one stitched screenshot,
+// so pages that keep growing (infinite scroll) still finish.
+const maxStitchCaptures = 100
+
+// stitchSetupScript measures the page and the scroll position to restore.
+const stitchSetupScript = `() => ({
+	height: Math.max(document.documentElement.scrollHeight, document.body ? document.body.scrollHeight : 0),
+	viewportHeight: window.innerHeight,
+	scrollX: window.scrollX,
+	scrollY: window.scrollY,
+})`
+
+// stitchScrollScript scrolls to y and waits for a frame to paint. Once past
+// the first capture, fixed and sticky elements are hidden so headers don't
+// repeat in every slice. Returns the actual scrollY.
+const stitchScrollScript = `async (y, hideFixed) => {
+	if (hideFixed && !window.__vibiumStitchHidden) {
+		const hidden = [];
+		for (const el of document.querySelectorAll('*')) {
+			const position = getComputedStyle(el).position;
+			if (position === 'fixed' || position === 'sticky') {
+				hidden.push([el, el.style.getPropertyValue('visibility'), el.style.getPropertyPriority('visibility')]);
+				el.style.setProperty('visibility', 'hidden', 'important');
+			}
+		}
+		window.__vibiumStitchHidden = hidden;
+	}
+	window.scrollTo(0, y);
+	await new Promise(resolve => requestAnimationFrame(() => requestAnimationFrame(resolve)));
+	return window.scrollY;
+}`
+
+// stitchRestoreScript shows hidden elements again and restores the scroll position.
+const stitchRestoreScript = `(x, y) => {
+	for (const [el, value, priority] of window.__vibiumStitchHidden || []) {
+		if (value) el.style.setProperty('visibility', value, priority);
+		else el.style.removeProperty('visibility');
+	}
+	delete window.__vibiumStitchHidden;
+	window.scrollTo(x, y);
+	return true;
+}`
+
+// StitchedScreenshot captures the full page by scrolling one viewport at a
+// time and stitching the captures together. It is slower than a fullPage
+// Screenshot but isn't truncated on pages taller than the GPU's maximum
+// texture size. Returns base64-encoded PNG data.
+func StitchedScreenshot(s Session, context string) (string, error) {
+	raw, err := EvaluateFunction(s, context, stitchSetupScript, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to measure page: %w", err)
+	}
+	page, _ := raw.(map[string]interface{})
+	height, _ := page["height"].(float64)
+	viewportHeight, _ := page["viewportHeight"].(float64)
+	if height <= 0 || viewportHeight <= 0 {
+		return "", fmt.Errorf("failed to measure page")
+	}
+	defer EvaluateFunction(s, context, stitchRestoreScript, []interface{}{page["scrollX"], page["scrollY"]})
+
+	var canvas *image.RGBA
+	var scale float64
+	lastY := -1.0
+	for i := 0; i < maxStitchCaptures; i++ {
+		v, err := EvaluateFunction(s, context, stitchScrollScript, []interface{}{float64(i) * viewportHeight, i > 0})
+		if err != nil {
+			return "", fmt.Errorf("failed to scroll: %w", err)
+		}
+		y, _ := v.(float64)
+		if y <= lastY {
+			break // can't scroll any further
+		}
+		lastY = y
+
+		data, err := Screenshot(s, context, false)
+		if err != nil {
+			return "", err
+		}
+		pngData, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode screenshot: %w", err)
+		}
+		img, err := png.Decode(bytes.NewReader(pngData))
+		if err != nil {
+			return "", fmt.Errorf("failed to decode screenshot: %w", err)
+		}
+
+		b := img.Bounds()
+		if canvas == nil {
+			scale = float64(b.Dy()) / viewportHeight
+			canvas = image.NewRGBA(image.Rect(0, 0, b.Dx(), int(math.Ceil(height*scale))))
+		}
+		top := int(math.Round(y * scale))
+		draw.Draw(canvas, image.Rect(0, top, b.Dx(), top+b.Dy()), img, b.Min, draw.Src)
+
+		if y+viewportHeight >= height {
+			break
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas); err != nil {
+		return "", fmt.Errorf("failed to encode screenshot: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// ElementScreenshot resolves an element (scrolling it into view) and captures
+// a screenshot clipped to its bounding box. Returns base64-encoded PNG data.
+func ElementScreenshot(s Session, context string, ep ElementParams) (string, error) {
+	info, err := ResolveElement(s, context, ep)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.SendBidiCommand("browsingContext.captureScreenshot", map[string]interface{}{
 		"context": context,
+		"clip": map[string]interface{}{
+			"type":   "box",
+			"x":      info.Box.X,
+			"y":      info.Box.Y,
+			"width":  info.Box.Width,
+			"height": info.Box.Height,
+		},
 	})
 	if err != nil {
 		return "", err
@@ -139,6 +267,162 @@ func PrintToPDF(s Session, context string) (string, error) {
 		return "", bidiErr
 	}
 
+	var ssResult struct {
+		Result struct {
+			Data string `json:"data"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(resp, &ssResult); err != nil {
+		return "", fmt.Errorf("screenshot parse failed: %w", err)
+	}
+	return ssResult.Result.Data, nil
+}
+
+// PDFOptions controls page layout for PrintToPDF. Zero values use the
+// browser defaults (Letter, portrait, scale 1, no backgrounds).
+type PDFOptions struct {
+	Format          string // paper size name, e.g. "A4" or "Letter"
+	Landscape       bool
+	PrintBackground bool
+	Scale           float64 // 0.1-2
+	Margin          *PDFMargin
+}
+
+// PDFMargin holds page margins in centimeters.
+type PDFMargin struct {
+	Top, Right, Bottom, Left float64
+}
+
+// pdfPaperSizes maps paper format names to width/height in centimeters.
+var pdfPaperSizes = map[string][2]float64{
+	"letter":  {21.59, 27.94},
+	"legal":   {21.59, 35.56},
+	"tabloid": {27.94, 43.18},
+	"ledger":  {43.18, 27.94},
+	"a0":      {84.1, 118.9},
+	"a1":      {59.4, 84.1},
+	"a2":      {42.0, 59.4},
+	"a3":      {29.7, 42.0},
+	"a4":      {21.0, 29.7},
+	"a5":      {14.8, 21.0},
+	"a6":      {10.5, 14.8},
+}
+
+// ExtractPDFOptions reads and validates PDF options from command params.
+// Margins may be numbers (centimeters) or strings with a unit: "1in", "10mm", "2cm", "96px".
+func ExtractPDFOptions(params map[string]interface{}) (PDFOptions, error) {
+	var opts PDFOptions
+	if f, ok := params["format"].(string); ok && f != "" {
+		if _, known := pdfPaperSizes[strings.ToLower(f)]; !known {
+			return opts, fmt.Errorf("unknown paper format %q (use Letter, Legal, Tabloid, Ledger, or A0-A6)", f)
+		}
+		opts.Format = f
+	}
+	opts.Landscape, _ = params["landscape"].(bool)
+	opts.PrintBackground, _ = params["printBackground"].(bool)
+	if sc, ok := params["scale"].(float64); ok {
+		if sc < 0.1 || sc > 2 {
+			return opts, fmt.Errorf("scale must be between 0.1 and 2, got %g", sc)
+		}
+		opts.Scale = sc
+	}
+	if m, ok := params["margin"].(map[string]interface{}); ok {
+		margin := &PDFMargin{}
+		for _, side := range []struct {
+			name string
+			dst  *float64
+		}{
+			{"top", &margin.Top}, {"right", &margin.Right},
+			{"bottom", &margin.Bottom}, {"left", &margin.Left},
+		} {
+			v, ok := m[side.name]
+			if !ok {
+				continue
+			}
+			cm, err := parseLengthCM(v)
+			if err != nil {
+				return opts, fmt.Errorf("invalid margin.%s: %w", side.name, err)
+			}
+			*side.dst = cm
+		}
+		opts.Margin = margin
+	}
+	return opts, nil
+}
+
+// parseLengthCM converts a length string with an in/cm/mm/px unit (a bare
+// number is centimeters) to centimeters.
+func parseLengthCM(v interface{}) (float64, error) {
+	var cm float64
+	switch val := v.(type) {
+	case string:
+		str := strings.TrimSpace(strings.ToLower(val))
+		units := []struct {
+			suffix string
+			factor float64
+		}{
+			{"in", 2.54}, {"cm", 1}, {"mm", 0.1}, {"px", 2.54 / 96},
+		}
+		factor := 1.0
+		for _, u := range units {
+			if strings.HasSuffix(str, u.suffix) {
+				str = strings.TrimSuffix(str, u.suffix)
+				factor = u.factor
+				break
+			}
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+		if err != nil {
+			return 0, fmt.Errorf("cannot parse %q", val)
+		}
+		cm = n * factor
+	default:
+		return 0, fmt.Errorf("must be a string with a unit (e.g. \"1in\"), got %T", v)
+	}
+	if cm < 0 {
+		return 0, fmt.Errorf("must not be negative")
+	}
+	return cm, nil
+}
+
+// PrintToPDF prints the page to PDF and returns base64-encoded PDF data.
+func PrintToPDF(s Session, context string, opts PDFOptions) (string, error) {
+	printParams := map[string]interface{}{
+		"context": context,
+	}
+	if opts.Format != "" {
+		size := pdfPaperSizes[strings.ToLower(opts.Format)]
+		printParams["page"] = map[string]interface{}{
+			"width":  size[0],
+			"height": size[1],
+		}
+	}
+	if opts.Landscape {
+		printParams["orientation"] = "landscape"
+	}
+	if opts.PrintBackground {
+		printParams["background"] = true
+	}
+	if opts.Scale != 0 {
+		printParams["scale"] = opts.Scale
+	}
+	if opts.Margin != nil {
+		printParams["margin"] = map[string]interface{}{
+			"top":    opts.Margin.Top,
+			"right":  opts.Margin.Right,
+			"bottom": opts.Margin.Bottom,
+			"left":   opts.Margin.Left,
+		}
+	}
+
+	resp, err := s.SendBidiCommand("browsingContext.print", printParams)
+	if err != nil {
+		return "", err
+	}
+	if bidiErr := checkBidiError(resp); bidiErr != nil {
+		return "", bidiErr
+	}
+
 	var printResult struct {
 		Result struct {
 			Data string `json:"data"`
@@ -149,3 +433,155 @@ func PrintToPDF(s Session, context string) (string, error) {
 	}
 	return printResult.Result.Data, nil
 }
+
+// CompareImages decodes two PNG images and returns the fraction of pixels
+// (0.0-1.0) that differ between them. Images with different dimensions are
+// reported as entirely different.
+func CompareImages(a, b []byte) (float64, error) {
+	imgA, err := png.Decode(bytes.NewReader(a))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode image: %w", err)
+	}
+	imgB, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	boundsA, boundsB := imgA.Bounds(), imgB.Bounds()
+	if boundsA.Dx() != boundsB.Dx() || boundsA.Dy() != boundsB.Dy() {
+		return 1, nil
+	}
+	total := boundsA.Dx() * boundsA.Dy()
+	if total == 0 {
+		return 0, nil
+	}
+
+	diff := 0
+	for y := 0; y < boundsA.Dy(); y++ {
+		for x := 0; x < boundsA.Dx(); x++ {
+			r1, g1, b1, a1 := imgA.At(boundsA.Min.X+x, boundsA.Min.Y+y).RGBA()
+			r2, g2, b2, a2 := imgB.At(boundsB.Min.X+x, boundsB.Min.Y+y).RGBA()
+			if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+				diff++
+			}
+		}
+	}
+	return float64(diff) / float64(total), nil
+}
+
+// ImageDiff is the result of comparing a screenshot against a baseline.
+type ImageDiff struct {
+	Width        int // compared area: the larger of the two images
+	Height       int
+	DiffPixels   int         // pixels that differ by more than the threshold
+	Ratio        float64     // DiffPixels / (Width * Height)
+	SizeMismatch bool        // the images have different dimensions
+	Image        *image.RGBA // the baseline faded, with differing pixels in red
+}
+
+// DiffImages compares two PNG images pixel by pixel. A pixel differs when
+// any channel differs by more than threshold (0-1, a fraction of the full
+// channel range); 0 requires an exact match. When the sizes differ, pixels
+// outside either image count as different.
+func DiffImages(baseline, actual []byte, threshold float64) (*ImageDiff, error) {
+	imgA, err := png.Decode(bytes.NewReader(baseline))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode baseline: %w", err)
+	}
+	imgB, err := png.Decode(bytes.NewReader(actual))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+
+	boundsA, boundsB := imgA.Bounds(), imgB.Bounds()
+	d := &ImageDiff{
+		Width:        max(boundsA.Dx(), boundsB.Dx()),
+		Height:       max(boundsA.Dy(), boundsB.Dy()),
+		SizeMismatch: boundsA.Dx() != boundsB.Dx() || boundsA.Dy() != boundsB.Dy(),
+	}
+	d.Image = image.NewRGBA(image.Rect(0, 0, d.Width, d.Height))
+	limit := uint32(threshold * 0xffff)
+	red := color.RGBA{R: 255, A: 255}
+
+	for y := 0; y < d.Height; y++ {
+		for x := 0; x < d.Width; x++ {
+			inA := x < boundsA.Dx() && y < boundsA.Dy()
+			inB := x < boundsB.Dx() && y < boundsB.Dy()
+			if !inA || !inB {
+				d.DiffPixels++
+				d.Image.SetRGBA(x, y, red)
+				continue
+			}
+			cA := imgA.At(boundsA.Min.X+x, boundsA.Min.Y+y)
+			r1, g1, b1, a1 := cA.RGBA()
+			r2, g2, b2, a2 := imgB.At(boundsB.Min.X+x, boundsB.Min.Y+y).RGBA()
+			if absDiff(r1, r2) > limit || absDiff(g1, g2) > limit || absDiff(b1, b2) > limit || absDiff(a1, a2) > limit {
+				d.DiffPixels++
+				d.Image.SetRGBA(x, y, red)
+				continue
+			}
+			gray := color.GrayModel.Convert(cA).(color.Gray).Y
+			faded := 255 - (255-gray)/4
+			d.Image.SetRGBA(x, y, color.RGBA{R: faded, G: faded, B: faded, A: 255})
+		}
+	}
+	if total := d.Width * d.Height; total > 0 {
+		d.Ratio = float64(d.DiffPixels) / float64(total)
+	}
+	return d, nil
+}
+
+// EncodePNG encodes the diff image as PNG.
+func (d *ImageDiff) EncodePNG() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, d.Image); err != nil {
+		return nil, fmt.Errorf("failed to encode diff image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func absDiff(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// WaitForStableScreenshot captures the viewport repeatedly until two
+// consecutive captures differ by at most tolerance (fraction of pixels), or
+// the timeout elapses. Returns the last base64 PNG and the number of captures.
+func WaitForStableScreenshot(s Session, context string, interval, timeout time.Duration, tolerance float64) (string, int, error) {
+	deadline := time.Now().Add(timeout)
+	var prev []byte
+	captures := 0
+
+	for {
+		data, err := Screenshot(s, context, false)
+		if err != nil {
+			return "", captures, err
+		}
+		captures++
+
+		pngData, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return "", captures, fmt.Errorf("failed to decode screenshot: %w", err)
+		}
+
+		if prev != nil {
+			ratio, err := CompareImages(prev, pngData)
+			if err != nil {
+				return "", captures, err
+			}
+			if ratio <= tolerance {
+				return data, captures, nil
+			}
+		}
+		prev = pngData
+
+		if time.Now().After(deadline) {
+			return "", captures, fmt.Errorf("timeout after %s waiting for page to be visually stable", timeout)
+		}
+
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// solidPNG encodes a w×h PNG filled with c, with the pixels in changed
+// painted black.
+func solidPNG(t *testing.T, w, h int, c color.RGBA, changed ...image.Point) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	for _, p := range changed {
+		img.SetRGBA(p.X, p.Y, color.RGBA{A: 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDiffImages(t *testing.T) {
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	offWhite := color.RGBA{R: 250, G: 250, B: 250, A: 255}
+	base := solidPNG(t, 10, 10, white)
+
+	tests := []struct {
+		name         string
+		actual       []byte
+		threshold    float64
+		wantPixels   int
+		wantRatio    float64
+		wantMismatch bool
+	}{
+		{"identical", solidPNG(t, 10, 10, white), 0, 0, 0, false},
+		{"within threshold", solidPNG(t, 10, 10, offWhite), 0.1, 0, 0, false},
+		{"over threshold", solidPNG(t, 10, 10, offWhite), 0, 100, 1, false},
+		{"some pixels changed", solidPNG(t, 10, 10, white, image.Pt(0, 0), image.Pt(9, 9)), 0.1, 2, 0.02, false},
+		{"size mismatch", solidPNG(t, 10, 12, white), 0.1, 20, 20.0 / 120, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := DiffImages(base, tt.actual, tt.threshold)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.DiffPixels != tt.wantPixels {
+				t.Errorf("DiffPixels = %d, want %d", d.DiffPixels, tt.wantPixels)
+			}
+			if d.Ratio != tt.wantRatio {
+				t.Errorf("Ratio = %v, want %v", d.Ratio, tt.wantRatio)
+			}
+			if d.SizeMismatch != tt.wantMismatch {
+				t.Errorf("SizeMismatch = %v, want %v", d.SizeMismatch, tt.wantMismatch)
+			}
+			if _, err := d.EncodePNG(); err != nil {
+				t.Errorf("EncodePNG: %v", err)
+			}
+		})
+	}
+}
+
+func TestDiffImagesRejectsNonPNG(t *testing.T) {
+	if _, err := DiffImages([]byte("not a png"), []byte("not a png"), 0); err == nil {
+		t.Error("expected a decode error")
+	}
+}
+
+func TestExtractPDFOptionsMargin(t *testing.T) {
+	opts, err := ExtractPDFOptions(map[string]interface{}{
+		"margin": map[string]interface{}{"top": "1in", "right": "10mm", "bottom": "2", "left": "96px"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PDFMargin{Top: 2.54, Right: 1, Bottom: 2, Left: 2.54}
+	got := *opts.Margin
+	for _, side := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"top", got.Top, want.Top}, {"right", got.Right, want.Right},
+		{"bottom", got.Bottom, want.Bottom}, {"left", got.Left, want.Left},
+	} {
+		if math.Abs(side.got-side.want) > 1e-9 {
+			t.Errorf("margin.%s = %v cm, want %v", side.name, side.got, side.want)
+		}
+	}
+
+	for _, bad := range []interface{}{1.5, "-1cm", "wide"} {
+		if _, err := ExtractPDFOptions(map[string]interface{}{"margin": map[string]interface{}{"top": bad}}); err == nil {
+			t.Errorf("margin.top %v should be rejected", bad)
+		}
+	}
+}
+
+func TestStitchedScreenshot(t *testing.T) {
+	// A 250px page in a 100px viewport at 2x: captures at scrollY 0, 100
+	// and, clamped at the bottom, 150. Each capture is shaded by its scrollY.
+	const height, viewport, dpr = 250, 100, 2
+	scrollY := 0.0
+	var hideFixed []bool
+	restored := false
+	f := &fakeSession{respond: func(method string, params map[string]interface{}) string {
+		if method == "browsingContext.captureScreenshot" {
+			shade := uint8(scrollY)
+			data := solidPNG(t, 4, viewport*dpr, color.RGBA{R: shade, G: shade, B: shade, A: 255})
+			return `{"result":{"data":"` + base64.StdEncoding.EncodeToString(data) + `"}}`
+		}
+		args, _ := params["arguments"].([]interface{})
+		switch params["functionDeclaration"] {
+		case stitchSetupScript:
+			return fmt.Sprintf(`{"result":{"type":"success","result":{"type":"object","value":[
+				["height",{"type":"number","value":%d}],["viewportHeight",{"type":"number","value":%d}],
+				["scrollX",{"type":"number","value":0}],["scrollY",{"type":"number","value":0}]]}}}`, height, viewport)
+		case stitchScrollScript:
+			y := args[0].(map[string]interface{})["value"].(float64)
+			scrollY = math.Min(y, height-viewport)
+			hideFixed = append(hideFixed, args[1].(map[string]interface{})["value"].(bool))
+			return fmt.Sprintf(`{"result":{"type":"success","result":{"type":"number","value":%v}}}`, scrollY)
+		case stitchRestoreScript:
+			restored = true
+			return `{"result":{"type":"success","result":{"type":"boolean","value":true}}}`
+		}
+		t.Fatalf("unexpected %s %v", method, params)
+		return ""
+	}}
+
+	data, err := StitchedScreenshot(f, "ctx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 4 || b.Dy() != height*dpr {
+		t.Fatalf("size = %v, want 4x%d", b.Size(), height*dpr)
+	}
+	for _, tt := range []struct{ row, shade int }{{0, 0}, {199, 0}, {200, 100}, {299, 100}, {300, 150}, {499, 150}} {
+		if r, _, _, _ := img.At(0, tt.row).RGBA(); int(r>>8) != tt.shade {
+			t.Errorf("row %d shade = %d, want %d", tt.row, r>>8, tt.shade)
+		}
+	}
+	if want := []bool{false, true, true}; !reflect.DeepEqual(hideFixed, want) {
+		t.Errorf("hideFixed per capture = %v, want %v", hideFixed, want)
+	}
+	if !restored {
+		t.Error("scroll position and fixed elements were not restored")
+	}
+}
@@ -108,6 +108,11 @@ func BuildFindScript(params map[string]interface{}, findAll bool) (string, []map
 	title, _ := params["title"].(string)
 	testid, _ := params["testid"].(string)
 	xpath, _ := params["xpath"].(string)
+	if x, ok := ParseXPathSelector(selector); ok {
+		selector, xpath = "", x
+	} else if ts, ok := ParseTextSelector(selector); ok {
+		selector, text = ts.Base, ts.Text
+	}
 
 	args := []map[string]interface{}{
 		{"type": "string", "value": scope},
@@ -138,18 +143,20 @@ func BuildFindScript(params map[string]interface{}, findAll bool) (string, []map
 		map[string]interface{}{"type": "string", "value": xpath},
 	)
 
+	ep := ExtractElementParams(params)
 	if findAll {
-		return buildSemanticFindAllScript(), args
+		return buildSemanticFindAllScript(ep), args
 	}
-	return buildSemanticFindScript(), args
+	return buildSemanticFindScript(ep), args
 }
 
 func buildCSSFindScript() string {
 	return `
 		(scope, selector) => {
-			const root = scope ? document.querySelector(scope) : document;
+			const root = resolveScope(scope);
 			if (!root) return null;
-			const el = root.querySelector(selector);
+		` + DeepQueryJS() + `
+			const el = queryDeep(root, selector);
 			if (!el) return null;
 			if (el.scrollIntoViewIfNeeded) {
 				el.scrollIntoViewIfNeeded(true);
@@ -169,9 +176,10 @@ func buildCSSFindScript() string {
 func buildCSSFindAllScript() string {
 	return `
 		(scope, selector, hasText, has) => {
-			const root = scope ? document.querySelector(scope) : document;
+			const root = resolveScope(scope);
 			if (!root) return '[]';
-			let els = Array.from(root.querySelectorAll(selector));
+		` + DeepQueryJS() + `
+			let els = queryAllDeep(root, selector);
 			if (hasText) {
 				els = els.filter(el => (el.textContent || '').includes(hasText));
 			}
@@ -191,8 +199,17 @@ func buildCSSFindAllScript() string {
 	`
 }
 
-func semanticMatchesHelper() string {
-	return `
+// semanticMatchesHelper returns the shared JS for semantic element matching.
+// Text and label filters are case-sensitive substring matches by default;
+// ep.Exact requires equality after trimming (and makes pickBest keep the
+// first match instead of the shortest), and ep.CaseInsensitive ignores case.
+// It includes DeepQueryJS, so a selector narrowing the match may use any
+// selector form queryAllDeep understands.
+func semanticMatchesHelper(ep ElementParams) string {
+	return fmt.Sprintf(`
+			const textExact = %t;
+			const textCaseInsensitive = %t;
+`, ep.Exact, ep.CaseInsensitive) + DeepQueryJS() + `
 			const IMPLICIT_ROLES = {
 				A: (el) => el.hasAttribute('href') ? 'link' : '',
 				AREA: (el) => el.hasAttribute('href') ? 'link' : '',
@@ -243,15 +260,22 @@ func semanticMatchesHelper() string {
 				return fn ? fn(el).toLowerCase() : '';
 			}
 
+			function textMatches(actual, expected) {
+				let a = (actual || '').trim();
+				let e = textExact ? expected.trim() : expected;
+				if (textCaseInsensitive) {
+					a = a.toLowerCase();
+					e = e.toLowerCase();
+				}
+				return textExact ? a === e : a.includes(e);
+			}
+
 			function matches(el, selector, role, text, label, placeholder, alt, title, testid) {
 				if (selector && !el.matches(selector)) return false;
 				if (role) {
 					if (getImplicitRole(el) !== role.toLowerCase()) return false;
 				}
-				if (text) {
-					const elText = (el.textContent || '').trim();
-					if (!elText.includes(text)) return false;
-				}
+				if (text && !textMatches(el.textContent, text)) return false;
 				if (label) {
 					const ariaLabel = el.getAttribute('aria-label') || '';
 					const labelledBy = el.getAttribute('aria-labelledby');
@@ -264,7 +288,7 @@ func semanticMatchesHelper() string {
 						const assocLabel = document.querySelector('label[for="' + el.id + '"]');
 						if (assocLabel) labelText = labelText || (assocLabel.textContent || '').trim();
 					}
-					if (!labelText.includes(label)) return false;
+					if (!textMatches(labelText, label)) return false;
 				}
 				if (placeholder && el.getAttribute('placeholder') !== placeholder) return false;
 				if (alt && el.getAttribute('alt') !== alt) return false;
@@ -292,6 +316,14 @@ func semanticMatchesHelper() string {
 							found.push(el);
 						}
 					}
+				} else if (selector) {
+					// queryAllDeep understands >>>, pierce= and XPath, which
+					// el.matches() does not.
+					for (const el of queryAllDeep(root, selector)) {
+						if (matches(el, '', role, text, label, placeholder, alt, title, testid)) {
+							found.push(el);
+						}
+					}
 				} else {
 					const walker = document.createTreeWalker(root, NodeFilter.SHOW_ELEMENT);
 					let node;
@@ -306,7 +338,7 @@ func semanticMatchesHelper() string {
 
 			function pickBest(found, text) {
 				if (found.length === 0) return null;
-				if (!text || found.length === 1) return found[0];
+				if (!text || textExact || found.length === 1) return found[0];
 				let best = found[0];
 				let bestLen = (best.textContent || '').length;
 				for (let i = 1; i < found.length; i++) {
@@ -321,12 +353,12 @@ func semanticMatchesHelper() string {
 	`
 }
 
-func buildSemanticFindScript() string {
+func buildSemanticFindScript(ep ElementParams) string {
 	return `
 		(scope, selector, role, text, label, placeholder, alt, title, testid, xpath) => {
-			const root = scope ? document.querySelector(scope) : document;
+			const root = resolveScope(scope);
 			if (!root) return null;
-` + semanticMatchesHelper() + `
+` + semanticMatchesHelper(ep) + `
 			const found = collectMatches(root, selector, role, text, label, placeholder, alt, title, testid, xpath);
 			const best = pickBest(found, text);
 			if (!best) return null;
@@ -340,12 +372,12 @@ func buildSemanticFindScript() string {
 	`
 }
 
-func buildSemanticFindAllScript() string {
+func buildSemanticFindAllScript(ep ElementParams) string {
 	return `
 		(scope, selector, role, text, label, placeholder, alt, title, testid, xpath, hasText, has) => {
-			const root = scope ? document.querySelector(scope) : document;
+			const root = resolveScope(scope);
 			if (!root) return '[]';
-` + semanticMatchesHelper() + `
+` + semanticMatchesHelper(ep) + `
 			let found = collectMatches(root, selector, role, text, label, placeholder, alt, title, testid, xpath);
 			if (hasText) {
 				found = found.filter(el => (el.textContent || '').includes(hasText));
@@ -271,6 +271,8 @@ func (r *Router) handlePageWindow(session *BrowserSession, cmd bidiCommand) {
 }
 
 // handlePageSetGeolocation handles vibium:page.setGeolocation — overrides geolocation.
+// Params: latitude, longitude, accuracy, altitude, heading, speed. Pass null
+// latitude and longitude to clear the override.
 func (r *Router) handlePageSetGeolocation(session *BrowserSession, cmd bidiCommand) {
 	context, err := r.resolveContext(session, cmd.Params)
 	if err != nil {
@@ -278,24 +280,47 @@ func (r *Router) handlePageSetGeolocation(session *BrowserSession, cmd bidiComma
 		return
 	}
 
-	lat, hasLat := cmd.Params["latitude"].(float64)
-	lng, hasLng := cmd.Params["longitude"].(float64)
-	if !hasLat || !hasLng {
-		r.sendError(session, cmd.ID, fmt.Errorf("latitude and longitude are required"))
+	coords, err := ExtractGeolocation(cmd.Params)
+	if err != nil {
+		r.sendError(session, cmd.ID, err)
 		return
 	}
 
-	accuracy := float64(1)
-	if acc, ok := cmd.Params["accuracy"].(float64); ok {
-		accuracy = acc
+	s := NewAPISession(r, session, context)
+	if err := SetGeolocation(s, context, coords); err != nil {
+		r.sendError(session, cmd.ID, err)
+		return
+	}
+	r.sendSuccess(session, cmd.ID, map[string]interface{}{})
+}
+
+// handleBrowserSetUserAgent handles vibium:browser.setUserAgent — overrides the
+// User-Agent (and optionally Accept-Language and navigator.platform).
+// Pass an empty userAgent to reset.
+func (r *Router) handleBrowserSetUserAgent(session *BrowserSession, cmd bidiCommand) {
+	context, err := r.resolveContext(session, cmd.Params)
+	if err != nil {
+		r.sendError(session, cmd.ID, err)
+		return
+	}
+
+	opts := UserAgentOptions{}
+	opts.UserAgent, _ = cmd.Params["userAgent"].(string)
+	opts.AcceptLanguage, _ = cmd.Params["acceptLanguage"].(string)
+	opts.Platform, _ = cmd.Params["platform"].(string)
+
+	var port int
+	var sessionID string
+	if session.LaunchResult != nil {
+		port, sessionID = session.LaunchResult.Port, session.LaunchResult.SessionID
 	}
 
 	s := NewAPISession(r, session, context)
-	if err := SetGeolocation(s, context, lat, lng, accuracy); err != nil {
+	if err := SetUserAgent(s, context, port, sessionID, opts); err != nil {
 		r.sendError(session, cmd.ID, err)
 		return
 	}
-	r.sendSuccess(session, cmd.ID, map[string]interface{}{})
+	r.sendSuccess(session, cmd.ID, map[string]interface{}{"userAgent": opts.UserAgent})
 }
 
 // ---------------------------------------------------------------------------
@@ -406,38 +431,426 @@ func SetWindow(port int, sessionID string, opts SetWindowOpts) error {
 	return ChromedriverPost(baseURL+"/rect", rect)
 }
 
-// geolocationScript is the JS that overrides navigator.geolocation.
-const geolocationScript = "(coordsJSON) => {\n" +
-	"const coords = JSON.parse(coordsJSON);\n" +
-	"const geo = navigator.geolocation;\n" +
-	"geo.getCurrentPosition = function(success, error, options) {\n" +
-	"  success({ coords: { latitude: coords.latitude, longitude: coords.longitude, accuracy: coords.accuracy,\n" +
-	"    altitude: null, altitudeAccuracy: null, heading: null, speed: null }, timestamp: Date.now() });\n" +
-	"};\n" +
-	"geo.watchPosition = function(success, error, options) {\n" +
-	"  success({ coords: { latitude: coords.latitude, longitude: coords.longitude, accuracy: coords.accuracy,\n" +
-	"    altitude: null, altitudeAccuracy: null, heading: null, speed: null }, timestamp: Date.now() });\n" +
-	"  return 0;\n" +
-	"};\n" +
-	"return 'ok';\n" +
-	"}"
+// UserAgentOptions holds User-Agent override settings.
+type UserAgentOptions struct {
+	UserAgent      string
+	AcceptLanguage string // optional Accept-Language header / navigator.language
+	Platform       string // optional navigator.platform
+}
+
+// SetUserAgent overrides the User-Agent for a browsing context using BiDi
+// emulation.setUserAgentOverride. BiDi has no Accept-Language or platform
+// override, so when either is set (or the browser lacks BiDi support) it
+// falls back to CDP Network.setUserAgentOverride through chromedriver, which
+// needs a local browser (port > 0). An empty UserAgent resets the override.
+func SetUserAgent(s Session, context string, port int, sessionID string, opts UserAgentOptions) error {
+	if opts.AcceptLanguage == "" && opts.Platform == "" {
+		var ua interface{} = opts.UserAgent
+		if opts.UserAgent == "" {
+			ua = nil
+		}
+		resp, err := s.SendBidiCommand("emulation.setUserAgentOverride", map[string]interface{}{
+			"userAgent": ua,
+			"contexts":  []interface{}{context},
+		})
+		if err == nil {
+			err = checkBidiError(resp)
+		}
+		if err == nil || port == 0 {
+			return err
+		}
+	}
+
+	if port == 0 {
+		return fmt.Errorf("acceptLanguage and platform overrides are not supported for remote browsers")
+	}
+	if opts.UserAgent == "" {
+		return fmt.Errorf("userAgent is required when setting acceptLanguage or platform")
+	}
+	params := map[string]interface{}{"userAgent": opts.UserAgent}
+	if opts.AcceptLanguage != "" {
+		params["acceptLanguage"] = opts.AcceptLanguage
+	}
+	if opts.Platform != "" {
+		params["platform"] = opts.Platform
+	}
+	return chromedriverCDP(port, sessionID, "Network.setUserAgentOverride", params)
+}
 
-// SetGeolocation overrides the browser geolocation via a JS override.
-func SetGeolocation(s Session, context string, lat, lon, accuracy float64) error {
-	coordsJSON, _ := json.Marshal(map[string]float64{
-		"latitude":  lat,
-		"longitude": lon,
-		"accuracy":  accuracy,
+// chromedriverCDP runs a CDP command on chromedriver's current window via the
+// goog/cdp/execute endpoint. It needs a local chromedriver.
+func chromedriverCDP(port int, sessionID, method string, params map[string]interface{}) error {
+	if port == 0 {
+		return fmt.Errorf("%s is not supported for remote browsers", method)
+	}
+	url := fmt.Sprintf("http://localhost:%d/session/%s/goog/cdp/execute", port, sessionID)
+	return ChromedriverPost(url, map[string]interface{}{
+		"cmd":    method,
+		"params": params,
 	})
+}
 
-	resp, err := s.SendBidiCommand("script.callFunction", map[string]interface{}{
-		"functionDeclaration": geolocationScript,
-		"target":              map[string]interface{}{"context": context},
-		"arguments": []map[string]interface{}{
-			{"type": "string", "value": string(coordsJSON)},
-		},
-		"awaitPromise":    false,
-		"resultOwnership": "root",
+// NetworkConditions are the values for CDP Network.emulateNetworkConditions.
+// Throughputs are in bytes per second; -1 means unthrottled.
+type NetworkConditions struct {
+	Preset             string  `json:"preset,omitempty"`
+	Offline            bool    `json:"offline"`
+	Latency            float64 `json:"latency"`
+	DownloadThroughput float64 `json:"downloadThroughput"`
+	UploadThroughput   float64 `json:"uploadThroughput"`
+}
+
+// NetworkPresets are the named profiles accepted by ExtractNetworkConditions,
+// matching Chrome DevTools' throttling presets.
+var NetworkPresets = map[string]NetworkConditions{
+	"slow-3g": {Latency: 2000, DownloadThroughput: 50000, UploadThroughput: 50000},
+	"fast-3g": {Latency: 562.5, DownloadThroughput: 180000, UploadThroughput: 84375},
+	"offline": {Offline: true, DownloadThroughput: -1, UploadThroughput: -1},
+	"online":  {DownloadThroughput: -1, UploadThroughput: -1},
+}
+
+// ExtractNetworkConditions reads a preset and/or custom latency,
+// downloadThroughput and uploadThroughput from params. Custom values override
+// the preset's; values left unset are unthrottled.
+func ExtractNetworkConditions(params map[string]interface{}) (NetworkConditions, error) {
+	c := NetworkPresets["online"]
+	preset, _ := params["preset"].(string)
+	if preset != "" {
+		p, ok := NetworkPresets[preset]
+		if !ok {
+			return c, fmt.Errorf("unknown preset %q (use slow-3g, fast-3g, offline, or online)", preset)
+		}
+		c = p
+		c.Preset = preset
+	}
+
+	custom := false
+	if v, ok := params["latency"].(float64); ok {
+		if v < 0 {
+			return c, fmt.Errorf("latency must be >= 0, got %g", v)
+		}
+		c.Latency = v
+		custom = true
+	}
+	for key, dst := range map[string]*float64{
+		"downloadThroughput": &c.DownloadThroughput,
+		"uploadThroughput":   &c.UploadThroughput,
+	} {
+		if v, ok := params[key].(float64); ok {
+			if v <= 0 && v != -1 {
+				return c, fmt.Errorf("%s must be > 0 bytes/s, or -1 for unthrottled; got %g", key, v)
+			}
+			*dst = v
+			custom = true
+		}
+	}
+	if preset == "" && !custom {
+		return c, fmt.Errorf("preset or latency/downloadThroughput/uploadThroughput is required")
+	}
+	if preset != "" && custom {
+		c.Preset = preset + " (customized)"
+	}
+	return c, nil
+}
+
+// EmulateNetworkConditions throttles (or cuts) the network of chromedriver's
+// current window through CDP Network.emulateNetworkConditions.
+func EmulateNetworkConditions(port int, sessionID string, c NetworkConditions) error {
+	return chromedriverCDP(port, sessionID, "Network.emulateNetworkConditions", map[string]interface{}{
+		"offline":            c.Offline,
+		"latency":            c.Latency,
+		"downloadThroughput": c.DownloadThroughput,
+		"uploadThroughput":   c.UploadThroughput,
+	})
+}
+
+// EmulateCPUThrottling slows the CPU of chromedriver's current window by rate
+// (e.g. 4 for a 4x slowdown; 1 removes throttling) through CDP
+// Emulation.setCPUThrottlingRate.
+func EmulateCPUThrottling(port int, sessionID string, rate float64) error {
+	if rate < 1 {
+		return fmt.Errorf("rate must be >= 1, got %g", rate)
+	}
+	return chromedriverCDP(port, sessionID, "Emulation.setCPUThrottlingRate", map[string]interface{}{
+		"rate": rate,
+	})
+}
+
+// GeolocationCoords holds the position reported by the Geolocation API.
+// Altitude, Heading and Speed are optional and reported as null when unset.
+type GeolocationCoords struct {
+	Latitude  float64
+	Longitude float64
+	Accuracy  float64
+	Altitude  *float64
+	Heading   *float64
+	Speed     *float64
+}
+
+// ExtractGeolocation reads and validates geolocation params. It returns nil
+// coordinates (meaning "clear the override") when clear is true or when
+// latitude and longitude are both passed as null.
+func ExtractGeolocation(params map[string]interface{}) (*GeolocationCoords, error) {
+	if clear, _ := params["clear"].(bool); clear {
+		return nil, nil
+	}
+	rawLat, hasLat := params["latitude"]
+	rawLng, hasLng := params["longitude"]
+	if hasLat && hasLng && rawLat == nil && rawLng == nil {
+		return nil, nil
+	}
+
+	lat, okLat := rawLat.(float64)
+	lng, okLng := rawLng.(float64)
+	if !okLat || !okLng {
+		return nil, fmt.Errorf("latitude and longitude are required (or clear the override)")
+	}
+	if lat < -90 || lat > 90 {
+		return nil, fmt.Errorf("latitude must be between -90 and 90, got %g", lat)
+	}
+	if lng < -180 || lng > 180 {
+		return nil, fmt.Errorf("longitude must be between -180 and 180, got %g", lng)
+	}
+
+	coords := &GeolocationCoords{Latitude: lat, Longitude: lng, Accuracy: 1}
+	if acc, ok := params["accuracy"].(float64); ok {
+		if acc < 0 {
+			return nil, fmt.Errorf("accuracy must not be negative")
+		}
+		coords.Accuracy = acc
+	}
+	if alt, ok := params["altitude"].(float64); ok {
+		coords.Altitude = &alt
+	}
+	if hdg, ok := params["heading"].(float64); ok {
+		if hdg < 0 || hdg >= 360 {
+			return nil, fmt.Errorf("heading must be between 0 and 360, got %g", hdg)
+		}
+		coords.Heading = &hdg
+	}
+	if spd, ok := params["speed"].(float64); ok {
+		if spd < 0 {
+			return nil, fmt.Errorf("speed must not be negative")
+		}
+		coords.Speed = &spd
+	}
+	return coords, nil
+}
+
+// SetGeolocation overrides the browser geolocation using BiDi
+// emulation.setGeolocationOverride, so the native navigator.geolocation API
+// (including watchPosition) reports the given position and the override
+// survives navigation. It also grants the geolocation permission for the
+// current origin so pages don't get a permission prompt or denial.
+// Pass nil coords to clear the override.
+func SetGeolocation(s Session, context string, coords *GeolocationCoords) error {
+	var bidiCoords interface{}
+	if coords != nil {
+		c := map[string]interface{}{
+			"latitude":  coords.Latitude,
+			"longitude": coords.Longitude,
+			"accuracy":  coords.Accuracy,
+		}
+		if coords.Altitude != nil {
+			c["altitude"] = *coords.Altitude
+		}
+		if coords.Heading != nil {
+			c["heading"] = *coords.Heading
+		}
+		if coords.Speed != nil {
+			c["speed"] = *coords.Speed
+		}
+		bidiCoords = c
+	}
+
+	resp, err := s.SendBidiCommand("emulation.setGeolocationOverride", map[string]interface{}{
+		"coordinates": bidiCoords,
+		"contexts":    []interface{}{context},
+	})
+	if err != nil {
+		return err
+	}
+	if err := checkBidiError(resp); err != nil {
+		return err
+	}
+
+	if coords != nil {
+		grantGeolocationPermission(s, context)
+	}
+	return nil
+}
+
+// grantGeolocationPermission grants the geolocation permission for the
+// context's current origin. Best-effort: opaque origins (about:blank, data:)
+// and browsers without permissions.setPermission are ignored.
+func grantGeolocationPermission(s Session, context string) {
+	origin, err := EvalSimpleScript(s, context, "() => window.location.origin")
+	if err != nil || origin == "" || origin == "null" {
+		return
+	}
+	s.SendBidiCommand("permissions.setPermission", map[string]interface{}{
+		"descriptor": map[string]interface{}{"name": "geolocation"},
+		"state":      "granted",
+		"origin":     origin,
+	})
+}
+
+// printStylesScript swaps "print" and "screen" in every stylesheet's media
+// list (link/style media attributes and @media rules) so print styles render
+// on screen. Calling it with enable=false restores the original media text.
+const printStylesScript = `(enable) => {
+	if (!enable) {
+		for (const [list, text] of (window.__vibiumPrintStyles || [])) {
+			try { list.mediaText = text; } catch (e) {}
+		}
+		delete window.__vibiumPrintStyles;
+		return 'ok';
+	}
+	if (window.__vibiumPrintStyles) return 'ok';
+	const saved = [];
+	const swap = (list) => {
+		const text = list.mediaText;
+		if (!text || !/\b(print|screen)\b/i.test(text)) return;
+		saved.push([list, text]);
+		list.mediaText = text.replace(/\b(print|screen)\b/gi, (m) => m.toLowerCase() === 'print' ? 'screen' : 'print');
+	};
+	const walk = (rules) => {
+		for (const rule of rules) {
+			if (rule.media) swap(rule.media);
+			if (rule.cssRules) walk(rule.cssRules);
+		}
+	};
+	for (const sheet of document.styleSheets) {
+		if (sheet.media) swap(sheet.media);
+		let rules;
+		try { rules = sheet.cssRules; } catch (e) { continue; }
+		walk(rules);
+	}
+	window.__vibiumPrintStyles = saved;
+	return 'ok';
+}`
+
+// setPrintStyles enables or disables on-screen rendering of print styles.
+func setPrintStyles(s Session, context string, enable bool) error {
+	resp, err := CallScript(s, context, printStylesScript, []map[string]interface{}{
+		{"type": "boolean", "value": enable},
+	})
+	if err != nil {
+		return err
+	}
+	return checkBidiError(resp)
+}
+
+// PrintPreviewScreenshot switches the page to print media, captures a
+// full-page screenshot, and restores the previous media emulation (if any)
+// before returning, even if the capture fails. Returns base64-encoded PNG data.
+func PrintPreviewScreenshot(s Session, context string) (data string, err error) {
+	prevMedia, err := EvaluateFunction(s, context, `() => (window.__vibiumMediaOverrides || {}).media ?? null`, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to read media emulation: %w", err)
+	}
+	if err := EmulateMedia(s, context, map[string]interface{}{"media": "print"}); err != nil {
+		return "", fmt.Errorf("failed to emulate print media: %w", err)
+	}
+	defer func() {
+		restoreErr := EmulateMedia(s, context, map[string]interface{}{"media": prevMedia})
+		if err == nil && restoreErr != nil {
+			err = fmt.Errorf("failed to restore media emulation: %w", restoreErr)
+		}
+	}()
+
+	if err := setPrintStyles(s, context, true); err != nil {
+		return "", fmt.Errorf("failed to apply print styles: %w", err)
+	}
+	defer func() {
+		restoreErr := setPrintStyles(s, context, false)
+		if err == nil && restoreErr != nil {
+			err = fmt.Errorf("failed to restore screen styles: %w", restoreErr)
+		}
+	}()
+
+	return Screenshot(s, context, true)
+}
+
+// SetOfflineEmulation takes every page in the default user context offline,
+// or back online, with emulation.setNetworkConditions. The browser then
+// fails requests, reports navigator.onLine and fires offline/online events
+// itself. Browsers without the command return an error.
+func SetOfflineEmulation(s Session, offline bool) error {
+	var conditions interface{}
+	if offline {
+		conditions = map[string]interface{}{"type": "offline"}
+	}
+	resp, err := s.SendBidiCommand("emulation.setNetworkConditions", map[string]interface{}{
+		"networkConditions": conditions,
+		"userContexts":      []interface{}{"default"},
+	})
+	if err != nil {
+		return err
+	}
+	return checkBidiError(resp)
+}
+
+// onLineShimScript overrides navigator.onLine and dispatches offline/online
+// on window when the state changes. It's the fallback for browsers without
+// network condition emulation, where requests are failed by an intercept.
+const onLineShimScript = `(offline) => {
+	const proto = Object.getPrototypeOf(navigator);
+	if (!window.__vibiumOnLine) {
+		window.__vibiumOnLine = Object.getOwnPropertyDescriptor(proto, 'onLine');
+	}
+	const was = navigator.onLine;
+	if (offline) {
+		Object.defineProperty(proto, 'onLine', { get: () => false, configurable: true, enumerable: true });
+	} else {
+		Object.defineProperty(proto, 'onLine', window.__vibiumOnLine);
+	}
+	if (was !== navigator.onLine) {
+		window.dispatchEvent(new Event(offline ? 'offline' : 'online'));
+	}
+	return 'ok';
+}`
+
+// SetOnLineShim updates navigator.onLine in the context's current document,
+// firing offline/online if it changed.
+func SetOnLineShim(s Session, context string, offline bool) error {
+	resp, err := CallScript(s, context, onLineShimScript, []map[string]interface{}{
+		{"type": "boolean", "value": offline},
+	})
+	if err != nil {
+		return err
+	}
+	return checkBidiError(resp)
+}
+
+// AddOfflinePreloadScript makes navigator.onLine report false in every new
+// document, so the fallback offline state survives navigations. It returns
+// the preload script ID for RemovePreloadScript.
+func AddOfflinePreloadScript(s Session) (string, error) {
+	resp, err := s.SendBidiCommand("script.addPreloadScript", map[string]interface{}{
+		"functionDeclaration": "() => (" + onLineShimScript + ")(true)",
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := checkBidiError(resp); err != nil {
+		return "", err
+	}
+	var result struct {
+		Result struct {
+			Script string `json:"script"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return "", fmt.Errorf("failed to parse addPreloadScript response: %w", err)
+	}
+	return result.Result.Script, nil
+}
+
+// RemovePreloadScript removes a preload script added with script.addPreloadScript.
+func RemovePreloadScript(s Session, script string) error {
+	resp, err := s.SendBidiCommand("script.removePreloadScript", map[string]interface{}{
+		"script": script,
 	})
 	if err != nil {
 		return err
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrintPreviewScreenshotRestoresPreviousMedia(t *testing.T) {
+	var overrides []string
+	s := &fakeSession{respond: func(method string, params map[string]interface{}) string {
+		switch {
+		case method == "browsingContext.captureScreenshot":
+			return `{"result":{"data":"png"}}`
+		case params["functionDeclaration"] == emulateMediaScript:
+			args := params["arguments"].([]map[string]interface{})
+			overrides = append(overrides, args[0]["value"].(string))
+		case strings.Contains(params["functionDeclaration"].(string), "__vibiumMediaOverrides"):
+			// The page was already emulating screen media.
+			return `{"result":{"type":"success","result":{"type":"string","value":"screen"}}}`
+		}
+		return `{"result":{"type":"success","result":{"type":"string","value":"ok"}}}`
+	}}
+
+	data, err := PrintPreviewScreenshot(s, "ctx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data != "png" {
+		t.Errorf("data = %q, want png", data)
+	}
+	want := []string{`{"media":"print"}`, `{"media":"screen"}`}
+	if strings.Join(overrides, " ") != strings.Join(want, " ") {
+		t.Errorf("media overrides = %v, want %v", overrides, want)
+	}
+}
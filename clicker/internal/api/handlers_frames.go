@@ -154,6 +154,42 @@ func FindFrame(s Session, context, nameOrURL string) (*FrameInfo, error) {
 	return nil, nil // no match
 }
 
+// FrameContextForElement returns the browsing context of the <iframe> or
+// <frame> element matched by ep, so commands can target the document inside.
+func FrameContextForElement(s Session, context string, ep ElementParams) (string, error) {
+	sharedID, err := ResolveElementRef(s, context, ep)
+	if err != nil {
+		return "", err
+	}
+	resp, err := CallScript(s, context, "(el) => el.contentWindow", []map[string]interface{}{
+		{"sharedId": sharedID},
+	})
+	if err != nil {
+		return "", err
+	}
+	if bidiErr := checkBidiError(resp); bidiErr != nil {
+		return "", bidiErr
+	}
+
+	var result struct {
+		Result struct {
+			Result struct {
+				Type  string `json:"type"`
+				Value struct {
+					Context string `json:"context"`
+				} `json:"value"`
+			} `json:"result"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return "", fmt.Errorf("failed to parse frame window: %w", err)
+	}
+	if result.Result.Result.Type != "window" || result.Result.Result.Value.Context == "" {
+		return "", fmt.Errorf("element is not a frame")
+	}
+	return result.Result.Result.Value.Context, nil
+}
+
 // handlePageFrames handles vibium:page.frames — returns all child frames of a page.
 func (r *Router) handlePageFrames(session *BrowserSession, cmd bidiCommand) {
 	context, err := r.resolveContext(session, cmd.Params)
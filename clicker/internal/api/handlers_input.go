@@ -27,6 +27,35 @@ func (r *Router) handleKeyboardPress(session *BrowserSession, cmd bidiCommand) {
 	r.sendSuccess(session, cmd.ID, map[string]interface{}{"pressed": true})
 }
 
+// handleKeyboardPressSequence handles vibium:keyboard.pressSequence — presses
+// keys or combos in order within one input.performActions.
+func (r *Router) handleKeyboardPressSequence(session *BrowserSession, cmd bidiCommand) {
+	raw, _ := cmd.Params["keys"].([]interface{})
+	keys := make([]string, 0, len(raw))
+	for _, k := range raw {
+		if key, ok := k.(string); ok && key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		r.sendError(session, cmd.ID, fmt.Errorf("keys must be a non-empty array of strings"))
+		return
+	}
+
+	context, err := r.resolveContext(session, cmd.Params)
+	if err != nil {
+		r.sendError(session, cmd.ID, err)
+		return
+	}
+
+	if err := PressKeys(NewAPISession(r, session, context), context, keys); err != nil {
+		r.sendError(session, cmd.ID, err)
+		return
+	}
+
+	r.sendSuccess(session, cmd.ID, map[string]interface{}{"pressed": true})
+}
+
 // handleKeyboardDown handles vibium:keyboard.down — presses a key down (no release).
 func (r *Router) handleKeyboardDown(session *BrowserSession, cmd bidiCommand) {
 	key, _ := cmd.Params["key"].(string)
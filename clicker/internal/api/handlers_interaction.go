@@ -3,7 +3,9 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"github.com/vibium/clicker/internal/bidi"
 )
@@ -127,7 +129,11 @@ func (r *Router) handleVibiumType(session *BrowserSession, cmd bidiCommand) {
 		r.sendError(session, cmd.ID, err)
 		return
 	}
-	if err := TypeText(s, context, text); err != nil {
+	typeText := TypeText
+	if raw, _ := cmd.Params["raw"].(bool); raw {
+		typeText = InsertText
+	}
+	if err := typeText(s, context, text); err != nil {
 		r.sendError(session, cmd.ID, err)
 		return
 	}
@@ -269,10 +275,15 @@ func (r *Router) handleVibiumUncheck(session *BrowserSession, cmd bidiCommand) {
 }
 
 // handleVibiumSelectOption handles the vibium:element.selectOption command.
-// Sets the value of a <select> element and dispatches a change event.
+// Selects options of a <select> element by value, values, label, or labels
+// and dispatches a change event. Returns the selected values.
 func (r *Router) handleVibiumSelectOption(session *BrowserSession, cmd bidiCommand) {
 	ep := ExtractElementParams(cmd.Params)
-	value, _ := cmd.Params["value"].(string)
+	spec, err := ExtractSelectSpec(cmd.Params)
+	if err != nil {
+		r.sendError(session, cmd.ID, err)
+		return
+	}
 
 	context, err := r.resolveContext(session, cmd.Params)
 	if err != nil {
@@ -286,23 +297,44 @@ func (r *Router) handleVibiumSelectOption(session *BrowserSession, cmd bidiComma
 		return
 	}
 	r.captureBeforeSnapshotAfterScroll(session, cmd.Params)
-	script, args := buildSelectOptionScript(ep, value)
-	resp, err := CallScript(s, context, script, args)
+	selected, err := applySelectSpec(s, context, ep, spec)
 	if err != nil {
 		r.sendError(session, cmd.ID, err)
 		return
 	}
-	val, err := parseScriptResult(resp)
+
+	r.sendSuccess(session, cmd.ID, map[string]interface{}{"selected": true, "values": selected})
+}
+
+// handleVibiumSelectByIndex handles the vibium:element.selectByIndex command.
+// Selects the <option> at a zero-based position and returns its value.
+func (r *Router) handleVibiumSelectByIndex(session *BrowserSession, cmd bidiCommand) {
+	ep := ExtractElementParams(cmd.Params)
+	optionIndex, ok := cmd.Params["optionIndex"].(float64)
+	if !ok {
+		r.sendError(session, cmd.ID, fmt.Errorf("optionIndex is required"))
+		return
+	}
+
+	context, err := r.resolveContext(session, cmd.Params)
 	if err != nil {
-		r.sendError(session, cmd.ID, fmt.Errorf("selectOption failed: %w", err))
+		r.sendError(session, cmd.ID, err)
 		return
 	}
-	if val != "ok" {
-		r.sendError(session, cmd.ID, fmt.Errorf("selectOption: %s", val))
+
+	s := NewAPISession(r, session, context)
+	if _, err := resolveWithActionability(s, context, ep, SelectChecks); err != nil {
+		r.sendError(session, cmd.ID, err)
+		return
+	}
+	r.captureBeforeSnapshotAfterScroll(session, cmd.Params)
+	value, err := applySelectByIndex(s, context, ep, int(optionIndex))
+	if err != nil {
+		r.sendError(session, cmd.ID, err)
 		return
 	}
 
-	r.sendSuccess(session, cmd.ID, map[string]interface{}{"selected": true})
+	r.sendSuccess(session, cmd.ID, map[string]interface{}{"selected": true, "value": value})
 }
 
 // handleVibiumHover handles the vibium:element.hover command.
@@ -323,7 +355,7 @@ func (r *Router) handleVibiumHover(session *BrowserSession, cmd bidiCommand) {
 		return
 	}
 	r.captureBeforeSnapshotAfterScroll(session, cmd.Params)
-	if err := HoverAtCenter(s, context, info); err != nil {
+	if err := HoverAtCenter(s, context, info, ExtractHoverOptions(cmd.Params)); err != nil {
 		r.sendError(session, cmd.ID, err)
 		return
 	}
@@ -370,6 +402,12 @@ func (r *Router) handleVibiumDragTo(session *BrowserSession, cmd bidiCommand) {
 	}
 	targetEp := ExtractElementParams(targetParams)
 
+	opts, err := ExtractDragOptions(cmd.Params)
+	if err != nil {
+		r.sendError(session, cmd.ID, err)
+		return
+	}
+
 	s := NewAPISession(r, session, context)
 	srcInfo, err := resolveWithActionability(s, context, ep, HoverChecks)
 	if err != nil {
@@ -388,27 +426,7 @@ func (r *Router) handleVibiumDragTo(session *BrowserSession, cmd bidiCommand) {
 	dstX := int(targetInfo.Box.X + targetInfo.Box.Width/2)
 	dstY := int(targetInfo.Box.Y + targetInfo.Box.Height/2)
 
-	dragParams := map[string]interface{}{
-		"context": context,
-		"actions": []map[string]interface{}{
-			{
-				"type": "pointer",
-				"id":   "mouse",
-				"parameters": map[string]interface{}{
-					"pointerType": "mouse",
-				},
-				"actions": []map[string]interface{}{
-					{"type": "pointerMove", "x": srcX, "y": srcY, "duration": 0},
-					{"type": "pointerDown", "button": 0},
-					{"type": "pause", "duration": 100},
-					{"type": "pointerMove", "x": dstX, "y": dstY, "duration": 200},
-					{"type": "pointerUp", "button": 0},
-				},
-			},
-		},
-	}
-
-	if _, err := s.SendBidiCommand("input.performActions", dragParams); err != nil {
+	if err := performDrag(s, context, srcX, srcY, dstX, dstY, opts); err != nil {
 		r.sendError(session, cmd.ID, err)
 		return
 	}
@@ -518,10 +536,21 @@ func (r *Router) handleVibiumElSetFiles(session *BrowserSession, cmd bidiCommand
 		return
 	}
 
+	// A directory is expanded to the files it contains
+	if dir, ok := cmd.Params["directory"].(string); ok && dir != "" {
+		n, err := UploadDirectory(NewAPISession(r, session, context), context, ep, dir)
+		if err != nil {
+			r.sendError(session, cmd.ID, err)
+			return
+		}
+		r.sendSuccess(session, cmd.ID, map[string]interface{}{"set": true, "count": n})
+		return
+	}
+
 	// Extract files array
 	filesRaw, ok := cmd.Params["files"]
 	if !ok {
-		r.sendError(session, cmd.ID, fmt.Errorf("el.setFiles requires 'files' parameter"))
+		r.sendError(session, cmd.ID, fmt.Errorf("el.setFiles requires 'files' or 'directory' parameter"))
 		return
 	}
 	filesArr, ok := filesRaw.([]interface{})
@@ -583,39 +612,40 @@ func (r *Router) pressKey(session *BrowserSession, context, key string) error {
 // Exported standalone input primitives — usable from both proxy and MCP.
 // ---------------------------------------------------------------------------
 
+// Mouse buttons, as numbered by WebDriver pointer actions.
+const (
+	ButtonLeft   = 0
+	ButtonMiddle = 1
+	ButtonRight  = 2
+)
+
 // ClickAtCenter performs a mouse click at the center of an element.
 func ClickAtCenter(s Session, context string, info *ElementInfo) error {
-	x := int(info.Box.X + info.Box.Width/2)
-	y := int(info.Box.Y + info.Box.Height/2)
-
-	clickParams := map[string]interface{}{
-		"context": context,
-		"actions": []map[string]interface{}{
-			{
-				"type": "pointer",
-				"id":   "mouse",
-				"parameters": map[string]interface{}{
-					"pointerType": "mouse",
-				},
-				"actions": []map[string]interface{}{
-					{"type": "pointerMove", "x": x, "y": y, "duration": 0},
-					{"type": "pointerDown", "button": 0},
-					{"type": "pointerUp", "button": 0},
-				},
-			},
-		},
-	}
-
-	_, err := s.SendBidiCommand("input.performActions", clickParams)
-	return err
+	return clickAtCenter(s, context, info, ButtonLeft, 1)
 }
 
 // DblClickAtCenter performs a double-click at the center of an element.
 func DblClickAtCenter(s Session, context string, info *ElementInfo) error {
+	return clickAtCenter(s, context, info, ButtonLeft, 2)
+}
+
+// clickAtCenter moves the mouse to the center of an element and presses and
+// releases button count times.
+func clickAtCenter(s Session, context string, info *ElementInfo, button, count int) error {
 	x := int(info.Box.X + info.Box.Width/2)
 	y := int(info.Box.Y + info.Box.Height/2)
 
-	dblclickParams := map[string]interface{}{
+	actions := []map[string]interface{}{
+		{"type": "pointerMove", "x": x, "y": y, "duration": 0},
+	}
+	for i := 0; i < count; i++ {
+		actions = append(actions,
+			map[string]interface{}{"type": "pointerDown", "button": button},
+			map[string]interface{}{"type": "pointerUp", "button": button},
+		)
+	}
+
+	clickParams := map[string]interface{}{
 		"context": context,
 		"actions": []map[string]interface{}{
 			{
@@ -624,18 +654,12 @@ func DblClickAtCenter(s Session, context string, info *ElementInfo) error {
 				"parameters": map[string]interface{}{
 					"pointerType": "mouse",
 				},
-				"actions": []map[string]interface{}{
-					{"type": "pointerMove", "x": x, "y": y, "duration": 0},
-					{"type": "pointerDown", "button": 0},
-					{"type": "pointerUp", "button": 0},
-					{"type": "pointerDown", "button": 0},
-					{"type": "pointerUp", "button": 0},
-				},
+				"actions": actions,
 			},
 		},
 	}
 
-	_, err := s.SendBidiCommand("input.performActions", dblclickParams)
+	_, err := s.SendBidiCommand("input.performActions", clickParams)
 	return err
 }
 
@@ -664,37 +688,176 @@ func TypeText(s Session, context, text string) error {
 	return err
 }
 
+// insertTextScript inserts text into the focused element at the caret the
+// way an IME commits a composition: as one edit with a native input event
+// (inputType "insertText"), rather than one key event per rune.
+const insertTextScript = `(text) => {
+	let el = document.activeElement;
+	while (el && el.shadowRoot && el.shadowRoot.activeElement) el = el.shadowRoot.activeElement;
+	if (!el || el === document.body) return 'no element has focus';
+	if (document.execCommand('insertText', false, text)) return 'ok';
+	if (typeof el.setRangeText === 'function' && typeof el.selectionStart === 'number') {
+		el.setRangeText(text, el.selectionStart, el.selectionEnd, 'end');
+		el.dispatchEvent(new InputEvent('input', { bubbles: true, inputType: 'insertText', data: text }));
+		return 'ok';
+	}
+	return 'focused element is not editable';
+}`
+
+// InsertText inserts text into the focused element in a single edit. Unlike
+// TypeText it handles CJK text and multi-codepoint emoji (e.g. flags), which
+// per-rune key events garble, but it fires no keydown/keyup events.
+func InsertText(s Session, context, text string) error {
+	resp, err := CallScript(s, context, insertTextScript, []map[string]interface{}{
+		{"type": "string", "value": text},
+	})
+	if err != nil {
+		return err
+	}
+	if bidiErr := checkBidiError(resp); bidiErr != nil {
+		return bidiErr
+	}
+	val, err := parseScriptResult(resp)
+	if err != nil {
+		return fmt.Errorf("insert text failed: %w", err)
+	}
+	if val != "ok" {
+		return fmt.Errorf("insert text: %s", val)
+	}
+	return nil
+}
+
 // PressKey presses a key or key combo (e.g. "Enter", "Control+a").
 func PressKey(s Session, context, key string) error {
+	return PressKeys(s, context, []string{key})
+}
+
+// PressKeys presses keys or key combos one after another in a single
+// input.performActions, so rapid sequences (e.g. "g", "g" for vim-style
+// bindings) arrive without round-trips between them.
+func PressKeys(s Session, context string, keys []string) error {
+	keyActions := make([]map[string]interface{}, 0)
+	for _, key := range keys {
+		keyActions = append(keyActions, keyComboActions(key)...)
+	}
+
+	params := map[string]interface{}{
+		"context": context,
+		"actions": []map[string]interface{}{
+			{
+				"type":    "key",
+				"id":      "keyboard",
+				"actions": keyActions,
+			},
+		},
+	}
+
+	_, err := s.SendBidiCommand("input.performActions", params)
+	return err
+}
+
+// keyComboActions returns the key actions that press and release a key or
+// key combo, holding modifiers around the main key.
+func keyComboActions(key string) []map[string]interface{} {
 	parts := strings.Split(key, "+")
 	keyActions := make([]map[string]interface{}, 0)
 
 	if len(parts) == 1 {
 		resolved := bidi.ResolveKey(parts[0])
-		keyActions = append(keyActions,
+		return append(keyActions,
 			map[string]interface{}{"type": "keyDown", "value": resolved},
 			map[string]interface{}{"type": "keyUp", "value": resolved},
 		)
-	} else {
-		for _, part := range parts[:len(parts)-1] {
-			keyActions = append(keyActions, map[string]interface{}{
-				"type":  "keyDown",
-				"value": bidi.ResolveKey(strings.TrimSpace(part)),
-			})
-		}
+	}
 
-		mainKey := bidi.ResolveKey(strings.TrimSpace(parts[len(parts)-1]))
-		keyActions = append(keyActions,
-			map[string]interface{}{"type": "keyDown", "value": mainKey},
-			map[string]interface{}{"type": "keyUp", "value": mainKey},
-		)
+	for _, part := range parts[:len(parts)-1] {
+		keyActions = append(keyActions, map[string]interface{}{
+			"type":  "keyDown",
+			"value": bidi.ResolveKey(strings.TrimSpace(part)),
+		})
+	}
 
-		for i := len(parts) - 2; i >= 0; i-- {
-			keyActions = append(keyActions, map[string]interface{}{
-				"type":  "keyUp",
-				"value": bidi.ResolveKey(strings.TrimSpace(parts[i])),
-			})
+	mainKey := bidi.ResolveKey(strings.TrimSpace(parts[len(parts)-1]))
+	keyActions = append(keyActions,
+		map[string]interface{}{"type": "keyDown", "value": mainKey},
+		map[string]interface{}{"type": "keyUp", "value": mainKey},
+	)
+
+	for i := len(parts) - 2; i >= 0; i-- {
+		keyActions = append(keyActions, map[string]interface{}{
+			"type":  "keyUp",
+			"value": bidi.ResolveKey(strings.TrimSpace(parts[i])),
+		})
+	}
+	return keyActions
+}
+
+// KeyStep is one step of a key sequence: "down" holds a key, "up" releases
+// it, and "press" taps it.
+type KeyStep struct {
+	Action string `json:"action"`
+	Key    string `json:"key"`
+}
+
+// ExtractKeySequence reads a key sequence from params["actions"], a list of
+// {action, key} objects.
+func ExtractKeySequence(params map[string]interface{}) ([]KeyStep, error) {
+	raw, ok := params["actions"].([]interface{})
+	if !ok || len(raw) == 0 {
+		return nil, fmt.Errorf("actions must be a non-empty array")
+	}
+	steps := make([]KeyStep, 0, len(raw))
+	for i, item := range raw {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("actions[%d] must be an object", i)
+		}
+		action, _ := m["action"].(string)
+		key, _ := m["key"].(string)
+		switch action {
+		case "down", "up", "press":
+		default:
+			return nil, fmt.Errorf("actions[%d]: unknown action %q (use down, up, or press)", i, action)
+		}
+		if key == "" {
+			return nil, fmt.Errorf("actions[%d]: key is required", i)
 		}
+		steps = append(steps, KeyStep{Action: action, Key: key})
+	}
+	return steps, nil
+}
+
+// PressKeySequence performs key steps in order as a single key action
+// sequence, so modifiers held with "down" apply to every following step.
+// Keys still held at the end are released.
+func PressKeySequence(s Session, context string, steps []KeyStep) error {
+	keyActions := make([]map[string]interface{}, 0, len(steps)*2)
+	var held []string
+	for _, step := range steps {
+		key := bidi.ResolveKey(step.Key)
+		switch step.Action {
+		case "down":
+			keyActions = append(keyActions, map[string]interface{}{"type": "keyDown", "value": key})
+			held = append(held, key)
+		case "up":
+			keyActions = append(keyActions, map[string]interface{}{"type": "keyUp", "value": key})
+			for i := len(held) - 1; i >= 0; i-- {
+				if held[i] == key {
+					held = append(held[:i], held[i+1:]...)
+					break
+				}
+			}
+		case "press":
+			keyActions = append(keyActions,
+				map[string]interface{}{"type": "keyDown", "value": key},
+				map[string]interface{}{"type": "keyUp", "value": key},
+			)
+		default:
+			return fmt.Errorf("unknown key action %q", step.Action)
+		}
+	}
+	for i := len(held) - 1; i >= 0; i-- {
+		keyActions = append(keyActions, map[string]interface{}{"type": "keyUp", "value": held[i]})
 	}
 
 	params := map[string]interface{}{
@@ -730,6 +893,26 @@ func Click(s Session, context string, ep ElementParams) error {
 	return ClickAtCenter(s, context, info)
 }
 
+// RightClick resolves an element with actionability checks and right-clicks at
+// its center, which opens the page's context menu handler.
+func RightClick(s Session, context string, ep ElementParams) error {
+	info, err := resolveWithActionability(s, context, ep, ClickChecks)
+	if err != nil {
+		return err
+	}
+	return clickAtCenter(s, context, info, ButtonRight, 1)
+}
+
+// MiddleClick resolves an element with actionability checks and middle-clicks
+// at its center; on a link this opens it in a new tab.
+func MiddleClick(s Session, context string, ep ElementParams) error {
+	info, err := resolveWithActionability(s, context, ep, ClickChecks)
+	if err != nil {
+		return err
+	}
+	return clickAtCenter(s, context, info, ButtonMiddle, 1)
+}
+
 // DblClick resolves an element with actionability checks and double-clicks at its center.
 func DblClick(s Session, context string, ep ElementParams) error {
 	info, err := resolveWithActionability(s, context, ep, ClickChecks)
@@ -740,19 +923,54 @@ func DblClick(s Session, context string, ep ElementParams) error {
 }
 
 // Hover resolves an element with actionability checks and moves the mouse to its center.
-func Hover(s Session, context string, ep ElementParams) error {
+func Hover(s Session, context string, ep ElementParams, opts HoverOptions) error {
 	info, err := resolveWithActionability(s, context, ep, HoverChecks)
 	if err != nil {
 		return err
 	}
-	return HoverAtCenter(s, context, info)
+	return HoverAtCenter(s, context, info, opts)
+}
+
+// hoverStepMs is the time given to each hover step, about one frame.
+const hoverStepMs = 16
+
+// HoverOptions controls how the pointer travels to a hovered element.
+type HoverOptions struct {
+	Steps  int // > 1 glides from the current position instead of jumping
+	HoldMs int // pause after arriving, for menus that debounce hover intent
+}
+
+// ExtractHoverOptions reads steps and holdMs from params.
+func ExtractHoverOptions(params map[string]interface{}) HoverOptions {
+	var opts HoverOptions
+	if v, ok := params["steps"].(float64); ok && v > 0 {
+		opts.Steps = int(v)
+	}
+	if v, ok := params["holdMs"].(float64); ok && v > 0 {
+		opts.HoldMs = int(v)
+	}
+	return opts
 }
 
 // HoverAtCenter moves the mouse to the center of an element without clicking.
-func HoverAtCenter(s Session, context string, info *ElementInfo) error {
+// With opts.Steps > 1 the move takes Steps frames, and the browser dispatches
+// intermediate mousemove events interpolated from the current pointer
+// position, as a real mouse would.
+func HoverAtCenter(s Session, context string, info *ElementInfo, opts HoverOptions) error {
 	x := int(info.Box.X + info.Box.Width/2)
 	y := int(info.Box.Y + info.Box.Height/2)
 
+	duration := 0
+	if opts.Steps > 1 {
+		duration = opts.Steps * hoverStepMs
+	}
+	actions := []map[string]interface{}{
+		{"type": "pointerMove", "x": x, "y": y, "duration": duration},
+	}
+	if opts.HoldMs > 0 {
+		actions = append(actions, map[string]interface{}{"type": "pause", "duration": opts.HoldMs})
+	}
+
 	hoverParams := map[string]interface{}{
 		"context": context,
 		"actions": []map[string]interface{}{
@@ -762,9 +980,7 @@ func HoverAtCenter(s Session, context string, info *ElementInfo) error {
 				"parameters": map[string]interface{}{
 					"pointerType": "mouse",
 				},
-				"actions": []map[string]interface{}{
-					{"type": "pointerMove", "x": x, "y": y, "duration": 0},
-				},
+				"actions": actions,
 			},
 		},
 	}
@@ -800,7 +1016,68 @@ func TapAtCenter(s Session, context string, info *ElementInfo) error {
 	return err
 }
 
+// PinchAtCenter performs a two-finger pinch around the center of an element.
+// The fingers start on a horizontal line through the center and move apart
+// (scale > 1, zoom in) or together (scale < 1, zoom out) in steps
+// synchronized moves, so the page sees both touches change on every frame.
+func PinchAtCenter(s Session, context string, info *ElementInfo, scale float64, steps int) error {
+	if scale <= 0 {
+		return fmt.Errorf("scale must be greater than 0")
+	}
+	if steps < 1 {
+		steps = 1
+	}
+
+	cx := info.Box.X + info.Box.Width/2
+	cy := info.Box.Y + info.Box.Height/2
+	half := math.Min(info.Box.Width, info.Box.Height) / 2
+
+	// Zooming in starts with the fingers close together; zooming out starts
+	// them near the element's edges so they have room to close.
+	start := half / 2
+	if scale < 1 {
+		start = half * 0.9
+	}
+	start = math.Max(start, 10)
+	end := start * scale
+
+	finger := func(id string, dir float64) map[string]interface{} {
+		actions := []map[string]interface{}{
+			{"type": "pointerMove", "x": int(cx + dir*start), "y": int(cy), "duration": 0},
+			{"type": "pointerDown", "button": 0},
+		}
+		for i := 1; i <= steps; i++ {
+			r := start + (end-start)*float64(i)/float64(steps)
+			actions = append(actions, map[string]interface{}{
+				"type": "pointerMove", "x": int(cx + dir*r), "y": int(cy), "duration": 20,
+			})
+		}
+		actions = append(actions, map[string]interface{}{"type": "pointerUp", "button": 0})
+		return map[string]interface{}{
+			"type": "pointer",
+			"id":   id,
+			"parameters": map[string]interface{}{
+				"pointerType": "touch",
+			},
+			"actions": actions,
+		}
+	}
+
+	pinchParams := map[string]interface{}{
+		"context": context,
+		"actions": []map[string]interface{}{
+			finger("touch1", -1),
+			finger("touch2", 1),
+		},
+	}
+
+	_, err := s.SendBidiCommand("input.performActions", pinchParams)
+	return err
+}
+
 // Fill resolves an element with actionability checks and sets its value via JS.
+// It does not click or hit-test the element, so inputs behind overlays or
+// sticky headers can still be filled.
 func Fill(s Session, context string, ep ElementParams, value string) error {
 	if _, err := resolveWithActionability(s, context, ep, FillChecks); err != nil {
 		return err
@@ -820,6 +1097,12 @@ func Fill(s Session, context string, ep ElementParams, value string) error {
 	return nil
 }
 
+// Clear resolves an element with fill actionability checks and empties its
+// value via JS, dispatching input and change. Clearing an empty field is a no-op.
+func Clear(s Session, context string, ep ElementParams) error {
+	return Fill(s, context, ep, "")
+}
+
 // TypeInto resolves an element with actionability checks, clicks to focus, and types text.
 func TypeInto(s Session, context string, ep ElementParams, text string) error {
 	info, err := resolveWithActionability(s, context, ep, ClickChecks)
@@ -832,6 +1115,19 @@ func TypeInto(s Session, context string, ep ElementParams, text string) error {
 	return TypeText(s, context, text)
 }
 
+// InsertTextInto resolves an element with actionability checks, clicks to
+// focus, and inserts text in a single edit (see InsertText).
+func InsertTextInto(s Session, context string, ep ElementParams, text string) error {
+	info, err := resolveWithActionability(s, context, ep, ClickChecks)
+	if err != nil {
+		return err
+	}
+	if err := ClickAtCenter(s, context, info); err != nil {
+		return err
+	}
+	return InsertText(s, context, text)
+}
+
 // PressOn resolves an element with actionability checks, clicks to focus, and presses a key.
 func PressOn(s Session, context string, ep ElementParams, key string) error {
 	info, err := resolveWithActionability(s, context, ep, ClickChecks)
@@ -896,24 +1192,166 @@ func IsChecked(s Session, context string, ep ElementParams) (bool, error) {
 	return val == "true", nil
 }
 
+// SelectSpec describes which <option>s to select. Values match option.value;
+// labels match the option's trimmed textContent. Both may be combined.
+type SelectSpec struct {
+	Values []string
+	Labels []string
+}
+
+// ExtractSelectSpec reads value/values/label/labels from command params.
+// An empty value is a real choice (e.g. an <option value=""> placeholder),
+// so keys count when present, not when non-empty.
+func ExtractSelectSpec(params map[string]interface{}) (SelectSpec, error) {
+	var spec SelectSpec
+	if v, ok := params["value"].(string); ok {
+		spec.Values = append(spec.Values, v)
+	}
+	if l, ok := params["label"].(string); ok {
+		spec.Labels = append(spec.Labels, l)
+	}
+	for _, key := range []string{"values", "labels"} {
+		list, ok := params[key].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range list {
+			str, ok := item.(string)
+			if !ok {
+				return spec, fmt.Errorf("%s must be an array of strings", key)
+			}
+			if key == "values" {
+				spec.Values = append(spec.Values, str)
+			} else {
+				spec.Labels = append(spec.Labels, str)
+			}
+		}
+	}
+	if len(spec.Values) == 0 && len(spec.Labels) == 0 {
+		return spec, fmt.Errorf("value, values, label, or labels is required")
+	}
+	return spec, nil
+}
+
 // SelectOption resolves a select element with actionability checks and sets its value.
 func SelectOption(s Session, context string, ep ElementParams, value string) error {
+	_, err := SelectOptions(s, context, ep, SelectSpec{Values: []string{value}})
+	return err
+}
+
+// SelectOptions resolves a select element with actionability checks and
+// selects the options matching spec. Returns the values that end up selected.
+func SelectOptions(s Session, context string, ep ElementParams, spec SelectSpec) ([]string, error) {
 	if _, err := resolveWithActionability(s, context, ep, SelectChecks); err != nil {
-		return err
+		return nil, err
 	}
-	script, args := buildSelectOptionScript(ep, value)
+	return applySelectSpec(s, context, ep, spec)
+}
+
+// applySelectSpec runs the select script against an already-resolved element.
+func applySelectSpec(s Session, context string, ep ElementParams, spec SelectSpec) ([]string, error) {
+	script, args := buildSelectOptionScript(ep, spec)
 	resp, err := CallScript(s, context, script, args)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	val, err := parseScriptResult(resp)
 	if err != nil {
-		return fmt.Errorf("selectOption failed: %w", err)
+		return nil, fmt.Errorf("selectOption failed: %w", err)
 	}
-	if val != "ok" {
-		return fmt.Errorf("selectOption: %s", val)
+
+	var result struct {
+		Selected []string `json:"selected"`
+		Error    string   `json:"error"`
 	}
-	return nil
+	if err := json.Unmarshal([]byte(val), &result); err != nil {
+		return nil, fmt.Errorf("selectOption: %s", val)
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("selectOption: %s", result.Error)
+	}
+	return result.Selected, nil
+}
+
+// SelectByIndex resolves a select element with actionability checks and
+// selects the option at the given zero-based index. Returns the new value.
+func SelectByIndex(s Session, context string, ep ElementParams, optionIndex int) (string, error) {
+	if _, err := resolveWithActionability(s, context, ep, SelectChecks); err != nil {
+		return "", err
+	}
+	return applySelectByIndex(s, context, ep, optionIndex)
+}
+
+// applySelectByIndex runs the select-by-index script against an already-resolved element.
+func applySelectByIndex(s Session, context string, ep ElementParams, optionIndex int) (string, error) {
+	script, args := buildSelectByIndexScript(ep, optionIndex)
+	resp, err := CallScript(s, context, script, args)
+	if err != nil {
+		return "", err
+	}
+	val, err := parseScriptResult(resp)
+	if err != nil {
+		return "", fmt.Errorf("selectByIndex failed: %w", err)
+	}
+
+	var result struct {
+		Value *string `json:"value"`
+		Error string  `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(val), &result); err != nil {
+		return "", fmt.Errorf("selectByIndex: %s", val)
+	}
+	if result.Error != "" {
+		return "", fmt.Errorf("selectByIndex: %s", result.Error)
+	}
+	if result.Value == nil {
+		return "", fmt.Errorf("selectByIndex: no value returned")
+	}
+	return *result.Value, nil
+}
+
+// OptionInfo describes one <option> of a <select> element.
+type OptionInfo struct {
+	Value    string `json:"value"`
+	Label    string `json:"label"`
+	Selected bool   `json:"selected"`
+	Disabled bool   `json:"disabled"`
+}
+
+// ListOptions returns the options of a <select> element in document order.
+// Labels are the option's trimmed label (its text unless a label attribute
+// is set), matching what SelectSpec.Labels compares against.
+func ListOptions(s Session, context string, ep ElementParams) ([]OptionInfo, error) {
+	script, args := buildElJSONScript(ep, `
+		if (el.tagName !== 'SELECT') {
+			return JSON.stringify({error: 'element is not a <select> (found <' + el.tagName.toLowerCase() + '>)'});
+		}
+		return JSON.stringify({options: Array.from(el.options, o => ({
+			value: o.value,
+			label: (o.label || o.textContent || '').trim(),
+			selected: o.selected,
+			disabled: o.disabled,
+		}))});
+	`)
+	val, err := EvalElementScript(s, context, script, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Options []OptionInfo `json:"options"`
+		Error   string       `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(val), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse options: %w", err)
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	if result.Options == nil {
+		result.Options = []OptionInfo{}
+	}
+	return result.Options, nil
 }
 
 // FocusElement resolves an element and focuses it via JS.
@@ -941,8 +1379,18 @@ func Tap(s Session, context string, ep ElementParams) error {
 	return TapAtCenter(s, context, info)
 }
 
+// Pinch resolves an element with actionability checks and pinches around its
+// center by scale.
+func Pinch(s Session, context string, ep ElementParams, scale float64, steps int) error {
+	info, err := resolveWithActionability(s, context, ep, HoverChecks)
+	if err != nil {
+		return err
+	}
+	return PinchAtCenter(s, context, info, scale, steps)
+}
+
 // DragTo resolves source and target elements with actionability checks and drags from one to the other.
-func DragTo(s Session, context string, source, target ElementParams) error {
+func DragTo(s Session, context string, source, target ElementParams, opts DragOptions) error {
 	srcInfo, err := resolveWithActionability(s, context, source, HoverChecks)
 	if err != nil {
 		return fmt.Errorf("source: %w", err)
@@ -957,6 +1405,66 @@ func DragTo(s Session, context string, source, target ElementParams) error {
 	dstX := int(targetInfo.Box.X + targetInfo.Box.Width/2)
 	dstY := int(targetInfo.Box.Y + targetInfo.Box.Height/2)
 
+	return performDrag(s, context, srcX, srcY, dstX, dstY, opts)
+}
+
+// DragBy presses the left mouse button at the source element's center (after
+// actionability checks), or at (x, y) when source has no selector, moves by
+// deltaX/deltaY, and releases. Returns the start point.
+func DragBy(s Session, context string, source ElementParams, x, y, deltaX, deltaY int, opts DragOptions) (int, int, error) {
+	if source.Selector != "" || hasSemantic(source) {
+		info, err := resolveWithActionability(s, context, source, HoverChecks)
+		if err != nil {
+			return 0, 0, fmt.Errorf("source: %w", err)
+		}
+		x = int(info.Box.X + info.Box.Width/2)
+		y = int(info.Box.Y + info.Box.Height/2)
+	}
+
+	return x, y, performDrag(s, context, x, y, x+deltaX, y+deltaY, opts)
+}
+
+// DragOptions controls how a drag is performed.
+type DragOptions struct {
+	Mode   string // "pointer" (mouse input actions) or "html5" (synthetic DragEvents)
+	HoldMs int    // pause between pressing the button and moving
+	MoveMs int    // total duration of the move
+	Steps  int    // number of pointerMove actions the move is split into
+}
+
+// DefaultDragOptions returns the drag options used when none are given.
+func DefaultDragOptions() DragOptions {
+	return DragOptions{Mode: "pointer", HoldMs: 100, MoveMs: 200, Steps: 1}
+}
+
+// ExtractDragOptions reads mode, holdMs, moveMs, and steps from params,
+// falling back to the defaults for missing or invalid timing values.
+func ExtractDragOptions(params map[string]interface{}) (DragOptions, error) {
+	opts := DefaultDragOptions()
+	if mode, ok := params["mode"].(string); ok && mode != "" {
+		if mode != "pointer" && mode != "html5" {
+			return opts, fmt.Errorf("unknown drag mode %q (use pointer or html5)", mode)
+		}
+		opts.Mode = mode
+	}
+	if v, ok := params["holdMs"].(float64); ok && v >= 0 {
+		opts.HoldMs = int(v)
+	}
+	if v, ok := params["moveMs"].(float64); ok && v >= 0 {
+		opts.MoveMs = int(v)
+	}
+	if v, ok := params["steps"].(float64); ok && v >= 1 {
+		opts.Steps = int(v)
+	}
+	return opts, nil
+}
+
+// performDrag drags from (srcX, srcY) to (dstX, dstY) using the mode in opts.
+func performDrag(s Session, context string, srcX, srcY, dstX, dstY int, opts DragOptions) error {
+	if opts.Mode == "html5" {
+		return html5Drag(s, context, srcX, srcY, dstX, dstY)
+	}
+
 	dragParams := map[string]interface{}{
 		"context": context,
 		"actions": []map[string]interface{}{
@@ -966,21 +1474,90 @@ func DragTo(s Session, context string, source, target ElementParams) error {
 				"parameters": map[string]interface{}{
 					"pointerType": "mouse",
 				},
-				"actions": []map[string]interface{}{
-					{"type": "pointerMove", "x": srcX, "y": srcY, "duration": 0},
-					{"type": "pointerDown", "button": 0},
-					{"type": "pause", "duration": 100},
-					{"type": "pointerMove", "x": dstX, "y": dstY, "duration": 200},
-					{"type": "pointerUp", "button": 0},
-				},
+				"actions": buildDragActions(srcX, srcY, dstX, dstY, opts),
 			},
 		},
 	}
 
-	_, err = s.SendBidiCommand("input.performActions", dragParams)
+	_, err := s.SendBidiCommand("input.performActions", dragParams)
 	return err
 }
 
+// html5DragScript dispatches the HTML5 drag-and-drop event sequence between
+// the elements at two viewport points, sharing one DataTransfer the way a
+// native drag does. As in a browser, drop only fires when a dragover
+// listener called preventDefault to accept it.
+const html5DragScript = `(sx, sy, dx, dy) => {
+	const hit = document.elementFromPoint(sx, sy);
+	if (!hit) return 'no element at the source point';
+	const src = hit.closest('[draggable="true"]') || hit;
+	const dst = document.elementFromPoint(dx, dy);
+	if (!dst) return 'no element at the target point';
+	const dataTransfer = new DataTransfer();
+	const fire = (el, type, x, y) => el.dispatchEvent(new DragEvent(type, {
+		bubbles: true, cancelable: true, composed: true,
+		clientX: x, clientY: y, dataTransfer,
+	}));
+	if (!fire(src, 'dragstart', sx, sy)) return 'dragstart was cancelled';
+	fire(src, 'drag', sx, sy);
+	fire(dst, 'dragenter', dx, dy);
+	const accepted = !fire(dst, 'dragover', dx, dy);
+	if (accepted) fire(dst, 'drop', dx, dy);
+	fire(src, 'dragend', dx, dy);
+	return accepted ? 'ok' : 'target did not accept the drop (no dragover handler called preventDefault)';
+}`
+
+// html5Drag performs a drag with synthetic DragEvents instead of mouse input,
+// for drag-and-drop libraries that only listen for HTML5 drag events.
+func html5Drag(s Session, context string, srcX, srcY, dstX, dstY int) error {
+	resp, err := CallScript(s, context, html5DragScript, []map[string]interface{}{
+		{"type": "number", "value": srcX},
+		{"type": "number", "value": srcY},
+		{"type": "number", "value": dstX},
+		{"type": "number", "value": dstY},
+	})
+	if err != nil {
+		return err
+	}
+	if bidiErr := checkBidiError(resp); bidiErr != nil {
+		return bidiErr
+	}
+	val, err := parseScriptResult(resp)
+	if err != nil {
+		return err
+	}
+	if val != "ok" {
+		return fmt.Errorf("html5 drag failed: %s", val)
+	}
+	return nil
+}
+
+// buildDragActions builds a pointer press at (srcX, srcY), a hold, a move to
+// (dstX, dstY) split into evenly spaced pointerMove actions, and a release.
+// Several steps make the browser fire intermediate mousemove/dragover events,
+// which many drag libraries need to register a drag.
+func buildDragActions(srcX, srcY, dstX, dstY int, opts DragOptions) []map[string]interface{} {
+	steps := opts.Steps
+	if steps < 1 {
+		steps = 1
+	}
+	actions := []map[string]interface{}{
+		{"type": "pointerMove", "x": srcX, "y": srcY, "duration": 0},
+		{"type": "pointerDown", "button": 0},
+		{"type": "pause", "duration": opts.HoldMs},
+	}
+	stepDuration := opts.MoveMs / steps
+	for i := 1; i <= steps; i++ {
+		actions = append(actions, map[string]interface{}{
+			"type":     "pointerMove",
+			"x":        srcX + (dstX-srcX)*i/steps,
+			"y":        srcY + (dstY-srcY)*i/steps,
+			"duration": stepDuration,
+		})
+	}
+	return append(actions, map[string]interface{}{"type": "pointerUp", "button": 0})
+}
+
 // ScrollWheel performs a mouse wheel scroll at the given coordinates.
 func ScrollWheel(s Session, context string, x, y, deltaX, deltaY int) error {
 	scrollParams := map[string]interface{}{
@@ -1006,6 +1583,90 @@ func ScrollWheel(s Session, context string, x, y, deltaX, deltaY int) error {
 	return err
 }
 
+// ScrollPosition is the window scroll offset in CSS pixels.
+type ScrollPosition struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// scrollToScript scrolls the window instantly (ignoring CSS scroll-behavior)
+// to "top", "bottom", or an {x, y} position, and returns the resulting offset.
+const scrollToScript = `(specJSON) => {
+	const spec = JSON.parse(specJSON);
+	const el = document.scrollingElement || document.documentElement;
+	let x = window.scrollX, y = window.scrollY;
+	if (spec.to === 'top') { y = 0; }
+	else if (spec.to === 'bottom') { y = el.scrollHeight; }
+	else if (spec.to === 'position') {
+		if (spec.x !== null) x = spec.x;
+		if (spec.y !== null) y = spec.y;
+	}
+	window.scrollTo({ left: x, top: y, behavior: 'instant' });
+	return JSON.stringify({ x: window.scrollX, y: window.scrollY });
+}`
+
+// ScrollTo scrolls the window to "top", "bottom", or (with to == "") the
+// given position; a nil coordinate keeps its current value. Returns the
+// resulting scroll position, clamped by the browser to the scrollable range.
+func ScrollTo(s Session, context, to string, x, y *float64) (ScrollPosition, error) {
+	spec := map[string]interface{}{"to": to, "x": x, "y": y}
+	if to == "" {
+		spec["to"] = "position"
+	}
+	specJSON, _ := json.Marshal(spec)
+	return evalScrollScript(s, context, scrollToScript, string(specJSON))
+}
+
+// ScrollBy dispatches a single wheel event of exactly deltaX/deltaY pixels at
+// (x, y) and returns the window scroll position once it stops changing, so
+// smooth scrolling has finished before the position is read.
+func ScrollBy(s Session, context string, x, y, deltaX, deltaY int) (ScrollPosition, error) {
+	if err := ScrollWheel(s, context, x, y, deltaX, deltaY); err != nil {
+		return ScrollPosition{}, err
+	}
+
+	pos, err := GetScrollPosition(s, context)
+	deadline := time.Now().Add(time.Second)
+	for err == nil && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+		var next ScrollPosition
+		next, err = GetScrollPosition(s, context)
+		if err == nil && next == pos {
+			break
+		}
+		pos = next
+	}
+	return pos, err
+}
+
+// GetScrollPosition returns the window scroll position.
+func GetScrollPosition(s Session, context string) (ScrollPosition, error) {
+	return evalScrollScript(s, context, `(specJSON) => JSON.stringify({ x: window.scrollX, y: window.scrollY })`, "{}")
+}
+
+// evalScrollScript runs a scroll script taking a JSON spec and parses the
+// {x, y} JSON it returns.
+func evalScrollScript(s Session, context, script, specJSON string) (ScrollPosition, error) {
+	var pos ScrollPosition
+	resp, err := CallScript(s, context, script, []map[string]interface{}{
+		{"type": "string", "value": specJSON},
+	})
+	if err != nil {
+		return pos, err
+	}
+	if bidiErr := checkBidiError(resp); bidiErr != nil {
+		return pos, bidiErr
+	}
+	val, err := parseScriptResult(resp)
+	if err != nil {
+		return pos, err
+	}
+	if err := json.Unmarshal([]byte(val), &pos); err != nil {
+		return pos, fmt.Errorf("failed to parse scroll position: %w", err)
+	}
+	return pos, nil
+}
+
 // --- Script builders for JS-based interactions ---
 
 // buildIsCheckedScript builds a JS function to check if an element is checked.
@@ -1019,14 +1680,15 @@ func buildIsCheckedScript(ep ElementParams) (string, []map[string]interface{}) {
 
 	script := `
 		(scope, selector, index, hasIndex) => {
-			const root = scope ? document.querySelector(scope) : document;
+			const root = resolveScope(scope);
 			if (!root) return 'false';
+		` + DeepQueryJS() + `
 			let el;
 			if (hasIndex) {
-				const all = root.querySelectorAll(selector);
+				const all = queryAllDeep(root, selector);
 				el = all[index];
 			} else {
-				el = root.querySelector(selector);
+				el = queryDeep(root, selector);
 			}
 			if (!el) return 'false';
 			return el.checked ? 'true' : 'false';
@@ -1035,37 +1697,108 @@ func buildIsCheckedScript(ep ElementParams) (string, []map[string]interface{}) {
 	return script, args
 }
 
-// buildSelectOptionScript builds a JS function to set a select element's value.
-func buildSelectOptionScript(ep ElementParams, value string) (string, []map[string]interface{}) {
+// buildSelectOptionScript builds a JS function that selects the options of a
+// <select> matching the given values and labels, then dispatches input and
+// change once. Returns JSON {selected: [...]} or {error: "..."}.
+func buildSelectOptionScript(ep ElementParams, spec SelectSpec) (string, []map[string]interface{}) {
+	valuesJSON, _ := json.Marshal(nonNilStrings(spec.Values))
+	labelsJSON, _ := json.Marshal(nonNilStrings(spec.Labels))
 	args := []map[string]interface{}{
 		{"type": "string", "value": ep.Scope},
 		{"type": "string", "value": ep.Selector},
 		{"type": "number", "value": ep.Index},
 		{"type": "boolean", "value": ep.HasIndex},
-		{"type": "string", "value": value},
+		{"type": "string", "value": string(valuesJSON)},
+		{"type": "string", "value": string(labelsJSON)},
 	}
 
 	script := `
-		(scope, selector, index, hasIndex, value) => {
-			const root = scope ? document.querySelector(scope) : document;
-			if (!root) return 'element not found';
+		(scope, selector, index, hasIndex, valuesJSON, labelsJSON) => {
+			const fail = (msg) => JSON.stringify({ error: msg });
+			const root = resolveScope(scope);
+			if (!root) return fail('element not found');
+		` + DeepQueryJS() + `
 			let el;
 			if (hasIndex) {
-				const all = root.querySelectorAll(selector);
+				const all = queryAllDeep(root, selector);
 				el = all[index];
 			} else {
-				el = root.querySelector(selector);
+				el = queryDeep(root, selector);
 			}
-			if (!el) return 'element not found';
-			el.value = value;
+			if (!el) return fail('element not found');
+			if (el.tagName !== 'SELECT') return fail('element is not a <select>');
+
+			const options = Array.from(el.options);
+			const wanted = [];
+			for (const v of JSON.parse(valuesJSON)) {
+				const opt = options.find(o => o.value === v);
+				if (!opt) return fail('no option with value "' + v + '"');
+				wanted.push(opt);
+			}
+			for (const l of JSON.parse(labelsJSON)) {
+				const opt = options.find(o => (o.textContent || '').trim() === l.trim());
+				if (!opt) return fail('no option with label "' + l + '"');
+				wanted.push(opt);
+			}
+			if (!el.multiple && new Set(wanted).size > 1) {
+				return fail('cannot select multiple options in a single-select <select>');
+			}
+
+			for (const opt of options) opt.selected = wanted.includes(opt);
 			el.dispatchEvent(new Event('input', { bubbles: true }));
 			el.dispatchEvent(new Event('change', { bubbles: true }));
-			return 'ok';
+			return JSON.stringify({ selected: options.filter(o => o.selected).map(o => o.value) });
 		}
 	`
 	return script, args
 }
 
+// buildSelectByIndexScript builds a JS function that sets a <select>'s
+// selectedIndex and dispatches input and change. Returns JSON {value} or {error}.
+func buildSelectByIndexScript(ep ElementParams, optionIndex int) (string, []map[string]interface{}) {
+	args := []map[string]interface{}{
+		{"type": "string", "value": ep.Scope},
+		{"type": "string", "value": ep.Selector},
+		{"type": "number", "value": ep.Index},
+		{"type": "boolean", "value": ep.HasIndex},
+		{"type": "number", "value": optionIndex},
+	}
+
+	script := `
+		(scope, selector, index, hasIndex, optionIndex) => {
+			const fail = (msg) => JSON.stringify({ error: msg });
+			const root = resolveScope(scope);
+			if (!root) return fail('element not found');
+		` + DeepQueryJS() + `
+			let el;
+			if (hasIndex) {
+				const all = queryAllDeep(root, selector);
+				el = all[index];
+			} else {
+				el = queryDeep(root, selector);
+			}
+			if (!el) return fail('element not found');
+			if (el.tagName !== 'SELECT') return fail('element is not a <select>');
+			if (optionIndex < 0 || optionIndex >= el.options.length) {
+				return fail('index ' + optionIndex + ' out of range (select has ' + el.options.length + ' options)');
+			}
+			el.selectedIndex = optionIndex;
+			el.dispatchEvent(new Event('input', { bubbles: true }));
+			el.dispatchEvent(new Event('change', { bubbles: true }));
+			return JSON.stringify({ value: el.value });
+		}
+	`
+	return script, args
+}
+
+// nonNilStrings returns s, or an empty slice if s is nil, so it marshals as [].
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
 // buildSetValueScript builds a JS function to set an element's value and dispatch events.
 func buildSetValueScript(ep ElementParams, value string) (string, []map[string]interface{}) {
 	args := []map[string]interface{}{
@@ -1078,14 +1811,15 @@ func buildSetValueScript(ep ElementParams, value string) (string, []map[string]i
 
 	script := `
 		(scope, selector, index, hasIndex, value) => {
-			const root = scope ? document.querySelector(scope) : document;
+			const root = resolveScope(scope);
 			if (!root) return 'element not found';
+		` + DeepQueryJS() + `
 			let el;
 			if (hasIndex) {
-				const all = root.querySelectorAll(selector);
+				const all = queryAllDeep(root, selector);
 				el = all[index];
 			} else {
-				el = root.querySelector(selector);
+				el = queryDeep(root, selector);
 			}
 			if (!el) return 'element not found';
 			el.focus();
@@ -1118,14 +1852,15 @@ func buildFocusScript(ep ElementParams) (string, []map[string]interface{}) {
 
 	script := `
 		(scope, selector, index, hasIndex) => {
-			const root = scope ? document.querySelector(scope) : document;
+			const root = resolveScope(scope);
 			if (!root) return 'not found';
+		` + DeepQueryJS() + `
 			let el;
 			if (hasIndex) {
-				const all = root.querySelectorAll(selector);
+				const all = queryAllDeep(root, selector);
 				el = all[index];
 			} else {
-				el = root.querySelector(selector);
+				el = queryDeep(root, selector);
 			}
 			if (!el) return 'not found';
 			el.focus();
@@ -1148,14 +1883,15 @@ func buildDispatchEventScript(ep ElementParams, eventType, initJSON string) (str
 
 	script := `
 		(scope, selector, index, hasIndex, eventType, initJSON) => {
-			const root = scope ? document.querySelector(scope) : document;
+			const root = resolveScope(scope);
 			if (!root) return 'not found';
+		` + DeepQueryJS() + `
 			let el;
 			if (hasIndex) {
-				const all = root.querySelectorAll(selector);
+				const all = queryAllDeep(root, selector);
 				el = all[index];
 			} else {
-				el = root.querySelector(selector);
+				el = queryDeep(root, selector);
 			}
 			if (!el) return 'not found';
 			const init = JSON.parse(initJSON);
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vibium/clicker/internal/bidi"
+)
+
+func TestExtractSelectSpec(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		want   SelectSpec
+	}{
+		{"value", map[string]interface{}{"value": "blue"}, SelectSpec{Values: []string{"blue"}}},
+		{"empty value placeholder", map[string]interface{}{"value": ""}, SelectSpec{Values: []string{""}}},
+		{"label", map[string]interface{}{"label": "Dark Blue"}, SelectSpec{Labels: []string{"Dark Blue"}}},
+		{"multi-select", map[string]interface{}{"values": []interface{}{"cheese", "olives"}, "labels": []interface{}{"Ham"}},
+			SelectSpec{Values: []string{"cheese", "olives"}, Labels: []string{"Ham"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractSelectSpec(tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractSelectSpec(%v) = %+v, want %+v", tt.params, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ExtractSelectSpec(map[string]interface{}{"selector": "#color"}); err == nil {
+		t.Error("no value or label should be an error")
+	}
+	if _, err := ExtractSelectSpec(map[string]interface{}{"values": []interface{}{"a", 1.0}}); err == nil {
+		t.Error("non-string values should be an error")
+	}
+}
+
+func TestHoverAtCenterActions(t *testing.T) {
+	info := &ElementInfo{Box: BoxInfo{X: 10, Y: 20, Width: 100, Height: 40}}
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		want   []map[string]interface{}
+	}{
+		{"jump", map[string]interface{}{}, []map[string]interface{}{
+			{"type": "pointerMove", "x": 60, "y": 40, "duration": 0},
+		}},
+		{"one step is a jump", map[string]interface{}{"steps": float64(1)}, []map[string]interface{}{
+			{"type": "pointerMove", "x": 60, "y": 40, "duration": 0},
+		}},
+		{"glide", map[string]interface{}{"steps": float64(5)}, []map[string]interface{}{
+			{"type": "pointerMove", "x": 60, "y": 40, "duration": 5 * hoverStepMs},
+		}},
+		{"glide and hold", map[string]interface{}{"steps": float64(5), "holdMs": float64(300)}, []map[string]interface{}{
+			{"type": "pointerMove", "x": 60, "y": 40, "duration": 5 * hoverStepMs},
+			{"type": "pause", "duration": 300},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &fakeSession{resp: `{}`}
+			if err := HoverAtCenter(s, "ctx", info, ExtractHoverOptions(tt.params)); err != nil {
+				t.Fatal(err)
+			}
+			if s.method != "input.performActions" {
+				t.Fatalf("method = %q", s.method)
+			}
+			source := s.params["actions"].([]map[string]interface{})[0]
+			if got := source["actions"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("actions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPressKeysSendsOneSequence(t *testing.T) {
+	s := &fakeSession{resp: `{}`}
+	if err := PressKeys(s, "ctx", []string{"g", "g", "Shift+G"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.calls) != 1 || s.method != "input.performActions" {
+		t.Fatalf("sent %d command(s), last %q; want one input.performActions", len(s.calls), s.method)
+	}
+	shift := bidi.ResolveKey("Shift")
+	want := []map[string]interface{}{
+		{"type": "keyDown", "value": "g"},
+		{"type": "keyUp", "value": "g"},
+		{"type": "keyDown", "value": "g"},
+		{"type": "keyUp", "value": "g"},
+		{"type": "keyDown", "value": shift},
+		{"type": "keyDown", "value": "G"},
+		{"type": "keyUp", "value": "G"},
+		{"type": "keyUp", "value": shift},
+	}
+	source := s.params["actions"].([]map[string]interface{})[0]
+	if got := source["actions"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("actions = %v, want %v", got, want)
+	}
+}
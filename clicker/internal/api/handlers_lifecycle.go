@@ -1,8 +1,13 @@
 package api
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
 )
 
 // handleBrowserPage handles vibium:browser.page — returns the first (default) browsing context.
@@ -344,6 +349,143 @@ func Upload(s Session, context string, ep ElementParams, files []string) error {
 	return err
 }
 
+// UploadDirectory sets every file under dir (recursively) on an
+// <input type="file" webkitdirectory> element, and returns how many files
+// were set. It errors if the input doesn't accept directories.
+//
+// The directory itself is handed to input.setFiles so the browser
+// enumerates it the way its own picker would, giving each file a
+// webkitRelativePath such as "photos/2024/a.jpg".
+func UploadDirectory(s Session, context string, ep ElementParams, dir string) (int, error) {
+	files, err := ExpandUploadDirectory(dir)
+	if err != nil {
+		return 0, err
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	sharedID, err := ResolveElementRef(s, context, ep)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := CallScript(s, context, "(el) => String(el.hasAttribute('webkitdirectory'))", []map[string]interface{}{
+		{"sharedId": sharedID},
+	})
+	if err != nil {
+		return 0, err
+	}
+	isDir, err := parseScriptResult(resp)
+	if err != nil {
+		return 0, err
+	}
+	if isDir != "true" {
+		return 0, fmt.Errorf("element is not a directory input (it has no webkitdirectory attribute)")
+	}
+
+	resp, err = s.SendBidiCommand("input.setFiles", map[string]interface{}{
+		"context": context,
+		"element": map[string]interface{}{
+			"sharedId": sharedID,
+		},
+		"files": []string{abs},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := checkBidiError(resp); err != nil {
+		return 0, err
+	}
+	return len(files), nil
+}
+
+// ExpandUploadDirectory returns the regular files under dir, recursively,
+// in lexical order.
+func ExpandUploadDirectory(dir string) ([]string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	var files []string
+	err = filepath.WalkDir(abs, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("directory %s contains no files", dir)
+	}
+	return files, nil
+}
+
+// UploadContent is an in-memory file for upload: a file name and its
+// base64-encoded content.
+type UploadContent struct {
+	Name    string
+	Content string
+}
+
+// ExtractUploadContents reads [{name, content}] upload params.
+func ExtractUploadContents(raw []interface{}) ([]UploadContent, error) {
+	contents := make([]UploadContent, 0, len(raw))
+	for i, item := range raw {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("contents[%d] must be an object with name and content", i)
+		}
+		name, _ := m["name"].(string)
+		content, ok := m["content"].(string)
+		if name == "" || !ok {
+			return nil, fmt.Errorf("contents[%d] requires name and content", i)
+		}
+		contents = append(contents, UploadContent{Name: name, Content: content})
+	}
+	return contents, nil
+}
+
+// WriteUploadFiles decodes in-memory files into dir so they can be passed to
+// Upload, and returns their paths. Each file gets its own subdirectory so
+// duplicate names keep their name as the browser reports it.
+func WriteUploadFiles(dir string, contents []UploadContent) ([]string, error) {
+	paths := make([]string, 0, len(contents))
+	for i, c := range contents {
+		name := filepath.Base(c.Name)
+		if name == "." || name == ".." || name == string(filepath.Separator) {
+			return nil, fmt.Errorf("invalid file name %q", c.Name)
+		}
+		data, err := base64.StdEncoding.DecodeString(c.Content)
+		if err != nil {
+			return nil, fmt.Errorf("content of %s is not valid base64: %w", c.Name, err)
+		}
+		sub := filepath.Join(dir, strconv.Itoa(i))
+		if err := os.MkdirAll(sub, 0o755); err != nil {
+			return nil, err
+		}
+		path := filepath.Join(sub, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
 // MouseMove moves the mouse to the given coordinates.
 func MouseMove(s Session, context string, x, y int) error {
 	params := map[string]interface{}{
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteUploadFiles(t *testing.T) {
+	contents, err := ExtractUploadContents([]interface{}{
+		map[string]interface{}{"name": "report.csv", "content": "YSxiCjEsMgo="},
+		map[string]interface{}{"name": "../report.csv", "content": "aGVsbG8="},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	paths, err := WriteUploadFiles(dir, contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("paths = %v, want 2", paths)
+	}
+	for i, want := range []string{"a,b\n1,2\n", "hello"} {
+		// Duplicate names stay intact and paths stay inside dir.
+		if filepath.Base(paths[i]) != "report.csv" || filepath.Dir(filepath.Dir(paths[i])) != dir {
+			t.Errorf("paths[%d] = %q, want report.csv under %s", i, paths[i], dir)
+		}
+		data, err := os.ReadFile(paths[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("paths[%d] content = %q, want %q", i, data, want)
+		}
+	}
+}
+
+func TestUploadContentsRejectsBadInput(t *testing.T) {
+	for _, raw := range [][]interface{}{
+		{"report.csv"},
+		{map[string]interface{}{"content": "aGVsbG8="}},
+		{map[string]interface{}{"name": "report.csv"}},
+	} {
+		if _, err := ExtractUploadContents(raw); err == nil {
+			t.Errorf("ExtractUploadContents(%v) should fail", raw)
+		}
+	}
+	for _, c := range []UploadContent{
+		{Name: "x.bin", Content: "not base64!"},
+		{Name: "..", Content: ""},
+	} {
+		if _, err := WriteUploadFiles(t.TempDir(), []UploadContent{c}); err == nil {
+			t.Errorf("WriteUploadFiles(%+v) should fail", c)
+		}
+	}
+}
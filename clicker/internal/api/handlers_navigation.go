@@ -1,7 +1,9 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -149,6 +151,7 @@ func (r *Router) handlePageContent(session *BrowserSession, cmd bidiCommand) {
 }
 
 // handlePageWaitForURL handles vibium:page.waitForURL — waits until the URL matches a pattern.
+// Set regex: true to treat the pattern as a regular expression.
 func (r *Router) handlePageWaitForURL(session *BrowserSession, cmd bidiCommand) {
 	context, err := r.resolveContext(session, cmd.Params)
 	if err != nil {
@@ -169,7 +172,12 @@ func (r *Router) handlePageWaitForURL(session *BrowserSession, cmd bidiCommand)
 	}
 
 	s := NewAPISession(r, session, context)
-	url, err := WaitForURL(s, context, pattern, timeout)
+	var url string
+	if isRegex, _ := cmd.Params["regex"].(bool); isRegex {
+		url, err = WaitForURLRegex(s, context, pattern, timeout)
+	} else {
+		url, err = WaitForURL(s, context, pattern, timeout)
+	}
 	if err != nil {
 		r.sendError(session, cmd.ID, err)
 		return
@@ -314,19 +322,91 @@ func GetContent(s Session, context string) (string, error) {
 	return EvalSimpleScript(s, context, "() => document.documentElement.outerHTML")
 }
 
+// LinkInfo describes one <a href> on the page.
+type LinkInfo struct {
+	Text    string `json:"text"`
+	Href    string `json:"href"`
+	Visible bool   `json:"visible"`
+}
+
+// GetLinks returns every <a href> on the page in document order, with hrefs
+// resolved to absolute URLs. Links sharing an href are reported once, with
+// the text of the first and visible set if any of them is visible. When
+// match is non-nil only links whose href it accepts are returned.
+func GetLinks(s Session, context string, match func(string) bool) ([]LinkInfo, error) {
+	val, err := EvalSimpleScript(s, context, `() => {
+		const links = [];
+		for (const a of document.querySelectorAll('a[href]')) {
+			let href;
+			try {
+				href = new URL(a.getAttribute('href'), location.href).href;
+			} catch (e) {
+				continue;
+			}
+			const rect = a.getBoundingClientRect();
+			const style = window.getComputedStyle(a);
+			const visible = rect.width > 0 && rect.height > 0 &&
+				style.display !== 'none' && style.visibility !== 'hidden';
+			const text = (a.innerText || a.getAttribute('aria-label') || a.title || '').trim();
+			links.push({text, href, visible});
+		}
+		return JSON.stringify(links);
+	}`)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []LinkInfo
+	if err := json.Unmarshal([]byte(val), &all); err != nil {
+		return nil, fmt.Errorf("failed to parse links: %w", err)
+	}
+
+	links := []LinkInfo{}
+	seen := map[string]int{}
+	for _, link := range all {
+		if match != nil && !match(link.Href) {
+			continue
+		}
+		if i, ok := seen[link.Href]; ok {
+			links[i].Visible = links[i].Visible || link.Visible
+			continue
+		}
+		seen[link.Href] = len(links)
+		links = append(links, link)
+	}
+	return links, nil
+}
+
 // WaitForURL waits until the URL matches a pattern.
 func WaitForURL(s Session, context, pattern string, timeout time.Duration) (string, error) {
+	return waitForURLMatch(s, context, func(url string) bool {
+		return matchesPattern(url, pattern)
+	}, fmt.Sprintf("URL matching '%s'", pattern), timeout)
+}
+
+// WaitForURLRegex waits until the URL matches a regular expression.
+// An invalid expression is reported immediately rather than after the timeout.
+func WaitForURLRegex(s Session, context, pattern string, timeout time.Duration) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL regex: %w", err)
+	}
+	return waitForURLMatch(s, context, re.MatchString, fmt.Sprintf("URL matching regex '%s'", pattern), timeout)
+}
+
+// waitForURLMatch polls window.location.href until match returns true.
+func waitForURLMatch(s Session, context string, match func(string) bool, desc string, timeout time.Duration) (string, error) {
 	deadline := time.Now().Add(timeout)
 	interval := 100 * time.Millisecond
 
 	for {
 		url, err := EvalSimpleScript(s, context, "() => window.location.href")
-		if err == nil && matchesPattern(url, pattern) {
+		if err == nil && match(url) {
 			return url, nil
 		}
 
 		if time.Now().After(deadline) {
-			return "", fmt.Errorf("timeout after %s waiting for URL matching '%s'", timeout, pattern)
+			return "", fmt.Errorf("timeout after %s waiting for %s", timeout, desc)
 		}
 
 		time.Sleep(interval)
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// urlSession answers location.href with each URL in turn, repeating the last.
+func urlSession(urls ...string) *fakeSession {
+	i := 0
+	return &fakeSession{respond: func(method string, params map[string]interface{}) string {
+		url := urls[i]
+		if i < len(urls)-1 {
+			i++
+		}
+		return `{"result":{"result":{"type":"string","value":"` + url + `"}}}`
+	}}
+}
+
+func TestWaitForURLRegex(t *testing.T) {
+	s := urlSession("https://app.example.com/login", "https://app.example.com/dashboard/42")
+	got, err := WaitForURLRegex(s, "ctx", `^https://app\.example\.com/dashboard/\d+`, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "https://app.example.com/dashboard/42" {
+		t.Errorf("url = %q", got)
+	}
+
+	// Substring matching stays the default: "." is literal there.
+	s = urlSession("https://app.example.com/dashboard/42")
+	if _, err := WaitForURL(s, "ctx", `dashboard/\d+`, 150*time.Millisecond); err == nil {
+		t.Error("WaitForURL should not treat the pattern as a regex")
+	}
+}
+
+func TestWaitForURLRegexInvalidFailsFast(t *testing.T) {
+	s := urlSession("https://example.com/")
+	start := time.Now()
+	_, err := WaitForURLRegex(s, "ctx", `dashboard/(\d+`, 10*time.Second)
+	if err == nil || !strings.Contains(err.Error(), "invalid URL regex") {
+		t.Fatalf("err = %v, want an invalid regex error", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("invalid regex took %s; it should fail before polling", time.Since(start))
+	}
+	if len(s.calls) != 0 {
+		t.Errorf("polled the page %d times for an invalid regex", len(s.calls))
+	}
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 )
 
@@ -285,3 +286,167 @@ func convertHeadersToBidi(headers map[string]interface{}) []map[string]interface
 	}
 	return bidiHeaders
 }
+
+// MergeBidiHeaders returns a request's BiDi headers with extra headers added,
+// replacing any existing header of the same name (case-insensitively). The
+// result is suitable for network.continueRequest, which replaces all headers.
+func MergeBidiHeaders(headers []interface{}, extra map[string]string) []map[string]interface{} {
+	merged := make([]map[string]interface{}, 0, len(headers)+len(extra))
+	for _, h := range headers {
+		hdr, ok := h.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := hdr["name"].(string)
+		replaced := false
+		for extraName := range extra {
+			if strings.EqualFold(name, extraName) {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, hdr)
+		}
+	}
+	for name, value := range extra {
+		merged = append(merged, map[string]interface{}{
+			"name":  name,
+			"value": map[string]interface{}{"type": "string", "value": value},
+		})
+	}
+	return merged
+}
+
+// HeaderScope limits extra request headers to the requests they are meant
+// for, so credentials aren't sent to third-party hosts.
+type HeaderScope struct {
+	Origin string // an origin, a URL glob with *, or "*" for every request
+
+	match func(string) bool
+}
+
+// NewHeaderScope builds a HeaderScope. A URL is reduced to its origin and
+// matches requests to that origin only; a pattern containing * is matched
+// against the whole request URL.
+func NewHeaderScope(origin string) (*HeaderScope, error) {
+	if strings.Contains(origin, "*") {
+		return &HeaderScope{
+			Origin: origin,
+			match:  func(u string) bool { return matchesPattern(u, origin) },
+		}, nil
+	}
+	o, err := URLOrigin(origin)
+	if err != nil {
+		return nil, err
+	}
+	return &HeaderScope{
+		Origin: o,
+		match: func(u string) bool {
+			reqOrigin, err := URLOrigin(u)
+			return err == nil && reqOrigin == o
+		},
+	}, nil
+}
+
+// Matches reports whether a request URL is in scope.
+func (s *HeaderScope) Matches(url string) bool {
+	return s.match(url)
+}
+
+// URLOrigin returns the scheme://host[:port] origin of an http(s) URL.
+func URLOrigin(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("%q is not an http(s) origin", rawURL)
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host), nil
+}
+
+// InterceptRule describes how to handle requests whose URL matches Pattern.
+type InterceptRule struct {
+	Pattern string // substring, glob (with *), or regex when Regex is set
+	Regex   bool
+	Action  string            // "block" or "fulfill"
+	Status  int               // fulfill only (default 200)
+	Headers map[string]string // fulfill only
+	Body    string            // fulfill only
+
+	match func(string) bool
+}
+
+// NewInterceptRule builds an InterceptRule from command params:
+// pattern, regex, action, status, headers, body.
+func NewInterceptRule(params map[string]interface{}) (*InterceptRule, error) {
+	rule := &InterceptRule{}
+	rule.Pattern, _ = params["pattern"].(string)
+	if rule.Pattern == "" {
+		return nil, fmt.Errorf("pattern is required")
+	}
+	rule.Regex, _ = params["regex"].(bool)
+	match, err := URLMatcher(rule.Pattern, rule.Regex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %q: %w", rule.Pattern, err)
+	}
+	rule.match = match
+
+	rule.Action, _ = params["action"].(string)
+	switch rule.Action {
+	case "block", "fulfill":
+	case "", "abort":
+		// abort is an alias of block
+		rule.Action = "block"
+	default:
+		return nil, fmt.Errorf("unknown action %q (use block, abort, or fulfill)", rule.Action)
+	}
+
+	rule.Status = 200
+	if status, ok := params["status"].(float64); ok {
+		if status < 100 || status > 599 {
+			return nil, fmt.Errorf("status must be between 100 and 599, got %g", status)
+		}
+		rule.Status = int(status)
+	}
+	if headers, ok := params["headers"].(map[string]interface{}); ok {
+		rule.Headers = make(map[string]string, len(headers))
+		for name, val := range headers {
+			rule.Headers[name] = fmt.Sprintf("%v", val)
+		}
+	}
+	rule.Body, _ = params["body"].(string)
+	return rule, nil
+}
+
+// Matches reports whether the rule applies to a request URL.
+func (rule *InterceptRule) Matches(url string) bool {
+	return rule.match(url)
+}
+
+// Resolve returns the BiDi command that completes a request blocked in the
+// beforeRequestSent phase according to the rule. A nil rule continues the
+// request unchanged. block fails the request with a network error; fulfill
+// answers it with the rule's status, headers, and body.
+func (rule *InterceptRule) Resolve(requestID string) (string, map[string]interface{}) {
+	params := map[string]interface{}{"request": requestID}
+	if rule == nil {
+		return "network.continueRequest", params
+	}
+	switch rule.Action {
+	case "fulfill":
+		params["statusCode"] = rule.Status
+		params["body"] = map[string]interface{}{
+			"type":  "string",
+			"value": rule.Body,
+		}
+		if len(rule.Headers) > 0 {
+			headers := make(map[string]interface{}, len(rule.Headers))
+			for name, val := range rule.Headers {
+				headers[name] = val
+			}
+			params["headers"] = convertHeadersToBidi(headers)
+		}
+		return "network.provideResponse", params
+	default:
+		return "network.failRequest", params
+	}
+}
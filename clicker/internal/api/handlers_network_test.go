@@ -0,0 +1,68 @@
+package api
+
+import "testing"
+
+func TestHeaderScope(t *testing.T) {
+	tests := []struct {
+		origin string
+		url    string
+		want   bool
+	}{
+		{"https://example.com", "https://example.com/login", true},
+		{"https://example.com/some/page?q=1", "https://example.com/api", true},
+		{"https://example.com", "https://EXAMPLE.com/", true},
+		{"https://example.com", "http://example.com/", false},
+		{"https://example.com", "https://example.com:8443/", false},
+		{"https://example.com", "https://cdn.example.com/", false},
+		{"https://example.com", "https://evil.com/?https://example.com", false},
+		{"https://*.example.com/*", "https://cdn.example.com/lib.js", true},
+		{"https://*.example.com/*", "https://example.org/", false},
+		{"*", "https://anywhere.net/", true},
+	}
+	for _, tt := range tests {
+		scope, err := NewHeaderScope(tt.origin)
+		if err != nil {
+			t.Fatalf("NewHeaderScope(%q): %v", tt.origin, err)
+		}
+		if got := scope.Matches(tt.url); got != tt.want {
+			t.Errorf("NewHeaderScope(%q).Matches(%q) = %v, want %v", tt.origin, tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestHeaderScopeRejectsNonHTTPOrigin(t *testing.T) {
+	for _, origin := range []string{"about:blank", "example.com", "file:///tmp/x"} {
+		if _, err := NewHeaderScope(origin); err == nil {
+			t.Errorf("NewHeaderScope(%q) should fail", origin)
+		}
+	}
+}
+
+func TestNewInterceptRuleActions(t *testing.T) {
+	tests := []struct {
+		action     string
+		wantAction string
+		wantMethod string
+	}{
+		{"", "block", "network.failRequest"},
+		{"block", "block", "network.failRequest"},
+		{"abort", "block", "network.failRequest"},
+		{"fulfill", "fulfill", "network.provideResponse"},
+	}
+	for _, tt := range tests {
+		rule, err := NewInterceptRule(map[string]interface{}{"pattern": "*/ads/*", "action": tt.action})
+		if err != nil {
+			t.Fatalf("action %q: %v", tt.action, err)
+		}
+		if rule.Action != tt.wantAction {
+			t.Errorf("action %q: Action = %q, want %q", tt.action, rule.Action, tt.wantAction)
+		}
+		if method, _ := rule.Resolve("req-1"); method != tt.wantMethod {
+			t.Errorf("action %q: Resolve = %s, want %s", tt.action, method, tt.wantMethod)
+		}
+	}
+
+	if _, err := NewInterceptRule(map[string]interface{}{"pattern": "*", "action": "drop"}); err == nil {
+		t.Error("unknown action should be an error")
+	}
+}
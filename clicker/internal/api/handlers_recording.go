@@ -8,9 +8,13 @@ import (
 )
 
 // handleRecordingStart handles vibium:recording.start — starts recording.
-// Options: name, screenshots, snapshots, sources, title.
+// Options: name, screenshots, snapshots, sources, title, intervalMs.
 func (r *Router) handleRecordingStart(session *BrowserSession, cmd bidiCommand) {
 	opts := ParseRecordingOptions(cmd.Params)
+	if err := opts.Validate(); err != nil {
+		r.sendError(session, cmd.ID, err)
+		return
+	}
 
 	// Create and start the recorder
 	recorder := NewRecorder()
@@ -20,7 +24,13 @@ func (r *Router) handleRecordingStart(session *BrowserSession, cmd bidiCommand)
 	session.recorder = recorder
 	session.mu.Unlock()
 
-	// Screenshots are captured per-action in dispatch(), not via a background loop.
+	// Screenshots are captured per-action in dispatch(); intervalMs adds
+	// periodic frames in between.
+	if opts.Screenshots {
+		recorder.StartScreenshotLoop(func() (string, string, error) {
+			return r.captureScreenshotForRecording(session, opts)
+		})
+	}
 
 	r.sendSuccess(session, cmd.ID, map[string]interface{}{})
 }
@@ -41,7 +51,8 @@ func (r *Router) handleRecordingStop(session *BrowserSession, cmd bidiCommand) {
 		return
 	}
 
-	// Stop recording and get zip data
+	// Stop the periodic screenshot loop, then stop recording and get zip data
+	recorder.StopScreenshots()
 	zipData, err := recorder.Stop()
 	if err != nil {
 		r.sendError(session, cmd.ID, err)
@@ -180,6 +191,33 @@ func ScreenshotParams(context string, opts RecordingStartOptions) map[string]int
 	return params
 }
 
+// CaptureRecordingScreenshotData takes a screenshot via the Session for the
+// recorder's periodic screenshot loop. Returns (base64 image data, pageID, error).
+func CaptureRecordingScreenshotData(s Session, opts RecordingStartOptions) (string, string, error) {
+	context, err := s.GetContextID()
+	if err != nil {
+		return "", "", err
+	}
+
+	resp, err := s.SendBidiCommandWithTimeout("browsingContext.captureScreenshot", ScreenshotParams(context, opts), 5*time.Second)
+	if err != nil {
+		return "", "", err
+	}
+	if bidiErr := checkBidiError(resp); bidiErr != nil {
+		return "", "", bidiErr
+	}
+
+	var ssResult struct {
+		Result struct {
+			Data string `json:"data"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(resp, &ssResult); err != nil {
+		return "", "", fmt.Errorf("screenshot parse failed: %w", err)
+	}
+	return ssResult.Result.Data, context, nil
+}
+
 // captureScreenshotForRecording takes a screenshot via BiDi for the recorder.
 // Returns (base64 image data, pageID, error).
 func (r *Router) captureScreenshotForRecording(session *BrowserSession, opts RecordingStartOptions) (string, string, error) {
@@ -245,9 +283,8 @@ func (r *Router) captureBeforeSnapshotAfterScroll(session *BrowserSession, param
 	}
 }
 
-// captureActionSnapshot captures a screenshot and wraps it as a frame-snapshot
-// for the Record Player / Playwright trace viewer. Returns the snapshot name
-// (e.g. "before@call@1") or "" on failure.
+// captureActionSnapshot resolves the action's browsing context and captures
+// a frame-snapshot for it via CaptureActionSnapshot.
 func (r *Router) captureActionSnapshot(session *BrowserSession, recorder *Recorder, params map[string]interface{}, callId, snapshotType string) string {
 	session.mu.Lock()
 	closed := session.closed
@@ -271,9 +308,27 @@ func (r *Router) captureActionSnapshot(session *BrowserSession, recorder *Record
 		}
 	}
 
+	session.mu.Lock()
+	frameURL := session.lastURL
+	session.mu.Unlock()
+
+	return CaptureActionSnapshot(NewAPISession(r, session, context), recorder, callId, snapshotType, frameURL)
+}
+
+// CaptureActionSnapshot captures a screenshot of the session's context and
+// wraps it as a frame-snapshot for the Record Player / Playwright trace
+// viewer. This is the shared version used by both the proxy dispatch() and
+// MCP Call() paths. Returns the snapshot name (e.g. "before@call@1") or ""
+// on failure.
+func CaptureActionSnapshot(s Session, recorder *Recorder, callId, snapshotType, frameURL string) string {
+	context, err := s.GetContextID()
+	if err != nil {
+		return ""
+	}
+
 	// Capture screenshot via native BiDi command (no JS execution)
 	opts := recorder.Options()
-	resp, err := r.sendInternalCommandWithTimeout(session, "browsingContext.captureScreenshot", ScreenshotParams(context, opts), 2*time.Second)
+	resp, err := s.SendBidiCommandWithTimeout("browsingContext.captureScreenshot", ScreenshotParams(context, opts), 2*time.Second)
 	if err != nil {
 		return ""
 	}
@@ -341,10 +396,6 @@ func (r *Router) captureActionSnapshot(session *BrowserSession, recorder *Record
 		map[string]interface{}{"url": imgSrc, "sha1": hash},
 	}
 
-	session.mu.Lock()
-	frameURL := session.lastURL
-	session.mu.Unlock()
-
 	return recorder.AddFrameSnapshot(callId, snapshotType, context, frameURL, "html", html, viewport, resourceOverrides)
 }
 
@@ -7,7 +7,8 @@ import (
 	"time"
 )
 
-// handleVibiumElText handles vibium:element.text — returns element.textContent.
+// handleVibiumElText handles vibium:element.text — returns the element's
+// visible text (innerText).
 func (r *Router) handleVibiumElText(session *BrowserSession, cmd bidiCommand) {
 	ep := ExtractElementParams(cmd.Params)
 	context, err := r.resolveContext(session, cmd.Params)
@@ -97,9 +98,9 @@ func (r *Router) handleVibiumElAttr(session *BrowserSession, cmd bidiCommand) {
 		args = append(args, map[string]interface{}{"type": "string", "value": name})
 		script = `
 			(scope, selector, role, text, label, placeholder, alt, title, testid, xpath, index, hasIndex, name) => {
-				const root = scope ? document.querySelector(scope) : document;
+				const root = resolveScope(scope);
 				if (!root) return JSON.stringify({error: 'root not found'});
-		` + semanticMatchesHelper() + `
+		` + semanticMatchesHelper(ep) + `
 				const found = collectMatches(root, selector, role, text, label, placeholder, alt, title, testid, xpath);
 				let el;
 				if (hasIndex) {
@@ -117,13 +118,14 @@ func (r *Router) handleVibiumElAttr(session *BrowserSession, cmd bidiCommand) {
 		args = append(args, map[string]interface{}{"type": "string", "value": name})
 		script = `
 			(scope, selector, index, hasIndex, name) => {
-				const root = scope ? document.querySelector(scope) : document;
+				const root = resolveScope(scope);
 				if (!root) return JSON.stringify({error: 'root not found'});
+			` + DeepQueryJS() + `
 				let el;
 				if (hasIndex) {
-					el = root.querySelectorAll(selector)[index];
+					el = queryAllDeep(root, selector)[index];
 				} else {
-					el = root.querySelector(selector);
+					el = queryDeep(root, selector);
 				}
 				if (!el) return JSON.stringify({error: 'element not found'});
 				const v = el.getAttribute(name);
@@ -165,6 +167,12 @@ func (r *Router) handleVibiumElAttr(session *BrowserSession, cmd bidiCommand) {
 	r.sendSuccess(session, cmd.ID, map[string]interface{}{"value": result.Value})
 }
 
+// boundsScriptBody reads an element's bounding box as JSON.
+const boundsScriptBody = `
+	const rect = el.getBoundingClientRect();
+	return JSON.stringify({x: rect.x, y: rect.y, width: rect.width, height: rect.height});
+`
+
 // handleVibiumElBounds handles vibium:element.bounds — returns getBoundingClientRect().
 func (r *Router) handleVibiumElBounds(session *BrowserSession, cmd bidiCommand) {
 	ep := ExtractElementParams(cmd.Params)
@@ -174,10 +182,7 @@ func (r *Router) handleVibiumElBounds(session *BrowserSession, cmd bidiCommand)
 		return
 	}
 
-	script, args := buildElJSONScript(ep, `
-		const rect = el.getBoundingClientRect();
-		return JSON.stringify({x: rect.x, y: rect.y, width: rect.width, height: rect.height});
-	`)
+	script, args := buildElJSONScript(ep, boundsScriptBody)
 
 	resp, err := r.sendInternalCommand(session, "script.callFunction", map[string]interface{}{
 		"functionDeclaration": script,
@@ -259,6 +264,13 @@ func (r *Router) handleVibiumElIsHidden(session *BrowserSession, cmd bidiCommand
 	r.sendSuccess(session, cmd.ID, map[string]interface{}{"hidden": hidden})
 }
 
+// Element-state script bodies shared by the proxy handlers and the exported
+// helpers below.
+const (
+	enabledScriptBody  = `return !el.disabled;`
+	editableScriptBody = `return !el.disabled && !el.readOnly;`
+)
+
 // handleVibiumElIsEnabled handles vibium:element.isEnabled — checks !element.disabled.
 func (r *Router) handleVibiumElIsEnabled(session *BrowserSession, cmd bidiCommand) {
 	ep := ExtractElementParams(cmd.Params)
@@ -268,7 +280,7 @@ func (r *Router) handleVibiumElIsEnabled(session *BrowserSession, cmd bidiComman
 		return
 	}
 
-	script, args := buildElBoolScript(ep, `return !el.disabled;`)
+	script, args := buildElBoolScript(ep, enabledScriptBody)
 	enabled, err := r.evalBoolScript(session, context, script, args)
 	if err != nil {
 		r.sendError(session, cmd.ID, err)
@@ -304,7 +316,7 @@ func (r *Router) handleVibiumElIsEditable(session *BrowserSession, cmd bidiComma
 		return
 	}
 
-	script, args := buildElBoolScript(ep, `return !el.disabled && !el.readOnly;`)
+	script, args := buildElBoolScript(ep, editableScriptBody)
 	editable, err := r.evalBoolScript(session, context, script, args)
 	if err != nil {
 		r.sendError(session, cmd.ID, err)
@@ -322,47 +334,13 @@ func (r *Router) handleVibiumElScreenshot(session *BrowserSession, cmd bidiComma
 		return
 	}
 
-	// Resolve element to get bounding box (also scrolls into view)
-	info, err := r.resolveElement(session, context, ep)
+	data, err := ElementScreenshot(NewAPISession(r, session, context), context, ep)
 	if err != nil {
 		r.sendError(session, cmd.ID, err)
 		return
 	}
 
-	// Use browsingContext.captureScreenshot with clip
-	clipParams := map[string]interface{}{
-		"context": context,
-		"clip": map[string]interface{}{
-			"type":   "box",
-			"x":      info.Box.X,
-			"y":      info.Box.Y,
-			"width":  info.Box.Width,
-			"height": info.Box.Height,
-		},
-	}
-
-	resp, err := r.sendInternalCommand(session, "browsingContext.captureScreenshot", clipParams)
-	if err != nil {
-		r.sendError(session, cmd.ID, err)
-		return
-	}
-
-	if bidiErr := checkBidiError(resp); bidiErr != nil {
-		r.sendError(session, cmd.ID, bidiErr)
-		return
-	}
-
-	var ssResult struct {
-		Result struct {
-			Data string `json:"data"`
-		} `json:"result"`
-	}
-	if err := json.Unmarshal(resp, &ssResult); err != nil {
-		r.sendError(session, cmd.ID, fmt.Errorf("screenshot parse failed: %w", err))
-		return
-	}
-
-	r.sendSuccess(session, cmd.ID, map[string]interface{}{"data": ssResult.Result.Data})
+	r.sendSuccess(session, cmd.ID, map[string]interface{}{"data": data})
 }
 
 // handleVibiumElWaitFor handles vibium:element.waitFor — waits for element state.
@@ -589,14 +567,15 @@ func buildElSemanticArgs(ep ElementParams) []map[string]interface{} {
 
 // buildElStateScript builds a script that finds an element and evaluates an expression.
 // The expression receives `el` as the found element and should return a string.
+// Arguments appended to the returned args reach the expression as `extra`.
 func buildElStateScript(ep ElementParams, expr string) (string, []map[string]interface{}) {
 	if hasSemantic(ep) {
 		args := buildElSemanticArgs(ep)
 		script := fmt.Sprintf(`
-			(scope, selector, role, text, label, placeholder, alt, title, testid, xpath, index, hasIndex) => {
-				const root = scope ? document.querySelector(scope) : document;
+			(scope, selector, role, text, label, placeholder, alt, title, testid, xpath, index, hasIndex, ...extra) => {
+				const root = resolveScope(scope);
 				if (!root) return null;
-		`+semanticMatchesHelper()+`
+		`+semanticMatchesHelper(ep)+`
 				const found = collectMatches(root, selector, role, text, label, placeholder, alt, title, testid, xpath);
 				let el;
 				if (hasIndex) {
@@ -613,14 +592,15 @@ func buildElStateScript(ep ElementParams, expr string) (string, []map[string]int
 
 	args := buildElBaseArgs(ep)
 	script := fmt.Sprintf(`
-		(scope, selector, index, hasIndex) => {
-			const root = scope ? document.querySelector(scope) : document;
+		(scope, selector, index, hasIndex, ...extra) => {
+			const root = resolveScope(scope);
 			if (!root) return null;
+		`+DeepQueryJS()+`
 			let el;
 			if (hasIndex) {
-				el = root.querySelectorAll(selector)[index];
+				el = queryAllDeep(root, selector)[index];
 			} else {
-				el = root.querySelector(selector);
+				el = queryDeep(root, selector);
 			}
 			if (!el) return null;
 			return %s;
@@ -636,9 +616,9 @@ func buildElBoolScript(ep ElementParams, body string) (string, []map[string]inte
 		args := buildElSemanticArgs(ep)
 		script := fmt.Sprintf(`
 			(scope, selector, role, text, label, placeholder, alt, title, testid, xpath, index, hasIndex) => {
-				const root = scope ? document.querySelector(scope) : document;
+				const root = resolveScope(scope);
 				if (!root) return 'error:root not found';
-		`+semanticMatchesHelper()+`
+		`+semanticMatchesHelper(ep)+`
 				const found = collectMatches(root, selector, role, text, label, placeholder, alt, title, testid, xpath);
 				let el;
 				if (hasIndex) {
@@ -657,13 +637,14 @@ func buildElBoolScript(ep ElementParams, body string) (string, []map[string]inte
 	args := buildElBaseArgs(ep)
 	script := fmt.Sprintf(`
 		(scope, selector, index, hasIndex) => {
-			const root = scope ? document.querySelector(scope) : document;
+			const root = resolveScope(scope);
 			if (!root) return 'error:root not found';
+		`+DeepQueryJS()+`
 			let el;
 			if (hasIndex) {
-				el = root.querySelectorAll(selector)[index];
+				el = queryAllDeep(root, selector)[index];
 			} else {
-				el = root.querySelector(selector);
+				el = queryDeep(root, selector);
 			}
 			if (!el) return 'error:element not found';
 			const _check = (el) => { %s };
@@ -680,9 +661,9 @@ func buildElJSONScript(ep ElementParams, body string) (string, []map[string]inte
 		args := buildElSemanticArgs(ep)
 		script := fmt.Sprintf(`
 			(scope, selector, role, text, label, placeholder, alt, title, testid, xpath, index, hasIndex) => {
-				const root = scope ? document.querySelector(scope) : document;
+				const root = resolveScope(scope);
 				if (!root) return JSON.stringify({error: 'root not found'});
-		`+semanticMatchesHelper()+`
+		`+semanticMatchesHelper(ep)+`
 				const found = collectMatches(root, selector, role, text, label, placeholder, alt, title, testid, xpath);
 				let el;
 				if (hasIndex) {
@@ -700,13 +681,14 @@ func buildElJSONScript(ep ElementParams, body string) (string, []map[string]inte
 	args := buildElBaseArgs(ep)
 	script := fmt.Sprintf(`
 		(scope, selector, index, hasIndex) => {
-			const root = scope ? document.querySelector(scope) : document;
+			const root = resolveScope(scope);
 			if (!root) return JSON.stringify({error: 'root not found'});
+		`+DeepQueryJS()+`
 			let el;
 			if (hasIndex) {
-				el = root.querySelectorAll(selector)[index];
+				el = queryAllDeep(root, selector)[index];
 			} else {
-				el = root.querySelector(selector);
+				el = queryDeep(root, selector);
 			}
 			if (!el) return JSON.stringify({error: 'element not found'});
 			%s
@@ -777,12 +759,60 @@ func GetText(s Session, context string, ep ElementParams) (string, error) {
 	return EvalElementScript(s, context, script, args)
 }
 
+// GetTextContent returns the textContent of an element, including text
+// hidden by CSS and the contents of script and style elements.
+func GetTextContent(s Session, context string, ep ElementParams) (string, error) {
+	script, args := buildElStateScript(ep, `(el.textContent || '').trim()`)
+	return EvalElementScript(s, context, script, args)
+}
+
 // GetInnerText returns the innerText of an element.
 func GetInnerText(s Session, context string, ep ElementParams) (string, error) {
 	script, args := buildElStateScript(ep, `(el.innerText || '').trim()`)
 	return EvalElementScript(s, context, script, args)
 }
 
+// GetAllInnerText returns the innerText of every element matching selector.
+// Returns an empty slice when nothing matches.
+func GetAllInnerText(s Session, context, selector string) ([]string, error) {
+	return getAllElementText(s, context, selector, "innerText")
+}
+
+// GetAllTextContent returns the textContent of every element matching
+// selector. Returns an empty slice when nothing matches.
+func GetAllTextContent(s Session, context, selector string) ([]string, error) {
+	return getAllElementText(s, context, selector, "textContent")
+}
+
+// getAllElementText reads a text property (innerText or textContent) from
+// every element matching selector.
+func getAllElementText(s Session, context, selector, prop string) ([]string, error) {
+	script := `(selector, prop) => {
+		` + DeepQueryJS() + `
+		return JSON.stringify(queryAllDeep(document, selector).map(el => (el[prop] || '').trim()));
+	}`
+	resp, err := CallScript(s, context, script, []map[string]interface{}{
+		{"type": "string", "value": selector},
+		{"type": "string", "value": prop},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if bidiErr := checkBidiError(resp); bidiErr != nil {
+		return nil, bidiErr
+	}
+	val, err := parseScriptResult(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	texts := []string{}
+	if err := json.Unmarshal([]byte(val), &texts); err != nil {
+		return nil, fmt.Errorf("failed to parse text list: %w", err)
+	}
+	return texts, nil
+}
+
 // GetInnerHTML returns the innerHTML of an element.
 func GetInnerHTML(s Session, context string, ep ElementParams) (string, error) {
 	script, args := buildElStateScript(ep, `el.innerHTML`)
@@ -795,6 +825,118 @@ func GetOuterHTML(s Session, context string, ep ElementParams) (string, error) {
 	return EvalElementScript(s, context, script, args)
 }
 
+// ElementContent holds an element's markup and rendered text.
+type ElementContent struct {
+	OuterHTML string `json:"outerHTML"`
+	InnerText string `json:"innerText"`
+}
+
+// GetHTMLAndText returns the outerHTML and innerText of an element in one call.
+func GetHTMLAndText(s Session, context string, ep ElementParams) (*ElementContent, error) {
+	script, args := buildElStateScript(ep, `JSON.stringify({outerHTML: el.outerHTML, innerText: (el.innerText || '').trim()})`)
+	val, err := EvalElementScript(s, context, script, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var content ElementContent
+	if err := json.Unmarshal([]byte(val), &content); err != nil {
+		return nil, fmt.Errorf("failed to parse element content: %w", err)
+	}
+	return &content, nil
+}
+
+// TableData is the cell text of an HTML table laid out as a grid. Cells
+// spanning several rows or columns repeat their text in every slot they
+// cover. HeaderRows counts the leading rows that are headers (the <thead>
+// rows, or a first row made only of <th> cells).
+type TableData struct {
+	Rows       [][]string `json:"rows"`
+	HeaderRows int        `json:"headerRows"`
+}
+
+// GetTable reads a <table> — the element itself or the first table inside
+// it — into a grid of trimmed cell text.
+func GetTable(s Session, context string, ep ElementParams) (*TableData, error) {
+	script, args := buildElJSONScript(ep, `
+		const table = el.tagName === 'TABLE' ? el : el.querySelector('table');
+		if (!table) return JSON.stringify({error: 'no <table> found in element'});
+
+		const trs = Array.from(table.rows);
+		const grid = trs.map(() => []);
+		trs.forEach((tr, r) => {
+			let c = 0;
+			for (const cell of tr.cells) {
+				while (grid[r][c] !== undefined) c++;
+				const text = (cell.innerText || '').trim();
+				const rowSpan = cell.rowSpan > 0 ? cell.rowSpan : trs.length - r;
+				const colSpan = Math.max(1, cell.colSpan);
+				for (let i = 0; i < rowSpan && r + i < trs.length; i++) {
+					for (let j = 0; j < colSpan; j++) grid[r + i][c + j] = text;
+				}
+				c += colSpan;
+			}
+		});
+		const width = Math.max(0, ...grid.map(row => row.length));
+		const rows = grid.map(row => Array.from({length: width}, (_, i) => row[i] === undefined ? '' : row[i]));
+
+		let headerRows = table.tHead ? table.tHead.rows.length : 0;
+		if (headerRows === 0 && trs.length > 0 && trs[0].cells.length > 0 &&
+			Array.from(trs[0].cells).every(cell => cell.tagName === 'TH')) {
+			headerRows = 1;
+		}
+		return JSON.stringify({rows, headerRows});
+	`)
+	val, err := EvalElementScript(s, context, script, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		TableData
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(val), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse table: %w", err)
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	if result.Rows == nil {
+		result.Rows = [][]string{}
+	}
+	return &result.TableData, nil
+}
+
+// Records returns the non-header rows as objects keyed by the text of the
+// last header row. Columns without header text are keyed "column N"
+// (1-based). Returns an error if the table has no header row.
+func (t *TableData) Records() ([]map[string]string, error) {
+	if t.HeaderRows == 0 {
+		return nil, fmt.Errorf("table has no header row (no <thead> or leading row of <th> cells)")
+	}
+	header := t.Rows[t.HeaderRows-1]
+	keys := make([]string, len(header))
+	for i, h := range header {
+		keys[i] = h
+		if h == "" {
+			keys[i] = fmt.Sprintf("column %d", i+1)
+		}
+	}
+
+	records := []map[string]string{}
+	for _, row := range t.Rows[t.HeaderRows:] {
+		record := make(map[string]string, len(keys))
+		for i, key := range keys {
+			if i < len(row) {
+				record[key] = row[i]
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
 // GetValue returns the value property of a form element.
 func GetValue(s Session, context string, ep ElementParams) (string, error) {
 	script, args := buildElStateScript(ep, `el.value || ''`)
@@ -811,9 +953,9 @@ func GetAttribute(s Session, context string, ep ElementParams, name string) (str
 		args = append(args, map[string]interface{}{"type": "string", "value": name})
 		script = `
 			(scope, selector, role, text, label, placeholder, alt, title, testid, xpath, index, hasIndex, name) => {
-				const root = scope ? document.querySelector(scope) : document;
+				const root = resolveScope(scope);
 				if (!root) return null;
-		` + semanticMatchesHelper() + `
+		` + semanticMatchesHelper(ep) + `
 				const found = collectMatches(root, selector, role, text, label, placeholder, alt, title, testid, xpath);
 				let el;
 				if (hasIndex) {
@@ -831,13 +973,14 @@ func GetAttribute(s Session, context string, ep ElementParams, name string) (str
 		args = append(args, map[string]interface{}{"type": "string", "value": name})
 		script = `
 			(scope, selector, index, hasIndex, name) => {
-				const root = scope ? document.querySelector(scope) : document;
+				const root = resolveScope(scope);
 				if (!root) return null;
+			` + DeepQueryJS() + `
 				let el;
 				if (hasIndex) {
-					el = root.querySelectorAll(selector)[index];
+					el = queryAllDeep(root, selector)[index];
 				} else {
-					el = root.querySelector(selector);
+					el = queryDeep(root, selector);
 				}
 				if (!el) return null;
 				const v = el.getAttribute(name);
@@ -849,6 +992,27 @@ func GetAttribute(s Session, context string, ep ElementParams, name string) (str
 	return EvalElementScript(s, context, script, args)
 }
 
+// GetAttributes returns several HTML attributes of an element in one call.
+// Attributes that are not present map to nil.
+func GetAttributes(s Session, context string, ep ElementParams, names []string) (map[string]*string, error) {
+	nameValues := make([]interface{}, len(names))
+	for i, name := range names {
+		nameValues[i] = name
+	}
+	script, args := buildElStateScript(ep, `JSON.stringify(Object.fromEntries(extra[0].map(n => [n, el.getAttribute(n)])))`)
+	args = append(args, serializeLocalValue(nameValues))
+	val, err := EvalElementScript(s, context, script, args)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := map[string]*string{}
+	if err := json.Unmarshal([]byte(val), &attrs); err != nil {
+		return nil, fmt.Errorf("failed to parse attributes: %w", err)
+	}
+	return attrs, nil
+}
+
 // IsVisible checks if an element is visible (not hidden, not zero-size).
 func IsVisible(s Session, context string, ep ElementParams) (bool, error) {
 	script, args := buildElBoolScript(ep, `
@@ -864,17 +1028,48 @@ func IsVisible(s Session, context string, ep ElementParams) (bool, error) {
 
 // IsEnabled checks if an element is enabled (!disabled).
 func IsEnabled(s Session, context string, ep ElementParams) (bool, error) {
-	script, args := buildElBoolScript(ep, `return !el.disabled;`)
+	script, args := buildElBoolScript(ep, enabledScriptBody)
 	return EvalBoolScript(s, context, script, args)
 }
 
-// GetCount counts elements matching a CSS selector.
-func GetCount(s Session, context, selector string) (int, error) {
-	expr := fmt.Sprintf(`() => document.querySelectorAll(%q).length`, selector)
-	val, err := EvalSimpleScript(s, context, expr)
+// IsEditable checks if an element is editable (!disabled && !readOnly).
+func IsEditable(s Session, context string, ep ElementParams) (bool, error) {
+	script, args := buildElBoolScript(ep, editableScriptBody)
+	return EvalBoolScript(s, context, script, args)
+}
+
+// GetCount counts elements matching a CSS selector (or XPath, see
+// ParseXPathSelector), within the element matching scope when scope is set.
+func GetCount(s Session, context, scope, selector string) (int, error) {
+	xpath, _ := ParseXPathSelector(selector)
+	script := `(scope, selector, xpath) => {
+		` + DeepQueryJS() + `
+		const root = scope ? queryDeep(document, scope) : document;
+		if (!root) return 'error:scope not found';
+		if (xpath) {
+			const xr = document.evaluate(xpath, root, null, XPathResult.ORDERED_NODE_SNAPSHOT_TYPE, null);
+			return String(xr.snapshotLength);
+		}
+		return String(queryAllDeep(root, selector).length);
+	}`
+	resp, err := CallScript(s, context, script, []map[string]interface{}{
+		{"type": "string", "value": scope},
+		{"type": "string", "value": selector},
+		{"type": "string", "value": xpath},
+	})
 	if err != nil {
 		return 0, err
 	}
+	if bidiErr := checkBidiError(resp); bidiErr != nil {
+		return 0, bidiErr
+	}
+	val, err := parseScriptResult(resp)
+	if err != nil {
+		return 0, err
+	}
+	if msg, ok := strings.CutPrefix(val, "error:"); ok {
+		return 0, fmt.Errorf("%s: %q", msg, scope)
+	}
 	var count int
 	if _, err := fmt.Sscanf(val, "%d", &count); err != nil {
 		return 0, fmt.Errorf("failed to parse count: %w", err)
@@ -951,6 +1146,69 @@ func ResolveElementNoWait(s Session, context string, ep ElementParams) (*Element
 	return &info, nil
 }
 
+// GetBounds returns the element's bounding box (getBoundingClientRect).
+func GetBounds(s Session, context string, ep ElementParams) (*BoxInfo, error) {
+	script, args := buildElJSONScript(ep, boundsScriptBody)
+	val, err := EvalElementScript(s, context, script, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		BoxInfo
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(val), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse bounds: %w", err)
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return &result.BoxInfo, nil
+}
+
+// DefaultStablePolls is the number of consecutive unchanged samples
+// WaitForStable requires when no count is given.
+const DefaultStablePolls = 2
+
+// WaitForStable polls the element's bounding box every 100ms until its
+// position and size are unchanged for polls consecutive samples, or times out.
+// Use it to wait out animations and transitions before interacting.
+func WaitForStable(s Session, context string, ep ElementParams, polls int) error {
+	if polls < 1 {
+		polls = DefaultStablePolls
+	}
+	deadline := time.Now().Add(ep.Timeout)
+	interval := 100 * time.Millisecond
+
+	var last *BoxInfo
+	unchanged := 0
+	for {
+		box, err := GetBounds(s, context, ep)
+		if err != nil {
+			last, unchanged = nil, 0
+		} else {
+			if last != nil && *box == *last {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+			last = box
+			if unchanged >= polls {
+				return nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			if last == nil {
+				return fmt.Errorf("timeout after %s: element not found", ep.Timeout)
+			}
+			return fmt.Errorf("timeout after %s: element still moving", ep.Timeout)
+		}
+		time.Sleep(interval)
+	}
+}
+
 // WaitForVisible polls until the element exists and is visible, or times out.
 func WaitForVisible(s Session, context string, ep ElementParams) error {
 	deadline := time.Now().Add(ep.Timeout)
@@ -995,6 +1253,39 @@ func WaitForHidden(s Session, context string, ep ElementParams) error {
 	}
 }
 
+// WaitForEnabled polls until the element exists and is not disabled, or times out.
+func WaitForEnabled(s Session, context string, ep ElementParams) error {
+	return waitForElementState(s, context, ep, IsEnabled, "enabled")
+}
+
+// WaitForEditable polls until the element exists and is neither disabled nor
+// read-only, or times out.
+func WaitForEditable(s Session, context string, ep ElementParams) error {
+	return waitForElementState(s, context, ep, IsEditable, "editable")
+}
+
+// waitForElementState polls a boolean state check every 100ms until it
+// reports true, or times out.
+func waitForElementState(s Session, context string, ep ElementParams, check func(Session, string, ElementParams) (bool, error), state string) error {
+	deadline := time.Now().Add(ep.Timeout)
+	interval := 100 * time.Millisecond
+
+	for {
+		ok, err := check(s, context, ep)
+		if err == nil && ok {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			if err != nil {
+				return fmt.Errorf("timeout after %s: %w", ep.Timeout, err)
+			}
+			return fmt.Errorf("timeout after %s: element not %s", ep.Timeout, state)
+		}
+		time.Sleep(interval)
+	}
+}
+
 // --- Page-level evaluation handlers ---
 
 // handlePageEval handles vibium:page.eval — evaluates a JS expression and returns the result.
@@ -1032,6 +1323,24 @@ func (r *Router) handlePageEval(session *BrowserSession, cmd bidiCommand) {
 	r.sendSuccess(session, cmd.ID, map[string]interface{}{"value": value})
 }
 
+// Evaluate evaluates a JS expression in the context (awaiting promises) and
+// returns the result as plain Go values suitable for json.Marshal.
+func Evaluate(s Session, context, expression string) (interface{}, error) {
+	resp, err := s.SendBidiCommand("script.evaluate", map[string]interface{}{
+		"expression":      expression,
+		"target":          map[string]interface{}{"context": context},
+		"awaitPromise":    true,
+		"resultOwnership": "none",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if bidiErr := checkBidiError(resp); bidiErr != nil {
+		return nil, bidiErr
+	}
+	return deserializeEvaluateResult(resp)
+}
+
 // handlePageAddScript handles vibium:page.addScript — injects a <script> tag.
 // Accepts "url" (for external script) or "content" (for inline JS).
 func (r *Router) handlePageAddScript(session *BrowserSession, cmd bidiCommand) {
@@ -1180,6 +1489,58 @@ func (r *Router) handlePageExpose(session *BrowserSession, cmd bidiCommand) {
 	r.sendSuccess(session, cmd.ID, map[string]interface{}{"exposed": true})
 }
 
+// EvaluateFunction calls a JS function declaration with typed arguments via
+// script.callFunction (awaiting promises), so caller data never has to be
+// spliced into source code. Arguments are JSON-like Go values.
+func EvaluateFunction(s Session, context, fn string, args []interface{}) (interface{}, error) {
+	bidiArgs := make([]interface{}, len(args))
+	for i, arg := range args {
+		bidiArgs[i] = serializeLocalValue(arg)
+	}
+	resp, err := s.SendBidiCommand("script.callFunction", map[string]interface{}{
+		"functionDeclaration": fn,
+		"target":              map[string]interface{}{"context": context},
+		"arguments":           bidiArgs,
+		"awaitPromise":        true,
+		"resultOwnership":     "none",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if bidiErr := checkBidiError(resp); bidiErr != nil {
+		return nil, bidiErr
+	}
+	return deserializeEvaluateResult(resp)
+}
+
+// serializeLocalValue converts a JSON-like Go value into a BiDi LocalValue.
+func serializeLocalValue(v interface{}) map[string]interface{} {
+	switch val := v.(type) {
+	case nil:
+		return map[string]interface{}{"type": "null"}
+	case bool:
+		return map[string]interface{}{"type": "boolean", "value": val}
+	case float64, int:
+		return map[string]interface{}{"type": "number", "value": val}
+	case string:
+		return map[string]interface{}{"type": "string", "value": val}
+	case []interface{}:
+		items := make([]interface{}, len(val))
+		for i, item := range val {
+			items[i] = serializeLocalValue(item)
+		}
+		return map[string]interface{}{"type": "array", "value": items}
+	case map[string]interface{}:
+		pairs := make([]interface{}, 0, len(val))
+		for key, item := range val {
+			pairs = append(pairs, []interface{}{key, serializeLocalValue(item)})
+		}
+		return map[string]interface{}{"type": "object", "value": pairs}
+	default:
+		return map[string]interface{}{"type": "string", "value": fmt.Sprintf("%v", val)}
+	}
+}
+
 // deserializeScriptResult extracts a usable value from a BiDi script result.
 // Handles primitives (string, number, boolean, null, undefined) and objects/arrays.
 func deserializeScriptResult(resp json.RawMessage) (interface{}, error) {
@@ -1235,3 +1596,80 @@ func deserializeScriptResult(resp json.RawMessage) (interface{}, error) {
 		return r.Value, nil
 	}
 }
+
+// deserializeEvaluateResult is deserializeScriptResult for the agent's
+// Evaluate and EvaluateFunction: script exceptions become errors and nested
+// values are deserialized all the way down. vibium:page.eval keeps
+// deserializeScriptResult so its wire result doesn't change for clients.
+func deserializeEvaluateResult(resp json.RawMessage) (interface{}, error) {
+	var result struct {
+		Result struct {
+			Type             string                 `json:"type"`
+			Result           map[string]interface{} `json:"result"`
+			ExceptionDetails struct {
+				Text string `json:"text"`
+			} `json:"exceptionDetails"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse script result: %w", err)
+	}
+	if result.Result.Type == "exception" {
+		return nil, fmt.Errorf("script exception: %s", result.Result.ExceptionDetails.Text)
+	}
+	return deserializeRemoteValue(result.Result.Result), nil
+}
+
+// deserializeRemoteValue converts a BiDi RemoteValue into plain Go values
+// (nil, bool, float64, string, []interface{}, map[string]interface{}) that
+// encode cleanly as JSON. Special numbers (NaN, Infinity, -0) and bigints stay
+// strings; values with no JSON form (functions, nodes, windows, ...) become
+// "[type]".
+func deserializeRemoteValue(rv map[string]interface{}) interface{} {
+	typ, _ := rv["type"].(string)
+	switch typ {
+	case "", "null", "undefined":
+		return nil
+	case "string", "number", "boolean", "bigint", "date":
+		return rv["value"]
+	case "regexp":
+		if re, ok := rv["value"].(map[string]interface{}); ok {
+			pattern, _ := re["pattern"].(string)
+			flags, _ := re["flags"].(string)
+			return "/" + pattern + "/" + flags
+		}
+		return nil
+	case "array", "set":
+		// {type: "array", value: [{type, value}, ...]}
+		items, _ := rv["value"].([]interface{})
+		out := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			m, _ := item.(map[string]interface{})
+			out = append(out, deserializeRemoteValue(m))
+		}
+		return out
+	case "object", "map":
+		// {type: "object", value: [[key, {type, value}], ...]}; map keys may
+		// themselves be remote values.
+		pairs, _ := rv["value"].([]interface{})
+		out := make(map[string]interface{}, len(pairs))
+		for _, pair := range pairs {
+			kv, ok := pair.([]interface{})
+			if !ok || len(kv) != 2 {
+				continue
+			}
+			var key string
+			switch k := kv[0].(type) {
+			case string:
+				key = k
+			case map[string]interface{}:
+				key = fmt.Sprintf("%v", deserializeRemoteValue(k))
+			}
+			m, _ := kv[1].(map[string]interface{})
+			out[key] = deserializeRemoteValue(m)
+		}
+		return out
+	default:
+		return "[" + typ + "]"
+	}
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSession records BiDi commands and answers each with respond, or with
+// resp when respond is nil.
+type fakeSession struct {
+	method  string
+	params  map[string]interface{}
+	calls   []map[string]interface{}
+	resp    string
+	respond func(method string, params map[string]interface{}) string
+}
+
+func (f *fakeSession) SendBidiCommand(method string, params map[string]interface{}) (json.RawMessage, error) {
+	f.method, f.params = method, params
+	f.calls = append(f.calls, params)
+	if f.respond != nil {
+		return json.RawMessage(f.respond(method, params)), nil
+	}
+	return json.RawMessage(f.resp), nil
+}
+
+func (f *fakeSession) SendBidiCommandWithTimeout(method string, params map[string]interface{}, _ time.Duration) (json.RawMessage, error) {
+	return f.SendBidiCommand(method, params)
+}
+
+func (f *fakeSession) GetContextID() (string, error) { return "ctx", nil }
+
+func (f *fakeSession) SetLastElementBox(*BoxInfo) {}
+
+// nestedResult is script.evaluate returning {a: 1, b: [1, {c: "x"}]}.
+const nestedResult = `{"result":{"type":"success","result":{"type":"object","value":[
+	["a",{"type":"number","value":1}],
+	["b",{"type":"array","value":[{"type":"number","value":1},{"type":"object","value":[["c",{"type":"string","value":"x"}]]}]}]
+]}}}`
+
+const exceptionResult = `{"result":{"type":"exception","exceptionDetails":{"text":"ReferenceError: foo is not defined"},"result":{"type":"error"}}}`
+
+// vibium:page.eval results are part of the client wire protocol; nested
+// values and exceptions must come back exactly as they always have.
+func TestDeserializeScriptResultWireFormat(t *testing.T) {
+	got, err := deserializeScriptResult(json.RawMessage(nestedResult))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"a": float64(1),
+		"b": []interface{}{
+			map[string]interface{}{"type": "number", "value": float64(1)},
+			map[string]interface{}{"type": "object", "value": []interface{}{
+				[]interface{}{"c", map[string]interface{}{"type": "string", "value": "x"}},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("nested object = %#v, want %#v", got, want)
+	}
+
+	if _, err := deserializeScriptResult(json.RawMessage(exceptionResult)); err != nil {
+		t.Errorf("exception returned error %v, want the raw value", err)
+	}
+}
+
+func TestDeserializeEvaluateResult(t *testing.T) {
+	tests := []struct {
+		name string
+		resp string
+		want interface{}
+	}{
+		{"string", `{"result":{"type":"success","result":{"type":"string","value":"hi"}}}`, "hi"},
+		{"undefined", `{"result":{"type":"success","result":{"type":"undefined"}}}`, nil},
+		{"nested", nestedResult, map[string]interface{}{
+			"a": float64(1),
+			"b": []interface{}{float64(1), map[string]interface{}{"c": "x"}},
+		}},
+		{"regexp", `{"result":{"type":"success","result":{"type":"regexp","value":{"pattern":"a+","flags":"g"}}}}`, "/a+/g"},
+		{"node", `{"result":{"type":"success","result":{"type":"node","sharedId":"n1"}}}`, "[node]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := deserializeEvaluateResult(json.RawMessage(tt.resp))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := deserializeEvaluateResult(json.RawMessage(exceptionResult)); err == nil {
+		t.Error("exception should be returned as an error")
+	}
+}
+
+func TestGetAttributesPassesNamesAsArgument(t *testing.T) {
+	s := &fakeSession{resp: `{"result":{"result":{"type":"string","value":"{\"data-x\":\"1\",\"a'b\":null}"}}}`}
+	names := []string{"data-x", "a'b"}
+
+	attrs, err := GetAttributes(s, "ctx", ElementParams{Selector: "#el"}, names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs["data-x"] == nil || *attrs["data-x"] != "1" || attrs["a'b"] != nil {
+		t.Errorf("attrs = %v", attrs)
+	}
+
+	fn, _ := s.params["functionDeclaration"].(string)
+	if strings.Contains(fn, "data-x") || strings.Contains(fn, "a'b") {
+		t.Error("attribute names were spliced into the script")
+	}
+	args := s.params["arguments"].([]map[string]interface{})
+	want := serializeLocalValue([]interface{}{"data-x", "a'b"})
+	if got := args[len(args)-1]; !reflect.DeepEqual(got, want) {
+		t.Errorf("last argument = %v, want %v", got, want)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"sort"
 	"strings"
+
+	"github.com/vibium/clicker/internal/bidi"
 )
 
 // --- BiDi cookie types ---
@@ -487,6 +490,65 @@ type CookieInfo struct {
 	HTTPOnly bool    `json:"httpOnly"`
 	Secure   bool    `json:"secure"`
 	SameSite string  `json:"sameSite"`
+	Expiry   int64   `json:"expiry,omitempty"`
+}
+
+// CookieChange describes a cookie whose value changed between two snapshots.
+type CookieChange struct {
+	Name     string `json:"name"`
+	Domain   string `json:"domain"`
+	Path     string `json:"path"`
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+}
+
+// CookieDiff is the result of comparing two cookie snapshots.
+type CookieDiff struct {
+	Added   []CookieInfo   `json:"added"`
+	Removed []CookieInfo   `json:"removed"`
+	Changed []CookieChange `json:"changed"`
+}
+
+// DiffCookies compares two cookie snapshots. Cookies are matched by
+// name, domain, and path.
+func DiffCookies(before, after []CookieInfo) CookieDiff {
+	key := func(c CookieInfo) string {
+		return c.Name + "\x00" + c.Domain + "\x00" + c.Path
+	}
+
+	prev := make(map[string]CookieInfo, len(before))
+	for _, c := range before {
+		prev[key(c)] = c
+	}
+
+	diff := CookieDiff{
+		Added:   []CookieInfo{},
+		Removed: []CookieInfo{},
+		Changed: []CookieChange{},
+	}
+	seen := make(map[string]bool, len(after))
+	for _, c := range after {
+		k := key(c)
+		seen[k] = true
+		old, ok := prev[k]
+		if !ok {
+			diff.Added = append(diff.Added, c)
+		} else if old.Value != c.Value {
+			diff.Changed = append(diff.Changed, CookieChange{
+				Name:     c.Name,
+				Domain:   c.Domain,
+				Path:     c.Path,
+				OldValue: old.Value,
+				NewValue: c.Value,
+			})
+		}
+	}
+	for _, c := range before {
+		if !seen[key(c)] {
+			diff.Removed = append(diff.Removed, c)
+		}
+	}
+	return diff
 }
 
 // GetCookies returns cookies for the given browsing context.
@@ -526,26 +588,34 @@ func GetCookies(s Session, context string) ([]CookieInfo, error) {
 			HTTPOnly: c.HTTPOnly,
 			Secure:   c.Secure,
 			SameSite: c.SameSite,
+			Expiry:   parseCookieExpiry(c.Expiry),
 		})
 	}
 	return cookies, nil
 }
 
-// SetCookie sets a cookie in the given browsing context.
-func SetCookie(s Session, context, name, value, domain, path string) error {
-	cookieMap := map[string]interface{}{
-		"name":  name,
-		"value": map[string]interface{}{"type": "string", "value": value},
+// parseCookieExpiry returns the expiry in epoch seconds, or 0 for a
+// session cookie.
+func parseCookieExpiry(raw *json.RawMessage) int64 {
+	if raw == nil {
+		return 0
 	}
-	if domain != "" {
-		cookieMap["domain"] = domain
+	var expiry float64
+	if err := json.Unmarshal(*raw, &expiry); err != nil {
+		return 0
 	}
-	if path != "" {
-		cookieMap["path"] = path
+	return int64(expiry)
+}
+
+// SetCookie sets a cookie in the given browsing context. The cookie's
+// attributes are validated first (see bidi.Cookie.Normalize).
+func SetCookie(s Session, context string, cookie bidi.Cookie) error {
+	if err := cookie.Normalize(); err != nil {
+		return err
 	}
 
 	params := map[string]interface{}{
-		"cookie": cookieMap,
+		"cookie": cookie.PartialCookie(),
 		"partition": map[string]interface{}{
 			"type":    "context",
 			"context": context,
@@ -581,6 +651,617 @@ func DeleteCookies(s Session, context, name string) error {
 	return checkBidiError(resp)
 }
 
+// StorageState is a portable snapshot of cookies and per-origin storage in
+// the shape Playwright's storageState uses, so state files can be shared
+// with Playwright tooling.
+type StorageState struct {
+	Cookies []StateCookie `json:"cookies"`
+	Origins []OriginState `json:"origins"`
+}
+
+// StateCookie is a cookie in Playwright's storageState form. Expires is in
+// epoch seconds, -1 for a session cookie; SameSite is Strict, Lax, or None.
+type StateCookie struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	Domain   string  `json:"domain"`
+	Path     string  `json:"path"`
+	Expires  float64 `json:"expires"`
+	HTTPOnly bool    `json:"httpOnly"`
+	Secure   bool    `json:"secure"`
+	SameSite string  `json:"sameSite"`
+}
+
+// OriginState holds the Web Storage and IndexedDB contents for one origin.
+// SessionStorage is a vibium extension; Playwright ignores it.
+type OriginState struct {
+	Origin         string              `json:"origin"`
+	LocalStorage   []StorageItem       `json:"localStorage"`
+	SessionStorage []StorageItem       `json:"sessionStorage,omitempty"`
+	IndexedDB      []IndexedDBDatabase `json:"indexedDB,omitempty"`
+}
+
+// IndexedDBDatabase is a dumped IndexedDB database with its object stores.
+type IndexedDBDatabase struct {
+	Name    string           `json:"name"`
+	Version int              `json:"version"`
+	Stores  []IndexedDBStore `json:"stores"`
+}
+
+// IndexedDBStore is a dumped object store. KeyPath is a string, an array of
+// strings, or null for out-of-line keys.
+type IndexedDBStore struct {
+	Name          string            `json:"name"`
+	KeyPath       interface{}       `json:"keyPath"`
+	AutoIncrement bool              `json:"autoIncrement"`
+	Indexes       []IndexedDBIndex  `json:"indexes"`
+	Records       []IndexedDBRecord `json:"records"`
+}
+
+// IndexedDBIndex describes an index on an object store.
+type IndexedDBIndex struct {
+	Name       string      `json:"name"`
+	KeyPath    interface{} `json:"keyPath"`
+	Unique     bool        `json:"unique"`
+	MultiEntry bool        `json:"multiEntry"`
+}
+
+// IndexedDBRecord is one key/value pair. Values that aren't plain JSON
+// (Dates, binary data, Maps, Sets) are tagged with a "$type" field.
+type IndexedDBRecord struct {
+	Key   interface{} `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// StorageItem is a single Web Storage key/value pair.
+type StorageItem struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// idbCodecJS defines encodeIDB/decodeIDB, which convert IndexedDB keys and
+// values to and from JSON. Dates, binary data, Maps, Sets, undefined, and
+// non-finite numbers are tagged with "$type"; Blobs are not supported.
+const idbCodecJS = `
+	const toBase64 = (bytes) => {
+		let bin = '';
+		for (let i = 0; i < bytes.length; i += 0x8000) {
+			bin += String.fromCharCode.apply(null, bytes.subarray(i, i + 0x8000));
+		}
+		return btoa(bin);
+	};
+	const fromBase64 = (b64) => Uint8Array.from(atob(b64), (c) => c.charCodeAt(0));
+	const encodeIDB = (v) => {
+		if (v === undefined) return { $type: 'undefined' };
+		if (v === null || typeof v === 'boolean' || typeof v === 'string') return v;
+		if (typeof v === 'number') return Number.isFinite(v) ? v : { $type: 'number', value: String(v) };
+		if (typeof v === 'bigint') return { $type: 'bigint', value: v.toString() };
+		if (v instanceof Date) return { $type: 'date', value: v.toISOString() };
+		if (v instanceof ArrayBuffer) return { $type: 'bytes', value: toBase64(new Uint8Array(v)) };
+		if (ArrayBuffer.isView(v)) {
+			return { $type: 'bytes', ctor: v.constructor.name, value: toBase64(new Uint8Array(v.buffer, v.byteOffset, v.byteLength)) };
+		}
+		if (Array.isArray(v)) return v.map(encodeIDB);
+		if (v instanceof Map) return { $type: 'map', value: [...v].map(([k, x]) => [encodeIDB(k), encodeIDB(x)]) };
+		if (v instanceof Set) return { $type: 'set', value: [...v].map(encodeIDB) };
+		if (typeof Blob !== 'undefined' && v instanceof Blob) throw new Error('Blob values are not supported');
+		const out = {};
+		for (const k of Object.keys(v)) out[k] = encodeIDB(v[k]);
+		return Object.prototype.hasOwnProperty.call(v, '$type') ? { $type: 'object', value: out } : out;
+	};
+	const decodeIDB = (v) => {
+		if (v === null || typeof v !== 'object') return v;
+		if (Array.isArray(v)) return v.map(decodeIDB);
+		switch (v.$type) {
+			case 'undefined': return undefined;
+			case 'number': return Number(v.value);
+			case 'bigint': return BigInt(v.value);
+			case 'date': return new Date(v.value);
+			case 'bytes': {
+				const bytes = fromBase64(v.value);
+				if (!v.ctor) return bytes.buffer;
+				if (v.ctor === 'DataView') return new DataView(bytes.buffer);
+				const Ctor = globalThis[v.ctor];
+				return new Ctor(bytes.buffer, 0, bytes.byteLength / (Ctor.BYTES_PER_ELEMENT || 1));
+			}
+			case 'map': return new Map(v.value.map(([k, x]) => [decodeIDB(k), decodeIDB(x)]));
+			case 'set': return new Set(v.value.map(decodeIDB));
+			case 'object': v = v.value; break;
+		}
+		const out = {};
+		for (const k of Object.keys(v)) out[k] = decodeIDB(v[k]);
+		return out;
+	};
+	const idbRequest = (req) => new Promise((resolve, reject) => {
+		req.onsuccess = () => resolve(req.result);
+		req.onerror = () => reject(req.error);
+	});
+`
+
+// dumpStorageJS returns the page's origin with its localStorage and
+// sessionStorage entries as {name, value} arrays and, when withIDB is set,
+// every IndexedDB database with its stores, indexes, and records.
+const dumpStorageJS = `async (withIDB) => {` + idbCodecJS + `
+	const dump = (store) => {
+		const items = [];
+		for (let i = 0; i < store.length; i++) {
+			const name = store.key(i);
+			items.push({ name, value: store.getItem(name) });
+		}
+		return items;
+	};
+	const dumpIDB = async () => {
+		if (typeof indexedDB === 'undefined' || !indexedDB.databases) return [];
+		const databases = [];
+		for (const info of await indexedDB.databases()) {
+			const db = await idbRequest(indexedDB.open(info.name));
+			try {
+				const stores = [];
+				for (const storeName of db.objectStoreNames) {
+					const store = db.transaction(storeName, 'readonly').objectStore(storeName);
+					const keys = await idbRequest(store.getAllKeys());
+					const values = await idbRequest(store.getAll());
+					const indexes = [];
+					for (const indexName of store.indexNames) {
+						const index = store.index(indexName);
+						indexes.push({ name: index.name, keyPath: index.keyPath, unique: index.unique, multiEntry: index.multiEntry });
+					}
+					stores.push({
+						name: storeName,
+						keyPath: store.keyPath,
+						autoIncrement: store.autoIncrement,
+						indexes,
+						records: keys.map((key, i) => ({ key: encodeIDB(key), value: encodeIDB(values[i]) }))
+					});
+				}
+				databases.push({ name: db.name, version: db.version, stores });
+			} finally {
+				db.close();
+			}
+		}
+		return databases;
+	};
+	return JSON.stringify({
+		origin: location.origin,
+		localStorage: dump(localStorage),
+		sessionStorage: dump(sessionStorage),
+		indexedDB: withIDB ? await dumpIDB() : undefined
+	});
+}`
+
+// restoreStorageJS writes one origin's entries into the page's storage if
+// the page is on that origin. Returns whether it applied.
+//
+// An IndexedDB database whose version and schema match the dump is cleared
+// and refilled in place, which works while the page holds it open. Any
+// other database is deleted and recreated; that waits for the page to close
+// its connections and fails if it doesn't within a few seconds. Web Storage
+// is written only after every database is restored, so a failed restore
+// leaves it untouched.
+const restoreStorageJS = `async (stateJSON) => {` + idbCodecJS + `
+	const state = JSON.parse(stateJSON);
+	if (state.origin !== location.origin) return false;
+	const sameKeyPath = (a, b) => JSON.stringify(a) === JSON.stringify(b);
+	const sameSchema = (db, dump) => {
+		if (db.version !== dump.version || db.objectStoreNames.length !== dump.stores.length) return false;
+		if (dump.stores.length === 0) return true;
+		const tx = db.transaction(dump.stores.map((s) => s.name), 'readonly');
+		return dump.stores.every((s) => {
+			const store = tx.objectStore(s.name);
+			const indexes = s.indexes || [];
+			return sameKeyPath(store.keyPath, s.keyPath) && store.autoIncrement === s.autoIncrement &&
+				store.indexNames.length === indexes.length &&
+				indexes.every((i) => {
+					if (!store.indexNames.contains(i.name)) return false;
+					const index = store.index(i.name);
+					return sameKeyPath(index.keyPath, i.keyPath) && index.unique === i.unique && index.multiEntry === i.multiEntry;
+				});
+		});
+	};
+	const openExisting = async (name) => {
+		if (indexedDB.databases) {
+			const known = await indexedDB.databases();
+			if (!known.some((info) => info.name === name)) return null;
+		}
+		return idbRequest(indexedDB.open(name));
+	};
+	const recreate = async (dump) => {
+		await new Promise((resolve, reject) => {
+			const req = indexedDB.deleteDatabase(dump.name);
+			let timer = null;
+			req.onsuccess = () => { clearTimeout(timer); resolve(); };
+			req.onerror = () => { clearTimeout(timer); reject(req.error); };
+			// Pages that close their connection on versionchange unblock the
+			// delete; give them a moment before giving up.
+			req.onblocked = () => {
+				timer = setTimeout(() => reject(new Error('IndexedDB database ' + dump.name + ' is held open by the page and its schema differs from the saved state')), 5000);
+			};
+		});
+		const open = indexedDB.open(dump.name, dump.version);
+		open.onupgradeneeded = () => {
+			const db = open.result;
+			for (const s of dump.stores) {
+				const store = db.createObjectStore(s.name, { keyPath: s.keyPath, autoIncrement: s.autoIncrement });
+				for (const index of s.indexes || []) {
+					store.createIndex(index.name, index.keyPath, { unique: index.unique, multiEntry: index.multiEntry });
+				}
+			}
+		};
+		return idbRequest(open);
+	};
+	for (const dump of state.indexedDB || []) {
+		let db = await openExisting(dump.name);
+		if (db && !sameSchema(db, dump)) {
+			db.close();
+			db = null;
+		}
+		if (!db) db = await recreate(dump);
+		try {
+			const names = dump.stores.map((s) => s.name);
+			if (names.length > 0) {
+				const tx = db.transaction(names, 'readwrite');
+				for (const s of dump.stores) {
+					const store = tx.objectStore(s.name);
+					store.clear();
+					for (const record of s.records || []) {
+						const value = decodeIDB(record.value);
+						if (s.keyPath === null) store.put(value, decodeIDB(record.key));
+						else store.put(value);
+					}
+				}
+				await new Promise((resolve, reject) => {
+					tx.oncomplete = () => resolve();
+					tx.onerror = () => reject(tx.error);
+					tx.onabort = () => reject(tx.error);
+				});
+			}
+		} finally {
+			db.close();
+		}
+	}
+	for (const item of state.localStorage || []) localStorage.setItem(item.name, item.value);
+	for (const item of state.sessionStorage || []) sessionStorage.setItem(item.name, item.value);
+	return true;
+}`
+
+// GetStorageState exports the cookies visible to the given browsing context
+// and the Web Storage of every distinct origin in the page and its frames,
+// so state kept by an embedded origin (e.g. an auth iframe) is included.
+// When withIndexedDB is set, each origin's IndexedDB databases are dumped too.
+func GetStorageState(s Session, context string, withIndexedDB bool) (*StorageState, error) {
+	cookies, err := GetCookies(s, context)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cookies: %w", err)
+	}
+
+	state := &StorageState{
+		Cookies: make([]StateCookie, 0, len(cookies)),
+		Origins: []OriginState{},
+	}
+	for _, c := range cookies {
+		expires := float64(-1)
+		if c.Expiry > 0 {
+			expires = float64(c.Expiry)
+		}
+		state.Cookies = append(state.Cookies, StateCookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Expires:  expires,
+			HTTPOnly: c.HTTPOnly,
+			Secure:   c.Secure,
+			SameSite: capitalizeSameSite(c.SameSite),
+		})
+	}
+
+	seen := make(map[string]bool)
+	for _, ctx := range storageContexts(s, context) {
+		raw, err := EvaluateFunction(s, ctx, dumpStorageJS, []interface{}{withIndexedDB})
+		if err != nil {
+			// Frames without storage access (e.g. about:blank, sandboxed)
+			// have nothing to export.
+			continue
+		}
+		text, _ := raw.(string)
+		var origin OriginState
+		if err := json.Unmarshal([]byte(text), &origin); err != nil {
+			return nil, fmt.Errorf("failed to parse storage data: %w", err)
+		}
+		if origin.Origin == "" || origin.Origin == "null" || seen[origin.Origin] {
+			continue
+		}
+		seen[origin.Origin] = true
+		if origin.LocalStorage == nil {
+			origin.LocalStorage = []StorageItem{}
+		}
+		state.Origins = append(state.Origins, origin)
+	}
+	return state, nil
+}
+
+// storageContexts returns the given browsing context followed by all of its
+// descendant frames.
+func storageContexts(s Session, context string) []string {
+	contexts := []string{context}
+	frames, err := ListFrames(s, context)
+	if err != nil {
+		return contexts
+	}
+	for _, f := range frames {
+		contexts = append(contexts, f.Context)
+	}
+	return contexts
+}
+
+// RestoreStorageResult counts what SetStorageState restored. Cookies the
+// browser rejects (e.g. SameSite=None without Secure) are skipped rather
+// than aborting the restore.
+type RestoreStorageResult struct {
+	Cookies        int `json:"cookies"`
+	SkippedCookies int `json:"skippedCookies"`
+	Origins        int `json:"origins"`
+}
+
+// SetStorageState restores cookies and Web Storage from a StorageState.
+// Each origin's storage is written into the page or frame currently on
+// that origin; origins with no matching frame are skipped. IndexedDB
+// databases in the state replace any existing databases of the same name.
+func SetStorageState(s Session, context string, state *StorageState) (*RestoreStorageResult, error) {
+	result := &RestoreStorageResult{}
+	for _, c := range state.Cookies {
+		cookie := bidi.Cookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HTTPOnly: c.HTTPOnly,
+			SameSite: c.SameSite,
+		}
+		if c.Expires > 0 {
+			cookie.Expiry = int64(c.Expires)
+		}
+		if err := SetCookie(s, context, cookie); err != nil {
+			result.SkippedCookies++
+			continue
+		}
+		result.Cookies++
+	}
+
+	if len(state.Origins) == 0 {
+		return result, nil
+	}
+
+	contexts := storageContexts(s, context)
+	for _, o := range state.Origins {
+		data, err := json.Marshal(o)
+		if err != nil {
+			return result, err
+		}
+		for _, ctx := range contexts {
+			origin, err := EvalSimpleScript(s, ctx, "() => location.origin")
+			if err != nil || origin != o.Origin {
+				continue
+			}
+			applied, err := EvaluateFunction(s, ctx, restoreStorageJS, []interface{}{string(data)})
+			if err != nil {
+				return result, fmt.Errorf("failed to restore storage for %s: %w", o.Origin, err)
+			}
+			if ok, _ := applied.(bool); ok {
+				result.Origins++
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// legacyStorageState is the format browser_storage_state wrote before it
+// adopted Playwright's shape: BiDi cookies plus the page's Web Storage as
+// name→value objects, usually JSON-encoded into a string.
+type legacyStorageState struct {
+	Cookies []bidi.Cookie   `json:"cookies"`
+	Storage json.RawMessage `json:"storage"`
+}
+
+// ParseStorageState decodes a state file in Playwright's storageState shape,
+// or in the legacy {cookies, storage} shape, which is converted.
+func ParseStorageState(data []byte) (*StorageState, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+	if _, legacy := probe["storage"]; !legacy {
+		var state StorageState
+		if err := json.Unmarshal(data, &state); err != nil {
+			return nil, err
+		}
+		return &state, nil
+	}
+	if _, ok := probe["origins"]; ok {
+		return nil, fmt.Errorf("state has both \"storage\" and \"origins\"; expected one format")
+	}
+
+	var legacy legacyStorageState
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return nil, err
+	}
+	state := &StorageState{
+		Cookies: make([]StateCookie, 0, len(legacy.Cookies)),
+		Origins: []OriginState{},
+	}
+	for _, c := range legacy.Cookies {
+		expires := float64(-1)
+		if c.Expiry > 0 {
+			expires = float64(c.Expiry)
+		}
+		state.Cookies = append(state.Cookies, StateCookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Expires:  expires,
+			HTTPOnly: c.HTTPOnly,
+			Secure:   c.Secure,
+			SameSite: capitalizeSameSite(c.SameSite),
+		})
+	}
+
+	// The old export stored the page script's JSON.stringify result as is.
+	raw := []byte(legacy.Storage)
+	var encoded string
+	if json.Unmarshal(raw, &encoded) == nil {
+		raw = []byte(encoded)
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return state, nil
+	}
+	var storage struct {
+		Origin         string            `json:"origin"`
+		LocalStorage   map[string]string `json:"localStorage"`
+		SessionStorage map[string]string `json:"sessionStorage"`
+	}
+	if err := json.Unmarshal(raw, &storage); err != nil {
+		return nil, fmt.Errorf("invalid legacy storage: %w", err)
+	}
+	if storage.Origin == "" {
+		return nil, fmt.Errorf("legacy storage has no origin")
+	}
+	state.Origins = append(state.Origins, OriginState{
+		Origin:         storage.Origin,
+		LocalStorage:   sortedStorageItems(storage.LocalStorage),
+		SessionStorage: sortedStorageItems(storage.SessionStorage),
+	})
+	return state, nil
+}
+
+// sortedStorageItems converts a name→value object to items sorted by name.
+func sortedStorageItems(m map[string]string) []StorageItem {
+	items := make([]StorageItem, 0, len(m))
+	for name, value := range m {
+		items = append(items, StorageItem{Name: name, Value: value})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return items
+}
+
+// ClearStorageOptions selects what ClearStorage wipes.
+type ClearStorageOptions struct {
+	Cookies        bool
+	LocalStorage   bool
+	SessionStorage bool
+	IndexedDB      bool
+	CacheStorage   bool
+}
+
+// ClearStorageResult counts what ClearStorage wiped. Storage counts are
+// summed across every origin in the page and its frames.
+type ClearStorageResult struct {
+	Cookies        int `json:"cookies"`
+	LocalStorage   int `json:"localStorage"`
+	SessionStorage int `json:"sessionStorage"`
+	IndexedDB      int `json:"indexedDB"`
+	CacheStorage   int `json:"cacheStorage"`
+}
+
+// clearStorageJS clears the selected stores for the frame's origin and
+// returns how many keys, databases, and caches were removed.
+const clearStorageJS = `async (optsJSON) => {
+	const opts = JSON.parse(optsJSON);
+	const result = { localStorage: 0, sessionStorage: 0, indexedDB: 0, cacheStorage: 0 };
+	if (opts.localStorage) {
+		result.localStorage = localStorage.length;
+		localStorage.clear();
+	}
+	if (opts.sessionStorage) {
+		result.sessionStorage = sessionStorage.length;
+		sessionStorage.clear();
+	}
+	if (opts.indexedDB && typeof indexedDB !== 'undefined' && indexedDB.databases) {
+		const dbs = await indexedDB.databases();
+		for (const db of dbs) {
+			await new Promise((resolve) => {
+				const req = indexedDB.deleteDatabase(db.name);
+				req.onsuccess = req.onerror = req.onblocked = () => resolve();
+			});
+			result.indexedDB++;
+		}
+	}
+	if (opts.cacheStorage && typeof caches !== 'undefined') {
+		for (const name of await caches.keys()) {
+			if (await caches.delete(name)) result.cacheStorage++;
+		}
+	}
+	return JSON.stringify(result);
+}`
+
+// ClearStorage wipes the selected kinds of browser state for the given
+// browsing context: cookies, plus Web Storage, IndexedDB, and Cache Storage
+// for every origin in the page and its frames.
+func ClearStorage(s Session, context string, opts ClearStorageOptions) (*ClearStorageResult, error) {
+	result := &ClearStorageResult{}
+
+	if opts.Cookies {
+		cookies, err := GetCookies(s, context)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cookies: %w", err)
+		}
+		if err := DeleteCookies(s, context, ""); err != nil {
+			return nil, fmt.Errorf("failed to delete cookies: %w", err)
+		}
+		result.Cookies = len(cookies)
+	}
+
+	if !opts.LocalStorage && !opts.SessionStorage && !opts.IndexedDB && !opts.CacheStorage {
+		return result, nil
+	}
+
+	optsJSON, _ := json.Marshal(map[string]bool{
+		"localStorage":   opts.LocalStorage,
+		"sessionStorage": opts.SessionStorage,
+		"indexedDB":      opts.IndexedDB,
+		"cacheStorage":   opts.CacheStorage,
+	})
+	seen := make(map[string]bool)
+	for _, ctx := range storageContexts(s, context) {
+		origin, err := EvalSimpleScript(s, ctx, "() => location.origin")
+		if err != nil || origin == "null" || seen[origin] {
+			continue
+		}
+		seen[origin] = true
+
+		raw, err := EvaluateFunction(s, ctx, clearStorageJS, []interface{}{string(optsJSON)})
+		if err != nil {
+			// Frames without storage access have nothing to clear.
+			continue
+		}
+		text, _ := raw.(string)
+		var counts ClearStorageResult
+		if err := json.Unmarshal([]byte(text), &counts); err != nil {
+			return nil, fmt.Errorf("failed to parse clear result: %w", err)
+		}
+		result.LocalStorage += counts.LocalStorage
+		result.SessionStorage += counts.SessionStorage
+		result.IndexedDB += counts.IndexedDB
+		result.CacheStorage += counts.CacheStorage
+	}
+	return result, nil
+}
+
+// capitalizeSameSite converts a BiDi sameSite value (strict, lax, none) to
+// the capitalized form Playwright uses.
+func capitalizeSameSite(sameSite string) string {
+	switch strings.ToLower(sameSite) {
+	case "strict":
+		return "Strict"
+	case "none":
+		return "None"
+	default:
+		return "Lax"
+	}
+}
+
 // --- Helper functions ---
 
 // getCookiesForContext fetches and normalizes cookies for a user context.
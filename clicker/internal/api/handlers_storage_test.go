@@ -0,0 +1,182 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/vibium/clicker/internal/bidi"
+)
+
+func TestParseStorageStateLegacy(t *testing.T) {
+	// Written by browser_storage_state before it adopted Playwright's shape:
+	// BiDi cookies and the page's storage as a JSON-encoded string.
+	data := []byte(`{
+		"cookies": [
+			{"name": "sid", "value": "abc", "domain": "example.com", "path": "/", "httpOnly": true, "sameSite": "strict", "expiry": 1900000000},
+			{"name": "theme", "value": "dark", "domain": "example.com", "path": "/", "sameSite": "lax"}
+		],
+		"storage": "{\"origin\":\"https://example.com\",\"localStorage\":{\"token\":\"t1\",\"lang\":\"en\"},\"sessionStorage\":{\"tab\":\"2\"}}"
+	}`)
+
+	state, err := ParseStorageState(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCookies := []StateCookie{
+		{Name: "sid", Value: "abc", Domain: "example.com", Path: "/", Expires: 1900000000, HTTPOnly: true, SameSite: "Strict"},
+		{Name: "theme", Value: "dark", Domain: "example.com", Path: "/", Expires: -1, SameSite: "Lax"},
+	}
+	if !reflect.DeepEqual(state.Cookies, wantCookies) {
+		t.Errorf("cookies = %+v, want %+v", state.Cookies, wantCookies)
+	}
+	wantOrigins := []OriginState{{
+		Origin:         "https://example.com",
+		LocalStorage:   []StorageItem{{Name: "lang", Value: "en"}, {Name: "token", Value: "t1"}},
+		SessionStorage: []StorageItem{{Name: "tab", Value: "2"}},
+	}}
+	if !reflect.DeepEqual(state.Origins, wantOrigins) {
+		t.Errorf("origins = %+v, want %+v", state.Origins, wantOrigins)
+	}
+}
+
+func TestParseStorageStateFormats(t *testing.T) {
+	state, err := ParseStorageState([]byte(`{"cookies": [], "origins": [{"origin": "https://example.com", "localStorage": [{"name": "a", "value": "1"}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Origins) != 1 || state.Origins[0].LocalStorage[0].Value != "1" {
+		t.Errorf("storageState origins = %+v", state.Origins)
+	}
+
+	state, err = ParseStorageState([]byte(`{"cookies": [], "storage": {"origin": "https://example.com", "localStorage": {"a": "1"}}}`))
+	if err != nil {
+		t.Fatalf("legacy storage as an object: %v", err)
+	}
+	if len(state.Origins) != 1 || state.Origins[0].Origin != "https://example.com" {
+		t.Errorf("legacy object origins = %+v", state.Origins)
+	}
+
+	for _, bad := range []string{
+		`{"cookies": [], "storage": "{}", "origins": []}`,
+		`{"cookies": [], "storage": "{\"localStorage\":{\"a\":\"1\"}}"}`,
+		`{"cookies": [], "storage": "not json"}`,
+		`[]`,
+	} {
+		if _, err := ParseStorageState([]byte(bad)); err == nil {
+			t.Errorf("ParseStorageState(%s) should fail", bad)
+		}
+	}
+}
+
+func TestSetStorageStateSkipsBadCookies(t *testing.T) {
+	f := &fakeSession{respond: func(method string, params map[string]interface{}) string {
+		cookie, _ := params["cookie"].(map[string]interface{})
+		if cookie["name"] == "rejected" {
+			return `{"type":"error","error":"unable to set cookie","message":"invalid domain"}`
+		}
+		return `{}`
+	}}
+	state := &StorageState{Cookies: []StateCookie{
+		{Name: "ok", Value: "1", Domain: "example.com", Path: "/", Expires: -1, SameSite: "Lax"},
+		{Name: "insecure", Value: "2", Domain: "example.com", Path: "/", Expires: -1, SameSite: "None"},
+		{Name: "rejected", Value: "3", Domain: "other.test", Path: "/", Expires: -1, SameSite: "Lax"},
+		{Name: "after", Value: "4", Domain: "example.com", Path: "/", Expires: -1, SameSite: "Strict"},
+	}}
+
+	result, err := SetStorageState(f, "ctx", state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := RestoreStorageResult{Cookies: 2, SkippedCookies: 2}
+	if *result != want {
+		t.Errorf("result = %+v, want %+v", *result, want)
+	}
+}
+
+func TestSetCookieAttributes(t *testing.T) {
+	f := &fakeSession{resp: `{}`}
+	cookie := bidi.Cookie{
+		Name: "sid", Value: "abc", Domain: "example.com", Path: "/",
+		Secure: true, HTTPOnly: true, SameSite: "None", Expiry: 1900000000,
+	}
+	if err := SetCookie(f, "ctx", cookie); err != nil {
+		t.Fatal(err)
+	}
+	if f.method != "storage.setCookie" {
+		t.Fatalf("method = %q, want storage.setCookie", f.method)
+	}
+	want := map[string]interface{}{
+		"name":     "sid",
+		"value":    map[string]interface{}{"type": "string", "value": "abc"},
+		"domain":   "example.com",
+		"path":     "/",
+		"secure":   true,
+		"httpOnly": true,
+		"sameSite": "none",
+		"expiry":   int64(1900000000),
+	}
+	if got := f.params["cookie"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("cookie = %#v, want %#v", got, want)
+	}
+}
+
+func TestSetCookieRejectsInvalidAttributes(t *testing.T) {
+	for _, cookie := range []bidi.Cookie{
+		{Name: "a", Value: "1", SameSite: "none"},
+		{Name: "a", Value: "1", SameSite: "sometimes"},
+		{Name: "a", Value: "1", Expiry: -5},
+	} {
+		f := &fakeSession{resp: `{}`}
+		if err := SetCookie(f, "ctx", cookie); err == nil {
+			t.Errorf("SetCookie(%+v) should fail", cookie)
+		}
+		if f.method != "" {
+			t.Errorf("SetCookie(%+v) sent %s", cookie, f.method)
+		}
+	}
+}
+
+func TestGetStorageStateCollectsFrameOrigins(t *testing.T) {
+	dumps := map[string]string{
+		"top":   `{"origin":"https://app.example.com","localStorage":[{"name":"cart","value":"3"}]}`,
+		"auth":  `{"origin":"https://auth.example.com","localStorage":[{"name":"token","value":"t1"}],"sessionStorage":[{"name":"nonce","value":"n"}]}`,
+		"ad":    `{"origin":"https://app.example.com","localStorage":[{"name":"cart","value":"stale"}]}`,
+		"blank": `{"origin":"null","localStorage":[]}`,
+	}
+	f := &fakeSession{respond: func(method string, params map[string]interface{}) string {
+		switch method {
+		case "storage.getCookies":
+			return `{"result":{"cookies":[]}}`
+		case "browsingContext.getTree":
+			return `{"result":{"contexts":[{"context":"top","url":"https://app.example.com/","children":[
+				{"context":"auth","url":"https://auth.example.com/frame","children":[]},
+				{"context":"ad","url":"https://app.example.com/ad","children":[
+					{"context":"blank","url":"about:blank","children":[]}
+				]}
+			]}]}}`
+		}
+		value := ""
+		if params["functionDeclaration"] == dumpStorageJS {
+			value = dumps[params["target"].(map[string]interface{})["context"].(string)]
+		}
+		data, _ := json.Marshal(value)
+		return `{"result":{"result":{"type":"string","value":` + string(data) + `}}}`
+	}}
+
+	state, err := GetStorageState(f, "top", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []OriginState{
+		{Origin: "https://app.example.com", LocalStorage: []StorageItem{{Name: "cart", Value: "3"}}},
+		{
+			Origin:         "https://auth.example.com",
+			LocalStorage:   []StorageItem{{Name: "token", Value: "t1"}},
+			SessionStorage: []StorageItem{{Name: "nonce", Value: "n"}},
+		},
+	}
+	if !reflect.DeepEqual(state.Origins, want) {
+		t.Errorf("origins = %+v, want %+v", state.Origins, want)
+	}
+}
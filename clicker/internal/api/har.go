@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// HARRecorder collects completed network requests into a standalone HAR 1.2
+// log, independent of recording. Entries are built with the same
+// request/response correlation the recorder uses for its network log.
+// It is not safe for concurrent use.
+type HARRecorder struct {
+	entries []map[string]interface{}
+}
+
+// NewHARRecorder creates an empty HARRecorder.
+func NewHARRecorder() *HARRecorder {
+	return &HARRecorder{entries: []map[string]interface{}{}}
+}
+
+// Add appends a completed request to the log.
+func (r *HARRecorder) Add(c *CompletedRequest) {
+	entry, _ := c.harEntry()["snapshot"].(map[string]interface{})
+	if entry == nil {
+		return
+	}
+	r.entries = append(r.entries, entry)
+}
+
+// Len returns the number of entries collected so far.
+func (r *HARRecorder) Len() int {
+	return len(r.entries)
+}
+
+// HAR returns the collected entries as a HAR 1.2 document.
+func (r *HARRecorder) HAR() map[string]interface{} {
+	return map[string]interface{}{
+		"log": map[string]interface{}{
+			"version": "1.2",
+			"creator": map[string]interface{}{
+				"name":    "vibium",
+				"version": "",
+			},
+			"pages":   []interface{}{},
+			"entries": r.entries,
+		},
+	}
+}
+
+// WriteFile writes the HAR document to path, creating directories as needed.
+func (r *HARRecorder) WriteFile(path string) error {
+	data, err := json.MarshalIndent(r.HAR(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode HAR: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create HAR dir: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -3,6 +3,8 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -81,9 +83,9 @@ func buildRefFindScript(ep ElementParams) (string, []map[string]interface{}) {
 		args := buildElSemanticArgs(ep)
 		script := `
 			(scope, selector, role, text, label, placeholder, alt, title, testid, xpath, index, hasIndex) => {
-				const root = scope ? document.querySelector(scope) : document;
+				const root = resolveScope(scope);
 				if (!root) return null;
-		` + semanticMatchesHelper() + `
+		` + semanticMatchesHelper(ep) + `
 				const found = collectMatches(root, selector, role, text, label, placeholder, alt, title, testid, xpath);
 				let el;
 				if (hasIndex) {
@@ -106,14 +108,15 @@ func buildRefFindScript(ep ElementParams) (string, []map[string]interface{}) {
 
 	script := `
 		(scope, selector, index, hasIndex) => {
-			const root = scope ? document.querySelector(scope) : document;
+			const root = resolveScope(scope);
 			if (!root) return null;
+		` + DeepQueryJS() + `
 			let el;
 			if (hasIndex) {
-				const all = root.querySelectorAll(selector);
+				const all = queryAllDeep(root, selector);
 				el = all[index];
 			} else {
-				el = root.querySelector(selector);
+				el = queryDeep(root, selector);
 			}
 			return el || null;
 		}
@@ -138,6 +141,11 @@ type ElementParams struct {
 	Context     string
 	Timeout     time.Duration
 	Force       bool
+
+	// Exact requires text/label to equal the element's (trimmed) text
+	// instead of containing it; CaseInsensitive ignores case either way.
+	Exact           bool
+	CaseInsensitive bool
 }
 
 // ExtractElementParams extracts element parameters from command params.
@@ -171,9 +179,162 @@ func ExtractElementParams(params map[string]interface{}) ElementParams {
 		ep.Force = force
 	}
 
+	ep.Exact, _ = params["exact"].(bool)
+	ep.CaseInsensitive, _ = params["caseInsensitive"].(bool)
+
+	if xpath, ok := ParseXPathSelector(ep.Selector); ok {
+		ep.Selector = ""
+		ep.Xpath = xpath
+	} else if ts, ok := ParseTextSelector(ep.Selector); ok {
+		ep.Selector = ts.Base
+		ep.Text = ts.Text
+		ep.Exact = ep.Exact || ts.Exact
+		ep.CaseInsensitive = ep.CaseInsensitive || ts.CaseInsensitive
+	}
+
 	return ep
 }
 
+// DeepQueryJS returns JS defining queryAllDeep(root, selector) and
+// queryDeep(root, selector), querySelectorAll/querySelector variants that
+// understand shadow-piercing, XPath and text selectors, and
+// resolveScope(scope), which resolves a scope selector the same way
+// (document when scope is empty). "host >>> inner" matches
+// inner inside the open shadow roots of elements matching host (and chains,
+// a >>> b >>> c); "pierce=sel" matches sel in root and in every open shadow
+// root beneath it. Closed shadow roots are not reachable from page scripts,
+// so elements inside them cannot be selected either way. XPath is recognized
+// as in ParseXPathSelector and evaluated relative to root. Text selectors (see
+// ParseTextSelector) match every element containing the text: for text= the
+// innermost such elements, for css:has-text() every element matching css.
+func DeepQueryJS() string {
+	return `
+			function parseTextSelector(selector) {
+				if (selector.startsWith('text=')) {
+					const rest = selector.slice('text='.length).trim();
+					if (rest.length >= 2 && (rest[0] === '"' || rest[0] === "'") && rest[rest.length - 1] === rest[0]) {
+						return { base: '', text: rest.slice(1, -1), exact: true };
+					}
+					return rest ? { base: '', text: rest, exact: false } : null;
+				}
+				const m = selector.match(/^(.*):has-text\((?:"([^"]*)"|'([^']*)')\)$/);
+				if (m) return { base: m[1].trim(), text: m[2] !== undefined ? m[2] : m[3], exact: false };
+				return null;
+			}
+
+			function queryAllByText(root, ts) {
+				const want = ts.exact ? ts.text : ts.text.toLowerCase();
+				const has = (el) => {
+					const text = (el.textContent || '').trim();
+					return ts.exact ? text === want : text.toLowerCase().includes(want);
+				};
+				if (ts.base) return queryAllDeep(root, ts.base).filter(has);
+				const scope = root === document ? document.body : root;
+				if (!scope) return [];
+				return Array.from(scope.querySelectorAll('*'))
+					.filter(el => has(el) && !Array.from(el.children).some(has));
+			}
+
+			function parseXPathSelector(selector) {
+				if (selector.startsWith('xpath=')) return selector.slice('xpath='.length) || null;
+				if (selector.startsWith('/') || selector.startsWith('(')) return selector;
+				return null;
+			}
+
+			function queryAllDeep(root, selector) {
+				const xpath = parseXPathSelector(selector);
+				if (xpath) {
+					const xr = document.evaluate(xpath, root, null, XPathResult.ORDERED_NODE_SNAPSHOT_TYPE, null);
+					const out = [];
+					for (let i = 0; i < xr.snapshotLength; i++) {
+						if (xr.snapshotItem(i).nodeType === 1) out.push(xr.snapshotItem(i));
+					}
+					return out;
+				}
+				const ts = parseTextSelector(selector);
+				if (ts) return queryAllByText(root, ts);
+				if (selector.startsWith('pierce=')) {
+					const inner = selector.slice('pierce='.length);
+					const out = [];
+					const visit = (r) => {
+						out.push(...r.querySelectorAll(inner));
+						for (const el of r.querySelectorAll('*')) {
+							if (el.shadowRoot) visit(el.shadowRoot);
+						}
+					};
+					visit(root);
+					return out;
+				}
+				if (!selector.includes('>>>')) return Array.from(root.querySelectorAll(selector));
+				const parts = selector.split('>>>').map(p => p.trim());
+				let scopes = [root];
+				for (let i = 0; i < parts.length; i++) {
+					const next = [];
+					for (const scope of scopes) {
+						for (const el of scope.querySelectorAll(parts[i])) {
+							if (i === parts.length - 1) next.push(el);
+							else if (el.shadowRoot) next.push(el.shadowRoot);
+						}
+					}
+					scopes = next;
+				}
+				return scopes;
+			}
+
+			function queryDeep(root, selector) {
+				return queryAllDeep(root, selector)[0] || null;
+			}
+
+			function resolveScope(scope) {
+				return scope ? queryDeep(document, scope) : document;
+			}
+	`
+}
+
+// ParseXPathSelector recognizes XPath given where a CSS selector is
+// expected: an explicit xpath= prefix, or an expression starting with / or (
+// (which no CSS selector does). It returns the bare expression.
+func ParseXPathSelector(selector string) (string, bool) {
+	if xpath, found := strings.CutPrefix(selector, "xpath="); found {
+		return xpath, xpath != ""
+	}
+	if strings.HasPrefix(selector, "/") || strings.HasPrefix(selector, "(") {
+		return selector, true
+	}
+	return "", false
+}
+
+// TextSelector is a Playwright-style text selector, which querySelector
+// cannot handle on its own.
+type TextSelector struct {
+	Base            string // CSS the element must also match; empty for text=
+	Text            string
+	Exact           bool
+	CaseInsensitive bool
+}
+
+var hasTextRe = regexp.MustCompile(`^(.*):has-text\((?:"([^"]*)"|'([^']*)')\)$`)
+
+// ParseTextSelector recognizes text=Submit (case-insensitive substring),
+// text="Submit" (exact), and css:has-text("Save") (a CSS match whose text
+// contains "Save", ignoring case). ok is false for anything else.
+func ParseTextSelector(selector string) (ts TextSelector, ok bool) {
+	if rest, found := strings.CutPrefix(selector, "text="); found {
+		rest = strings.TrimSpace(rest)
+		if len(rest) >= 2 && (rest[0] == '"' || rest[0] == '\'') && rest[len(rest)-1] == rest[0] {
+			return TextSelector{Text: rest[1 : len(rest)-1], Exact: true}, true
+		}
+		if rest == "" {
+			return TextSelector{}, false
+		}
+		return TextSelector{Text: rest, CaseInsensitive: true}, true
+	}
+	if m := hasTextRe.FindStringSubmatch(selector); m != nil {
+		return TextSelector{Base: strings.TrimSpace(m[1]), Text: m[2] + m[3], CaseInsensitive: true}, true
+	}
+	return TextSelector{}, false
+}
+
 // hasSemantic returns true if any semantic selector params are set.
 func hasSemantic(ep ElementParams) bool {
 	return ep.Role != "" || ep.Text != "" || ep.Label != "" || ep.Placeholder != "" ||
@@ -193,14 +354,15 @@ func buildActionFindScript(ep ElementParams) (string, []map[string]interface{})
 		}
 		script := `
 			(scope, selector, index, hasIndex) => {
-				const root = scope ? document.querySelector(scope) : document;
+				const root = resolveScope(scope);
 				if (!root) return null;
+			` + DeepQueryJS() + `
 				let el;
 				if (hasIndex) {
-					const all = root.querySelectorAll(selector);
+					const all = queryAllDeep(root, selector);
 					el = all[index];
 				} else {
-					el = root.querySelector(selector);
+					el = queryDeep(root, selector);
 				}
 				if (!el) return null;
 				if (el.scrollIntoViewIfNeeded) {
@@ -237,9 +399,9 @@ func buildActionFindScript(ep ElementParams) (string, []map[string]interface{})
 
 	script := `
 		(scope, selector, role, text, label, placeholder, alt, title, testid, xpath, index, hasIndex) => {
-			const root = scope ? document.querySelector(scope) : document;
+			const root = resolveScope(scope);
 			if (!root) return null;
-	` + semanticMatchesHelper() + `
+	` + semanticMatchesHelper(ep) + `
 			const found = collectMatches(root, selector, role, text, label, placeholder, alt, title, testid, xpath);
 			let el;
 			if (hasIndex) {
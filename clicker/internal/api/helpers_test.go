@@ -0,0 +1,28 @@
+package api
+
+import "testing"
+
+func TestExtractElementParamsTextMatching(t *testing.T) {
+	tests := []struct {
+		name            string
+		params          map[string]interface{}
+		text            string
+		exact, caseFree bool
+	}{
+		{"substring by default", map[string]interface{}{"text": "Save"}, "Save", false, false},
+		{"exact", map[string]interface{}{"text": "Save", "exact": true}, "Save", true, false},
+		{"case-insensitive", map[string]interface{}{"text": "save", "caseInsensitive": true}, "save", false, true},
+		{"text= selector ignores case", map[string]interface{}{"selector": "text=Save"}, "Save", false, true},
+		{"quoted text= selector is exact", map[string]interface{}{"selector": `text="Save"`}, "Save", true, false},
+		{"flags add to a text= selector", map[string]interface{}{"selector": `text="Save"`, "caseInsensitive": true}, "Save", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep := ExtractElementParams(tt.params)
+			if ep.Text != tt.text || ep.Exact != tt.exact || ep.CaseInsensitive != tt.caseFree {
+				t.Errorf("text=%q exact=%v caseInsensitive=%v; want %q %v %v",
+					ep.Text, ep.Exact, ep.CaseInsensitive, tt.text, tt.exact, tt.caseFree)
+			}
+		})
+	}
+}
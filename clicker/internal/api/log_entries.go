@@ -0,0 +1,60 @@
+package api
+
+// LogEntry is a parsed BiDi log.entryAdded event.
+type LogEntry struct {
+	Type      string       `json:"type"`             // "console" or "javascript"
+	Level     string       `json:"level"`            // debug, info, warn, or error
+	Method    string       `json:"method,omitempty"` // console method: log, warn, error, ...
+	Text      string       `json:"text"`
+	Timestamp float64      `json:"timestamp"` // ms since epoch
+	Context   string       `json:"context,omitempty"`
+	URL       string       `json:"url,omitempty"`
+	Line      int          `json:"lineNumber,omitempty"`
+	Column    int          `json:"columnNumber,omitempty"`
+	Stack     []StackFrame `json:"stack,omitempty"`
+}
+
+// StackFrame is one frame of a BiDi stack trace.
+type StackFrame struct {
+	Function string `json:"functionName"`
+	URL      string `json:"url"`
+	Line     int    `json:"lineNumber"`
+	Column   int    `json:"columnNumber"`
+}
+
+// ParseLogEntry extracts a LogEntry from log.entryAdded event params.
+// The source location is taken from the top stack frame when present; the
+// full stack is kept for uncaught exceptions ("javascript" entries).
+func ParseLogEntry(params map[string]interface{}) *LogEntry {
+	entry := &LogEntry{}
+	entry.Type, _ = params["type"].(string)
+	entry.Level, _ = params["level"].(string)
+	entry.Method, _ = params["method"].(string)
+	entry.Text, _ = params["text"].(string)
+	entry.Timestamp = toFloat64(params["timestamp"])
+	if source, ok := params["source"].(map[string]interface{}); ok {
+		entry.Context, _ = source["context"].(string)
+	}
+	if stack, ok := params["stackTrace"].(map[string]interface{}); ok {
+		frames, _ := stack["callFrames"].([]interface{})
+		for i, f := range frames {
+			frame, ok := f.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			sf := StackFrame{
+				Line:   int(toFloat64(frame["lineNumber"])),
+				Column: int(toFloat64(frame["columnNumber"])),
+			}
+			sf.Function, _ = frame["functionName"].(string)
+			sf.URL, _ = frame["url"].(string)
+			if i == 0 {
+				entry.URL, entry.Line, entry.Column = sf.URL, sf.Line, sf.Column
+			}
+			if entry.Type == "javascript" {
+				entry.Stack = append(entry.Stack, sf)
+			}
+		}
+	}
+	return entry
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxMockBodySize caps the response bodies the browser keeps for mock recording.
+const maxMockBodySize = 20 * 1024 * 1024
+
+// MockResponse is a response saved by mock recording and served back by
+// mock replay. Each is stored as <MockKey>.json in the mock directory.
+type MockResponse struct {
+	Method     string            `json:"method"`
+	URL        string            `json:"url"`
+	Status     int               `json:"status"`
+	StatusText string            `json:"statusText,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body"` // base64
+}
+
+// MockKey identifies a saved response by request method and URL.
+func MockKey(method, url string) string {
+	sum := sha1.Sum([]byte(strings.ToUpper(method) + " " + url))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewMockResponse builds a MockResponse from a completed request and its
+// decoded body. Content-Encoding and Content-Length are dropped because the
+// saved body is already decoded.
+func NewMockResponse(c *CompletedRequest, body []byte) *MockResponse {
+	m := &MockResponse{
+		Method:     c.Method,
+		URL:        c.URL,
+		Status:     c.Status,
+		StatusText: c.StatusText,
+		Headers:    map[string]string{},
+		Body:       base64.StdEncoding.EncodeToString(body),
+	}
+	for name, value := range c.Headers {
+		switch strings.ToLower(name) {
+		case "content-encoding", "content-length":
+			continue
+		}
+		m.Headers[name] = value
+	}
+	return m
+}
+
+// Save writes the response to dir, replacing any earlier recording of the
+// same method and URL.
+func (m *MockResponse) Save(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mock dir: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, MockKey(m.Method, m.URL)+".json"), data, 0o644)
+}
+
+// LoadMocks reads every saved response in dir, keyed by MockKey.
+func LoadMocks(dir string) (map[string]*MockResponse, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	mocks := make(map[string]*MockResponse, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var m MockResponse
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("invalid mock %s: %w", filepath.Base(file), err)
+		}
+		mocks[MockKey(m.Method, m.URL)] = &m
+	}
+	return mocks, nil
+}
+
+// ProvideResponseParams returns the network.provideResponse params that
+// answer a blocked request with the saved response.
+func (m *MockResponse) ProvideResponseParams(requestID string) map[string]interface{} {
+	headers := make(map[string]interface{}, len(m.Headers))
+	for name, value := range m.Headers {
+		headers[name] = value
+	}
+	params := map[string]interface{}{
+		"request":    requestID,
+		"statusCode": m.Status,
+		"headers":    convertHeadersToBidi(headers),
+		"body": map[string]interface{}{
+			"type":  "base64",
+			"value": m.Body,
+		},
+	}
+	if m.StatusText != "" {
+		params["reasonPhrase"] = m.StatusText
+	}
+	return params
+}
+
+// AddResponseCollector asks the browser to keep response bodies so they can
+// be read with GetResponseBody. It returns the collector ID.
+func AddResponseCollector(s Session) (string, error) {
+	resp, err := s.SendBidiCommand("network.addDataCollector", map[string]interface{}{
+		"dataTypes":          []string{"response"},
+		"maxEncodedDataSize": maxMockBodySize,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := checkBidiError(resp); err != nil {
+		return "", err
+	}
+	var result struct {
+		Result struct {
+			Collector string `json:"collector"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return "", fmt.Errorf("failed to parse addDataCollector response: %w", err)
+	}
+	return result.Result.Collector, nil
+}
+
+// RemoveDataCollector removes a collector added with AddResponseCollector.
+func RemoveDataCollector(s Session, collector string) error {
+	resp, err := s.SendBidiCommand("network.removeDataCollector", map[string]interface{}{
+		"collector": collector,
+	})
+	if err != nil {
+		return err
+	}
+	return checkBidiError(resp)
+}
+
+// GetResponseBody returns a response body kept by the collector, releasing
+// it from the browser.
+func GetResponseBody(s Session, collector, requestID string) ([]byte, error) {
+	resp, err := s.SendBidiCommand("network.getData", map[string]interface{}{
+		"dataType":  "response",
+		"request":   requestID,
+		"collector": collector,
+		"disown":    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBidiError(resp); err != nil {
+		return nil, err
+	}
+	var result struct {
+		Result struct {
+			Bytes struct {
+				Type  string `json:"type"`
+				Value string `json:"value"`
+			} `json:"bytes"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse getData response: %w", err)
+	}
+	if result.Result.Bytes.Type == "base64" {
+		return base64.StdEncoding.DecodeString(result.Result.Bytes.Value)
+	}
+	return []byte(result.Result.Bytes.Value), nil
+}
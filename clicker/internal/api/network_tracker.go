@@ -0,0 +1,103 @@
+package api
+
+import (
+	"regexp"
+	"time"
+)
+
+// RequestTracker correlates BiDi network.beforeRequestSent events with the
+// network.responseCompleted or network.fetchError event that finishes them.
+// It is not safe for concurrent use.
+type RequestTracker struct {
+	pending map[string]*pendingRequest // BiDi request ID -> pending request
+}
+
+// NewRequestTracker creates an empty RequestTracker.
+func NewRequestTracker() *RequestTracker {
+	return &RequestTracker{pending: make(map[string]*pendingRequest)}
+}
+
+// CompletedRequest is a network request paired with its outcome.
+type CompletedRequest struct {
+	RequestID  string // BiDi request ID
+	URL        string
+	Method     string
+	Status     int
+	StatusText string
+	Headers    map[string]string // response headers
+	Failed     bool              // true for network.fetchError
+	ErrorText  string            // fetchError reason
+	Completed  time.Time         // when the completing event was tracked
+
+	request        *pendingRequest
+	responseParams map[string]interface{}
+}
+
+// Track feeds a network event to the tracker. It returns the correlated
+// request when the event finishes one, and nil otherwise. Responses without a
+// matching beforeRequestSent are reported best-effort from the response alone.
+func (rt *RequestTracker) Track(method string, params map[string]interface{}) *CompletedRequest {
+	switch method {
+	case "network.beforeRequestSent":
+		if req := parsePendingRequest(params); req != nil {
+			rt.pending[req.requestID] = req
+		}
+		return nil
+
+	case "network.responseCompleted", "network.fetchError":
+		requestID := extractRequestID(params)
+		pending := rt.pending[requestID]
+		if pending == nil {
+			pending = parsePendingRequestFromResponse(params)
+		} else {
+			delete(rt.pending, requestID)
+		}
+		if pending == nil {
+			return nil
+		}
+
+		c := &CompletedRequest{
+			RequestID:      requestID,
+			URL:            pending.url,
+			Method:         pending.method,
+			Failed:         method == "network.fetchError",
+			Completed:      time.Now(),
+			request:        pending,
+			responseParams: params,
+		}
+		if c.Failed {
+			c.ErrorText, _ = params["errorText"].(string)
+			return c
+		}
+		if resp, ok := params["response"].(map[string]interface{}); ok {
+			c.Status = int(toFloat64(resp["status"]))
+			c.StatusText, _ = resp["statusText"].(string)
+			headers, _ := resp["headers"].([]interface{})
+			c.Headers = make(map[string]string, len(headers))
+			for _, h := range flattenBidiHeaders(headers) {
+				hdr := h.(map[string]interface{})
+				c.Headers[hdr["name"].(string)] = hdr["value"].(string)
+			}
+		}
+		return c
+	}
+	return nil
+}
+
+// harEntry builds the recording's resource-snapshot event for the request.
+func (c *CompletedRequest) harEntry() recordEvent {
+	return bidiToHAREntry(c.request, c.responseParams, c.Failed)
+}
+
+// URLMatcher returns a function reporting whether a URL matches pattern.
+// Patterns are substrings or globs with *, or regular expressions when regex is set.
+func URLMatcher(pattern string, regex bool) (func(string) bool, error) {
+	if !regex {
+		return func(url string) bool { return matchesPattern(url, pattern) }, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return re.MatchString, nil
+}
@@ -25,8 +25,24 @@ type RecordingStartOptions struct {
 	Sources     bool    `json:"sources"`
 	Title       string  `json:"title"`
 	Bidi        bool    `json:"bidi"`
-	Format      string  `json:"format"`  // "png" or "jpeg" (default "jpeg")
-	Quality     float64 `json:"quality"` // 0.0-1.0 for JPEG (default 0.5)
+	Format      string  `json:"format"`        // "png" or "jpeg" (default "jpeg")
+	Quality     float64 `json:"quality"`       // 0.0-1.0 for JPEG (default 0.5)
+	IntervalMs  int     `json:"intervalMs"`    // periodic screenshot period; 0 = per-action only
+	FPS         float64 `json:"fps,omitempty"` // set when the period was given as fps; IntervalMs holds it converted
+}
+
+// MinRecordingIntervalMs is the shortest allowed periodic screenshot interval.
+const MinRecordingIntervalMs = 100
+
+// Validate checks option values that can't be silently defaulted.
+func (o RecordingStartOptions) Validate() error {
+	if o.FPS > 0 && o.IntervalMs < MinRecordingIntervalMs {
+		return fmt.Errorf("fps must be at most %d (got %g)", 1000/MinRecordingIntervalMs, o.FPS)
+	}
+	if o.IntervalMs < 0 || (o.IntervalMs > 0 && o.IntervalMs < MinRecordingIntervalMs) {
+		return fmt.Errorf("intervalMs must be at least %d (got %d)", MinRecordingIntervalMs, o.IntervalMs)
+	}
+	return nil
 }
 
 // ParseRecordingOptions extracts RecordingStartOptions from a params map.
@@ -62,6 +78,13 @@ func ParseRecordingOptions(params map[string]interface{}) RecordingStartOptions
 	if q, ok := params["quality"].(float64); ok && q >= 0 && q <= 1 {
 		opts.Quality = q
 	}
+	// Periodic screenshots: intervalMs, or fps as a convenience
+	if ms, ok := params["intervalMs"].(float64); ok {
+		opts.IntervalMs = int(ms)
+	} else if fps, ok := params["fps"].(float64); ok && fps > 0 {
+		opts.FPS = fps
+		opts.IntervalMs = int(1000 / fps)
+	}
 	return opts
 }
 
@@ -98,10 +121,11 @@ type Recorder struct {
 	network         []recordEvent      // current chunk's network events
 	resources       map[string][]byte // sha1 hex -> binary data (PNG/HTML)
 	groupStack      []groupEntry       // nested group entries (name + callId)
-	pendingRequests map[string]*pendingRequest // BiDi request ID -> pending request
+	requests        *RequestTracker    // correlates network requests with responses
 	chunkIndex      int
 	startTime       int64 // unix ms
 	actionCounter   int   // monotonic counter for action/bidi callIds
+	activePage      string // page the screenshot loop captures (MCP path)
 
 	// Screenshot goroutine control
 	screenshotStop chan struct{}
@@ -112,7 +136,7 @@ type Recorder struct {
 func NewRecorder() *Recorder {
 	return &Recorder{
 		resources:       make(map[string][]byte),
-		pendingRequests: make(map[string]*pendingRequest),
+		requests:        NewRequestTracker(),
 	}
 }
 
@@ -133,7 +157,7 @@ func (t *Recorder) Start(opts RecordingStartOptions) {
 	t.events = nil
 	t.network = nil
 	t.resources = make(map[string][]byte)
-	t.pendingRequests = make(map[string]*pendingRequest)
+	t.requests = NewRequestTracker()
 	t.groupStack = nil
 	t.chunkIndex = 0
 	t.startTime = time.Now().UnixMilli()
@@ -168,6 +192,10 @@ func (t *Recorder) Stop() ([]byte, error) {
 	}
 
 	t.recording = false
+	// Close groups left open so they don't render as unfinished in the viewer
+	for len(t.groupStack) > 0 {
+		t.stopGroupLocked()
+	}
 	return t.buildZipLocked()
 }
 
@@ -249,13 +277,18 @@ func (t *Recorder) StartGroup(name string) {
 	t.events = append(t.events, ev)
 }
 
-// StopGroup adds a group-end marker to the recording.
-func (t *Recorder) StopGroup() {
+// StopGroup adds a group-end marker to the recording and returns the name of
+// the group it closed, or "" if no group was open.
+func (t *Recorder) StopGroup() string {
 	t.mu.Lock()
 	defer t.mu.Unlock()
+	return t.stopGroupLocked()
+}
 
+// stopGroupLocked closes the innermost open group. Must be called with t.mu held.
+func (t *Recorder) stopGroupLocked() string {
 	if len(t.groupStack) == 0 {
-		return
+		return ""
 	}
 
 	entry := t.groupStack[len(t.groupStack)-1]
@@ -266,6 +299,7 @@ func (t *Recorder) StopGroup() {
 		"callId":  entry.callId,
 		"endTime": float64(time.Now().UnixMilli()),
 	})
+	return entry.name
 }
 
 // Options returns the current recording options.
@@ -594,37 +628,9 @@ func (t *Recorder) RecordBidiEvent(msg string) {
 	now := float64(time.Now().UnixMilli())
 
 	switch bidiEvent.Method {
-	case "network.beforeRequestSent":
-		req := parsePendingRequest(bidiEvent.Params)
-		if req != nil {
-			t.pendingRequests[req.requestID] = req
-		}
-
-	case "network.responseCompleted":
-		requestID := extractRequestID(bidiEvent.Params)
-		pending := t.pendingRequests[requestID]
-		if pending == nil {
-			// No matching request — write a best-effort entry from response only
-			pending = parsePendingRequestFromResponse(bidiEvent.Params)
-		} else {
-			delete(t.pendingRequests, requestID)
-		}
-		if pending != nil {
-			entry := bidiToHAREntry(pending, bidiEvent.Params, false)
-			t.network = append(t.network, entry)
-		}
-
-	case "network.fetchError":
-		requestID := extractRequestID(bidiEvent.Params)
-		pending := t.pendingRequests[requestID]
-		if pending == nil {
-			pending = parsePendingRequestFromResponse(bidiEvent.Params)
-		} else {
-			delete(t.pendingRequests, requestID)
-		}
-		if pending != nil {
-			entry := bidiToHAREntry(pending, bidiEvent.Params, true)
-			t.network = append(t.network, entry)
+	case "network.beforeRequestSent", "network.responseCompleted", "network.fetchError":
+		if completed := t.requests.Track(bidiEvent.Method, bidiEvent.Params); completed != nil {
+			t.network = append(t.network, completed.harEntry())
 		}
 
 	default:
@@ -918,10 +924,30 @@ func (t *Recorder) StopScreenshots() {
 	}
 }
 
-// StartScreenshotLoop starts a background goroutine that captures screenshots periodically.
+// SetActivePage records which page the screenshot loop should capture.
+func (t *Recorder) SetActivePage(pageID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.activePage = pageID
+}
+
+// ActivePage returns the page set by SetActivePage.
+func (t *Recorder) ActivePage() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.activePage
+}
+
+// StartScreenshotLoop starts a background goroutine that captures screenshots
+// every Options().IntervalMs. It does nothing if the interval is 0.
 // captureFunc should return (base64-encoded image data, pageID, error).
 func (t *Recorder) StartScreenshotLoop(captureFunc func() (string, string, error)) {
 	t.mu.Lock()
+	interval := time.Duration(t.options.IntervalMs) * time.Millisecond
+	if interval <= 0 || t.screenshotStop != nil {
+		t.mu.Unlock()
+		return
+	}
 	t.screenshotStop = make(chan struct{})
 	stopCh := t.screenshotStop
 	t.mu.Unlock()
@@ -929,7 +955,7 @@ func (t *Recorder) StartScreenshotLoop(captureFunc func() (string, string, error
 	t.screenshotWg.Add(1)
 	go func() {
 		defer t.screenshotWg.Done()
-		ticker := time.NewTicker(100 * time.Millisecond)
+		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
 		for {
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRecordingOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]interface{}
+		wantErr string
+	}{
+		{"default", map[string]interface{}{}, ""},
+		{"intervalMs at the floor", map[string]interface{}{"intervalMs": float64(100)}, ""},
+		{"intervalMs below the floor", map[string]interface{}{"intervalMs": float64(50)}, "intervalMs must be at least 100"},
+		{"fps at the ceiling", map[string]interface{}{"fps": float64(10)}, ""},
+		{"fps above the ceiling", map[string]interface{}{"fps": float64(30)}, "fps must be at most 10 (got 30)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ParseRecordingOptions(tt.params).Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecorderStopGroup(t *testing.T) {
+	r := NewRecorder()
+	r.Start(RecordingStartOptions{Name: "session"})
+
+	if name := r.StopGroup(); name != "" {
+		t.Errorf("StopGroup with no open group = %q, want \"\"", name)
+	}
+	r.StartGroup("Checkout")
+	r.StartGroup("Payment")
+	if name := r.StopGroup(); name != "Payment" {
+		t.Errorf("StopGroup = %q, want the innermost group", name)
+	}
+	if name := r.StopGroup(); name != "Checkout" {
+		t.Errorf("StopGroup = %q, want Checkout", name)
+	}
+}
+
+func TestRecorderStopClosesOpenGroups(t *testing.T) {
+	r := NewRecorder()
+	r.Start(RecordingStartOptions{Name: "session"})
+	r.StartGroup("Login")
+	r.StartGroup("Submit")
+
+	data, err := r.Stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := zr.Open("0-trace.trace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trace, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	open := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(trace)), "\n") {
+		var ev map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatal(err)
+		}
+		callID, _ := ev["callId"].(string)
+		switch ev["type"] {
+		case "before":
+			open[callID], _ = ev["title"].(string)
+		case "after":
+			delete(open, callID)
+		}
+	}
+	if len(open) != 0 {
+		t.Errorf("groups left open after Stop: %v", open)
+	}
+}
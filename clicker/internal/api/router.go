@@ -13,8 +13,10 @@ import (
 	"github.com/vibium/clicker/internal/browser"
 )
 
-// DefaultTimeout is the default timeout for element resolution and actionability checks.
-const DefaultTimeout = 30 * time.Second
+// DefaultTimeout is the default timeout for element resolution, actionability
+// checks, and waits. It is a variable so the CLI can override it from
+// --timeout or VIBIUM_TIMEOUT at startup.
+var DefaultTimeout = 30 * time.Second
 
 // BrowserSession represents a browser session connected to a client.
 type BrowserSession struct {
@@ -204,7 +206,7 @@ func handlerCapturesBefore(method string) bool {
 	case "vibium:element.click", "vibium:element.dblclick", "vibium:element.hover", "vibium:element.tap",
 		"vibium:element.check", "vibium:element.uncheck", "vibium:element.dragTo",
 		"vibium:element.fill", "vibium:element.type", "vibium:element.press", "vibium:element.clear",
-		"vibium:element.selectOption":
+		"vibium:element.selectOption", "vibium:element.selectByIndex":
 		return true
 	}
 	return false
@@ -338,6 +340,9 @@ func (r *Router) OnClientMessage(client ClientTransport, msg string) {
 	case "vibium:element.selectOption":
 		r.dispatch(session, cmd, r.handleVibiumSelectOption)
 		return
+	case "vibium:element.selectByIndex":
+		r.dispatch(session, cmd, r.handleVibiumSelectByIndex)
+		return
 	case "vibium:element.hover":
 		r.dispatch(session, cmd, r.handleVibiumHover)
 		return
@@ -410,6 +415,9 @@ func (r *Router) OnClientMessage(client ClientTransport, msg string) {
 	case "vibium:keyboard.press":
 		r.dispatch(session, cmd, r.handleKeyboardPress)
 		return
+	case "vibium:keyboard.pressSequence":
+		r.dispatch(session, cmd, r.handleKeyboardPressSequence)
+		return
 	case "vibium:keyboard.down":
 		r.dispatch(session, cmd, r.handleKeyboardDown)
 		return
@@ -570,6 +578,9 @@ func (r *Router) OnClientMessage(client ClientTransport, msg string) {
 	case "vibium:page.setGeolocation":
 		r.dispatch(session, cmd, r.handlePageSetGeolocation)
 		return
+	case "vibium:browser.setUserAgent":
+		r.dispatch(session, cmd, r.handleBrowserSetUserAgent)
+		return
 	case "vibium:page.setWindow":
 		r.dispatch(session, cmd, r.handlePageSetWindow)
 		return
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vibium/clicker/internal/bidi"
+)
+
+// fakeClient records every message the router sends to the client.
+type fakeClient struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (c *fakeClient) ID() uint64 { return 1 }
+
+func (c *fakeClient) Send(msg string) error {
+	c.mu.Lock()
+	c.msgs = append(c.msgs, msg)
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *fakeClient) Close() error { return nil }
+
+func (c *fakeClient) responsesFor(id int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, m := range c.msgs {
+		var resp struct {
+			ID int `json:"id"`
+		}
+		if json.Unmarshal([]byte(m), &resp) == nil && resp.ID == id {
+			n++
+		}
+	}
+	return n
+}
+
+// newFakeBidiServer starts a WebSocket server that answers every command
+// with an "unknown command" error, like a browser that doesn't know the
+// vibium: extension methods.
+func newFakeBidiServer(t *testing.T) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var cmd struct {
+				ID int `json:"id"`
+			}
+			json.Unmarshal(data, &cmd)
+			resp, _ := json.Marshal(map[string]interface{}{
+				"id":      cmd.ID,
+				"type":    "error",
+				"error":   "unknown command",
+				"message": "fake browser",
+			})
+			if err := conn.WriteMessage(websocket.TextMessage, resp); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestVibiumCommandsAreNotForwarded(t *testing.T) {
+	methods := []string{
+		"vibium:element.click",
+		"vibium:element.selectOption",
+		"vibium:element.selectByIndex",
+	}
+	for i, method := range methods {
+		t.Run(method, func(t *testing.T) {
+			srv := newFakeBidiServer(t)
+			conn, err := bidi.Connect("ws" + strings.TrimPrefix(srv.URL, "http"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := NewRouter(true, "", nil)
+			client := &fakeClient{}
+			session := &BrowserSession{
+				BidiConn:       conn,
+				Client:         client,
+				stopChan:       make(chan struct{}),
+				internalCmds:   make(map[int]chan json.RawMessage),
+				nextInternalID: 1000000,
+			}
+			r.sessions.Store(client.ID(), session)
+			go r.routeBrowserToClient(session)
+			t.Cleanup(func() { r.OnClientDisconnect(client) })
+
+			id := i + 1
+			msg, _ := json.Marshal(map[string]interface{}{
+				"id":     id,
+				"method": method,
+				"params": map[string]interface{}{"selector": "select", "value": "a", "index": 0},
+			})
+			r.OnClientMessage(client, string(msg))
+
+			deadline := time.Now().Add(5 * time.Second)
+			for client.responsesFor(id) == 0 && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			// Give a raw forward to the browser time to come back as a second response.
+			time.Sleep(200 * time.Millisecond)
+			if got := client.responsesFor(id); got != 1 {
+				t.Errorf("got %d responses for id %d, want 1", got, id)
+			}
+		})
+	}
+}
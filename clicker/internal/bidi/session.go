@@ -100,6 +100,27 @@ func (c *Client) SendCommandWithTimeout(method string, params interface{}, timeo
 	}
 }
 
+// SendCommandNoWait sends a BiDi command without waiting for its response.
+// The response is discarded when it arrives during a later SendCommand. Use it
+// from an event handler, where waiting would consume the in-flight response.
+func (c *Client) SendCommandNoWait(method string, params interface{}) error {
+	cmd := NewCommand(method, params)
+
+	data, err := cmd.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to marshal command: %w", err)
+	}
+
+	if c.verbose {
+		fmt.Printf("       --> %s\n", string(data))
+	}
+
+	if err := c.conn.Send(string(data)); err != nil {
+		return fmt.Errorf("failed to send command: %w", err)
+	}
+	return nil
+}
+
 // SessionStatusResult represents the result of session.status command.
 type SessionStatusResult struct {
 	Ready   bool   `json:"ready"`
@@ -3,6 +3,7 @@ package bidi
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 // Cookie represents a browser cookie.
@@ -15,6 +16,54 @@ type Cookie struct {
 	HTTPOnly bool    `json:"httpOnly,omitempty"`
 	SameSite string  `json:"sameSite,omitempty"`
 	Size     float64 `json:"size,omitempty"`
+	Expiry   int64   `json:"expiry,omitempty"` // epoch seconds; 0 means a session cookie
+}
+
+// Normalize lowercases SameSite and validates the cookie attributes.
+// SameSite must be strict, lax, or none, and none requires Secure.
+func (c *Cookie) Normalize() error {
+	c.SameSite = strings.ToLower(c.SameSite)
+	switch c.SameSite {
+	case "", "strict", "lax":
+	case "none":
+		if !c.Secure {
+			return fmt.Errorf("sameSite=none requires secure")
+		}
+	default:
+		return fmt.Errorf("invalid sameSite %q (expected strict, lax, or none)", c.SameSite)
+	}
+	if c.Expiry < 0 {
+		return fmt.Errorf("expiry must be epoch seconds, got %d", c.Expiry)
+	}
+	return nil
+}
+
+// PartialCookie returns the cookie as a storage.PartialCookie map for
+// storage.setCookie. Unset attributes are left to the browser defaults.
+func (c Cookie) PartialCookie() map[string]interface{} {
+	cookieMap := map[string]interface{}{
+		"name":  c.Name,
+		"value": map[string]interface{}{"type": "string", "value": c.Value},
+	}
+	if c.Domain != "" {
+		cookieMap["domain"] = c.Domain
+	}
+	if c.Path != "" {
+		cookieMap["path"] = c.Path
+	}
+	if c.Secure {
+		cookieMap["secure"] = true
+	}
+	if c.HTTPOnly {
+		cookieMap["httpOnly"] = true
+	}
+	if c.SameSite != "" {
+		cookieMap["sameSite"] = c.SameSite
+	}
+	if c.Expiry > 0 {
+		cookieMap["expiry"] = c.Expiry
+	}
+	return cookieMap
 }
 
 // PartitionKey represents a storage partition key for cookies.
@@ -71,19 +120,12 @@ func (c *Client) SetCookie(context string, cookie Cookie) error {
 		context = tree.Contexts[0].Context
 	}
 
-	cookieMap := map[string]interface{}{
-		"name":  cookie.Name,
-		"value": map[string]interface{}{"type": "string", "value": cookie.Value},
-	}
-	if cookie.Domain != "" {
-		cookieMap["domain"] = cookie.Domain
-	}
-	if cookie.Path != "" {
-		cookieMap["path"] = cookie.Path
+	if err := cookie.Normalize(); err != nil {
+		return err
 	}
 
 	params := map[string]interface{}{
-		"cookie": cookieMap,
+		"cookie": cookie.PartialCookie(),
 		"partition": map[string]interface{}{
 			"type":    "context",
 			"context": context,
@@ -48,8 +48,12 @@ func Call(toolName string, args map[string]interface{}) (*agent.ToolsCallResult,
 	}
 
 	if result.IsError {
-		if len(result.Content) > 0 {
-			return nil, fmt.Errorf("%s", result.Content[0].Text)
+		// Error results may lead with an image (a screenshot that differs
+		// from its baseline), so report the first text.
+		for _, c := range result.Content {
+			if c.Type == "text" && c.Text != "" {
+				return nil, fmt.Errorf("%s", c.Text)
+			}
 		}
 		return nil, fmt.Errorf("tool call failed")
 	}
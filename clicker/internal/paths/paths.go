@@ -201,3 +201,16 @@ func GetScreenshotDir() (string, error) {
 		return filepath.Join(home, "Pictures", "Vibium"), nil
 	}
 }
+
+// GetSkillsDir returns the directory where composable skill files are stored.
+// Defaults to ~/.vibium/skills/ and can be overridden with VIBIUM_SKILLS_DIR.
+func GetSkillsDir() (string, error) {
+	if dir := os.Getenv("VIBIUM_SKILLS_DIR"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".vibium", "skills"), nil
+}
@@ -20,6 +20,36 @@ export interface ActionOptions {
   timeout?: number;
 }
 
+export interface ClickOptions extends ActionOptions {
+  /** Skip actionability checks and click as soon as the element is found. Bypasses safety checks. */
+  force?: boolean;
+}
+
+export interface TypeOptions extends ActionOptions {
+  /** Skip actionability checks and type as soon as the element is found. Bypasses safety checks. */
+  force?: boolean;
+  /** Insert the text in one edit instead of per-character key presses (CJK, emoji). */
+  raw?: boolean;
+}
+
+export interface DragOptions extends ActionOptions {
+  /** 'pointer' (mouse input, default) or 'html5' (synthetic drag-and-drop events). */
+  mode?: 'pointer' | 'html5';
+  /** Pause in ms between pressing the button and moving. Default: 100 */
+  holdMs?: number;
+  /** Total duration of the move in ms. Default: 200 */
+  moveMs?: number;
+  /** Number of intermediate pointer moves. Default: 1 */
+  steps?: number;
+}
+
+export interface HoverOptions extends ActionOptions {
+  /** Glide to the element over this many frames instead of jumping. */
+  steps?: number;
+  /** Keep hovering for this many milliseconds after arriving. */
+  holdMs?: number;
+}
+
 export interface SelectorOptions {
   role?: string;
   text?: string;
@@ -30,6 +60,10 @@ export interface SelectorOptions {
   testid?: string;
   xpath?: string;
   near?: string;
+  /** Require text/label to equal the element's trimmed text. Default is a case-sensitive substring match. */
+  exact?: boolean;
+  /** Ignore case when matching text/label. */
+  caseInsensitive?: boolean;
   timeout?: number;
 }
 
@@ -81,9 +115,10 @@ export class Element {
    * Click the element.
    * Waits for element to be visible, stable, receive events, and enabled.
    */
-  async click(options?: ActionOptions): Promise<void> {
+  async click(options?: ClickOptions): Promise<void> {
     await this.client.send('vibium:element.click', this.commandParams({
       timeout: options?.timeout,
+      force: options?.force,
     }));
   }
 
@@ -109,10 +144,12 @@ export class Element {
    * Type text into the element (appends to existing content).
    * Waits for element to be visible, stable, receive events, enabled, and editable.
    */
-  async type(text: string, options?: ActionOptions): Promise<void> {
+  async type(text: string, options?: TypeOptions): Promise<void> {
     await this.client.send('vibium:element.type', this.commandParams({
       text,
       timeout: options?.timeout,
+      raw: options?.raw,
+      force: options?.force,
     }));
   }
 
@@ -157,9 +194,11 @@ export class Element {
   }
 
   /** Hover over the element (move mouse to center, no click). */
-  async hover(options?: ActionOptions): Promise<void> {
+  async hover(options?: HoverOptions): Promise<void> {
     await this.client.send('vibium:element.hover', this.commandParams({
       timeout: options?.timeout,
+      steps: options?.steps,
+      holdMs: options?.holdMs,
     }));
   }
 
@@ -171,10 +210,14 @@ export class Element {
   }
 
   /** Drag this element to a target element. */
-  async dragTo(target: Element, options?: ActionOptions): Promise<void> {
+  async dragTo(target: Element, options?: DragOptions): Promise<void> {
     await this.client.send('vibium:element.dragTo', this.commandParams({
       target: target.toParams(),
       timeout: options?.timeout,
+      mode: options?.mode,
+      holdMs: options?.holdMs,
+      moveMs: options?.moveMs,
+      steps: options?.steps,
     }));
   }
 
@@ -201,10 +244,13 @@ export class Element {
     }));
   }
 
-  /** Set files on an <input type="file"> element. */
-  async setFiles(files: string[], options?: ActionOptions): Promise<void> {
+  /**
+   * Set files on an <input type="file"> element. Pass `{ directory }` to
+   * upload every file in a directory to an input with `webkitdirectory`.
+   */
+  async setFiles(files: string[] | { directory: string }, options?: ActionOptions): Promise<void> {
     await this.client.send('vibium:element.setFiles', this.commandParams({
-      files,
+      ...(Array.isArray(files) ? { files } : { directory: files.directory }),
       timeout: options?.timeout,
     }));
   }
@@ -387,22 +433,22 @@ export class Element {
 /** A Promise<Element> that also exposes Element methods for chaining. */
 export type FluentElement = Promise<Element> & {
   // Interaction
-  click(options?: ActionOptions): Promise<void>;
+  click(options?: ClickOptions): Promise<void>;
   dblclick(options?: ActionOptions): Promise<void>;
   fill(value: string, options?: ActionOptions): Promise<void>;
-  type(text: string, options?: ActionOptions): Promise<void>;
+  type(text: string, options?: TypeOptions): Promise<void>;
   press(key: string, options?: ActionOptions): Promise<void>;
   clear(options?: ActionOptions): Promise<void>;
   check(options?: ActionOptions): Promise<void>;
   uncheck(options?: ActionOptions): Promise<void>;
   selectOption(value: string, options?: ActionOptions): Promise<void>;
-  hover(options?: ActionOptions): Promise<void>;
+  hover(options?: HoverOptions): Promise<void>;
   focus(options?: ActionOptions): Promise<void>;
-  dragTo(target: Element, options?: ActionOptions): Promise<void>;
+  dragTo(target: Element, options?: DragOptions): Promise<void>;
   tap(options?: ActionOptions): Promise<void>;
   scrollIntoView(options?: ActionOptions): Promise<void>;
   dispatchEvent(eventType: string, eventInit?: Record<string, unknown>, options?: ActionOptions): Promise<void>;
-  setFiles(files: string[], options?: ActionOptions): Promise<void>;
+  setFiles(files: string[] | { directory: string }, options?: ActionOptions): Promise<void>;
   // State
   text(): Promise<string>;
   innerText(): Promise<string>;
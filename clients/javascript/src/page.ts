@@ -19,10 +19,25 @@ export interface FindOptions {
 export interface ScreenshotOptions {
   /** Capture full scrollable page instead of just the viewport. */
   fullPage?: boolean;
+  /** Capture the full page by scrolling and stitching viewport captures, for pages too tall for fullPage. */
+  stitch?: boolean;
   /** Capture a specific region of the page. */
   clip?: { x: number; y: number; width: number; height: number };
 }
 
+export interface GeolocationCoords {
+  latitude: number;
+  longitude: number;
+  /** Accuracy in meters (default: 1). */
+  accuracy?: number;
+  /** Altitude in meters. */
+  altitude?: number;
+  /** Direction of travel in degrees clockwise from true north. */
+  heading?: number;
+  /** Ground speed in meters per second. */
+  speed?: number;
+}
+
 interface VibiumFindResult {
   tag: string;
   text: string;
@@ -59,6 +74,14 @@ export class Keyboard {
     });
   }
 
+  /** Press keys or combos in order, sent as one burst (e.g. ["g", "g"]). */
+  async pressSequence(keys: string[]): Promise<void> {
+    await this.client.send('vibium:keyboard.pressSequence', {
+      context: this.contextId,
+      keys,
+    });
+  }
+
   /** Press a key down (without releasing). */
   async down(key: string): Promise<void> {
     await this.client.send('vibium:keyboard.down', {
@@ -458,11 +481,11 @@ export class Page {
     });
   }
 
-  /** Override the browser's geolocation. */
-  async setGeolocation(coords: { latitude: number; longitude: number; accuracy?: number }): Promise<void> {
+  /** Override the browser's geolocation. Pass null to clear the override. */
+  async setGeolocation(coords: GeolocationCoords | null): Promise<void> {
     await this.client.send('vibium:page.setGeolocation', {
       context: this.contextId,
-      ...coords,
+      ...(coords ?? { latitude: null, longitude: null }),
     });
   }
 
@@ -525,6 +548,7 @@ export class Page {
     const result = await this.client.send<ScreenshotResult>('vibium:page.screenshot', {
       context: this.contextId,
       fullPage: options?.fullPage,
+      stitch: options?.stitch,
       clip: options?.clip,
     });
     return Buffer.from(result.data, 'base64');
@@ -11,6 +11,8 @@ export interface RecordingStartOptions {
   format?: 'jpeg' | 'png';
   /** JPEG quality 0.0-1.0 (default 0.5). Ignored for PNG. */
   quality?: number;
+  /** Also capture a screenshot every N ms (minimum 100). Default: after each action only. */
+  intervalMs?: number;
 }
 
 export interface RecordingStopOptions {
@@ -1,5 +1,5 @@
 import { SyncBridge } from './bridge';
-import { ActionOptions, BoundingBox, ElementInfo, SelectorOptions } from '../element';
+import { ActionOptions, BoundingBox, ClickOptions, DragOptions, ElementInfo, HoverOptions, SelectorOptions, TypeOptions } from '../element';
 
 const customInspect = Symbol.for('nodejs.util.inspect.custom');
 
@@ -23,7 +23,7 @@ export class ElementSync {
    * Click the element.
    * Waits for element to be visible, stable, receive events, and enabled.
    */
-  click(options?: ActionOptions): void {
+  click(options?: ClickOptions): void {
     this.bridge.call('element.click', [this.elementId, options]);
   }
 
@@ -44,7 +44,7 @@ export class ElementSync {
    * Type text into the element.
    * Waits for element to be visible, stable, receive events, enabled, and editable.
    */
-  type(text: string, options?: ActionOptions): void {
+  type(text: string, options?: TypeOptions): void {
     this.bridge.call('element.type', [this.elementId, text, options]);
   }
 
@@ -77,7 +77,7 @@ export class ElementSync {
   }
 
   /** Hover over the element (move mouse to center, no click). */
-  hover(options?: ActionOptions): void {
+  hover(options?: HoverOptions): void {
     this.bridge.call('element.hover', [this.elementId, options]);
   }
 
@@ -87,7 +87,7 @@ export class ElementSync {
   }
 
   /** Drag this element to a target element. */
-  dragTo(target: ElementSync, options?: ActionOptions): void {
+  dragTo(target: ElementSync, options?: DragOptions): void {
     this.bridge.call('element.dragTo', [this.elementId, (target as any).elementId, options]);
   }
 
@@ -180,7 +180,7 @@ export class ElementSync {
     this.bridge.call('element.waitUntil', [this.elementId, state, options]);
   }
 
-  setFiles(files: string[], options?: ActionOptions): void {
+  setFiles(files: string[] | { directory: string }, options?: ActionOptions): void {
     this.bridge.call('element.setFiles', [this.elementId, files, options]);
   }
 
@@ -13,6 +13,10 @@ export class KeyboardSync {
     this.bridge.call('keyboard.press', [this.pageId, key]);
   }
 
+  pressSequence(keys: string[]): void {
+    this.bridge.call('keyboard.pressSequence', [this.pageId, keys]);
+  }
+
   down(key: string): void {
     this.bridge.call('keyboard.down', [this.pageId, key]);
   }
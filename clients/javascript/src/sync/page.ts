@@ -8,7 +8,7 @@ import { BrowserContextSync } from './context';
 import { RouteSync, RouteRequest } from './route';
 import { DialogSync, DialogData } from './dialog';
 import { ElementInfo, SelectorOptions } from '../element';
-import { A11yNode, ScreenshotOptions, FindOptions } from '../page';
+import { A11yNode, ScreenshotOptions, FindOptions, GeolocationCoords } from '../page';
 
 const customInspect = Symbol.for('nodejs.util.inspect.custom');
 
@@ -333,7 +333,7 @@ export class PageSync {
     this._bridge.call('page.setContent', [this._pageId, html]);
   }
 
-  setGeolocation(coords: { latitude: number; longitude: number; accuracy?: number }): void {
+  setGeolocation(coords: GeolocationCoords | null): void {
     this._bridge.call('page.setGeolocation', [this._pageId, coords]);
   }
 
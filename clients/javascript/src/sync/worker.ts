@@ -1,6 +1,6 @@
 import { parentPort, workerData, MessagePort } from 'worker_threads';
 import { browser, Browser } from '../browser';
-import { Page } from '../page';
+import { Page, GeolocationCoords } from '../page';
 import { BrowserContext } from '../context';
 import { Element, SelectorOptions } from '../element';
 
@@ -454,7 +454,7 @@ const handlers: Record<string, Handler> = {
   },
 
   'page.setGeolocation': async (args) => {
-    const [pageId, coords] = args as [number, { latitude: number; longitude: number; accuracy?: number }];
+    const [pageId, coords] = args as [number, GeolocationCoords | null];
     await getPage(pageId).setGeolocation(coords);
     return { success: true };
   },
@@ -1073,6 +1073,12 @@ const handlers: Record<string, Handler> = {
     return { success: true };
   },
 
+  'keyboard.pressSequence': async (args) => {
+    const [pageId, keys] = args as [number, string[]];
+    await getPage(pageId).keyboard.pressSequence(keys);
+    return { success: true };
+  },
+
   'keyboard.down': async (args) => {
     const [pageId, key] = args as [number, string];
     await getPage(pageId).keyboard.down(key);
@@ -1231,7 +1237,7 @@ const handlers: Record<string, Handler> = {
   },
 
   'element.setFiles': async (args) => {
-    const [elementId, files, options] = args as [number, string[], any];
+    const [elementId, files, options] = args as [number, string[] | { directory: string }, any];
     await getElement(elementId).setFiles(files, options);
     return { success: true };
   },
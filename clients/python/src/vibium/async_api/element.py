@@ -58,8 +58,8 @@ class Element:
 
     # --- Interaction ---
 
-    async def click(self, timeout: Optional[int] = None) -> None:
-        await self._client.send("vibium:element.click", self._command_params({"timeout": timeout}))
+    async def click(self, timeout: Optional[int] = None, force: bool = False) -> None:
+        await self._client.send("vibium:element.click", self._command_params({"timeout": timeout, "force": force}))
 
     async def dblclick(self, timeout: Optional[int] = None) -> None:
         await self._client.send("vibium:element.dblclick", self._command_params({"timeout": timeout}))
@@ -67,8 +67,10 @@ class Element:
     async def fill(self, value: str, timeout: Optional[int] = None) -> None:
         await self._client.send("vibium:element.fill", self._command_params({"value": value, "timeout": timeout}))
 
-    async def type(self, text: str, timeout: Optional[int] = None) -> None:
-        await self._client.send("vibium:element.type", self._command_params({"text": text, "timeout": timeout}))
+    async def type(self, text: str, timeout: Optional[int] = None, raw: bool = False, force: bool = False) -> None:
+        await self._client.send("vibium:element.type", self._command_params({
+            "text": text, "timeout": timeout, "raw": raw, "force": force,
+        }))
 
     async def press(self, key: str, timeout: Optional[int] = None) -> None:
         await self._client.send("vibium:element.press", self._command_params({"key": key, "timeout": timeout}))
@@ -85,16 +87,37 @@ class Element:
     async def select_option(self, value: str, timeout: Optional[int] = None) -> None:
         await self._client.send("vibium:element.selectOption", self._command_params({"value": value, "timeout": timeout}))
 
-    async def hover(self, timeout: Optional[int] = None) -> None:
-        await self._client.send("vibium:element.hover", self._command_params({"timeout": timeout}))
+    async def hover(
+        self,
+        timeout: Optional[int] = None,
+        steps: Optional[int] = None,
+        hold_ms: Optional[int] = None,
+    ) -> None:
+        await self._client.send("vibium:element.hover", self._command_params({
+            "timeout": timeout,
+            "steps": steps,
+            "holdMs": hold_ms,
+        }))
 
     async def focus(self, timeout: Optional[int] = None) -> None:
         await self._client.send("vibium:element.focus", self._command_params({"timeout": timeout}))
 
-    async def drag_to(self, target: Element, timeout: Optional[int] = None) -> None:
+    async def drag_to(
+        self,
+        target: Element,
+        timeout: Optional[int] = None,
+        hold_ms: Optional[int] = None,
+        move_ms: Optional[int] = None,
+        steps: Optional[int] = None,
+        mode: Optional[str] = None,
+    ) -> None:
         await self._client.send("vibium:element.dragTo", self._command_params({
             "target": target._to_params(),
             "timeout": timeout,
+            "mode": mode,
+            "holdMs": hold_ms,
+            "moveMs": move_ms,
+            "steps": steps,
         }))
 
     async def tap(self, timeout: Optional[int] = None) -> None:
@@ -115,11 +138,13 @@ class Element:
             "timeout": timeout,
         }))
 
-    async def set_files(self, files: List[str], timeout: Optional[int] = None) -> None:
-        await self._client.send("vibium:element.setFiles", self._command_params({
-            "files": files,
-            "timeout": timeout,
-        }))
+    async def set_files(self, files: Optional[List[str]] = None, timeout: Optional[int] = None,
+                        directory: Optional[str] = None) -> None:
+        """Set files on a file input. Pass directory= to upload every file in a
+        directory to an input with the webkitdirectory attribute."""
+        params = {"files": files} if directory is None else {"directory": directory}
+        params["timeout"] = timeout
+        await self._client.send("vibium:element.setFiles", self._command_params(params))
 
     # --- State ---
 
@@ -210,6 +235,8 @@ class Element:
         testid: Optional[str] = None,
         xpath: Optional[str] = None,
         near: Optional[str] = None,
+        exact: bool = False,
+        case_insensitive: bool = False,
         timeout: Optional[int] = None,
     ) -> Element:
         params: Dict[str, Any] = {
@@ -225,6 +252,10 @@ class Element:
                              ("testid", testid), ("xpath", xpath), ("near", near)]:
                 if val is not None:
                     params[key] = val
+            if exact:
+                params["exact"] = True
+            if case_insensitive:
+                params["caseInsensitive"] = True
 
         result = await self._client.send("vibium:element.find", params)
         info = ElementInfo(
@@ -252,6 +283,8 @@ class Element:
         testid: Optional[str] = None,
         xpath: Optional[str] = None,
         near: Optional[str] = None,
+        exact: bool = False,
+        case_insensitive: bool = False,
         timeout: Optional[int] = None,
     ) -> List[Element]:
         params: Dict[str, Any] = {
@@ -267,6 +300,10 @@ class Element:
                              ("testid", testid), ("xpath", xpath), ("near", near)]:
                 if val is not None:
                     params[key] = val
+            if exact:
+                params["exact"] = True
+            if case_insensitive:
+                params["caseInsensitive"] = True
 
         result = await self._client.send("vibium:element.findAll", params)
         sel_str = selector or ""
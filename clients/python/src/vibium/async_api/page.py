@@ -43,6 +43,9 @@ class Keyboard:
     async def press(self, key: str) -> None:
         await self._client.send("vibium:keyboard.press", {"context": self._context_id, "key": key})
 
+    async def press_sequence(self, keys: List[str]) -> None:
+        await self._client.send("vibium:keyboard.pressSequence", {"context": self._context_id, "keys": keys})
+
     async def down(self, key: str) -> None:
         await self._client.send("vibium:keyboard.down", {"context": self._context_id, "key": key})
 
@@ -187,6 +190,8 @@ class Page:
         testid: Optional[str] = None,
         xpath: Optional[str] = None,
         near: Optional[str] = None,
+        exact: bool = False,
+        case_insensitive: bool = False,
         timeout: Optional[int] = None,
     ) -> Element:
         """Find an element by CSS selector or semantic options."""
@@ -199,6 +204,10 @@ class Page:
                              ("testid", testid), ("xpath", xpath), ("near", near)]:
                 if val is not None:
                     params[key] = val
+            if exact:
+                params["exact"] = True
+            if case_insensitive:
+                params["caseInsensitive"] = True
 
         result = await self._client.send("vibium:page.find", params)
         info = ElementInfo(tag=result["tag"], text=result["text"], box=BoundingBox(**result["box"]))
@@ -222,6 +231,8 @@ class Page:
         testid: Optional[str] = None,
         xpath: Optional[str] = None,
         near: Optional[str] = None,
+        exact: bool = False,
+        case_insensitive: bool = False,
         timeout: Optional[int] = None,
     ) -> List[Element]:
         """Find all elements matching a selector or semantic options."""
@@ -234,6 +245,10 @@ class Page:
                              ("testid", testid), ("xpath", xpath), ("near", near)]:
                 if val is not None:
                     params[key] = val
+            if exact:
+                params["exact"] = True
+            if case_insensitive:
+                params["caseInsensitive"] = True
 
         result = await self._client.send("vibium:page.findAll", params)
         sel_str = selector or ""
@@ -566,11 +581,13 @@ class Page:
         self,
         full_page: Optional[bool] = None,
         clip: Optional[Dict[str, Any]] = None,
+        stitch: Optional[bool] = None,
     ) -> bytes:
         """Take a screenshot. Returns PNG bytes."""
         result = await self._client.send("vibium:page.screenshot", {
             "context": self._context_id,
             "fullPage": full_page,
+            "stitch": stitch,
             "clip": clip,
         })
         return base64.b64decode(result["data"])
@@ -654,8 +671,10 @@ class Page:
         """Replace the page HTML content."""
         await self._client.send("vibium:page.setContent", {"context": self._context_id, "html": html})
 
-    async def set_geolocation(self, coords: Dict[str, float]) -> None:
-        """Override the browser's geolocation."""
+    async def set_geolocation(self, coords: Optional[Dict[str, float]]) -> None:
+        """Override the browser's geolocation. Pass None to clear the override."""
+        if coords is None:
+            coords = {"latitude": None, "longitude": None}
         await self._client.send("vibium:page.setGeolocation", {
             "context": self._context_id, **coords,
         })
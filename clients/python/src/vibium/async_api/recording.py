@@ -26,12 +26,14 @@ class Recording:
         bidi: Optional[bool] = None,
         format: Optional[str] = None,
         quality: Optional[float] = None,
+        interval_ms: Optional[int] = None,
     ) -> None:
         """Start recording.
 
         Args:
             format: Screenshot format — 'jpeg' (default, faster/smaller) or 'png' (lossless).
             quality: JPEG quality 0.0-1.0 (default 0.5). Ignored for PNG.
+            interval_ms: Also capture a screenshot every N ms (minimum 100).
         """
         params: Dict[str, Any] = {"userContext": self._user_context_id}
         if name is not None:
@@ -50,6 +52,8 @@ class Recording:
             params["format"] = format
         if quality is not None:
             params["quality"] = quality
+        if interval_ms is not None:
+            params["intervalMs"] = interval_ms
         await self._client.send("vibium:recording.start", params)
 
     async def stop(self, path: Optional[str] = None) -> bytes:
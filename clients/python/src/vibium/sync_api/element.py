@@ -27,8 +27,8 @@ class Element:
 
     # --- Interaction ---
 
-    def click(self, timeout: Optional[int] = None) -> None:
-        self._loop.run(self._async.click(timeout))
+    def click(self, timeout: Optional[int] = None, force: bool = False) -> None:
+        self._loop.run(self._async.click(timeout, force))
 
     def dblclick(self, timeout: Optional[int] = None) -> None:
         self._loop.run(self._async.dblclick(timeout))
@@ -36,8 +36,8 @@ class Element:
     def fill(self, value: str, timeout: Optional[int] = None) -> None:
         self._loop.run(self._async.fill(value, timeout))
 
-    def type(self, text: str, timeout: Optional[int] = None) -> None:
-        self._loop.run(self._async.type(text, timeout))
+    def type(self, text: str, timeout: Optional[int] = None, raw: bool = False, force: bool = False) -> None:
+        self._loop.run(self._async.type(text, timeout, raw, force))
 
     def press(self, key: str, timeout: Optional[int] = None) -> None:
         self._loop.run(self._async.press(key, timeout))
@@ -54,14 +54,27 @@ class Element:
     def select_option(self, value: str, timeout: Optional[int] = None) -> None:
         self._loop.run(self._async.select_option(value, timeout))
 
-    def hover(self, timeout: Optional[int] = None) -> None:
-        self._loop.run(self._async.hover(timeout))
+    def hover(
+        self,
+        timeout: Optional[int] = None,
+        steps: Optional[int] = None,
+        hold_ms: Optional[int] = None,
+    ) -> None:
+        self._loop.run(self._async.hover(timeout, steps, hold_ms))
 
     def focus(self, timeout: Optional[int] = None) -> None:
         self._loop.run(self._async.focus(timeout))
 
-    def drag_to(self, target: Element, timeout: Optional[int] = None) -> None:
-        self._loop.run(self._async.drag_to(target._async, timeout))
+    def drag_to(
+        self,
+        target: Element,
+        timeout: Optional[int] = None,
+        hold_ms: Optional[int] = None,
+        move_ms: Optional[int] = None,
+        steps: Optional[int] = None,
+        mode: Optional[str] = None,
+    ) -> None:
+        self._loop.run(self._async.drag_to(target._async, timeout, hold_ms, move_ms, steps, mode))
 
     def tap(self, timeout: Optional[int] = None) -> None:
         self._loop.run(self._async.tap(timeout))
@@ -77,8 +90,9 @@ class Element:
     ) -> None:
         self._loop.run(self._async.dispatch_event(event_type, event_init, timeout))
 
-    def set_files(self, files: List[str], timeout: Optional[int] = None) -> None:
-        self._loop.run(self._async.set_files(files, timeout))
+    def set_files(self, files: Optional[List[str]] = None, timeout: Optional[int] = None,
+                  directory: Optional[str] = None) -> None:
+        self._loop.run(self._async.set_files(files, timeout, directory))
 
     # --- State ---
 
@@ -150,11 +164,14 @@ class Element:
         testid: Optional[str] = None,
         xpath: Optional[str] = None,
         near: Optional[str] = None,
+        exact: bool = False,
+        case_insensitive: bool = False,
         timeout: Optional[int] = None,
     ) -> Element:
         async_el = self._loop.run(self._async.find(
             selector, role=role, text=text, label=label, placeholder=placeholder,
-            alt=alt, title=title, testid=testid, xpath=xpath, near=near, timeout=timeout,
+            alt=alt, title=title, testid=testid, xpath=xpath, near=near,
+            exact=exact, case_insensitive=case_insensitive, timeout=timeout,
         ))
         return Element(async_el, self._loop)
 
@@ -172,10 +189,13 @@ class Element:
         testid: Optional[str] = None,
         xpath: Optional[str] = None,
         near: Optional[str] = None,
+        exact: bool = False,
+        case_insensitive: bool = False,
         timeout: Optional[int] = None,
     ) -> List[Element]:
         async_elements = self._loop.run(self._async.find_all(
             selector, role=role, text=text, label=label, placeholder=placeholder,
-            alt=alt, title=title, testid=testid, xpath=xpath, near=near, timeout=timeout,
+            alt=alt, title=title, testid=testid, xpath=xpath, near=near,
+            exact=exact, case_insensitive=case_insensitive, timeout=timeout,
         ))
         return [Element(el, self._loop) for el in async_elements]
@@ -26,6 +26,9 @@ class Keyboard:
     def press(self, key: str) -> None:
         self._loop.run(self._async.press(key))
 
+    def press_sequence(self, keys: List[str]) -> None:
+        self._loop.run(self._async.press_sequence(keys))
+
     def down(self, key: str) -> None:
         self._loop.run(self._async.down(key))
 
@@ -148,11 +151,14 @@ class Page:
         testid: Optional[str] = None,
         xpath: Optional[str] = None,
         near: Optional[str] = None,
+        exact: bool = False,
+        case_insensitive: bool = False,
         timeout: Optional[int] = None,
     ) -> Element:
         async_el = self._loop.run(self._async.find(
             selector, role=role, text=text, label=label, placeholder=placeholder,
-            alt=alt, title=title, testid=testid, xpath=xpath, near=near, timeout=timeout,
+            alt=alt, title=title, testid=testid, xpath=xpath, near=near,
+            exact=exact, case_insensitive=case_insensitive, timeout=timeout,
         ))
         return Element(async_el, self._loop)
 
@@ -170,11 +176,14 @@ class Page:
         testid: Optional[str] = None,
         xpath: Optional[str] = None,
         near: Optional[str] = None,
+        exact: bool = False,
+        case_insensitive: bool = False,
         timeout: Optional[int] = None,
     ) -> List[Element]:
         async_elements = self._loop.run(self._async.find_all(
             selector, role=role, text=text, label=label, placeholder=placeholder,
-            alt=alt, title=title, testid=testid, xpath=xpath, near=near, timeout=timeout,
+            alt=alt, title=title, testid=testid, xpath=xpath, near=near,
+            exact=exact, case_insensitive=case_insensitive, timeout=timeout,
         ))
         return [Element(el, self._loop) for el in async_elements]
 
@@ -199,8 +208,9 @@ class Page:
         self,
         full_page: Optional[bool] = None,
         clip: Optional[Dict[str, Any]] = None,
+        stitch: Optional[bool] = None,
     ) -> bytes:
-        return self._loop.run(self._async.screenshot(full_page=full_page, clip=clip))
+        return self._loop.run(self._async.screenshot(full_page=full_page, clip=clip, stitch=stitch))
 
     def pdf(self) -> bytes:
         return self._loop.run(self._async.pdf())
@@ -248,7 +258,7 @@ class Page:
     def set_content(self, html: str) -> None:
         self._loop.run(self._async.set_content(html))
 
-    def set_geolocation(self, coords: Dict[str, float]) -> None:
+    def set_geolocation(self, coords: Optional[Dict[str, float]]) -> None:
         self._loop.run(self._async.set_geolocation(coords))
 
     def set_window(self, **options: Any) -> None:
@@ -26,11 +26,13 @@ class Recording:
         bidi: Optional[bool] = None,
         format: Optional[str] = None,
         quality: Optional[float] = None,
+        interval_ms: Optional[int] = None,
     ) -> None:
         self._loop.run(self._async.start(name=name, screenshots=screenshots,
                                           snapshots=snapshots, sources=sources,
                                           title=title, bidi=bidi,
-                                          format=format, quality=quality))
+                                          format=format, quality=quality,
+                                          interval_ms=interval_ms))
 
     def stop(self, path: Optional[str] = None) -> bytes:
         return self._loop.run(self._async.stop(path=path))
@@ -67,3 +67,46 @@ describe('CLI: Elements', () => {
     assert.match(result, /typed/i, 'Should confirm text was typed');
   });
 });
+
+describe('CLI: Shadow DOM selectors', () => {
+  test('plain CSS selector matches the light DOM', () => {
+    execSync(`${VIBIUM} go ${baseURL}/shadow`, { encoding: 'utf-8', timeout: 30000 });
+    const result = execSync(`${VIBIUM} text "#light"`, { encoding: 'utf-8', timeout: 30000 });
+    assert.match(result, /Light DOM/, 'Should read light DOM text');
+  });
+
+  test('>>> selector descends through shadow roots', () => {
+    execSync(`${VIBIUM} go ${baseURL}/shadow`, { encoding: 'utf-8', timeout: 30000 });
+    const outer = execSync(`${VIBIUM} text "#host >>> .label"`, { encoding: 'utf-8', timeout: 30000 });
+    assert.match(outer, /Outer shadow/, 'Should read text inside the shadow root');
+    execSync(`${VIBIUM} click "#host >>> #inner-host >>> .deep"`, { encoding: 'utf-8', timeout: 30000 });
+    const deep = execSync(`${VIBIUM} text "#host >>> #inner-host >>> .deep"`, { encoding: 'utf-8', timeout: 30000 });
+    assert.match(deep, /Clicked/, 'Should click inside a nested shadow root');
+  });
+
+  test('pierce= selector searches every open shadow root', () => {
+    execSync(`${VIBIUM} go ${baseURL}/shadow`, { encoding: 'utf-8', timeout: 30000 });
+    const result = execSync(`${VIBIUM} text "pierce=.deep"`, { encoding: 'utf-8', timeout: 30000 });
+    assert.match(result, /Deep button/, 'Should find the element in a nested shadow root');
+  });
+
+  test('state checks and attributes resolve >>> selectors', () => {
+    execSync(`${VIBIUM} go ${baseURL}/shadow`, { encoding: 'utf-8', timeout: 30000 });
+    const visible = execSync(`${VIBIUM} is visible "#host >>> .label"`, { encoding: 'utf-8', timeout: 30000 });
+    assert.match(visible, /true/, 'Should check visibility inside the shadow root');
+    const attr = execSync(`${VIBIUM} attr "#host >>> #inner-host >>> .deep" class`, { encoding: 'utf-8', timeout: 30000 });
+    assert.match(attr, /deep/, 'Should read an attribute inside a nested shadow root');
+  });
+
+  test('text selectors combine with >>>', () => {
+    execSync(`${VIBIUM} go ${baseURL}/shadow`, { encoding: 'utf-8', timeout: 30000 });
+    const result = execSync(`${VIBIUM} text '#host >>> p:has-text("outer")'`, { encoding: 'utf-8', timeout: 30000 });
+    assert.match(result, /Outer shadow/, 'Should match text inside the shadow root');
+  });
+
+  test('state checks accept XPath', () => {
+    execSync(`${VIBIUM} go ${baseURL}/shadow`, { encoding: 'utf-8', timeout: 30000 });
+    const result = execSync(`${VIBIUM} is visible "xpath=//p[@id='light']"`, { encoding: 'utf-8', timeout: 30000 });
+    assert.match(result, /true/, 'Should resolve the XPath selector');
+  });
+});
@@ -0,0 +1,43 @@
+/**
+ * CLI Tests: --json output
+ * Commands print a {ok, result} or {ok, error} envelope and exit accordingly.
+ */
+
+const { test, describe } = require('node:test');
+const assert = require('node:assert');
+const { spawnSync } = require('node:child_process');
+const { VIBIUM } = require('../helpers');
+
+const vibiumJSON = (...args) => {
+  const result = spawnSync(VIBIUM, [...args, '--json'], { encoding: 'utf-8', timeout: 30000 });
+  return { status: result.status, out: JSON.parse(result.stdout) };
+};
+
+describe('CLI: --json output', () => {
+  test('version prints a result envelope', () => {
+    const { status, out } = vibiumJSON('version');
+    assert.strictEqual(status, 0);
+    assert.strictEqual(out.ok, true);
+    assert.match(out.result, /^\S+ v\S+$/);
+  });
+
+  test('paths prints structured paths', () => {
+    const { status, out } = vibiumJSON('paths');
+    assert.strictEqual(status, 0);
+    assert.strictEqual(out.ok, true);
+    assert.deepStrictEqual(Object.keys(out.result).sort(), ['cacheDir', 'chrome', 'chromedriver']);
+  });
+
+  test('argument errors print an error envelope and exit 1', () => {
+    const { status, out } = vibiumJSON('viewport', '800');
+    assert.strictEqual(status, 1);
+    assert.deepStrictEqual(out, { ok: false, error: 'provide both width and height' });
+  });
+
+  test('unknown commands print an error envelope and exit 1', () => {
+    const { status, out } = vibiumJSON('no-such-command');
+    assert.strictEqual(status, 1);
+    assert.strictEqual(out.ok, false);
+    assert.match(out.error, /unknown command/);
+  });
+});
@@ -59,4 +59,26 @@ describe('CLI: Navigation', () => {
     });
     assert.match(result, /Example Domain/i, 'Should return page title');
   });
+
+  test('eval --json keeps the value type and runs the code unchanged', () => {
+    const evalJSON = (expr) => JSON.parse(execSync(`${VIBIUM} eval --json ${JSON.stringify(expr)}`, {
+      encoding: 'utf-8',
+      timeout: 30000,
+    }));
+
+    assert.deepStrictEqual(evalJSON('({ n: 1, tags: ["a"] })'), { ok: true, result: { n: 1, tags: ['a'] } });
+    assert.strictEqual(evalJSON('"42"').result, '42', 'Strings should stay strings');
+    assert.strictEqual(evalJSON('42').result, 42);
+    assert.strictEqual(evalJSON('const a = 20; a + 1').result, 21, 'Multi-statement code should run as written');
+    assert.strictEqual(evalJSON('10n ** 20n').result, '100000000000000000000', 'BigInts should not throw');
+    assert.strictEqual(evalJSON('(() => { const o = {}; o.self = o; return o; })()').ok, true, 'Cyclic values should not throw');
+  });
+
+  test('screenshot and pdf write raw bytes to stdout', () => {
+    const png = execSync(`${VIBIUM} screenshot https://example.com --stdout --json`, { timeout: 30000 });
+    assert.deepStrictEqual([...png.subarray(0, 8)], [0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a], 'Should be only PNG bytes');
+
+    const pdf = execSync(`${VIBIUM} pdf -o -`, { timeout: 30000 });
+    assert.strictEqual(pdf.subarray(0, 5).toString(), '%PDF-', 'Should be only PDF bytes');
+  });
 });
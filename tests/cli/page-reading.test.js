@@ -25,6 +25,22 @@ describe('CLI: Page Reading', () => {
     assert.match(result, /Example Domain/, 'Should contain h1 text');
   });
 
+  test('text --all --json returns a list that @refs can scope', () => {
+    const all = JSON.parse(execSync(`${VIBIUM} text https://example.com "p" --all --json`, {
+      encoding: 'utf-8',
+      timeout: 30000,
+    }));
+    assert.strictEqual(all.ok, true);
+    assert.ok(Array.isArray(all.result) && all.result.length === 2, `Should list both paragraphs: ${JSON.stringify(all)}`);
+
+    execSync(`${VIBIUM} find "p" --all`, { encoding: 'utf-8', timeout: 30000 });
+    const ref = JSON.parse(execSync(`${VIBIUM} text @e2 --all --json`, {
+      encoding: 'utf-8',
+      timeout: 30000,
+    }));
+    assert.deepStrictEqual(ref.result, [all.result[1]], 'Should read only the element behind @e2');
+  });
+
   test('html command returns page HTML', () => {
     const result = execSync(`${VIBIUM} html https://example.com "h1"`, {
       encoding: 'utf-8',
@@ -0,0 +1,58 @@
+/**
+ * CLI Tests: vibium run
+ * Runs script files against a single in-process browser session.
+ */
+
+const { test, describe, before, after } = require('node:test');
+const assert = require('node:assert');
+const { spawnSync } = require('node:child_process');
+const fs = require('node:fs');
+const os = require('node:os');
+const path = require('node:path');
+const { VIBIUM } = require('../helpers');
+
+describe('CLI: run', () => {
+  let dir, script;
+
+  before(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'vibium-run-'));
+    script = path.join(dir, 'steps.txt');
+    fs.writeFileSync(script, [
+      '# a failing step between two reads',
+      'set_content "<h1>Scripted</h1>"',
+      'evaluate "document.querySelector(\'h1\').textContent"',
+      'teleport somewhere',
+      'evaluate "1 + 1"',
+    ].join('\n'));
+  });
+
+  after(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  const run = (...args) => spawnSync(VIBIUM, ['run', script, '--headless', '--json', ...args], {
+    encoding: 'utf-8',
+    timeout: 60000,
+  });
+
+  test('stops at the first failing step', () => {
+    const result = run();
+    assert.strictEqual(result.status, 1, 'Should exit non-zero');
+    const out = JSON.parse(result.stdout);
+    assert.strictEqual(out.ok, false);
+    assert.deepStrictEqual(out.result.map(s => [s.step, s.action, s.ok]), [
+      [1, 'set_content', true],
+      [2, 'evaluate', true],
+      [3, 'teleport', false],
+    ]);
+    assert.strictEqual(out.result[1].result, 'Scripted', 'Steps should share one page');
+  });
+
+  test('--keep-going runs the remaining steps', () => {
+    const result = run('--keep-going');
+    assert.strictEqual(result.status, 1, 'Should still exit non-zero');
+    const out = JSON.parse(result.stdout);
+    assert.strictEqual(out.result.length, 4);
+    assert.strictEqual(out.result[3].result, '2');
+  });
+});
@@ -305,4 +305,16 @@ describe('Daemon CLI: Recording', () => {
     assert.ok(inputEvents[0].box.width > 0, 'box.width should be > 0');
     assert.ok(inputEvents[0].box.height > 0, 'box.height should be > 0');
   });
+
+  test('record open --json reports errors as a JSON envelope', () => {
+    const missing = clickerJSONSafe(`record open ${tmpPath('missing.zip')}`);
+    assert.strictEqual(missing.ok, false);
+    assert.match(missing.error, /failed to open recording/);
+
+    const notRecording = tmpPath('empty.zip');
+    execSync(`cd "${os.tmpdir()}" && echo x > ${path.basename(notRecording)}.txt && zip -q "${notRecording}" ${path.basename(notRecording)}.txt && rm ${path.basename(notRecording)}.txt`);
+    const empty = clickerJSONSafe(`record open ${notRecording}`);
+    assert.strictEqual(empty.ok, false);
+    assert.match(empty.error, /no trace found/);
+  });
 });
@@ -130,6 +130,17 @@ const SELECTORS_HTML = `<html><head><title>Selectors</title></head><body>
   <div class="container"><span class="inner-text">Hello from span</span></div>
 </body></html>`;
 
+const SHADOW_HTML = `<html><head><title>Shadow DOM</title></head><body>
+  <p id="light" class="label">Light DOM</p>
+  <div id="host"></div>
+  <script>
+    var root = document.getElementById('host').attachShadow({ mode: 'open' });
+    root.innerHTML = '<p class="label">Outer shadow</p><div id="inner-host"></div>';
+    root.getElementById('inner-host').attachShadow({ mode: 'open' }).innerHTML =
+      '<button class="deep" onclick="this.textContent = \\'Clicked\\'">Deep button</button>';
+  </script>
+</body></html>`;
+
 const routes = {
   '/': HOME_HTML,
   '/login': LOGIN_HTML,
@@ -141,6 +152,7 @@ const routes = {
   '/dynamic_loading/1': DYNAMIC_LOADING_HTML,
   '/add_remove_elements/': ADD_REMOVE_HTML,
   '/selectors': SELECTORS_HTML,
+  '/shadow': SHADOW_HTML,
 };
 
 function handleRequest(req, res) {
@@ -151,6 +163,23 @@ function handleRequest(req, res) {
     return;
   }
 
+  // Echo the request headers back as JSON (plain text, so the browser shows it as-is)
+  if (req.url === '/headers') {
+    res.writeHead(200, { 'Content-Type': 'text/plain' });
+    res.end(JSON.stringify(req.headers));
+    return;
+  }
+
+  // Serve a small text file as an attachment, so the browser downloads it
+  if (req.url === '/download') {
+    res.writeHead(200, {
+      'Content-Type': 'text/plain',
+      'Content-Disposition': 'attachment; filename="report.txt"',
+    });
+    res.end('quarterly numbers\n');
+    return;
+  }
+
   const html = routes[req.url] || routes['/'];
   res.writeHead(200, { 'Content-Type': 'text/html' });
   res.end(html);
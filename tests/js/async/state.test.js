@@ -35,6 +35,14 @@ describe('Element State: text and content', () => {
     assert.strictEqual(text, 'Example Domain');
   });
 
+  test('text() leaves out text hidden by CSS', async () => {
+    const vibe = await bro.page();
+    await vibe.setContent('<p id="msg">Saved<span style="display:none"> (draft)</span></p>');
+
+    const text = await vibe.find('#msg').text();
+    assert.strictEqual(text, 'Saved');
+  });
+
   test('innerText() returns rendered text', async () => {
     const vibe = await bro.page();
     await vibe.go('https://example.com');
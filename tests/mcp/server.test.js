@@ -10,6 +10,7 @@ const fs = require('node:fs');
 const path = require('node:path');
 const os = require('node:os');
 const { VIBIUM } = require('../helpers');
+const { createTestServer } = require('../helpers/test-server');
 
 /**
  * Helper to run MCP server and send/receive JSON-RPC messages
@@ -22,10 +23,11 @@ class MCPClient {
     this.resolvers = [];
   }
 
-  start() {
+  start(env = {}, args = []) {
     return new Promise((resolve, reject) => {
-      this.proc = spawn(VIBIUM, ['mcp'], {
+      this.proc = spawn(VIBIUM, ['mcp', ...args], {
         stdio: ['pipe', 'pipe', 'pipe'],
+        env: { ...process.env, ...env },
       });
 
       this.proc.stdout.on('data', (data) => {
@@ -94,6 +96,12 @@ class MCPClient {
     return response;
   }
 
+  async callTool(name, args = {}) {
+    const response = await this.call('tools/call', { name, arguments: args });
+    assert.ok(response.result, `${name} should have a result`);
+    return response.result;
+  }
+
   stop() {
     if (this.proc) {
       if (process.platform === 'win32') {
@@ -138,12 +146,12 @@ describe('MCP Server: Protocol', () => {
     assert.ok(response.result.capabilities.tools, 'Should have tools capability');
   });
 
-  test('tools/list returns all 85 browser tools', async () => {
+  test('tools/list returns all 137 browser tools', async () => {
     const response = await client.call('tools/list', {});
 
     assert.ok(response.result, 'Should have result');
     assert.ok(response.result.tools, 'Should have tools array');
-    assert.strictEqual(response.result.tools.length, 85, 'Should have 85 tools');
+    assert.strictEqual(response.result.tools.length, 137, 'Should have 137 tools');
 
     const toolNames = response.result.tools.map(t => t.name);
     const expectedTools = [
@@ -180,6 +188,49 @@ describe('MCP Server: Protocol', () => {
       'browser_record_start_chunk', 'browser_record_stop_chunk',
       'browser_storage_state', 'browser_restore_storage',
       'browser_download_set_dir',
+      'browser_wait_until_stable_screenshot',
+      'browser_invoke_skill_chain',
+      'browser_get_cookies_changed',
+      'browser_set_screenshot_dir',
+      'browser_get_element_html_and_text',
+      'browser_focus_next', 'browser_focus_previous',
+      'browser_print_preview',
+      'browser_select_by_index',
+      'browser_get_attributes',
+      'browser_set_user_agent',
+      'browser_request_intercept', 'browser_request_unintercept',
+      'browser_wait_for_response',
+      'browser_console_logs',
+      'browser_page_errors',
+      'browser_scroll_by',
+      'browser_drag_by',
+      'browser_right_click',
+      'browser_tap',
+      'browser_pinch',
+      'browser_key_sequence',
+      'browser_clear',
+      'browser_is_editable',
+      'browser_get_outer_html',
+      'browser_select_options',
+      'browser_get_table',
+      'browser_get_links',
+      'browser_snapshot',
+      'browser_find_all_by_role',
+      'browser_switch_frame', 'browser_switch_frame_parent', 'browser_switch_frame_top',
+      'browser_wait_for_page',
+      'browser_get_cookie',
+      'browser_clear_storage',
+      'browser_har_start', 'browser_har_stop',
+      'browser_block_urls', 'browser_unblock_urls',
+      'browser_set_offline',
+      'browser_emulate_network',
+      'browser_emulate_cpu',
+      'browser_mock_record', 'browser_mock_replay', 'browser_mock_stop',
+      'browser_set_extra_headers', 'browser_set_basic_auth',
+      'browser_wait_for_download',
+      'browser_list_downloads',
+      'browser_set_dialog_handler',
+      'browser_get_dialog',
     ];
     for (const tool of expectedTools) {
       assert.ok(toolNames.includes(tool), `Should have ${tool}`);
@@ -292,6 +343,18 @@ describe('MCP Server: Browser Tools', () => {
     );
   });
 
+  test('browser_evaluate returns objects as JSON', async () => {
+    const result = await client.callTool('browser_evaluate', {
+      expression: '({ title: document.title, sizes: [1, { h: 2 }] })',
+    });
+
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.deepStrictEqual(JSON.parse(result.content[0].text), {
+      title: 'Example Domain',
+      sizes: [1, { h: 2 }],
+    });
+  });
+
   test('browser_screenshot returns image', async () => {
     const response = await client.call('tools/call', {
       name: 'browser_screenshot',
@@ -307,6 +370,54 @@ describe('MCP Server: Browser Tools', () => {
     assert.ok(content.data.length > 100, 'Should have base64 data');
   });
 
+  test('browser_screenshot paints over one mask selector', async () => {
+    const plain = await client.call('tools/call', { name: 'browser_screenshot', arguments: {} });
+    const response = await client.call('tools/call', {
+      name: 'browser_screenshot',
+      arguments: { mask: ['h1'] },
+    });
+
+    assert.ok(response.result, 'Should have result');
+    assert.ok(!response.result.isError, `Should not be an error: ${JSON.stringify(response.result.content)}`);
+    const content = response.result.content[0];
+    assert.strictEqual(content.type, 'image', 'Should be image type');
+    assert.notStrictEqual(content.data, plain.result.content[0].data, 'Masked screenshot should differ');
+  });
+
+  test('browser_screenshot paints over several mask selectors', async () => {
+    const oneMask = await client.call('tools/call', {
+      name: 'browser_screenshot',
+      arguments: { mask: ['h1'] },
+    });
+    const response = await client.call('tools/call', {
+      name: 'browser_screenshot',
+      arguments: { mask: ['h1', 'p'], maskColor: '#000000' },
+    });
+
+    assert.ok(response.result, 'Should have result');
+    assert.ok(!response.result.isError, `Should not be an error: ${JSON.stringify(response.result.content)}`);
+    const content = response.result.content[0];
+    assert.strictEqual(content.type, 'image', 'Should be image type');
+    assert.notStrictEqual(content.data, oneMask.result.content[0].data, 'Each selector should be masked');
+
+    // Masks are removed after the capture
+    const left = await client.call('tools/call', {
+      name: 'browser_evaluate',
+      arguments: { expression: "document.querySelectorAll('.__vibium_mask').length" },
+    });
+    assert.strictEqual(left.result.content[0].text, '0', 'Should remove mask overlays');
+  });
+
+  test('browser_screenshot rejects a maskColor that is not a CSS color', async () => {
+    const response = await client.call('tools/call', {
+      name: 'browser_screenshot',
+      arguments: { mask: ['h1'], maskColor: 'red;width:100vw' },
+    });
+
+    assert.strictEqual(response.result.isError, true, 'Should be an error');
+    assert.match(response.result.content[0].text, /invalid maskColor/);
+  });
+
   test('browser_click clicks element', async () => {
     const response = await client.call('tools/call', {
       name: 'browser_click',
@@ -1053,3 +1164,1379 @@ describe('MCP Server: Recording', { timeout: 120000 }, () => {
     }
   });
 });
+
+describe('MCP Server: Selectors', () => {
+  let client;
+
+  before(async () => {
+    client = new MCPClient();
+    await client.start();
+    await client.call('initialize', { capabilities: {} });
+  });
+
+  after(async () => {
+    await client.call('tools/call', { name: 'browser_stop', arguments: {} });
+    client.stop();
+  });
+
+  const fruits = `
+    <ul>
+      <li>Apple</li>
+      <li>apple pie</li>
+      <li>Banana</li>
+    </ul>
+    <button>Save</button>
+    <button>Save all</button>
+    <button>Cancel</button>
+  `;
+
+  test('browser_count counts every text= match', async () => {
+    await client.callTool('browser_set_content', { html: fruits });
+
+    const result = await client.callTool('browser_count', { selector: 'text=apple' });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.strictEqual(result.content[0].text, '2');
+  });
+
+  test('browser_count counts every :has-text() match', async () => {
+    await client.callTool('browser_set_content', { html: fruits });
+
+    const result = await client.callTool('browser_count', { selector: 'button:has-text("save")' });
+    assert.strictEqual(result.content[0].text, '2');
+  });
+
+  test('browser_count returns 0 for text that is not on the page', async () => {
+    await client.callTool('browser_set_content', { html: fruits });
+
+    const result = await client.callTool('browser_count', { selector: 'text=Cherry' });
+    assert.ok(!result.isError, 'Should not be an error');
+    assert.strictEqual(result.content[0].text, '0');
+  });
+
+  test('browser_find_all returns every text= match', async () => {
+    await client.callTool('browser_set_content', { html: fruits });
+
+    const result = await client.callTool('browser_find_all', { selector: 'text=apple' });
+    const lines = result.content[0].text.split('\n');
+    assert.strictEqual(lines.length, 2, `Should find both apples: ${result.content[0].text}`);
+    assert.ok(lines[0].startsWith('@e1'));
+    assert.ok(lines[1].startsWith('@e2'));
+  });
+
+  test('browser_get_text all reads every :has-text() match', async () => {
+    await client.callTool('browser_set_content', { html: fruits });
+
+    const result = await client.callTool('browser_get_text', { selector: 'button:has-text("Save")', all: true });
+    assert.deepStrictEqual(result.content[0].text.split('\n'), ['Save', 'Save all']);
+  });
+
+  test('browser_map marks offscreen elements only when asked', async () => {
+    await client.callTool('browser_set_content', {
+      html: '<button>Top</button><div style="height: 3000px"></div><button>Bottom</button>',
+    });
+
+    const plain = await client.callTool('browser_map');
+    assert.deepStrictEqual(plain.content[0].text.split('\n'), ['@e1 [button] "Top"', '@e2 [button] "Bottom"']);
+
+    const marked = await client.callTool('browser_map', { markOffscreen: true });
+    const lines = marked.content[0].text.split('\n');
+    assert.ok(!lines[0].includes('offscreen'), `Visible button should not be marked: ${lines[0]}`);
+    assert.ok(lines[1].endsWith('(offscreen, below fold)'), `Should mark the offscreen button: ${lines[1]}`);
+  });
+
+  test('browser_find_all_by_role joins inline children without a space', async () => {
+    await client.callTool('browser_set_content', {
+      html: '<button>foo<b>bar</b></button><div role="button"><p>Save</p><p>draft</p></div>',
+    });
+
+    const result = await client.callTool('browser_find_all_by_role', { role: 'button' });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    const names = JSON.parse(result.content[0].text).map(m => m.name);
+    assert.deepStrictEqual(names, ['foobar', 'Save draft']);
+  });
+
+  test('browser_find_all_by_role names other roles by their text content', async () => {
+    await client.callTool('browser_set_content', { html: '<div role="region">Latest <em>news</em></div>' });
+
+    const result = await client.callTool('browser_find_all_by_role', { role: 'region', name: 'Latest news' });
+    const matches = JSON.parse(result.content[0].text);
+    assert.strictEqual(matches.length, 1, `Should match by text content: ${result.content[0].text}`);
+  });
+
+  test('browser_snapshot attaches usable refs to interactive nodes', async () => {
+    await client.callTool('browser_set_content', {
+      html: `<h1>Settings</h1>
+        <button onclick="document.title = 'saved'">Save</button>`,
+    });
+
+    const result = await client.callTool('browser_snapshot');
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    const nodes = [];
+    (function walk(node) {
+      nodes.push(node);
+      (node.children || []).forEach(walk);
+    })(JSON.parse(result.content[0].text));
+
+    const heading = nodes.find(n => n.role === 'heading');
+    assert.strictEqual(heading.name, 'Settings');
+    assert.strictEqual(heading.ref, undefined, 'Headings are not interactive');
+    const button = nodes.find(n => n.role === 'button' && n.name === 'Save');
+    assert.ok(button && button.ref && button.ref.startsWith('@e'), `Button should carry a ref: ${result.content[0].text}`);
+
+    await client.callTool('browser_click', { selector: button.ref });
+    const title = await client.callTool('browser_evaluate', { expression: 'document.title' });
+    assert.strictEqual(title.content[0].text, 'saved');
+  });
+
+  test('browser_find_all_by_role lists every match in order with an index and ref', async () => {
+    await client.callTool('browser_set_content', {
+      html: `<div role="tablist">
+          <button role="tab">Overview</button>
+          <button role="tab">Specs</button>
+          <button role="tab" onclick="document.title = 'reviews'">Reviews</button>
+        </div>
+        <button>Buy</button>`,
+    });
+
+    const result = await client.callTool('browser_find_all_by_role', { role: 'tab' });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    const tabs = JSON.parse(result.content[0].text);
+    assert.deepStrictEqual(tabs.map(t => [t.index, t.ref, t.tag, t.text]), [
+      [0, '@e1', 'button', 'Overview'],
+      [1, '@e2', 'button', 'Specs'],
+      [2, '@e3', 'button', 'Reviews'],
+    ]);
+    assert.ok(tabs.every(t => t.box.w > 0 && t.box.h > 0), 'Each match should have a box');
+
+    await client.callTool('browser_click', { selector: tabs[2].ref });
+    const title = await client.callTool('browser_evaluate', { expression: 'document.title' });
+    assert.strictEqual(title.content[0].text, 'reviews', 'The third ref should click the third tab');
+
+    const named = JSON.parse((await client.callTool('browser_find_all_by_role', { role: 'tab', name: 'e', limit: 2 })).content[0].text);
+    assert.deepStrictEqual(named.map(t => t.text), ['Overview', 'Specs'], 'name filters and limit caps the list');
+  });
+
+  test('browser_find text matching defaults to substring and honors exact and caseInsensitive', async () => {
+    await client.callTool('browser_set_content', {
+      html: '<button>Save and close</button><button>Autosave settings</button><button>Save</button>',
+    });
+    const find = async (args) => {
+      const result = await client.callTool('browser_find', { timeout: 500, ...args });
+      return result.isError ? null : result.content[0].text;
+    };
+
+    assert.match(await find({ text: 'Save' }), /"Save"$/, 'Substring matching prefers the shortest match');
+    assert.match(await find({ text: 'save' }), /Autosave settings/, 'Matching is case-sensitive by default');
+    assert.match(await find({ text: 'save', caseInsensitive: true }), /"Save"$/);
+    assert.match(await find({ text: 'save and close', exact: true, caseInsensitive: true }), /Save and close/);
+    assert.strictEqual(await find({ text: 'Save and', exact: true }), null, 'exact should not match a prefix');
+  });
+
+  test('browser_count counts within a scope or a frame', async () => {
+    await client.callTool('browser_set_content', {
+      html: `<ul id="fruits"><li>Apple</li><li>Pear</li></ul>
+        <ul id="veg"><li>Leek</li><li>Kale</li><li>Okra</li></ul>
+        <iframe name="basket" srcdoc="<ul><li>Plum</li></ul>"></iframe>`,
+    });
+    const count = async (args) => {
+      const result = await client.callTool('browser_count', args);
+      return result.isError ? `error: ${result.content[0].text}` : result.content[0].text;
+    };
+
+    assert.strictEqual(await count({ selector: 'li' }), '5', 'Without a scope the whole page counts');
+    assert.strictEqual(await count({ selector: 'li', scope: '#veg' }), '3');
+    assert.strictEqual(await count({ selector: 'li', frame: 'basket' }), '1');
+    assert.match(await count({ selector: 'li', scope: '#nuts' }), /^error: .*scope not found/);
+
+    await client.callTool('browser_find', { selector: '#fruits' });
+    assert.strictEqual(await count({ selector: 'li', scope: '@e1' }), '2', 'The scope can be an @ref');
+  });
+});
+
+describe('MCP Server: Screenshot baseline', () => {
+  let client, dir;
+
+  before(async () => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'vibium-baseline-'));
+    client = new MCPClient();
+    await client.start({}, ['--screenshot-dir', dir]);
+    await client.call('initialize', { capabilities: {} });
+  });
+
+  after(async () => {
+    await client.call('tools/call', { name: 'browser_stop', arguments: {} });
+    client.stop();
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  test('browser_screenshot saves a missing baseline, then matches it', async () => {
+    await client.callTool('browser_set_content', { html: '<h1 style="color: green">Stable</h1>' });
+
+    const first = await client.callTool('browser_screenshot', { baseline: 'base/home.png' });
+    assert.ok(!first.isError, `Should not be an error: ${JSON.stringify(first.content)}`);
+    assert.ok(fs.existsSync(path.join(dir, 'base', 'home.png')), 'Should save the baseline');
+
+    const second = await client.callTool('browser_screenshot', { baseline: 'base/home.png' });
+    assert.ok(!second.isError, 'Matching screenshot should not be an error');
+    assert.ok(second.content.some(c => c.type === 'text' && c.text.startsWith('Matches baseline')));
+  });
+
+  test('browser_screenshot reports a baseline mismatch as an error', async () => {
+    await client.callTool('browser_set_content', { html: '<h1 style="color: green">Before</h1>' });
+    await client.callTool('browser_screenshot', { baseline: 'changed.png' });
+
+    await client.callTool('browser_set_content', { html: '<h1 style="color: red">After</h1>' });
+    const result = await client.callTool('browser_screenshot', { baseline: 'changed.png', diffPath: 'changed-diff.png' });
+    assert.strictEqual(result.isError, true, 'Mismatch should be an error result');
+    assert.strictEqual(result.content[0].type, 'image', 'Should still return the screenshot');
+    assert.ok(result.content.some(c => c.type === 'text' && c.text.startsWith('Differs from baseline')));
+    assert.ok(fs.existsSync(path.join(dir, 'changed-diff.png')), 'Should write the diff image');
+  });
+});
+
+describe('MCP Server: Upload', () => {
+  let client, dir;
+
+  before(async () => {
+    client = new MCPClient();
+    await client.start();
+    await client.call('initialize', { capabilities: {} });
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'vibium-upload-'));
+    fs.mkdirSync(path.join(dir, 'photos', '2024'), { recursive: true });
+    fs.writeFileSync(path.join(dir, 'photos', 'a.txt'), 'a');
+    fs.writeFileSync(path.join(dir, 'photos', '2024', 'b.txt'), 'b');
+  });
+
+  after(async () => {
+    await client.call('tools/call', { name: 'browser_stop', arguments: {} });
+    client.stop();
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  test('browser_upload directory keeps each webkitRelativePath', async () => {
+    await client.callTool('browser_set_content', { html: '<input type="file" webkitdirectory>' });
+
+    const result = await client.callTool('browser_upload', {
+      selector: 'input',
+      directory: path.join(dir, 'photos'),
+    });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.match(result.content[0].text, /^Set 2 file\(s\)/);
+
+    const paths = await client.callTool('browser_evaluate', {
+      expression: "Array.from(document.querySelector('input').files, f => f.webkitRelativePath).sort().join(',')",
+    });
+    assert.strictEqual(paths.content[0].text, 'photos/2024/b.txt,photos/a.txt');
+  });
+
+  test('browser_upload directory rejects an input without webkitdirectory', async () => {
+    await client.callTool('browser_set_content', { html: '<input type="file" multiple>' });
+
+    const result = await client.callTool('browser_upload', {
+      selector: 'input',
+      directory: path.join(dir, 'photos'),
+    });
+    assert.strictEqual(result.isError, true, 'Should be an error');
+  });
+
+  test('browser_upload contents uploads in-memory files', async () => {
+    await client.callTool('browser_set_content', { html: '<input type="file" multiple>' });
+
+    const result = await client.callTool('browser_upload', {
+      selector: 'input',
+      contents: [
+        { name: 'report.csv', content: Buffer.from('a,b\n1,2\n').toString('base64') },
+        { name: 'notes.txt', content: Buffer.from('hello').toString('base64') },
+      ],
+    });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+
+    const files = await client.callTool('browser_evaluate', {
+      expression: "Promise.all(Array.from(document.querySelector('input').files, async f => f.name + ':' + await f.text())).then(a => a.join('|'))",
+    });
+    assert.strictEqual(files.content[0].text, 'report.csv:a,b\n1,2\n|notes.txt:hello');
+
+    const bad = await client.callTool('browser_upload', {
+      selector: 'input',
+      contents: [{ name: 'x.bin', content: 'not base64!' }],
+    });
+    assert.strictEqual(bad.isError, true, 'Invalid base64 should be an error');
+  });
+});
+
+describe('MCP Server: Page tools', () => {
+  let client;
+
+  before(async () => {
+    client = new MCPClient();
+    await client.start();
+    await client.call('initialize', { capabilities: {} });
+  });
+
+  after(async () => {
+    await client.call('tools/call', { name: 'browser_stop', arguments: {} });
+    client.stop();
+  });
+
+  const tallPage = '<div style="height: 5000px; width: 5000px">Tall</div>';
+
+  test('browser_scroll to bottom and to an absolute position', async () => {
+    await client.callTool('browser_set_content', { html: tallPage });
+
+    const bottom = await client.callTool('browser_scroll', { to: 'bottom' });
+    assert.ok(!bottom.isError, `Should not be an error: ${JSON.stringify(bottom.content)}`);
+    assert.match(bottom.content[0].text, /^Scrolled to bottom \(scrollX: 0, scrollY: [1-9]\d*\)$/);
+
+    const pos = await client.callTool('browser_scroll', { scrollX: 40, scrollY: 1500 });
+    assert.strictEqual(pos.content[0].text, 'Scrolled to position (scrollX: 40, scrollY: 1500)');
+  });
+
+  test('browser_pdf accepts margins with units', async () => {
+    await client.callTool('browser_set_content', { html: '<h1>Report</h1>' });
+
+    const result = await client.callTool('browser_pdf', {
+      format: 'A4',
+      margin: { top: '1in', right: '10mm', bottom: '2', left: '2cm' },
+    });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.strictEqual(Buffer.from(result.content[0].text, 'base64').subarray(0, 5).toString(), '%PDF-', 'Should return a PDF');
+
+    const bad = await client.callTool('browser_pdf', { margin: { top: 'wide' } });
+    assert.strictEqual(bad.isError, true, 'Unparseable margin should be an error');
+  });
+
+  test('browser_set_geolocation overrides the position and clear removes it', async () => {
+    await client.callTool('browser_navigate', { url: 'https://example.com' });
+
+    const set = await client.callTool('browser_set_geolocation', { latitude: 51.5, longitude: -0.125 });
+    assert.ok(!set.isError, `Should not be an error: ${JSON.stringify(set.content)}`);
+    const pos = await client.callTool('browser_evaluate', {
+      expression: 'new Promise((resolve, reject) => navigator.geolocation.getCurrentPosition(p => resolve(p.coords.latitude + "," + p.coords.longitude), reject))',
+    });
+    assert.strictEqual(pos.content[0].text, '51.5,-0.125');
+
+    const cleared = await client.callTool('browser_set_geolocation', { clear: true });
+    assert.strictEqual(cleared.content[0].text, 'Geolocation override cleared');
+  });
+
+  test('browser_scroll rejects to combined with a position', async () => {
+    const result = await client.callTool('browser_scroll', { to: 'top', scrollY: 10 });
+    assert.strictEqual(result.isError, true, 'Should be an error');
+  });
+
+  test('browser_wait_until_stable_screenshot returns once the page stops changing', async () => {
+    await client.callTool('browser_set_content', { html: '<h1>Still</h1>' });
+
+    const result = await client.callTool('browser_wait_until_stable_screenshot', { interval: 100, timeout: 10000 });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.match(result.content[0].text, /^Page visually stable after \d+ captures/);
+    assert.strictEqual(result.content[1].type, 'image', 'Should return the stable screenshot');
+  });
+
+  test('browser_get_element_html_and_text returns markup and visible text', async () => {
+    await client.callTool('browser_set_content', { html: '<p id="msg">Hello <b>there</b></p>' });
+
+    const result = await client.callTool('browser_get_element_html_and_text', { selector: '#msg' });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.deepStrictEqual(JSON.parse(result.content[0].text), {
+      outerHTML: '<p id="msg">Hello <b>there</b></p>',
+      innerText: 'Hello there',
+    });
+
+    const short = await client.callTool('browser_get_element_html_and_text', { selector: '#msg', maxLength: 5 });
+    assert.strictEqual(JSON.parse(short.content[0].text).outerHTML, '<p id... [truncated 29 characters]');
+  });
+
+  test('browser_focus_next and browser_focus_previous move focus in tab order', async () => {
+    await client.callTool('browser_set_content', {
+      html: '<input id="first" placeholder="First"><input id="second" placeholder="Second">',
+    });
+
+    const first = await client.callTool('browser_focus_next');
+    assert.ok(!first.isError, `Should not be an error: ${JSON.stringify(first.content)}`);
+    assert.match(first.content[0].text, /^Focused .*First.*#first/);
+
+    const second = await client.callTool('browser_focus_next');
+    assert.match(second.content[0].text, /^Focused .*Second.*#second/);
+
+    const back = await client.callTool('browser_focus_previous');
+    assert.match(back.content[0].text, /^Focused .*First.*#first/);
+  });
+
+  test('browser_print_preview renders print styles and restores screen media', async () => {
+    await client.callTool('browser_set_content', {
+      html: '<style>@media print { h1 { display: none } }</style><h1>Screen only</h1>',
+    });
+    const screen = await client.callTool('browser_screenshot');
+
+    const result = await client.callTool('browser_print_preview');
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.strictEqual(result.content[0].type, 'image', 'Should return an image');
+    assert.notStrictEqual(result.content[0].data, screen.content[0].data, 'Should render print styles');
+
+    const media = await client.callTool('browser_evaluate', { expression: "matchMedia('print').matches" });
+    assert.strictEqual(media.content[0].text, 'false', 'Should restore screen media');
+  });
+
+  test('browser_select_by_index selects the nth option', async () => {
+    await client.callTool('browser_set_content', {
+      html: '<select id="size"><option value="s">Small</option><option value="m">Medium</option><option value="l">Large</option></select>',
+    });
+
+    const result = await client.callTool('browser_select_by_index', { selector: '#size', optionIndex: 2 });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.strictEqual(result.content[0].text, 'Selected option 2 (value "l") in #size');
+
+    const value = await client.callTool('browser_get_value', { selector: '#size' });
+    assert.strictEqual(value.content[0].text, 'l');
+
+    const outOfRange = await client.callTool('browser_select_by_index', { selector: '#size', optionIndex: 5 });
+    assert.strictEqual(outOfRange.isError, true, 'Out-of-range index should be an error');
+  });
+
+  test('browser_get_attributes returns the requested attributes, null when missing', async () => {
+    await client.callTool('browser_set_content', { html: `<a id="home" href="/home" data-track="nav">Home</a>` });
+
+    const result = await client.callTool('browser_get_attributes', {
+      selector: '#home',
+      attributes: ['href', 'data-track', "it's-missing"],
+    });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.deepStrictEqual(JSON.parse(result.content[0].text), {
+      href: '/home',
+      'data-track': 'nav',
+      "it's-missing": null,
+    });
+  });
+
+  test('browser_console_logs returns captured messages by level', async () => {
+    await client.callTool('browser_console_logs', { clear: true });
+    await client.callTool('browser_evaluate', { expression: "console.log('hello', 42); console.warn('careful'); 'logged'" });
+
+    const warn = await client.callTool('browser_console_logs', { level: 'warn' });
+    assert.ok(!warn.isError, `Should not be an error: ${JSON.stringify(warn.content)}`);
+    assert.deepStrictEqual(JSON.parse(warn.content[0].text).map(e => e.text), ['careful']);
+
+    const all = await client.callTool('browser_console_logs', { clear: true });
+    assert.deepStrictEqual(JSON.parse(all.content[0].text).map(e => e.text), ['hello 42', 'careful']);
+    const after = await client.callTool('browser_console_logs');
+    assert.deepStrictEqual(JSON.parse(after.content[0].text), [], 'clear should empty the buffer');
+  });
+
+  test('browser_page_errors returns uncaught exceptions', async () => {
+    await client.callTool('browser_page_errors', { clear: true });
+    await client.callTool('browser_evaluate', {
+      expression: "new Promise(resolve => { setTimeout(() => { throw new Error('boom') }); setTimeout(() => resolve('thrown'), 50); })",
+    });
+
+    const result = await client.callTool('browser_page_errors', { clear: true });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    const errors = JSON.parse(result.content[0].text);
+    assert.strictEqual(errors.length, 1, `Should capture one error: ${result.content[0].text}`);
+    assert.strictEqual(errors[0].type, 'javascript');
+    assert.match(errors[0].text, /boom/);
+  });
+
+  test('browser_scroll_by scrolls by exact pixels and reports the position', async () => {
+    await client.callTool('browser_set_content', { html: tallPage });
+    await client.callTool('browser_scroll', { to: 'top' });
+
+    const result = await client.callTool('browser_scroll_by', { deltaY: 400 });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    const scrollY = await client.callTool('browser_evaluate', { expression: 'window.scrollY' });
+    assert.ok(Number(scrollY.content[0].text) > 0, 'Should have scrolled down');
+    assert.strictEqual(result.content[0].text, `Scrolled by (0, 400) (scrollX: 0, scrollY: ${scrollY.content[0].text})`);
+  });
+
+  test('browser_drag_by drags from an element by an offset', async () => {
+    await client.callTool('browser_set_content', {
+      html: `<div id="box" style="position: absolute; left: 0; top: 0; width: 100px; height: 100px; background: teal"></div>
+        <script>
+          document.addEventListener('mousedown', e => { window.down = e.clientX + ',' + e.clientY; });
+          document.addEventListener('mouseup', e => { window.up = e.clientX + ',' + e.clientY; });
+        </script>`,
+    });
+
+    const result = await client.callTool('browser_drag_by', { source: '#box', deltaX: 120, deltaY: 30 });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.strictEqual(result.content[0].text, 'Dragged from (50, 50) by (120, 30)');
+
+    const points = await client.callTool('browser_evaluate', { expression: "window.down + ' -> ' + window.up" });
+    assert.strictEqual(points.content[0].text, '50,50 -> 170,80');
+  });
+
+  test('browser_right_click opens the context menu event on the element', async () => {
+    await client.callTool('browser_set_content', {
+      html: `<div id="target" style="width: 100px; height: 100px">Target</div>
+        <script>
+          document.getElementById('target').addEventListener('contextmenu', e => {
+            e.preventDefault();
+            window.menu = 'button ' + e.button;
+          });
+        </script>`,
+    });
+
+    const result = await client.callTool('browser_right_click', { selector: '#target' });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.strictEqual(result.content[0].text, 'Right-clicked element: #target');
+    const menu = await client.callTool('browser_evaluate', { expression: 'window.menu' });
+    assert.strictEqual(menu.content[0].text, 'button 2');
+  });
+
+  test('browser_tap sends a touch tap to the element', async () => {
+    await client.callTool('browser_set_content', {
+      html: `<button id="btn" style="width: 100px; height: 40px">Tap me</button>
+        <script>
+          const btn = document.getElementById('btn');
+          btn.addEventListener('pointerdown', e => { window.pointerType = e.pointerType; });
+          btn.addEventListener('click', () => { btn.textContent = 'Tapped'; });
+        </script>`,
+    });
+
+    const result = await client.callTool('browser_tap', { selector: '#btn' });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.strictEqual(result.content[0].text, 'Tapped element: #btn');
+    const state = await client.callTool('browser_evaluate', { expression: "window.pointerType + ' ' + document.getElementById('btn').textContent" });
+    assert.strictEqual(state.content[0].text, 'touch Tapped');
+  });
+
+  test('browser_pinch spreads two touch points apart for a zoom in', async () => {
+    await client.callTool('browser_set_content', {
+      html: `<div id="map" style="width: 300px; height: 300px; touch-action: none; background: #ddd"></div>
+        <script>
+          const start = {}, last = {};
+          const map = document.getElementById('map');
+          map.addEventListener('pointerdown', e => { start[e.pointerId] = last[e.pointerId] = [e.clientX, e.clientY]; });
+          map.addEventListener('pointermove', e => { last[e.pointerId] = [e.clientX, e.clientY]; });
+          window.spread = () => {
+            const dist = (pts) => { const [a, b] = Object.values(pts); return Math.hypot(a[0] - b[0], a[1] - b[1]); };
+            return Object.keys(start).length + ' pointers, ' + (dist(last) > dist(start) ? 'apart' : 'together');
+          };
+        </script>`,
+    });
+
+    const result = await client.callTool('browser_pinch', { selector: '#map', scale: 2 });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.strictEqual(result.content[0].text, 'Pinched in on #map (scale 2)');
+    const spread = await client.callTool('browser_evaluate', { expression: 'window.spread()' });
+    assert.strictEqual(spread.content[0].text, '2 pointers, apart');
+  });
+
+  test('browser_key_sequence holds Shift across several presses', async () => {
+    await client.callTool('browser_set_content', { html: '<input id="name" value="abcdef">' });
+    await client.callTool('browser_focus', { selector: '#name' });
+
+    const result = await client.callTool('browser_key_sequence', {
+      actions: [
+        { action: 'press', key: 'End' },
+        { action: 'down', key: 'Shift' },
+        { action: 'press', key: 'ArrowLeft' },
+        { action: 'press', key: 'ArrowLeft' },
+        { action: 'press', key: 'ArrowLeft' },
+        { action: 'up', key: 'Shift' },
+      ],
+    });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.strictEqual(result.content[0].text,
+      'Performed key sequence: press:End down:Shift press:ArrowLeft press:ArrowLeft press:ArrowLeft up:Shift');
+
+    const selected = await client.callTool('browser_evaluate', {
+      expression: "(i => i.value.substring(i.selectionStart, i.selectionEnd))(document.getElementById('name'))",
+    });
+    assert.strictEqual(selected.content[0].text, 'def');
+  });
+
+  test('browser_clear empties an input', async () => {
+    await client.callTool('browser_set_content', { html: '<input id="q" value="hello">' });
+
+    const result = await client.callTool('browser_clear', { selector: '#q' });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.strictEqual(result.content[0].text, 'Cleared #q');
+    const value = await client.callTool('browser_get_value', { selector: '#q' });
+    assert.strictEqual(value.content[0].text, '');
+  });
+
+  test('browser_is_editable tells editable fields from readonly and disabled ones', async () => {
+    await client.callTool('browser_set_content', {
+      html: '<input id="open"><input id="ro" readonly><input id="off" disabled><div id="rich" contenteditable="true">x</div>',
+    });
+
+    const editable = async (selector) => (await client.callTool('browser_is_editable', { selector })).content[0].text;
+    assert.strictEqual(await editable('#open'), 'true');
+    assert.strictEqual(await editable('#ro'), 'false');
+    assert.strictEqual(await editable('#off'), 'false');
+    assert.strictEqual(await editable('#rich'), 'true');
+  });
+
+  test('browser_get_outer_html returns the element with its own tag', async () => {
+    await client.callTool('browser_set_content', { html: '<ul id="list"><li>One</li></ul>' });
+
+    const result = await client.callTool('browser_get_outer_html', { selector: '#list' });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.strictEqual(result.content[0].text, '<ul id="list"><li>One</li></ul>');
+  });
+
+  test('browser_select_options lists values, labels and state', async () => {
+    await client.callTool('browser_set_content', {
+      html: '<select id="size"><option value="s">Small</option><option value="m" selected>Medium</option><option value="l" label="Large size" disabled>L</option></select>',
+    });
+
+    const result = await client.callTool('browser_select_options', { selector: '#size' });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.deepStrictEqual(JSON.parse(result.content[0].text), [
+      { value: 's', label: 'Small', selected: false, disabled: false },
+      { value: 'm', label: 'Medium', selected: true, disabled: false },
+      { value: 'l', label: 'Large size', selected: false, disabled: true },
+    ]);
+  });
+
+  test('browser_get_table returns rows and header-keyed records', async () => {
+    await client.callTool('browser_set_content', {
+      html: `<table id="t">
+        <tr><th>Name</th><th>Qty</th></tr>
+        <tr><td>Apple</td><td>3</td></tr>
+        <tr><td colspan="2">Sold out</td></tr>
+      </table>`,
+    });
+
+    const rows = await client.callTool('browser_get_table', { selector: '#t' });
+    assert.ok(!rows.isError, `Should not be an error: ${JSON.stringify(rows.content)}`);
+    assert.deepStrictEqual(JSON.parse(rows.content[0].text), [
+      ['Name', 'Qty'],
+      ['Apple', '3'],
+      ['Sold out', 'Sold out'],
+    ]);
+
+    const records = await client.callTool('browser_get_table', { selector: '#t', headers: true });
+    assert.deepStrictEqual(JSON.parse(records.content[0].text), [
+      { Name: 'Apple', Qty: '3' },
+      { Name: 'Sold out', Qty: 'Sold out' },
+    ]);
+  });
+
+  test('browser_get_links lists unique links and filters by pattern', async () => {
+    await client.callTool('browser_set_content', {
+      html: `<a href="https://example.com/docs">Docs</a>
+        <a href="https://example.com/docs">Docs again</a>
+        <a href="https://example.org/blog" style="display: none">Blog</a>
+        <a name="anchor">No href</a>`,
+    });
+
+    const all = await client.callTool('browser_get_links');
+    assert.ok(!all.isError, `Should not be an error: ${JSON.stringify(all.content)}`);
+    assert.deepStrictEqual(JSON.parse(all.content[0].text), [
+      { text: 'Docs', href: 'https://example.com/docs', visible: true },
+      { text: 'Blog', href: 'https://example.org/blog', visible: false },
+    ]);
+
+    const filtered = await client.callTool('browser_get_links', { pattern: 'example.org' });
+    assert.deepStrictEqual(JSON.parse(filtered.content[0].text).map(l => l.href), ['https://example.org/blog']);
+  });
+
+  test('browser_switch_frame enters nested frames and the parent/top tools leave them', async () => {
+    await client.callTool('browser_set_content', {
+      html: `<p>Top</p>
+        <iframe name="outer" srcdoc="<iframe name='inner' srcdoc='<p>Inner</p>'></iframe><p>Outer</p>"></iframe>`,
+    });
+    const text = async () => (await client.callTool('browser_get_text', { selector: 'p' })).content[0].text;
+
+    const outer = await client.callTool('browser_switch_frame', { frame: 'outer' });
+    assert.ok(!outer.isError, `Should not be an error: ${JSON.stringify(outer.content)}`);
+    assert.strictEqual(await text(), 'Outer');
+
+    await client.callTool('browser_switch_frame', { frame: 'inner' });
+    assert.strictEqual(await text(), 'Inner');
+
+    await client.callTool('browser_switch_frame_parent');
+    assert.strictEqual(await text(), 'Outer');
+
+    await client.callTool('browser_switch_frame', { frame: 'inner' });
+    const top = await client.callTool('browser_switch_frame_top');
+    assert.strictEqual(top.content[0].text, 'Switched to the top-level page');
+    assert.strictEqual(await text(), 'Top');
+
+    const again = await client.callTool('browser_switch_frame_parent');
+    assert.strictEqual(again.content[0].text, 'Already on the top-level page');
+  });
+
+  test('browser_set_dialog_handler answers dialogs as they open', async () => {
+    await client.callTool('browser_set_content', {
+      html: `<button id="ask" onclick="document.title = prompt('Your name?', 'anon')">Ask</button>
+        <button id="confirm" onclick="document.title = String(confirm('Sure?'))">Confirm</button>`,
+    });
+    const title = async () => (await client.callTool('browser_evaluate', { expression: 'document.title' })).content[0].text;
+
+    const set = await client.callTool('browser_set_dialog_handler', { policy: 'accept-with-text', text: 'Ada' });
+    assert.ok(!set.isError, `Should not be an error: ${JSON.stringify(set.content)}`);
+    assert.strictEqual(set.content[0].text, 'Dialogs will be accepted automatically, answering prompts with "Ada"');
+    const click = await client.callTool('browser_click', { selector: '#ask' });
+    const after = await client.callTool('browser_evaluate', { expression: 'document.title' });
+    assert.strictEqual(after.content[0].text, 'Ada');
+    // The note rides on whichever call saw the dialog open
+    const notes = [...click.content, ...after.content].map(c => c.text);
+    assert.ok(
+      notes.includes('Note: auto-accepted prompt dialog "Your name?"'),
+      `Should note the answered dialog: ${JSON.stringify(notes)}`,
+    );
+
+    await client.callTool('browser_set_dialog_handler', { policy: 'dismiss' });
+    await client.callTool('browser_click', { selector: '#confirm' });
+    assert.strictEqual(await title(), 'false');
+
+    const none = await client.callTool('browser_set_dialog_handler', { policy: 'none' });
+    assert.strictEqual(none.content[0].text, 'Dialogs are no longer handled automatically');
+    const missingText = await client.callTool('browser_set_dialog_handler', { policy: 'accept-with-text' });
+    assert.strictEqual(missingText.isError, true, 'accept-with-text should require text');
+  });
+
+  test('browser_get_dialog describes the open dialog until it is answered', async () => {
+    await client.callTool('browser_set_content', { html: '<p>Dialogs</p>' });
+    await client.callTool('browser_set_dialog_handler', { policy: 'none' });
+    const dialogWhen = async (ready) => {
+      for (let i = 0; i < 50; i++) {
+        const text = (await client.callTool('browser_get_dialog')).content[0].text;
+        if (ready(text)) return text;
+        await new Promise(r => setTimeout(r, 100));
+      }
+      assert.fail('Dialog state did not change');
+    };
+
+    assert.strictEqual((await client.callTool('browser_get_dialog')).content[0].text, 'null');
+    await client.callTool('browser_evaluate', {
+      expression: "setTimeout(() => { document.title = prompt('Rename to?', 'draft'); }, 0); 'scheduled'",
+    });
+
+    const open = JSON.parse(await dialogWhen(text => text !== 'null'));
+    assert.deepStrictEqual(open, { type: 'prompt', message: 'Rename to?', defaultValue: 'draft' });
+
+    await client.callTool('browser_dialog_accept', { text: 'final' });
+    assert.strictEqual(await dialogWhen(text => text === 'null'), 'null');
+    const title = await client.callTool('browser_evaluate', { expression: 'document.title' });
+    assert.strictEqual(title.content[0].text, 'final');
+  });
+
+  test('browser_select picks options by value, label, and several at once', async () => {
+    await client.callTool('browser_set_content', {
+      html: `<select id="color">
+          <option value="">Choose a color</option>
+          <option value="b1">Dark Blue</option>
+          <option value="r1">Red</option>
+        </select>
+        <select id="toppings" multiple>
+          <option value="cheese">Cheese</option>
+          <option value="ham">Ham</option>
+          <option value="olives">Olives</option>
+        </select>`,
+    });
+    const value = async (expr) => (await client.callTool('browser_evaluate', { expression: expr })).content[0].text;
+
+    const byLabel = await client.callTool('browser_select', { selector: '#color', label: 'Dark Blue' });
+    assert.ok(!byLabel.isError, `Should not be an error: ${JSON.stringify(byLabel.content)}`);
+    assert.strictEqual(byLabel.content[0].text, 'Selected values ["b1"] in #color');
+
+    const placeholder = await client.callTool('browser_select', { selector: '#color', value: '' });
+    assert.ok(!placeholder.isError, `Empty value should select the placeholder: ${JSON.stringify(placeholder.content)}`);
+    assert.strictEqual(await value("document.querySelector('#color').selectedIndex"), '0');
+
+    const multi = await client.callTool('browser_select', { selector: '#toppings', values: ['cheese'], labels: ['Olives'] });
+    assert.strictEqual(multi.content[0].text, 'Selected values ["cheese","olives"] in #toppings');
+
+    const missing = await client.callTool('browser_select', { selector: '#color', label: 'Green' });
+    assert.strictEqual(missing.isError, true, 'Unknown label should be an error');
+    assert.match(missing.content[0].text, /no option with label "Green"/);
+    const tooMany = await client.callTool('browser_select', { selector: '#color', values: ['b1', 'r1'] });
+    assert.strictEqual(tooMany.isError, true, 'Several values on a single select should be an error');
+  });
+
+  test('browser_screenshot with a selector captures only that element', async () => {
+    await client.callTool('browser_set_content', {
+      html: '<div style="height: 1500px">Above</div><div id="badge" style="width: 120px; height: 40px; background: teal">Badge</div>',
+    });
+    const pngSize = (data) => {
+      const png = Buffer.from(data, 'base64');
+      return { width: png.readUInt32BE(16), height: png.readUInt32BE(20) };
+    };
+    const dpr = Number((await client.callTool('browser_evaluate', { expression: 'window.devicePixelRatio' })).content[0].text);
+
+    const element = await client.callTool('browser_screenshot', { selector: '#badge' });
+    assert.ok(!element.isError, `Should not be an error: ${JSON.stringify(element.content)}`);
+    assert.strictEqual(element.content[0].type, 'image');
+    assert.deepStrictEqual(pngSize(element.content[0].data), { width: 120 * dpr, height: 40 * dpr }, 'Should clip to the element box, even below the fold');
+
+    const both = await client.callTool('browser_screenshot', { selector: '#badge', fullPage: true });
+    assert.deepStrictEqual(pngSize(both.content[0].data), { width: 120 * dpr, height: 40 * dpr }, 'selector should win over fullPage');
+    assert.match(both.content[1].text, /fullPage is ignored when selector is given/);
+  });
+
+  test('browser_evaluate binds args to the function without string building', async () => {
+    await client.callTool('browser_set_content', { html: '<input id="q">' });
+    const tricky = `it's "quoted"\n</script>\${alert(1)}`;
+
+    const typed = await client.callTool('browser_evaluate', {
+      expression: '(text, opts) => { const el = document.getElementById(opts.id); el.value = text; return { value: el.value, count: opts.count + 1, flags: opts.flags }; }',
+      args: [tricky, { id: 'q', count: 41, flags: [true, null] }],
+    });
+    assert.ok(!typed.isError, `Should not be an error: ${JSON.stringify(typed.content)}`);
+    assert.deepStrictEqual(JSON.parse(typed.content[0].text), { value: tricky, count: 42, flags: [true, null] });
+
+    const none = await client.callTool('browser_evaluate', { expression: '() => 7', args: [] });
+    assert.strictEqual(none.content[0].text, '7', 'An empty args array still calls the function');
+  });
+
+  test('browser_type without a selector types into the focused element', async () => {
+    await client.callTool('browser_set_content', {
+      html: '<input id="first"><div id="notes" contenteditable="true"></div>',
+    });
+    await client.callTool('browser_evaluate', { expression: "document.getElementById('first').focus(); 'focused'" });
+    await client.callTool('browser_press', { key: 'Tab' });
+
+    const typed = await client.callTool('browser_type', { text: 'hello' });
+    assert.ok(!typed.isError, `Should not be an error: ${JSON.stringify(typed.content)}`);
+    assert.strictEqual(typed.content[0].text, 'Typed 5 character(s) into the focused element');
+    const state = await client.callTool('browser_evaluate', {
+      expression: "document.getElementById('first').value + '|' + document.getElementById('notes').textContent",
+    });
+    assert.strictEqual(state.content[0].text, '|hello', 'Should type into the contenteditable focused by Tab');
+
+    const empty = await client.callTool('browser_type', { text: '' });
+    assert.strictEqual(empty.isError, true, 'Empty text without a selector should be an error');
+  });
+
+  test('browser_type raw inserts CJK text and multi-codepoint emoji intact', async () => {
+    await client.callTool('browser_set_content', {
+      html: `<input id="name"><div id="bio" contenteditable="true"></div>
+        <script>
+          window.inputs = [];
+          document.addEventListener('input', (e) => window.inputs.push(e.inputType + ':' + e.data));
+        </script>`,
+    });
+    const text = '日本語のテキスト 🇯🇵 👩‍👩‍👧';
+
+    const typed = await client.callTool('browser_type', { selector: '#name', text, raw: true });
+    assert.ok(!typed.isError, `Should not be an error: ${JSON.stringify(typed.content)}`);
+    const value = await client.callTool('browser_evaluate', { expression: "document.getElementById('name').value" });
+    assert.strictEqual(value.content[0].text, text);
+    const events = await client.callTool('browser_evaluate', { expression: 'JSON.stringify(window.inputs)' });
+    assert.deepStrictEqual(JSON.parse(events.content[0].text), [`insertText:${text}`], 'Should be one insertText input event');
+
+    await client.callTool('browser_evaluate', { expression: "document.getElementById('bio').focus(); 'focused'" });
+    await client.callTool('browser_type', { text: '中文 🏳️‍🌈', raw: true });
+    const bio = await client.callTool('browser_evaluate', { expression: "document.getElementById('bio').textContent" });
+    assert.strictEqual(bio.content[0].text, '中文 🏳️‍🌈', 'Should insert into the focused contenteditable');
+  });
+
+  test('browser_a11y_tree withSelectors attaches selectors that act on the node', async () => {
+    await client.callTool('browser_set_content', {
+      html: `<h1>Orders</h1>
+        <button onclick="document.title = 'archived'">Archive</button>
+        <button onclick="document.title = 'deleted'">Delete</button>`,
+    });
+    const nodes = (tree) => [tree, ...(tree.children || []).flatMap(nodes)];
+
+    const plain = JSON.parse((await client.callTool('browser_a11y_tree')).content[0].text);
+    assert.ok(nodes(plain).every(n => n.selector === undefined), 'Selectors should be opt-in');
+
+    const tree = JSON.parse((await client.callTool('browser_a11y_tree', { withSelectors: true })).content[0].text);
+    const del = nodes(tree).find(n => n.role === 'button' && n.name === 'Delete');
+    assert.ok(del && del.selector, `Delete button should carry a selector: ${JSON.stringify(tree)}`);
+    const heading = nodes(tree).find(n => n.role === 'heading');
+    assert.strictEqual(heading.selector, undefined, 'Only interactive nodes get selectors');
+
+    await client.callTool('browser_click', { selector: del.selector });
+    const title = await client.callTool('browser_evaluate', { expression: 'document.title' });
+    assert.strictEqual(title.content[0].text, 'deleted', 'The selector should target that node');
+  });
+
+  test('a frame argument runs one element tool inside that iframe', async () => {
+    await client.callTool('browser_set_content', {
+      html: `<button id="pay">Top pay</button>
+        <iframe name="checkout" srcdoc="<button id='pay' onclick='parent.document.title = &quot;paid in frame&quot;'>Pay</button>"></iframe>`,
+    });
+
+    const inFrame = await client.callTool('browser_get_text', { selector: '#pay', frame: 'checkout' });
+    assert.strictEqual(inFrame.content[0].text, 'Pay');
+    const click = await client.callTool('browser_click', { selector: '#pay', frame: 'checkout' });
+    assert.ok(!click.isError, `Should not be an error: ${JSON.stringify(click.content)}`);
+    const title = await client.callTool('browser_evaluate', { expression: 'document.title' });
+    assert.strictEqual(title.content[0].text, 'paid in frame');
+
+    const top = await client.callTool('browser_get_text', { selector: '#pay' });
+    assert.strictEqual(top.content[0].text, 'Top pay', 'Later calls run on the page again');
+
+    const missing = await client.callTool('browser_click', { selector: '#pay', frame: 'nope' });
+    assert.strictEqual(missing.isError, true, 'An unknown frame should be an error');
+  });
+
+  test('browser_new_page reports index and context, and switch: false keeps the active page', async () => {
+    await client.callTool('browser_set_content', { html: '<p>original</p>' });
+
+    const background = await client.callTool('browser_new_page', { switch: false });
+    assert.ok(!background.isError, `Should not be an error: ${JSON.stringify(background.content)}`);
+    const match = background.content[0].text.match(/^New page \[(\d+)\] opened \(context (\S+)\) in the background$/);
+    assert.ok(match, `Unexpected message: ${background.content[0].text}`);
+    const list = await client.callTool('browser_list_pages');
+    assert.ok(list.content[0].text.includes(`[${match[1]}]`), 'Index should match browser_list_pages');
+
+    const text = await client.callTool('browser_evaluate', { expression: 'document.body.textContent' });
+    assert.strictEqual(text.content[0].text, 'original', 'Active page should be unchanged');
+
+    const foreground = await client.callTool('browser_new_page');
+    assert.doesNotMatch(foreground.content[0].text, /in the background/);
+    const blank = await client.callTool('browser_evaluate', { expression: 'location.href' });
+    assert.strictEqual(blank.content[0].text, 'about:blank', 'New page should become active');
+
+    await client.callTool('browser_close_page');
+    await client.callTool('browser_close_page', { index: Number(match[1]) });
+    await client.callTool('browser_switch_page', { index: 0 });
+  });
+
+  test('browser_switch_page latest: true follows a target=_blank popup', async () => {
+    await client.callTool('browser_set_content', {
+      html: '<a id="pop" href="about:blank#popup" target="_blank">Open</a>',
+    });
+    await client.callTool('browser_click', { selector: '#pop' });
+
+    const result = await client.callTool('browser_switch_page', { latest: true });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    const href = await client.callTool('browser_evaluate', { expression: 'location.href' });
+    assert.strictEqual(href.content[0].text, 'about:blank#popup', 'Should switch to the popup');
+
+    await client.callTool('browser_close_page');
+    await client.callTool('browser_switch_page', { index: 0 });
+  });
+
+  test('browser_keys sequence presses keys in order', async () => {
+    await client.callTool('browser_set_content', {
+      html: '<script>window.pressed = []; addEventListener("keydown", e => pressed.push(e.key));</script>',
+    });
+
+    const result = await client.callTool('browser_keys', { sequence: ['g', 'g', 'Shift+G'] });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    const pressed = await client.callTool('browser_evaluate', { expression: 'pressed.join(",")' });
+    assert.strictEqual(pressed.content[0].text, 'g,g,Shift,G');
+  });
+});
+
+describe('MCP Server: Skill chains', () => {
+  let client, dir;
+
+  before(async () => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'vibium-skills-'));
+    fs.writeFileSync(path.join(dir, 'show-title.json'), JSON.stringify({
+      steps: [{ tool: 'browser_set_content', args: { html: '<h1>{{title}}</h1>' } }],
+    }));
+    fs.writeFileSync(path.join(dir, 'check-title.json'), JSON.stringify({
+      steps: [
+        { skill: 'show-title', params: { title: '{{title}}' } },
+        { tool: 'browser_get_text', args: { selector: 'h1' } },
+      ],
+    }));
+
+    client = new MCPClient();
+    await client.start({ VIBIUM_SKILLS_DIR: dir });
+    await client.call('initialize', { capabilities: {} });
+  });
+
+  after(async () => {
+    await client.call('tools/call', { name: 'browser_stop', arguments: {} });
+    client.stop();
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  test('browser_invoke_skill_chain runs nested skills with parameters', async () => {
+    const result = await client.callTool('browser_invoke_skill_chain', {
+      name: 'check-title',
+      params: { title: 'Hello' },
+    });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.deepStrictEqual(result.content[0].text.split('\n'), [
+      'Ran skill "check-title" (2 steps)',
+      '1. [show-title] browser_set_content: Page content set (14 chars)',
+      '2. [check-title] browser_get_text: Hello',
+    ]);
+  });
+
+  test('browser_invoke_skill_chain reports a missing skill', async () => {
+    const result = await client.callTool('browser_invoke_skill_chain', { name: 'nope' });
+    assert.strictEqual(result.isError, true, 'Should be an error');
+    assert.match(result.content[0].text, /skill "nope" not found/);
+  });
+});
+
+describe('MCP Server: Network tools', () => {
+  let client, server, baseURL;
+
+  before(async () => {
+    ({ server, baseURL } = await createTestServer());
+    client = new MCPClient();
+    await client.start();
+    await client.call('initialize', { capabilities: {} });
+  });
+
+  after(async () => {
+    await client.call('tools/call', { name: 'browser_stop', arguments: {} });
+    client.stop();
+    server.close();
+  });
+
+  test('browser_get_cookies_changed reports added and changed cookies', async () => {
+    await client.callTool('browser_navigate', { url: baseURL });
+
+    const first = await client.callTool('browser_get_cookies_changed');
+    assert.match(first.content[0].text, /^Cookie snapshot taken/);
+
+    await client.callTool('browser_evaluate', { expression: "document.cookie = 'theme=dark'" });
+    const added = JSON.parse((await client.callTool('browser_get_cookies_changed')).content[0].text);
+    assert.deepStrictEqual(added.added.map(c => c.name), ['theme']);
+
+    await client.callTool('browser_evaluate', { expression: "document.cookie = 'theme=light'" });
+    const changed = JSON.parse((await client.callTool('browser_get_cookies_changed')).content[0].text);
+    assert.deepStrictEqual(changed.added, []);
+    assert.strictEqual(changed.changed[0].oldValue, 'dark');
+    assert.strictEqual(changed.changed[0].newValue, 'light');
+  });
+
+  test('browser_set_user_agent overrides navigator.userAgent and the request header', async () => {
+    const result = await client.callTool('browser_set_user_agent', { userAgent: 'VibiumBot/1.0' });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.strictEqual(result.content[0].text, 'User agent set to "VibiumBot/1.0"');
+
+    await client.callTool('browser_navigate', { url: `${baseURL}/headers` });
+    const headers = JSON.parse((await client.callTool('browser_get_text')).content[0].text);
+    assert.strictEqual(headers['user-agent'], 'VibiumBot/1.0');
+    const ua = await client.callTool('browser_evaluate', { expression: 'navigator.userAgent' });
+    assert.strictEqual(ua.content[0].text, 'VibiumBot/1.0');
+  });
+
+  test('browser_request_intercept fulfills and blocks requests until unintercepted', async () => {
+    await client.callTool('browser_navigate', { url: baseURL });
+
+    const stub = await client.callTool('browser_request_intercept', {
+      pattern: '/api/data',
+      action: 'fulfill',
+      headers: { 'Content-Type': 'application/json' },
+      body: '{"stubbed":true}',
+    });
+    assert.ok(!stub.isError, `Should not be an error: ${JSON.stringify(stub.content)}`);
+    assert.strictEqual(stub.content[0].text, 'Intercepting "/api/data" (fulfill with status 200) — 1 active rule(s)');
+    const block = await client.callTool('browser_request_intercept', { pattern: '/blocked' });
+    assert.strictEqual(block.content[0].text, 'Intercepting "/blocked" (block) — 2 active rule(s)');
+
+    const fetchText = (url) => client.callTool('browser_evaluate', {
+      expression: `fetch('${url}').then(r => r.text(), () => 'failed')`,
+    });
+    assert.strictEqual((await fetchText('/api/data')).content[0].text, '{"stubbed":true}');
+    assert.strictEqual((await fetchText('/blocked')).content[0].text, 'failed');
+
+    const removed = await client.callTool('browser_request_unintercept');
+    assert.strictEqual(removed.content[0].text, 'Removed 2 rule(s) — 0 active rule(s)');
+    assert.match((await fetchText('/api/data')).content[0].text, /<html>/, 'Should reach the server again');
+  });
+
+  test('browser_wait_for_response returns a response made by the previous call', async () => {
+    await client.callTool('browser_navigate', { url: baseURL });
+    await client.callTool('browser_evaluate', { expression: "fetch('/headers?from=page'); 'started'" });
+
+    const result = await client.callTool('browser_wait_for_response', { pattern: '/headers?from=page' });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    const resp = JSON.parse(result.content[0].text);
+    assert.strictEqual(resp.url, `${baseURL}/headers?from=page`);
+    assert.strictEqual(resp.method, 'GET');
+    assert.strictEqual(resp.status, 200);
+
+    const missing = await client.callTool('browser_wait_for_response', { pattern: '/never', timeout: 500 });
+    assert.strictEqual(missing.isError, true, 'Should time out');
+  });
+
+  test('browser_wait_for_page returns a popup opened by the previous call', async () => {
+    await client.callTool('browser_navigate', { url: baseURL });
+    await client.callTool('browser_evaluate', {
+      expression: "document.body.insertAdjacentHTML('afterbegin', '<a id=\"popup\" href=\"/checkboxes\" target=\"_blank\">Open</a>'); 'ok'",
+    });
+    await client.callTool('browser_click', { selector: '#popup' });
+
+    const result = await client.callTool('browser_wait_for_page', { url: '/checkboxes' });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    const page = JSON.parse(result.content[0].text);
+    assert.strictEqual(page.url, `${baseURL}/checkboxes`);
+    assert.ok(page.index > 0, 'Popup should not be the first page');
+    assert.ok(page.context, 'Should return the popup context');
+    await client.callTool('browser_close_page', { index: page.index });
+
+    const none = await client.callTool('browser_wait_for_page', { timeout: 500 });
+    assert.strictEqual(none.isError, true, 'Should time out without a new page');
+  });
+
+  test('browser_get_cookie returns one cookie by name or null', async () => {
+    await client.callTool('browser_navigate', { url: baseURL });
+    await client.callTool('browser_evaluate', { expression: "document.cookie = 'session=abc123; path=/'" });
+
+    const result = await client.callTool('browser_get_cookie', { name: 'session' });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    const cookie = JSON.parse(result.content[0].text);
+    assert.strictEqual(cookie.name, 'session');
+    assert.strictEqual(cookie.value, 'abc123');
+    assert.strictEqual(cookie.domain, '127.0.0.1');
+
+    const otherDomain = await client.callTool('browser_get_cookie', { name: 'session', domain: 'example.com' });
+    assert.strictEqual(otherDomain.content[0].text, 'null');
+    const missing = await client.callTool('browser_get_cookie', { name: 'nope' });
+    assert.strictEqual(missing.content[0].text, 'null');
+  });
+
+  test('browser_clear_storage wipes the selected stores and reports counts', async () => {
+    await client.callTool('browser_navigate', { url: baseURL });
+    await client.callTool('browser_clear_storage');
+    await client.callTool('browser_evaluate', {
+      expression: "document.cookie = 'a=1'; localStorage.setItem('x', '1'); localStorage.setItem('y', '2'); sessionStorage.setItem('z', '3'); 'ok'",
+    });
+
+    const result = await client.callTool('browser_clear_storage', { sessionStorage: false });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.strictEqual(result.content[0].text, 'Cleared 1 cookies, 2 localStorage keys');
+
+    const left = await client.callTool('browser_evaluate', {
+      expression: "[document.cookie, localStorage.length, sessionStorage.getItem('z')].join('|')",
+    });
+    assert.strictEqual(left.content[0].text, '|0|3');
+  });
+
+  test('browser_har_start and browser_har_stop write the captured requests', async () => {
+    const harPath = path.join(fs.mkdtempSync(path.join(os.tmpdir(), 'vibium-har-')), 'capture.har');
+
+    const start = await client.callTool('browser_har_start');
+    assert.strictEqual(start.content[0].text, 'HAR capture started');
+    const twice = await client.callTool('browser_har_start');
+    assert.strictEqual(twice.isError, true, 'Should refuse a second capture');
+
+    await client.callTool('browser_navigate', { url: `${baseURL}/checkboxes` });
+    const stop = await client.callTool('browser_har_stop', { path: harPath });
+    assert.ok(!stop.isError, `Should not be an error: ${JSON.stringify(stop.content)}`);
+
+    const har = JSON.parse(fs.readFileSync(harPath, 'utf8'));
+    assert.strictEqual(har.log.version, '1.2');
+    assert.strictEqual(stop.content[0].text, `HAR with ${har.log.entries.length} requests saved to ${harPath}`);
+    const page = har.log.entries.find(e => e.request.url === `${baseURL}/checkboxes`);
+    assert.ok(page, 'Should capture the navigation');
+    assert.strictEqual(page.response.status, 200);
+
+    const again = await client.callTool('browser_har_stop', { path: harPath });
+    assert.strictEqual(again.isError, true, 'Should fail without a capture in progress');
+  });
+
+  test('browser_block_urls blocks matching requests until unblocked', async () => {
+    await client.callTool('browser_navigate', { url: baseURL });
+    const fetchText = (url) => client.callTool('browser_evaluate', {
+      expression: `fetch('${url}').then(r => r.text(), () => 'failed')`,
+    });
+
+    const block = await client.callTool('browser_block_urls', { patterns: ['*/checkboxes', '/hovers'] });
+    assert.ok(!block.isError, `Should not be an error: ${JSON.stringify(block.content)}`);
+    assert.strictEqual(block.content[0].text, 'Blocking 2 pattern(s): "*/checkboxes", "/hovers"');
+    assert.strictEqual((await fetchText('/checkboxes')).content[0].text, 'failed');
+    assert.strictEqual((await fetchText('/hovers')).content[0].text, 'failed');
+    assert.match((await fetchText('/dropdown')).content[0].text, /The Internet - Dropdown/, 'Unmatched URLs should load');
+
+    const one = await client.callTool('browser_unblock_urls', { patterns: ['/hovers'] });
+    assert.strictEqual(one.content[0].text, 'Unblocked 1 pattern(s) — 1 still blocked: "*/checkboxes"');
+    assert.match((await fetchText('/hovers')).content[0].text, /The Internet - Hovers/);
+
+    const all = await client.callTool('browser_unblock_urls');
+    assert.strictEqual(all.content[0].text, 'Unblocked 1 pattern(s) — 0 still blocked');
+    assert.match((await fetchText('/checkboxes')).content[0].text, /The Internet - Checkboxes/);
+  });
+
+  test('browser_set_offline fails requests and updates navigator.onLine', async () => {
+    await client.callTool('browser_navigate', { url: baseURL });
+    const state = () => client.callTool('browser_evaluate', {
+      expression: "fetch('/checkboxes').then(() => 'ok', () => 'failed').then(r => navigator.onLine + ' ' + r)",
+    });
+
+    const off = await client.callTool('browser_set_offline', { offline: true });
+    assert.ok(!off.isError, `Should not be an error: ${JSON.stringify(off.content)}`);
+    assert.strictEqual(off.content[0].text, 'Browser is offline');
+    assert.strictEqual((await state()).content[0].text, 'false failed');
+
+    const on = await client.callTool('browser_set_offline', { offline: false });
+    assert.strictEqual(on.content[0].text, 'Browser is online');
+    assert.strictEqual((await state()).content[0].text, 'true ok');
+  });
+
+  test('browser_emulate_network applies presets and custom latency', async () => {
+    await client.callTool('browser_navigate', { url: baseURL });
+    const fetchMs = async () => Number((await client.callTool('browser_evaluate', {
+      expression: "(async () => { const t = performance.now(); await fetch('/checkboxes?' + Math.random()); return Math.round(performance.now() - t); })()",
+    })).content[0].text);
+
+    const result = await client.callTool('browser_emulate_network', { preset: 'fast-3g', latency: 600 });
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    assert.deepStrictEqual(JSON.parse(result.content[0].text), {
+      preset: 'fast-3g (customized)',
+      offline: false,
+      latency: 600,
+      downloadThroughput: 180000,
+      uploadThroughput: 84375,
+    });
+    assert.ok(await fetchMs() >= 500, 'Requests should be delayed by the added latency');
+
+    const online = await client.callTool('browser_emulate_network', { preset: 'online' });
+    assert.strictEqual(JSON.parse(online.content[0].text).latency, 0);
+    assert.ok(await fetchMs() < 500, 'Throttling should be removed');
+
+    const bad = await client.callTool('browser_emulate_network', { downloadThroughput: 0 });
+    assert.strictEqual(bad.isError, true, 'Should reject a zero throughput');
+  });
+
+  test('browser_emulate_cpu throttles and unthrottles the CPU', async () => {
+    const throttled = await client.callTool('browser_emulate_cpu', { rate: 4 });
+    assert.ok(!throttled.isError, `Should not be an error: ${JSON.stringify(throttled.content)}`);
+    assert.strictEqual(throttled.content[0].text, 'CPU throttled to 4x slowdown');
+
+    const removed = await client.callTool('browser_emulate_cpu', { rate: 1 });
+    assert.ok(!removed.isError, `Should not be an error: ${JSON.stringify(removed.content)}`);
+    assert.strictEqual(removed.content[0].text, 'CPU throttling removed');
+  });
+
+  test('browser_mock_record saves responses that browser_mock_replay serves', async () => {
+    const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'vibium-mocks-'));
+    await client.callTool('browser_navigate', { url: baseURL });
+    const fetchText = (url) => client.callTool('browser_evaluate', {
+      expression: `fetch('${url}').then(r => r.text(), () => 'failed')`,
+    });
+
+    const record = await client.callTool('browser_mock_record', { dir, patterns: ['/checkboxes'] });
+    assert.ok(!record.isError, `Should not be an error: ${JSON.stringify(record.content)}`);
+    await fetchText('/checkboxes');
+    await fetchText('/hovers');
+    const saved = await client.callTool('browser_mock_stop');
+    assert.strictEqual(saved.content[0].text, `Saved 1 response(s) to ${dir}`);
+
+    const replay = await client.callTool('browser_mock_replay', { dir, patterns: ['/checkboxes', '/hovers'], strict: true });
+    assert.ok(!replay.isError, `Should not be an error: ${JSON.stringify(replay.content)}`);
+    assert.match((await fetchText('/checkboxes')).content[0].text, /The Internet - Checkboxes/);
+    assert.strictEqual((await fetchText('/hovers')).content[0].text, 'failed', 'Strict replay should fail unsaved requests');
+
+    const served = await client.callTool('browser_mock_stop');
+    assert.strictEqual(served.content[0].text, `Served 1 saved response(s) from ${dir}; failed 1 request(s) with no saved response`);
+    const idle = await client.callTool('browser_mock_stop');
+    assert.strictEqual(idle.isError, true, 'Should fail with nothing in progress');
+  });
+
+  test('browser_set_extra_headers and browser_set_basic_auth send headers to the page origin', async () => {
+    await client.callTool('browser_navigate', { url: baseURL });
+    const sentHeaders = async () => {
+      await client.callTool('browser_navigate', { url: `${baseURL}/headers` });
+      return JSON.parse((await client.callTool('browser_get_text')).content[0].text);
+    };
+
+    const extra = await client.callTool('browser_set_extra_headers', { headers: { 'X-Feature-Flag': 'beta' } });
+    assert.ok(!extra.isError, `Should not be an error: ${JSON.stringify(extra.content)}`);
+    assert.strictEqual(extra.content[0].text, `Sending 1 extra header(s) to ${baseURL}: X-Feature-Flag`);
+    const auth = await client.callTool('browser_set_basic_auth', { username: 'alice', password: 'secret' });
+    assert.strictEqual(auth.content[0].text, `Sending Basic auth for "alice" to ${baseURL}`);
+
+    let headers = await sentHeaders();
+    assert.strictEqual(headers['x-feature-flag'], 'beta');
+    assert.strictEqual(headers.authorization, 'Basic YWxpY2U6c2VjcmV0');
+
+    await client.callTool('browser_set_extra_headers', { headers: { 'X-Feature-Flag': 'beta' }, origin: 'https://staging.example.com' });
+    assert.strictEqual((await client.callTool('browser_set_basic_auth', { username: '' })).content[0].text, 'Basic auth cleared');
+    headers = await sentHeaders();
+    assert.strictEqual(headers['x-feature-flag'], undefined, 'Headers should not leak to other origins');
+    assert.strictEqual(headers.authorization, undefined);
+
+    const cleared = await client.callTool('browser_set_extra_headers', { headers: {} });
+    assert.strictEqual(cleared.content[0].text, 'Extra headers cleared');
+  });
+
+  test('browser_wait_for_download returns a download started by the previous call', async () => {
+    const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'vibium-downloads-'));
+    await client.callTool('browser_download_set_dir', { path: dir });
+    await client.callTool('browser_navigate', { url: baseURL });
+    await client.callTool('browser_evaluate', {
+      expression: "document.body.insertAdjacentHTML('afterbegin', '<a id=\"report\" href=\"/download\">Report</a>'); 'ok'",
+    });
+    await client.callTool('browser_click', { selector: '#report' });
+
+    const result = await client.callTool('browser_wait_for_download');
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    const download = JSON.parse(result.content[0].text);
+    assert.strictEqual(download.url, `${baseURL}/download`);
+    assert.strictEqual(download.suggestedFilename, 'report.txt');
+    assert.strictEqual(download.size, 'quarterly numbers\n'.length);
+    assert.strictEqual(fs.readFileSync(download.path, 'utf8'), 'quarterly numbers\n');
+
+    const none = await client.callTool('browser_wait_for_download', { timeout: 500 });
+    assert.strictEqual(none.isError, true, 'Should time out without a new download');
+  });
+
+  test('browser_list_downloads reports finished downloads', async () => {
+    const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'vibium-downloads-'));
+    await client.callTool('browser_download_set_dir', { path: dir });
+    await client.callTool('browser_navigate', { url: baseURL });
+    await client.callTool('browser_evaluate', {
+      expression: "document.body.insertAdjacentHTML('afterbegin', '<a id=\"report\" href=\"/download\">Report</a>'); 'ok'",
+    });
+    await client.callTool('browser_click', { selector: '#report' });
+    await client.callTool('browser_wait_for_download');
+
+    const result = await client.callTool('browser_list_downloads');
+    assert.ok(!result.isError, `Should not be an error: ${JSON.stringify(result.content)}`);
+    const downloads = JSON.parse(result.content[0].text);
+    const last = downloads[downloads.length - 1];
+    assert.strictEqual(last.url, `${baseURL}/download`);
+    assert.strictEqual(last.suggestedFilename, 'report.txt');
+    assert.strictEqual(last.state, 'complete');
+    assert.strictEqual(last.bytes, 'quarterly numbers\n'.length);
+    assert.ok(last.path.startsWith(dir), `Should be saved in the download dir: ${last.path}`);
+    assert.ok(!Number.isNaN(Date.parse(last.started)), 'started should be a timestamp');
+  });
+
+  test('browser_restore_storage restores IndexedDB while the page holds it open', async () => {
+    await client.callTool('browser_navigate', { url: baseURL });
+    const seed = await client.callTool('browser_evaluate', {
+      expression: `new Promise((resolve, reject) => {
+        const req = indexedDB.open('app', 1);
+        req.onupgradeneeded = () => req.result.createObjectStore('notes', { keyPath: 'id' });
+        req.onsuccess = () => {
+          window.db = req.result;
+          const tx = window.db.transaction('notes', 'readwrite');
+          tx.objectStore('notes').put({ id: 1, text: 'saved' });
+          tx.oncomplete = () => { localStorage.setItem('token', 'saved'); resolve('seeded'); };
+        };
+        req.onerror = () => reject(req.error);
+      })`,
+    });
+    assert.strictEqual(seed.content[0].text, 'seeded');
+
+    const exported = await client.callTool('browser_storage_state');
+    const statePath = path.join(os.tmpdir(), `vibium-state-${process.pid}.json`);
+    fs.writeFileSync(statePath, exported.content[0].text);
+
+    // The page keeps window.db open and never closes it on versionchange.
+    await client.callTool('browser_evaluate', {
+      expression: `new Promise((resolve) => {
+        const tx = window.db.transaction('notes', 'readwrite');
+        tx.objectStore('notes').put({ id: 1, text: 'changed' });
+        tx.objectStore('notes').put({ id: 2, text: 'extra' });
+        tx.oncomplete = () => { localStorage.setItem('token', 'changed'); resolve('changed'); };
+      })`,
+    });
+
+    try {
+      const restored = await client.callTool('browser_restore_storage', { path: statePath });
+      assert.ok(!restored.isError, `Should not be an error: ${JSON.stringify(restored.content)}`);
+      assert.match(restored.content[0].text, /1 of 1 origins/);
+    } finally {
+      fs.unlinkSync(statePath);
+    }
+
+    const after = await client.callTool('browser_evaluate', {
+      expression: `new Promise((resolve) => {
+        const req = window.db.transaction('notes').objectStore('notes').getAll();
+        req.onsuccess = () => resolve(JSON.stringify({ notes: req.result, token: localStorage.getItem('token') }));
+      })`,
+    });
+    assert.deepStrictEqual(JSON.parse(after.content[0].text), { notes: [{ id: 1, text: 'saved' }], token: 'saved' });
+  });
+});